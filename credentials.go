@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/credentials"
+)
+
+const (
+	// defaultCredentialWarnCheckInterval is how often credential expiry is checked
+	defaultCredentialWarnCheckInterval = time.Minute
+
+	// defaultCredentialWarnThreshold is how close to expiry credentials are before a warning is logged
+	defaultCredentialWarnThreshold = 5 * time.Minute
+)
+
+// credentialCheck is the JSON report written by the check-credentials subcommand
+type credentialCheck struct {
+	credentials.Identity
+
+	// AccessKey is the masked access key ID of the loaded credentials
+	AccessKey string `json:"AccessKey"`
+
+	// IdentityChecked is false when the STS identity lookup was skipped
+	IdentityChecked bool `json:"IdentityChecked"`
+}
+
+// runCheckCredentials loads AWS credentials and resolves their identity with
+// STS GetCallerIdentity, writing the result to w as JSON. The identity lookup
+// is skipped with --skip-identity-check for environments without STS access.
+// The credential sources default to MCP_CREDENTIAL_SOURCES, so the check
+// tries the same sources as the proxy.
+func runCheckCredentials(ctx context.Context, args []string, w io.Writer) error {
+	flags := flag.NewFlagSet("check-credentials", flag.ContinueOnError)
+	profile := flags.String("profile", "", "AWS credential profile name")
+	region := flags.String("region", "", "AWS region for the STS endpoint")
+	roleARN := flags.String("role-arn", "", "IAM role to assume with STS AssumeRole")
+	externalID := flags.String("external-id", "", "external ID passed to AssumeRole")
+	roleSessionName := flags.String("role-session-name", "", "session name for the assumed role")
+	roleSessionDuration := flags.Duration("role-session-duration", 0, "lifetime of assumed-role credentials (default 1h, maximum 12h)")
+	roleSessionTags := make(map[string]string)
+	flags.Func("role-session-tag", "session tag for the assumed role as Key=Value (repeatable)", func(value string) error {
+		key, tagValue, ok := strings.Cut(value, "=")
+		if !ok || key == "" {
+			return errors.New("session tag must be Key=Value")
+		}
+		roleSessionTags[key] = tagValue
+		return nil
+	})
+	credentialProcess := flags.String("credential-process", "", "command that prints AWS credential process JSON")
+	credentialSources := flags.String("credential-sources", os.Getenv("MCP_CREDENTIAL_SOURCES"), "comma delimited list of credential sources to try in order (env, profile, process, sso, webidentity, ecs, ec2)")
+	vaultAddr := flags.String("vault-addr", "", "HashiCorp Vault server URL (default VAULT_ADDR)")
+	vaultPath := flags.String("vault-path", "", "Vault AWS secrets engine credentials path")
+	ssoStartURL := flags.String("sso-start-url", "", "AWS SSO portal URL")
+	ssoAccountID := flags.String("sso-account-id", "", "AWS account of the SSO role")
+	ssoRoleName := flags.String("sso-role-name", "", "SSO permission set role name")
+	ssoRegion := flags.String("sso-region", "", "region hosting the AWS SSO portal")
+	webIdentityTokenFile := flags.String("web-identity-token-file", "", "file holding an OIDC token exchanged for the web identity role's credentials")
+	webIdentityRoleARN := flags.String("web-identity-role-arn", "", "IAM role assumed with the web identity token")
+	skipIdentityCheck := flags.Bool("skip-identity-check", false, "load credentials without calling STS")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	provider := &credentials.Provider{
+		Profile:               *profile,
+		Region:                *region,
+		AssumeRoleARN:         *roleARN,
+		AssumeRoleExternalID:  *externalID,
+		AssumeRoleSessionName: *roleSessionName,
+		AssumeRoleDuration:    *roleSessionDuration,
+		AssumeRoleSessionTags: roleSessionTags,
+		CredentialProcess:     *credentialProcess,
+		CredentialSources:     splitSources(*credentialSources),
+		Vault:                 vaultProvider(*vaultAddr, *vaultPath),
+		SSOStartURL:           *ssoStartURL,
+		SSOAccountID:          *ssoAccountID,
+		SSORoleName:           *ssoRoleName,
+		SSORegion:             *ssoRegion,
+		WebIdentityTokenFile:  *webIdentityTokenFile,
+		WebIdentityRoleARN:    *webIdentityRoleARN,
+	}
+	awsCfg, err := provider.LoadConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS credentials: %w", err)
+	}
+	creds, err := awsCfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS credentials: %w", err)
+	}
+
+	report := credentialCheck{AccessKey: maskAccessKey(creds.AccessKeyID)}
+	if !*skipIdentityCheck {
+		report.Identity, err = credentials.IdentityFromConfig(ctx, awsCfg)
+		if err != nil {
+			return err
+		}
+		report.IdentityChecked = true
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// splitSources splits a comma delimited list of credential sources
+func splitSources(value string) []string {
+	var sources []string
+	for _, source := range strings.Split(value, ",") {
+		if source = strings.TrimSpace(source); source != "" {
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
+
+// vaultProvider returns a Vault credential provider for path, or nil when no
+// Vault path is configured. The token is read from VAULT_TOKEN.
+func vaultProvider(addr, path string) *credentials.VaultProvider {
+	if path == "" {
+		return nil
+	}
+	return &credentials.VaultProvider{VaultAddr: addr, VaultPath: path}
+}
+
+// watchCredentialExpiry checks the cached credentials every interval until ctx
+// is done, warning when temporary credentials are about to expire. A zero
+// interval or threshold uses the default; a negative interval disables the checks.
+func watchCredentialExpiry(ctx context.Context, provider aws.CredentialsProvider, interval, threshold time.Duration, logger *slog.Logger) {
+	if interval < 0 {
+		return
+	}
+	if interval == 0 {
+		interval = defaultCredentialWarnCheckInterval
+	}
+	if threshold <= 0 {
+		threshold = defaultCredentialWarnThreshold
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				checkCredentialExpiry(ctx, provider, threshold, logger)
+			}
+		}
+	}()
+}
+
+// checkCredentialExpiry retrieves credentials from the cache and logs a warning
+// when they expire within threshold. Credentials that have already expired are
+// logged as an error and refreshed immediately.
+func checkCredentialExpiry(ctx context.Context, provider aws.CredentialsProvider, threshold time.Duration, logger *slog.Logger) {
+	creds, err := provider.Retrieve(ctx)
+	if err != nil {
+		logger.Error("failed to retrieve AWS credentials", "error", err)
+		return
+	}
+	if !creds.CanExpire {
+		return
+	}
+
+	remaining := time.Until(creds.Expires)
+	switch {
+	case remaining <= 0:
+		logger.Error(fmt.Sprintf("AWS credentials expired %s ago; refreshing now", (-remaining).Round(time.Second)),
+			"expires", creds.Expires,
+		)
+		if cache, ok := provider.(interface{ Invalidate() }); ok {
+			cache.Invalidate()
+		}
+		creds, err = provider.Retrieve(ctx)
+		if err != nil {
+			logger.Error("failed to refresh AWS credentials", "error", err)
+			return
+		}
+		logger.Info("AWS credentials refreshed", "expires", creds.Expires)
+	case remaining < threshold:
+		logger.Warn(fmt.Sprintf("AWS credentials expire in %s; proxy will attempt refresh automatically", remaining.Round(time.Second)),
+			"expires", creds.Expires,
+		)
+	}
+}
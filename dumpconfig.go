@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/config"
+)
+
+// configValue is a field of the dump-config report
+type configValue struct {
+	// Value is the effective value of the field
+	Value string `json:"value"`
+
+	// Source is where the value came from: default, env, file, config_source, or flag
+	Source string `json:"source"`
+}
+
+// runDumpConfig loads the configuration from the environment, configuration
+// file, config source, and command-line flags and writes the effective value
+// and source of each field to w as JSON. Invalid values are written as they
+// are; use the validate subcommand to check them. Sensitive fields, such as
+// headers, are left out.
+func runDumpConfig(w io.Writer, logger *slog.Logger) error {
+	builder := config.CommandLineBuilder()
+	cfg, err := builder.Build()
+	if cfg == nil {
+		return err
+	}
+	if err != nil {
+		logger.Debug("configuration is invalid", "error", err)
+	}
+	return writeConfigDump(w, cfg, builder.Sources())
+}
+
+// writeConfigDump writes cfg.AsMap to w as JSON, annotating each field with
+// its source
+func writeConfigDump(w io.Writer, cfg *config.Config, sources map[string]string) error {
+	report := make(map[string]configValue)
+	for key, value := range cfg.AsMap() {
+		report[key] = configValue{Value: value, Source: sources[key]}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
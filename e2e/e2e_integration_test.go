@@ -5,6 +5,7 @@ package e2e
 import (
 	"bytes"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"net/http"
@@ -14,18 +15,30 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/nisimpson/mcp-sigv4-proxy/internal/signer"
 	"github.com/nisimpson/mcp-sigv4-proxy/internal/transport"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// record names a cassette file that receives every signed exchange, for replay
+// with transport.ReplayTransport (go test -tags=e2e ./e2e -record=cassette.ndjson)
+var record = flag.String("record", "", "append signed requests and responses to this cassette file")
+
 // createSigningHTTPClient creates an HTTP client that uses the actual SigningRoundTripper
 // from the transport package. This ensures e2e tests use the real production code.
-func createSigningHTTPClient(signer signer.Signer) *http.Client {
-	return &http.Client{
-		Transport: transport.NewSigningRoundTripper(http.DefaultTransport, signer, make(map[string]string)),
+// With -record, the exchanges are also appended to the cassette.
+func createSigningHTTPClient(t *testing.T, signer signer.Signer) *http.Client {
+	t.Helper()
+	var rt http.RoundTripper = transport.NewSigningRoundTripper(http.DefaultTransport, signer, make(map[string]string))
+	if *record != "" {
+		recorder, err := transport.NewRecordingTransport(rt, *record)
+		require.NoError(t, err)
+		t.Cleanup(func() { recorder.Close() })
+		rt = recorder
 	}
+	return &http.Client{Transport: rt}
 }
 
 // TestIntegration_EndToEndMessageFlow tests the complete end-to-end flow
@@ -209,9 +222,9 @@ func TestIntegration_EndToEndMessageFlow(t *testing.T) {
 
 			// Create a V4 signer
 			v4Signer := &signer.V4Signer{
-				Credentials: testCreds,
-				Region:      tt.expectedRegion,
-				Service:     tt.expectedService,
+				CredentialsProvider: credentials.StaticCredentialsProvider{Value: testCreds},
+				Region:              tt.expectedRegion,
+				Service:             tt.expectedService,
 			}
 
 			// Create the signing transport (for verification)
@@ -221,7 +234,7 @@ func TestIntegration_EndToEndMessageFlow(t *testing.T) {
 			}
 
 			// Create an HTTP client using the actual SigningRoundTripper
-			client := createSigningHTTPClient(v4Signer)
+			client := createSigningHTTPClient(t, v4Signer)
 
 			// Send a request through the signing transport
 			requestJSON, err := json.Marshal(tt.requestBody)
@@ -325,13 +338,13 @@ func TestIntegration_SigV4SignatureVerification(t *testing.T) {
 
 			// Create a V4 signer with the test credentials
 			v4Signer := &signer.V4Signer{
-				Credentials: tt.credentials,
-				Region:      tt.region,
-				Service:     tt.service,
+				CredentialsProvider: credentials.StaticCredentialsProvider{Value: tt.credentials},
+				Region:              tt.region,
+				Service:             tt.service,
 			}
 
 			// Create an HTTP client using the actual SigningRoundTripper
-			client := createSigningHTTPClient(v4Signer)
+			client := createSigningHTTPClient(t, v4Signer)
 
 			// Make a request through the signing transport
 			requestBody := `{"jsonrpc":"2.0","id":1,"method":"test"}`
@@ -403,7 +416,7 @@ func TestIntegration_SigV4aNotAvailable(t *testing.T) {
 	}
 
 	// Create an HTTP client using the actual SigningRoundTripper
-	client := createSigningHTTPClient(v4aSigner)
+	client := createSigningHTTPClient(t, v4aSigner)
 
 	// Attempt to make a request (should fail during signing)
 	requestBody := `{"jsonrpc":"2.0","id":1,"method":"test"}`
@@ -488,13 +501,13 @@ func TestIntegration_ErrorForwarding(t *testing.T) {
 			}
 
 			v4Signer := &signer.V4Signer{
-				Credentials: testCreds,
-				Region:      "us-east-1",
-				Service:     "execute-api",
+				CredentialsProvider: credentials.StaticCredentialsProvider{Value: testCreds},
+				Region:              "us-east-1",
+				Service:             "execute-api",
 			}
 
 			// Create an HTTP client using the actual SigningRoundTripper
-			client := createSigningHTTPClient(v4Signer)
+			client := createSigningHTTPClient(t, v4Signer)
 
 			// Make a request through the signing transport
 			requestBody := `{"jsonrpc":"2.0","id":1,"method":"test"}`
@@ -547,13 +560,13 @@ func TestIntegration_NetworkErrorHandling(t *testing.T) {
 	}
 
 	v4Signer := &signer.V4Signer{
-		Credentials: testCreds,
-		Region:      "us-east-1",
-		Service:     "execute-api",
+		CredentialsProvider: credentials.StaticCredentialsProvider{Value: testCreds},
+		Region:              "us-east-1",
+		Service:             "execute-api",
 	}
 
 	// Create an HTTP client using the actual SigningRoundTripper
-	client := createSigningHTTPClient(v4Signer)
+	client := createSigningHTTPClient(t, v4Signer)
 	client.Timeout = 2 * time.Second
 
 	// Attempt to make a request
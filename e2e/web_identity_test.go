@@ -0,0 +1,132 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/credentials"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assumeRoleWithWebIdentityResponse is the STS query-protocol response body
+const assumeRoleWithWebIdentityResponse = `<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>ASIAWEBIDENTITY%d</AccessKeyId>
+      <SecretAccessKey>web-identity-secret</SecretAccessKey>
+      <SessionToken>web-identity-session</SessionToken>
+      <Expiration>%s</Expiration>
+    </Credentials>
+    <AssumedRoleUser>
+      <Arn>arn:aws:sts::123456789012:assumed-role/irsa/mcp-sigv4-proxy</Arn>
+      <AssumedRoleId>AROAEXAMPLE:mcp-sigv4-proxy</AssumedRoleId>
+    </AssumedRoleUser>
+  </AssumeRoleWithWebIdentityResult>
+  <ResponseMetadata>
+    <RequestId>00000000-0000-0000-0000-000000000000</RequestId>
+  </ResponseMetadata>
+</AssumeRoleWithWebIdentityResponse>`
+
+// TestIntegration_WebIdentityCredentials verifies that the credentials provider
+// exchanges the token file named by AWS_WEB_IDENTITY_TOKEN_FILE or by the
+// provider's fields with a mock STS endpoint, and re-reads the rotated token
+// when the credentials are refreshed.
+func TestIntegration_WebIdentityCredentials(t *testing.T) {
+	const roleARN = "arn:aws:iam::123456789012:role/irsa"
+
+	tests := []struct {
+		name     string
+		provider func(t *testing.T, tokenFile string) *credentials.Provider
+	}{
+		{
+			name: "environment",
+			provider: func(t *testing.T, tokenFile string) *credentials.Provider {
+				t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", tokenFile)
+				t.Setenv("AWS_ROLE_ARN", roleARN)
+				return &credentials.Provider{Region: "us-east-1"}
+			},
+		},
+		{
+			name: "provider fields",
+			provider: func(t *testing.T, tokenFile string) *credentials.Provider {
+				return &credentials.Provider{Region: "us-east-1", WebIdentityTokenFile: tokenFile, WebIdentityRoleARN: roleARN}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu     sync.Mutex
+				tokens []string
+				roles  []string
+			)
+			sts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, r.ParseForm())
+				if r.Form.Get("Action") != "AssumeRoleWithWebIdentity" {
+					http.Error(w, "unexpected action "+r.Form.Get("Action"), http.StatusBadRequest)
+					return
+				}
+
+				mu.Lock()
+				tokens = append(tokens, r.Form.Get("WebIdentityToken"))
+				roles = append(roles, r.Form.Get("RoleArn"))
+				call := len(tokens)
+				mu.Unlock()
+
+				// Expire inside the refresh window so every retrieval calls STS again
+				expiration := time.Now().Add(time.Minute).UTC().Format(time.RFC3339)
+				w.Header().Set("Content-Type", "text/xml")
+				fmt.Fprintf(w, assumeRoleWithWebIdentityResponse, call, expiration)
+			}))
+			defer sts.Close()
+
+			tokenFile := filepath.Join(t.TempDir(), "token")
+			require.NoError(t, os.WriteFile(tokenFile, []byte("first-token"), 0o600))
+
+			// Isolate the default chain from the host's credentials
+			t.Setenv("AWS_ACCESS_KEY_ID", "")
+			t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+			t.Setenv("AWS_SESSION_TOKEN", "")
+			t.Setenv("AWS_PROFILE", "")
+			t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+			t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+			t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+			t.Setenv("AWS_ENDPOINT_URL_STS", sts.URL)
+			t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", "")
+			t.Setenv("AWS_ROLE_ARN", "")
+
+			cfg, err := tt.provider(t, tokenFile).LoadConfig(context.Background())
+			require.NoError(t, err)
+
+			creds, err := cfg.Credentials.Retrieve(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, "web-identity-session", creds.SessionToken)
+
+			// Kubernetes rotates the projected token in place
+			require.NoError(t, os.WriteFile(tokenFile, []byte("second-token"), 0o600))
+			refreshed, err := cfg.Credentials.Retrieve(context.Background())
+			require.NoError(t, err)
+			assert.NotEqual(t, creds.AccessKeyID, refreshed.AccessKeyID)
+
+			mu.Lock()
+			defer mu.Unlock()
+			require.GreaterOrEqual(t, len(tokens), 2)
+			assert.Equal(t, "first-token", tokens[0])
+			assert.Equal(t, "second-token", tokens[len(tokens)-1])
+			for _, role := range roles {
+				assert.Equal(t, roleARN, role)
+			}
+		})
+	}
+}
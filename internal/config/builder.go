@@ -0,0 +1,232 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+)
+
+// layer is a configuration source applied by a Builder
+type layer struct {
+	// source names the layer in Builder.Source
+	source string
+
+	// apply returns cfg with the layer's values applied over it
+	apply func(cfg *Config) (*Config, error)
+}
+
+// Builder loads configuration from layered sources. Each source is applied
+// over the sources added before it, and the Builder records the source that
+// set each field.
+type Builder struct {
+	layers       []layer
+	flags        *commandLine
+	cfg          *Config
+	fieldSources map[string]string
+}
+
+// NewBuilder returns a Builder without any sources
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// CommandLineBuilder returns a Builder that loads configuration as Load does:
+// environment variables with the --env-prefix prefix, then the configuration
+// file and config source named by flags or environment variables, then
+// command-line flags. The command line is parsed when it is called.
+func CommandLineBuilder() *Builder {
+	b := NewBuilder()
+	flags := b.commandLine()
+	return b.WithEnvPrefix(flags.envPrefix).
+		WithFile(flags.configFile).
+		WithConfigSource(flags.configSource).
+		WithFlags()
+}
+
+// WithEnv adds the unprefixed environment variables read by LoadFromEnv
+func (b *Builder) WithEnv() *Builder {
+	return b.WithEnvPrefix("")
+}
+
+// WithEnvPrefix adds environment variables read with prefix, as in
+// LoadFromEnvWithPrefix. Variables that are unset or set to a zero value, such
+// as false, leave the fields of earlier sources unchanged.
+func (b *Builder) WithEnvPrefix(prefix string) *Builder {
+	env := environment{prefix: strings.TrimSuffix(strings.ToUpper(prefix), "_")}
+	return b.with(SourceEnv, func(cfg *Config) (*Config, error) {
+		overlay(cfg, env.config())
+		return cfg, nil
+	})
+}
+
+// WithFile adds a JSON configuration file, as in LoadFromFile. An empty path
+// loads the file named by an earlier source, such as MCP_CONFIG_FILE, if any.
+func (b *Builder) WithFile(path string) *Builder {
+	return b.with(SourceFile, func(cfg *Config) (*Config, error) {
+		file := path
+		if file == "" {
+			file = cfg.ConfigFile
+		}
+		if file == "" {
+			return cfg, nil
+		}
+		fileCfg, err := LoadFromFile(file, cfg)
+		if fileCfg == nil {
+			return nil, err
+		}
+		return fileCfg, nil
+	})
+}
+
+// WithConfigSource adds a remote config source, as in LoadFromConfigSource. An
+// empty source loads the config source named by an earlier source, if any.
+func (b *Builder) WithConfigSource(source string) *Builder {
+	return b.with(SourceConfigSource, func(cfg *Config) (*Config, error) {
+		uri := source
+		if uri == "" {
+			uri = cfg.ConfigSource
+		}
+		if uri == "" {
+			return cfg, nil
+		}
+		sourceCfg, err := LoadFromConfigSource(context.Background(), uri, cfg)
+		if sourceCfg == nil {
+			return nil, err
+		}
+		return sourceCfg, nil
+	})
+}
+
+// WithFlags adds the command-line flags. The flags are defined on the default
+// flag set and parsed once per Builder.
+func (b *Builder) WithFlags() *Builder {
+	return b.with(SourceFlag, func(cfg *Config) (*Config, error) {
+		flags := b.commandLine()
+		flags.apply(cfg)
+
+		// The file and config source were loaded by name, so their fields
+		// already hold the flag values
+		if flags.configFile != "" {
+			b.fieldSources["ConfigFile"] = SourceFlag
+		}
+		if flags.configSource != "" {
+			b.fieldSources["ConfigSource"] = SourceFlag
+		}
+		return cfg, nil
+	})
+}
+
+// with adds a layer
+func (b *Builder) with(source string, apply func(cfg *Config) (*Config, error)) *Builder {
+	b.layers = append(b.layers, layer{source: source, apply: apply})
+	return b
+}
+
+// commandLine parses the command-line flags the first time it is called
+func (b *Builder) commandLine() *commandLine {
+	if b.flags == nil {
+		b.flags = parseFlags()
+	}
+	return b.flags
+}
+
+// Build applies the sources in the order they were added over the default
+// configuration and validates the result. On validation failure the
+// configuration is still returned alongside the error, matching LoadFromEnv.
+func (b *Builder) Build() (*Config, error) {
+	cfg := &Config{SignatureVersion: "v4", Profile: "default"}
+	b.cfg = nil
+	b.fieldSources = make(map[string]string)
+
+	for _, l := range b.layers {
+		before := *cfg
+		next, err := l.apply(cfg)
+		if err != nil {
+			return nil, err
+		}
+		for _, change := range Diff(&before, next) {
+			b.fieldSources[change.Field] = l.source
+		}
+		cfg = next
+	}
+	b.cfg = cfg
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// Source returns the source that set the Config field named field after
+// Build: SourceEnv, SourceFile, SourceConfigSource, SourceFlag, or
+// SourceDefault when no source changed it. A field set by several sources
+// reports the last one that changed its value. It returns "" for names that
+// are not Config fields.
+func (b *Builder) Source(field string) string {
+	if source, ok := b.fieldSources[field]; ok {
+		return source
+	}
+	if _, ok := reflect.TypeFor[Config]().FieldByName(field); ok {
+		return SourceDefault
+	}
+	return ""
+}
+
+// Sources returns the source of every field in Config.AsMap, keyed like AsMap
+func (b *Builder) Sources() map[string]string {
+	fields := reflect.TypeFor[Config]()
+	fieldSources := make(map[string]string, fields.NumField())
+	for i := range fields.NumField() {
+		name := fields.Field(i).Name
+		if !sensitiveFields[name] {
+			fieldSources[fieldKey(name)] = b.Source(name)
+		}
+	}
+	return fieldSources
+}
+
+// ExplainAll returns a table of every Config field with its value and source,
+// in struct field order, after Build. The values of sensitive fields are
+// redacted.
+func (b *Builder) ExplainAll() string {
+	if b.cfg == nil {
+		return ""
+	}
+	values := b.cfg.AsMap()
+	cfgValue := reflect.ValueOf(*b.cfg)
+	fields := cfgValue.Type()
+
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FIELD\tVALUE\tSOURCE")
+	for i := range fields.NumField() {
+		name := fields.Field(i).Name
+		value := values[fieldKey(name)]
+		if sensitiveFields[name] && !cfgValue.Field(i).IsZero() {
+			value = "(redacted)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", name, value, b.Source(name))
+	}
+	w.Flush()
+	return sb.String()
+}
+
+// overlay copies the fields of src that are set over dst. Method timeouts are
+// merged by method, as SetMethodTimeout does.
+func overlay(dst, src *Config) {
+	timeouts := src.MethodTimeouts
+	src.MethodTimeouts = nil
+
+	dstValue := reflect.ValueOf(dst).Elem()
+	srcValue := reflect.ValueOf(src).Elem()
+	for i := range srcValue.NumField() {
+		if field := srcValue.Field(i); !field.IsZero() {
+			dstValue.Field(i).Set(field)
+		}
+	}
+	for method, timeout := range timeouts {
+		dst.SetMethodTimeout(timeout, method)
+	}
+}
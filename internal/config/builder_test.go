@@ -0,0 +1,131 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setCommandLine replaces the command line and default flag set for the test
+func setCommandLine(t *testing.T, args ...string) {
+	t.Helper()
+	origArgs, origFlags := os.Args, flag.CommandLine
+	t.Cleanup(func() { os.Args, flag.CommandLine = origArgs, origFlags })
+	os.Args = append([]string{"sigv4-proxy"}, args...)
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+}
+
+func TestBuilder_Sources(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "proxy.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"region": "us-west-2", "timeout": "45s"}`), 0o600))
+
+	t.Setenv("MCP_TARGET_URL", "https://env.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	setCommandLine(t, "--service-name", "lambda", "--timeout", "1m")
+
+	builder := NewBuilder().WithEnv().WithFile(path).WithFlags()
+	cfg, err := builder.Build()
+	require.NoError(t, err)
+	assert.Equal(t, "https://env.example.com", cfg.TargetURL)
+	assert.Equal(t, "us-west-2", cfg.Region)
+	assert.Equal(t, "lambda", cfg.ServiceName)
+	assert.Equal(t, time.Minute, cfg.Timeout)
+
+	assert.Equal(t, SourceEnv, builder.Source("TargetURL"))
+	assert.Equal(t, SourceFile, builder.Source("Region"))
+	assert.Equal(t, SourceFile, builder.Source("ConfigFile"))
+	assert.Equal(t, SourceFlag, builder.Source("ServiceName"))
+	assert.Equal(t, SourceFlag, builder.Source("Timeout"))
+	assert.Equal(t, SourceDefault, builder.Source("SignatureVersion"))
+	assert.Equal(t, SourceDefault, builder.Source("DryRun"))
+	assert.Empty(t, builder.Source("NotAField"))
+
+	fieldSources := builder.Sources()
+	assert.Equal(t, SourceFile, fieldSources["region"])
+	assert.Equal(t, SourceFlag, fieldSources["service_name"])
+	assert.NotContains(t, fieldSources, "headers")
+}
+
+func TestBuilder_LayerOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "proxy.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"target_url": "https://file.example.com",
+		"region": "us-west-2",
+		"service_name": "execute-api",
+		"method_timeouts": {"tools/call": "5m"}
+	}`), 0o600))
+
+	t.Setenv("MCP_TARGET_URL", "https://env.example.com")
+	t.Setenv("MCP_LIST_TIMEOUT", "10s")
+
+	// Environment variables added after the file take precedence over it,
+	// and method timeouts are merged
+	builder := NewBuilder().WithFile(path).WithEnv()
+	cfg, err := builder.Build()
+	require.NoError(t, err)
+	assert.Equal(t, "https://env.example.com", cfg.TargetURL)
+	assert.Equal(t, "us-west-2", cfg.Region)
+	assert.Equal(t, 5*time.Minute, cfg.MethodTimeouts["tools/call"])
+	assert.Equal(t, 10*time.Second, cfg.MethodTimeouts["tools/list"])
+	assert.Equal(t, SourceEnv, builder.Source("TargetURL"))
+	assert.Equal(t, SourceFile, builder.Source("Region"))
+}
+
+func TestBuilder_ReturnsInvalidConfig(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "not a url")
+
+	builder := NewBuilder().WithEnv()
+	cfg, err := builder.Build()
+	require.Error(t, err)
+	require.NotNil(t, cfg)
+	assert.Equal(t, "not a url", cfg.TargetURL)
+	assert.Equal(t, SourceEnv, builder.Source("TargetURL"))
+}
+
+func TestCommandLineBuilder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "proxy.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"region": "us-west-2"}`), 0o600))
+
+	t.Setenv("STAGING_TARGET_URL", "https://staging.example.com")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("AWS_REGION", "us-east-1")
+	setCommandLine(t, "--env-prefix", "STAGING", "--config-file", path, "--profile", "deploy")
+
+	builder := CommandLineBuilder()
+	cfg, err := builder.Build()
+	require.NoError(t, err)
+	assert.Equal(t, "https://staging.example.com", cfg.TargetURL)
+	assert.Equal(t, "us-west-2", cfg.Region)
+	assert.Equal(t, "deploy", cfg.Profile)
+
+	assert.Equal(t, SourceEnv, builder.Source("TargetURL"))
+	assert.Equal(t, SourceFile, builder.Source("Region"))
+	assert.Equal(t, SourceFlag, builder.Source("Profile"))
+	assert.Equal(t, SourceFlag, builder.Source("ConfigFile"))
+}
+
+func TestBuilder_ExplainAll(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://env.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_HEADERS", "Authorization=Bearer secret")
+
+	builder := NewBuilder().WithEnv()
+	assert.Empty(t, builder.ExplainAll(), "nothing is explained before Build")
+	_, err := builder.Build()
+	require.NoError(t, err)
+
+	table := builder.ExplainAll()
+	lines := strings.Split(strings.TrimSpace(table), "\n")
+	assert.Equal(t, []string{"FIELD", "VALUE", "SOURCE"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"TargetURL", "https://env.example.com", "env"}, strings.Fields(lines[1]))
+	assert.Contains(t, table, "(redacted)")
+	assert.NotContains(t, table, "secret")
+}
@@ -1,14 +1,25 @@
 package config
 
 import (
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"log/slog"
+	"maps"
+	"mime"
+	"net"
+	"net/http"
 	"net/url"
 	"os"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
+	"unicode"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/credentials"
 )
 
 // Config holds proxy configuration
@@ -28,50 +39,578 @@ type Config struct {
 	// Profile is the AWS credential profile name (optional)
 	Profile string
 
+	// RoleARN is an IAM role to assume with STS AssumeRole before signing (optional)
+	RoleARN string
+
+	// ExternalID is passed to AssumeRole for roles whose trust policy requires it (optional)
+	ExternalID string
+
+	// RoleSessionName names the assumed-role session (optional)
+	RoleSessionName string
+
+	// RoleSessionDuration is how long assumed-role credentials last (0 means 1 hour, maximum 12 hours)
+	RoleSessionDuration time.Duration
+
+	// RoleSessionTags are session tags attached to the assumed role (optional)
+	RoleSessionTags map[string]string
+
+	// CredentialProcess is a command that prints AWS credential process JSON,
+	// used instead of the default credential chain (optional)
+	CredentialProcess string
+
+	// CredentialSources lists the credential sources to try, in order, in place
+	// of the default chain, such as env and profile (optional)
+	CredentialSources []string
+
+	// VaultAddr is the HashiCorp Vault server URL used with VaultPath
+	// (optional, defaults to VAULT_ADDR)
+	VaultAddr string
+
+	// VaultPath is a Vault AWS secrets engine credentials endpoint, such as
+	// aws/creds/my-role, used instead of the default credential chain (optional)
+	VaultPath string
+
+	// CredentialWarnCheckInterval is how often credential expiry is checked
+	// (0 means 1 minute, negative disables the checks)
+	CredentialWarnCheckInterval time.Duration
+
+	// CredentialWarnThreshold is how close to expiry credentials are before a warning is logged (0 means 5 minutes)
+	CredentialWarnThreshold time.Duration
+
+	// SSOStartURL is the AWS IAM Identity Center (SSO) portal URL; with the
+	// other SSO fields it selects the SSO role's credentials (optional)
+	SSOStartURL string
+
+	// SSOAccountID is the AWS account of the SSO role (optional)
+	SSOAccountID string
+
+	// SSORoleName is the SSO permission set role name (optional)
+	SSORoleName string
+
+	// SSORegion is the region hosting the SSO portal (optional)
+	SSORegion string
+
+	// WebIdentityTokenFile is a file holding an OIDC token exchanged for
+	// WebIdentityRoleARN's credentials, used instead of the default
+	// credential chain (optional)
+	WebIdentityTokenFile string
+
+	// WebIdentityRoleARN is the role assumed with WebIdentityTokenFile (optional)
+	WebIdentityRoleARN string
+
 	// Comma delimited list of headers
 	Headers string
 
+	// ExtraSignedHeaders names headers that every request must carry and
+	// the SigV4 signature must cover (optional)
+	ExtraSignedHeaders []string
+
+	// ForwardResponseHeaders names the target response headers added to the
+	// _meta of forwarded tool, resource, and prompt results (optional, nil
+	// forwards X-Amzn-Requestid and an empty list forwards none)
+	ForwardResponseHeaders []string
+
+	// SigningHost is the Host signed in place of the target URL's host, for
+	// targets reached through a VPC endpoint (optional)
+	SigningHost string
+
+	// RefreshOn401 re-signs a request the target rejects with 401 Unauthorized
+	// with refreshed credentials and sends it once more
+	RefreshOn401 bool
+
 	// Timeout is the request timeout duration for HTTP requests to the target server
 	Timeout time.Duration
 
+	// DialTimeout bounds establishing a TCP connection to the target (0 uses 30s)
+	DialTimeout time.Duration
+
+	// TLSHandshakeTimeout bounds the TLS handshake with the target (0 uses 10s)
+	TLSHandshakeTimeout time.Duration
+
+	// ResponseHeaderTimeout bounds the wait for the target's response headers
+	// after the request is sent (0 means no limit)
+	ResponseHeaderTimeout time.Duration
+
+	// IdleConnectionTimeout is how long an idle keep-alive connection to the
+	// target is kept open (0 uses 90s)
+	IdleConnectionTimeout time.Duration
+
+	// DNSCacheTTL caches resolved target addresses for this long (0 disables the cache)
+	DNSCacheTTL time.Duration
+
 	// EnableSSE enables Server-Sent Events for streaming responses
 	EnableSSE bool
+
+	// TLSCAFile is the path to a PEM file of additional CA certificates to trust (optional)
+	TLSCAFile string
+
+	// ClientCertFile is the path to a PEM client certificate for mutual TLS (optional)
+	ClientCertFile string
+
+	// ClientKeyFile is the path to the PEM private key for ClientCertFile (optional)
+	ClientKeyFile string
+
+	// TLSSkipVerify disables verification of the target server's TLS certificate (insecure)
+	TLSSkipVerify bool
+
+	// ProxyURL is the outbound HTTP, HTTPS, or SOCKS5 proxy (optional, defaults to
+	// the HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment variables)
+	ProxyURL string
+
+	// StripPathPrefix is removed from request paths before signing (optional)
+	StripPathPrefix string
+
+	// PathRewrite rewrites request paths with a regular expression before signing (optional)
+	PathRewrite PathRewrite
+
+	// MaxRequestBody caps request bodies in bytes (0 defaults to 64 MB, negative disables the limit)
+	MaxRequestBody int64
+
+	// MaxResponseBody caps non-streaming response bodies in bytes (0 defaults
+	// to 64 MB, negative disables the limit)
+	MaxResponseBody int64
+
+	// MaxSSEEventBytes caps a single SSE event in bytes (0 defaults to 10 MB,
+	// negative disables the limit)
+	MaxSSEEventBytes int64
+
+	// MaxSSETotalBytes caps the bytes read from one SSE stream before it is
+	// ended for reconnection (0 defaults to 10 MB, negative disables the limit)
+	MaxSSETotalBytes int64
+
+	// GzipRequests compresses request bodies with gzip before signing
+	GzipRequests bool
+
+	// GzipResponses requests gzip-encoded responses and decompresses them
+	GzipResponses bool
+
+	// BinaryContentTypes are the response media types passed through as raw
+	// bytes, without decompression; entries such as image/* match every subtype
+	// (defaults to application/octet-stream, image/*, and audio/*)
+	BinaryContentTypes []string
+
+	// TCPKeepAlive is the idle time before TCP keep-alive probes are sent
+	// (0 defaults to 30 seconds with SSE and the OS default otherwise, negative disables probes)
+	TCPKeepAlive time.Duration
+
+	// TCPProbeInterval is the time between unanswered TCP keep-alive probes (0 uses the OS default)
+	TCPProbeInterval time.Duration
+
+	// TCPProbeCount is the number of unanswered TCP keep-alive probes before a
+	// connection is dropped (0 uses the OS default)
+	TCPProbeCount int
+
+	// MaxIdleConns caps idle connections kept open across all hosts (0 uses 100)
+	MaxIdleConns int
+
+	// MaxIdleConnsPerHost caps idle connections kept open to each host (0 uses 10)
+	MaxIdleConnsPerHost int
+
+	// MaxConnsPerHost caps connections to each host, including those in use
+	// (0 means no limit)
+	MaxConnsPerHost int
+
+	// MaxConcurrentRequests caps the requests in flight to the target; further
+	// requests wait for a slot (0 means no limit)
+	MaxConcurrentRequests int
+
+	// ForceHTTP2 negotiates HTTP/2 over TLS with the target even when a custom
+	// TLS configuration is in use
+	ForceHTTP2 bool
+
+	// AllowH2C sends requests to http:// targets over cleartext HTTP/2 (h2c)
+	AllowH2C bool
+
+	// HTTP2PingInterval is how long an HTTP/2 connection to the target may go
+	// without receiving a frame before a PING is sent (0 means 30 seconds,
+	// negative disables PINGs)
+	HTTP2PingInterval time.Duration
+
+	// HTTP2PingTimeout is how long to wait for a PING to be answered before
+	// the connection is closed and re-dialed (0 means 15 seconds)
+	HTTP2PingTimeout time.Duration
+
+	// InjectRequestID adds a unique X-Request-ID header to every signed request
+	InjectRequestID bool
+
+	// XRayEnabled records an AWS X-Ray subsegment for every request sent to the
+	// target and forwards the X-Amzn-Trace-Id header
+	XRayEnabled bool
+
+	// DebugMode logs a dump of every signed request and response at DEBUG
+	// level, with credentials redacted (not available in production builds)
+	DebugMode bool
+
+	// EnableTimeline logs at DEBUG level when each phase of every request to
+	// the target happened, from queueing through signing, connecting, and
+	// reading the response
+	EnableTimeline bool
+
+	// DryRun signs the initialize request for the target and logs it without
+	// sending it, then exits; useful for checking credentials and signing
+	DryRun bool
+
+	// FallbackURLs are alternate target endpoints tried in order when the
+	// primary target fails with a network error or 5xx response (optional)
+	FallbackURLs []string
+
+	// FailoverTimeout caps the time spent on each fallback attempt (0 means no limit)
+	FailoverTimeout time.Duration
+
+	// ShadowTargetURL receives an asynchronous copy of every MCP request sent
+	// to the target, with its responses discarded (optional)
+	ShadowTargetURL string
+
+	// SSEMaxReconnects caps the attempts to reconnect a dropped SSE stream
+	// (0 leaves reconnection to the MCP client)
+	SSEMaxReconnects int
+
+	// SSERetryDelay is the delay before the first SSE reconnect attempt, doubling
+	// after each failure (0 defaults to one second)
+	SSERetryDelay time.Duration
+
+	// SSEMaxRetryDelay caps the delay between SSE reconnect attempts (0 defaults to 30 seconds)
+	SSEMaxRetryDelay time.Duration
+
+	// SSEHeartbeat is how long an SSE stream may go without data before it is
+	// reconnected (0 defaults to 60 seconds, negative disables the check)
+	SSEHeartbeat time.Duration
+
+	// RateLimitRPS limits signed requests per second (0 disables rate limiting)
+	RateLimitRPS float64
+
+	// RateLimitBurst is the maximum number of requests allowed in a burst
+	// (0 defaults to RateLimitRPS rounded up)
+	RateLimitBurst int
+
+	// ClientRateLimitRPS limits the requests per second of each MCP client
+	// (0 disables per-client rate limiting)
+	ClientRateLimitRPS float64
+
+	// ClientRateLimitBurst is the maximum number of requests a client may
+	// make in a burst (0 defaults to ClientRateLimitRPS rounded up)
+	ClientRateLimitBurst int
+
+	// ClientLimiterIdle is how long the rate limiter of an idle client is kept
+	// (0 means 10 minutes)
+	ClientLimiterIdle time.Duration
+
+	// SkipHealthCheck disables the unsigned connectivity check performed at startup
+	SkipHealthCheck bool
+
+	// SkipIdentityValidation disables the STS GetCallerIdentity check performed at startup
+	SkipIdentityValidation bool
+
+	// HealthCheckPath is appended to the target URL for the startup connectivity check (optional)
+	HealthCheckPath string
+
+	// ProbeAddr is the address, such as ":8081", that serves the /live and
+	// /ready health probe endpoints (optional, empty disables them)
+	ProbeAddr string
+
+	// MetricsAddr is the address, such as ":9090", that serves Prometheus
+	// metrics at /metrics (optional, empty disables it)
+	MetricsAddr string
+
+	// ListenAddr is the address, such as "127.0.0.1:8080", that accepts MCP
+	// clients over WebSocket instead of a single client over stdio (optional,
+	// empty uses stdio). The listener has no authentication.
+	ListenAddr string
+
+	// ToolNamePrefix is prepended to the names of the forwarded tools, so a
+	// client connected to several proxies can tell them apart (optional)
+	ToolNamePrefix string
+
+	// PromptNamePrefix is prepended to the names of the forwarded prompts (optional)
+	PromptNamePrefix string
+
+	// RefreshInterval is how often the target server's capabilities are
+	// re-listed and the forwarded set updated (0 disables refresh)
+	RefreshInterval time.Duration
+
+	// PingInterval is how often the target session is pinged to keep it alive
+	// through network intermediaries (0 disables pings)
+	PingInterval time.Duration
+
+	// PingTimeout is how long to wait for a ping response before the target
+	// session is reconnected (defaults to 10s)
+	PingTimeout time.Duration
+
+	// EnableSampling forwards sampling requests from the target server to the
+	// connected MCP client (only reliable with a single connected client)
+	EnableSampling bool
+
+	// EnableRoots relays the roots announced by the MCP client to the target server
+	EnableRoots bool
+
+	// EnableLogForwarding relays the target server's log notifications to the
+	// MCP client and its logging/setLevel requests to the target server
+	EnableLogForwarding bool
+
+	// IsolatedSessions connects each MCP client to the target server with its
+	// own session, at the cost of one target connection per client
+	IsolatedSessions bool
+
+	// ValidateToolArguments rejects tool calls whose arguments do not match
+	// the tool's input schema before they are forwarded to the target
+	ValidateToolArguments bool
+
+	// FeatureFlags turns experimental features on or off by flag name (see
+	// ListFeatureFlags); flags that are not set keep their default
+	FeatureFlags map[string]bool
+
+	// EnableDeduplication shares one target call between concurrent identical
+	// calls to tools annotated as read-only or idempotent
+	EnableDeduplication bool
+
+	// ResponseCacheMaxEntries caps the number of cached target responses
+	// (0 disables the response cache)
+	ResponseCacheMaxEntries int
+
+	// ResponseCacheTTL is how long a cached response is served (0 means
+	// responses are only evicted to make room)
+	ResponseCacheTTL time.Duration
+
+	// ResponseCacheMethods lists the MCP methods whose responses are cached
+	ResponseCacheMethods []string
+
+	// ToolCacheMaxEntries caps the number of cached tool call results
+	// (0 disables the tool result cache)
+	ToolCacheMaxEntries int
+
+	// ToolCacheTTL is how long a cached tool call result is served (0 means
+	// results are only evicted to make room)
+	ToolCacheTTL time.Duration
+
+	// ToolCacheToolTTLs overrides ToolCacheTTL by tool name
+	ToolCacheToolTTLs map[string]time.Duration
+
+	// ToolCacheExclude lists tools whose results are never cached
+	ToolCacheExclude []string
+
+	// ToolCacheWarmup lists tool calls made at startup to fill the tool
+	// result cache
+	ToolCacheWarmup []WarmupCall
+
+	// MethodTimeouts bounds target calls by MCP method name, such as "tools/call";
+	// methods without an entry use Timeout
+	MethodTimeouts map[string]time.Duration
+
+	// DrainTimeout is how long in-flight requests may run after a shutdown
+	// signal before they are cancelled (0 cancels them immediately)
+	DrainTimeout time.Duration
+
+	// AuditLogPath is a file that receives an NDJSON record of every signed request (optional)
+	AuditLogPath string
+
+	// StatsDAddr is the host:port of a StatsD server that receives request
+	// metrics over UDP (optional)
+	StatsDAddr string
+
+	// StatsDPrefix prefixes every StatsD metric name (defaults to "sigv4proxy")
+	StatsDPrefix string
+
+	// StatsDSampleRate is the fraction of StatsD metrics sent, from 0 to 1 (defaults to 1)
+	StatsDSampleRate float64
+
+	// ConfigFile is the path to a JSON configuration file (optional). When set,
+	// the file is re-read on SIGHUP and reloadable settings are applied live.
+	ConfigFile string
+
+	// ConfigSource names a remote configuration source applied after the
+	// configuration file (optional): AWS Systems Manager Parameter Store as
+	// ssm://prefix or AWS Secrets Manager as asm://secret-id
+	ConfigSource string
+
+	// ConfigSourcePollInterval is how often an asm:// config source is checked
+	// for a rotated secret, which is then reloaded (0 means 5 minutes,
+	// negative disables polling)
+	ConfigSourcePollInterval time.Duration
+}
+
+// PathRewrite replaces matches of the regular expression Pattern in request
+// paths with Replacement, which may reference capture groups as $1
+type PathRewrite struct {
+	Pattern     string
+	Replacement string
+}
+
+// WarmupCall is a tool call made at startup to fill the tool result cache
+type WarmupCall struct {
+	// Tool is the name of the tool to call
+	Tool string `json:"tool"`
+
+	// Arguments are the JSON arguments of the call (optional)
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// parseWarmupCalls parses a JSON array of warmup calls, such as
+// [{"tool": "get_weather", "arguments": {"city": "Paris"}}]
+func parseWarmupCalls(value string) ([]WarmupCall, error) {
+	var calls []WarmupCall
+	if err := json.Unmarshal([]byte(value), &calls); err != nil {
+		return nil, fmt.Errorf("tool cache warmup must be a JSON array of {\"tool\", \"arguments\"} objects: %w", err)
+	}
+	return calls, nil
 }
 
 // LoadFromEnv loads configuration from environment variables only.
 // This is useful for testing and for environments where flags aren't used.
 func LoadFromEnv() (*Config, error) {
-	cfg := &Config{
-		TargetURL:        os.Getenv("MCP_TARGET_URL"),
-		Region:           os.Getenv("AWS_REGION"),
-		ServiceName:      os.Getenv("AWS_SERVICE_NAME"),
-		SignatureVersion: os.Getenv("AWS_SIG_VERSION"),
-		Profile:          os.Getenv("AWS_PROFILE"),
-		EnableSSE:        getBoolEnv("MCP_ENABLE_SSE"),
-		Timeout:          getDurationEnv("MCP_TIMEOUT"),
-		Headers:          os.Getenv("MCP_HEADERS"),
-	}
+	return LoadFromEnvWithPrefix("")
+}
 
-	// Set default signature version if not specified
-	if cfg.SignatureVersion == "" {
-		cfg.SignatureVersion = "v4"
-	}
+// LoadFromEnvWithPrefix loads configuration from environment variables whose
+// names start with prefix, so several proxies can be configured in the same
+// environment. Each variable is read as prefix_NAME, where NAME drops any MCP_
+// prefix, and falls back to the unprefixed variable: with the prefix STAGING,
+// STAGING_TARGET_URL is read before MCP_TARGET_URL and STAGING_AWS_REGION
+// before AWS_REGION. An empty prefix reads the unprefixed variables only.
+func LoadFromEnvWithPrefix(prefix string) (*Config, error) {
+	return NewBuilder().WithEnvPrefix(prefix).Build()
+}
 
-	// Set default profile if not specified
-	if cfg.Profile == "" {
-		cfg.Profile = "default"
+// config returns the configuration set by environment variables
+func (e environment) config() *Config {
+	cfg := &Config{
+		TargetURL:                   e.get("MCP_TARGET_URL"),
+		Region:                      e.get("AWS_REGION"),
+		ServiceName:                 e.get("AWS_SERVICE_NAME"),
+		SignatureVersion:            e.get("AWS_SIG_VERSION"),
+		Profile:                     e.get("AWS_PROFILE"),
+		RoleARN:                     e.get("MCP_ROLE_ARN"),
+		ExternalID:                  e.get("MCP_EXTERNAL_ID"),
+		RoleSessionName:             e.get("MCP_ROLE_SESSION_NAME"),
+		RoleSessionDuration:         e.getDuration("MCP_ROLE_SESSION_DURATION"),
+		RoleSessionTags:             e.getMap("MCP_ROLE_SESSION_TAGS"),
+		CredentialProcess:           e.get("MCP_CREDENTIAL_PROCESS"),
+		CredentialSources:           e.getList("MCP_CREDENTIAL_SOURCES"),
+		VaultAddr:                   e.get("MCP_VAULT_ADDR"),
+		VaultPath:                   e.get("MCP_VAULT_PATH"),
+		CredentialWarnCheckInterval: e.getDuration("MCP_CREDENTIAL_WARN_CHECK_INTERVAL"),
+		CredentialWarnThreshold:     e.getDuration("MCP_CREDENTIAL_WARN_THRESHOLD"),
+		SSOStartURL:                 e.get("MCP_SSO_START_URL"),
+		SSOAccountID:                e.get("MCP_SSO_ACCOUNT_ID"),
+		SSORoleName:                 e.get("MCP_SSO_ROLE_NAME"),
+		SSORegion:                   e.get("MCP_SSO_REGION"),
+		WebIdentityTokenFile:        e.get("MCP_WEB_IDENTITY_TOKEN_FILE"),
+		WebIdentityRoleARN:          e.get("MCP_WEB_IDENTITY_ROLE_ARN"),
+		EnableSSE:                   e.getBool("MCP_ENABLE_SSE"),
+		Timeout:                     e.getDuration("MCP_TIMEOUT"),
+		DialTimeout:                 e.getDuration("MCP_DIAL_TIMEOUT"),
+		TLSHandshakeTimeout:         e.getDuration("MCP_TLS_HANDSHAKE_TIMEOUT"),
+		ResponseHeaderTimeout:       e.getDuration("MCP_RESPONSE_HEADER_TIMEOUT"),
+		IdleConnectionTimeout:       e.getDuration("MCP_IDLE_CONNECTION_TIMEOUT"),
+		DNSCacheTTL:                 e.getDuration("MCP_DNS_CACHE_TTL"),
+		Headers:                     e.get("MCP_HEADERS"),
+		ExtraSignedHeaders:          e.getList("MCP_EXTRA_SIGNED_HEADERS"),
+		ForwardResponseHeaders:      e.getList("MCP_FORWARD_RESPONSE_HEADERS"),
+		SigningHost:                 e.get("MCP_SIGNING_HOST"),
+		RefreshOn401:                e.getBool("MCP_REFRESH_ON_401"),
+		TLSCAFile:                   e.get("MCP_TLS_CA_FILE"),
+		ClientCertFile:              e.get("MCP_TLS_CERT_FILE"),
+		ClientKeyFile:               e.get("MCP_TLS_KEY_FILE"),
+		TLSSkipVerify:               e.getBool("MCP_TLS_SKIP_VERIFY"),
+		ProxyURL:                    e.get("MCP_PROXY_URL"),
+		StripPathPrefix:             e.get("MCP_STRIP_PATH_PREFIX"),
+		PathRewrite: PathRewrite{
+			Pattern:     e.get("MCP_PATH_REWRITE_PATTERN"),
+			Replacement: e.get("MCP_PATH_REWRITE_REPLACEMENT"),
+		},
+		MaxRequestBody:          e.getInt64("MCP_MAX_REQUEST_BODY_BYTES"),
+		MaxResponseBody:         e.getInt64("MCP_MAX_RESPONSE_BODY_BYTES"),
+		MaxSSEEventBytes:        e.getInt64("MCP_MAX_SSE_EVENT_BYTES"),
+		MaxSSETotalBytes:        e.getInt64("MCP_MAX_SSE_TOTAL_BYTES"),
+		GzipRequests:            e.getBool("MCP_GZIP_REQUESTS"),
+		GzipResponses:           e.getBool("MCP_GZIP_RESPONSES"),
+		BinaryContentTypes:      e.getList("MCP_BINARY_CONTENT_TYPES"),
+		TCPKeepAlive:            e.getDuration("MCP_TCP_KEEPALIVE"),
+		TCPProbeInterval:        e.getDuration("MCP_TCP_KEEPALIVE_INTERVAL"),
+		TCPProbeCount:           e.getInt("MCP_TCP_KEEPALIVE_COUNT"),
+		MaxIdleConns:            e.getInt("MCP_MAX_IDLE_CONNS"),
+		MaxIdleConnsPerHost:     e.getInt("MCP_MAX_IDLE_CONNS_PER_HOST"),
+		MaxConnsPerHost:         e.getInt("MCP_MAX_CONNS_PER_HOST"),
+		MaxConcurrentRequests:   e.getInt("MCP_MAX_CONCURRENT_REQUESTS"),
+		ForceHTTP2:              e.getBool("MCP_FORCE_HTTP2"),
+		AllowH2C:                e.getBool("MCP_ALLOW_H2C"),
+		HTTP2PingInterval:       e.getDuration("MCP_HTTP2_PING_INTERVAL"),
+		HTTP2PingTimeout:        e.getDuration("MCP_HTTP2_PING_TIMEOUT"),
+		InjectRequestID:         e.getBool("MCP_INJECT_REQUEST_ID"),
+		XRayEnabled:             e.getBool("MCP_XRAY"),
+		DebugMode:               e.getBool("MCP_DEBUG"),
+		EnableTimeline:          e.getBool("MCP_ENABLE_TIMELINE"),
+		DryRun:                  e.getBool("MCP_DRY_RUN"),
+		ConfigFile:              e.get("MCP_CONFIG_FILE"),
+		ConfigSource:            e.get("MCP_CONFIG_SOURCE"),
+		FallbackURLs:            e.getList("MCP_FALLBACK_URLS"),
+		ShadowTargetURL:         e.get("MCP_SHADOW_TARGET_URL"),
+		FailoverTimeout:         e.getDuration("MCP_FAILOVER_TIMEOUT"),
+		SSEMaxReconnects:        e.getInt("MCP_SSE_MAX_RECONNECTS"),
+		SSERetryDelay:           e.getDuration("MCP_SSE_RECONNECT_DELAY"),
+		SSEMaxRetryDelay:        e.getDuration("MCP_SSE_MAX_RECONNECT_DELAY"),
+		SSEHeartbeat:            e.getDuration("MCP_SSE_HEARTBEAT_TIMEOUT"),
+		RateLimitRPS:            e.getFloat("MCP_RATE_LIMIT_RPS"),
+		RateLimitBurst:          e.getInt("MCP_RATE_LIMIT_BURST"),
+		ClientRateLimitRPS:      e.getFloat("MCP_CLIENT_RATE_LIMIT_RPS"),
+		ClientRateLimitBurst:    e.getInt("MCP_CLIENT_RATE_LIMIT_BURST"),
+		ClientLimiterIdle:       e.getDuration("MCP_CLIENT_LIMITER_IDLE"),
+		SkipHealthCheck:         e.getBool("MCP_SKIP_HEALTH_CHECK"),
+		SkipIdentityValidation:  e.getBool("MCP_SKIP_IDENTITY_CHECK"),
+		HealthCheckPath:         e.get("MCP_HEALTH_CHECK_PATH"),
+		ProbeAddr:               e.get("MCP_PROBE_ADDR"),
+		MetricsAddr:             e.get("MCP_METRICS_ADDR"),
+		ListenAddr:              e.get("MCP_LISTEN_ADDR"),
+		ToolNamePrefix:          e.get("MCP_TOOL_NAME_PREFIX"),
+		PromptNamePrefix:        e.get("MCP_PROMPT_NAME_PREFIX"),
+		RefreshInterval:         e.getDuration("MCP_REFRESH_INTERVAL"),
+		PingInterval:            e.getDuration("MCP_PING_INTERVAL"),
+		PingTimeout:             e.getDuration("MCP_PING_TIMEOUT"),
+		EnableSampling:          e.getBool("MCP_ENABLE_SAMPLING"),
+		EnableRoots:             e.getBool("MCP_ENABLE_ROOTS"),
+		EnableLogForwarding:     e.getBool("MCP_ENABLE_LOG_FORWARDING"),
+		IsolatedSessions:        e.getBool("MCP_ISOLATED_SESSIONS"),
+		ValidateToolArguments:   e.getBool("MCP_VALIDATE_TOOL_ARGUMENTS"),
+		FeatureFlags:            e.getFeatureFlags(),
+		EnableDeduplication:     e.getBool("MCP_ENABLE_DEDUPLICATION"),
+		ResponseCacheMaxEntries: e.getInt("MCP_RESPONSE_CACHE_MAX_ENTRIES"),
+		ResponseCacheTTL:        e.getDuration("MCP_RESPONSE_CACHE_TTL"),
+		ResponseCacheMethods:    e.getList("MCP_RESPONSE_CACHE_METHODS"),
+		ToolCacheMaxEntries:     e.getInt("MCP_TOOL_CACHE_MAX_ENTRIES"),
+		ToolCacheTTL:            e.getDuration("MCP_TOOL_CACHE_TTL"),
+		ToolCacheToolTTLs:       e.getDurationMap("MCP_TOOL_CACHE_TOOL_TTLS"),
+		ToolCacheExclude:        e.getList("MCP_TOOL_CACHE_EXCLUDE"),
+		ToolCacheWarmup:         e.getWarmupCalls("MCP_TOOL_CACHE_WARMUP"),
+		DrainTimeout:            e.getDuration("MCP_DRAIN_TIMEOUT"),
+		AuditLogPath:            e.get("MCP_AUDIT_LOG"),
+		StatsDAddr:              e.get("MCP_STATSD_ADDR"),
+		StatsDPrefix:            e.get("MCP_STATSD_PREFIX"),
+		StatsDSampleRate:        e.getFloat("MCP_STATSD_SAMPLE_RATE"),
 	}
+	cfg.SetMethodTimeout(e.getDuration("MCP_TOOL_CALL_TIMEOUT"), "tools/call")
+	cfg.SetMethodTimeout(e.getDuration("MCP_LIST_TIMEOUT"), ListMethods...)
+	cfg.ConfigSourcePollInterval = e.getDuration("MCP_CONFIG_SOURCE_POLL_INTERVAL")
+	return cfg
+}
 
-	// Validate configuration
-	if err := cfg.Validate(); err != nil {
-		return cfg, err
-	}
+// environment reads configuration environment variables, preferring the
+// prefixed name of each variable when a prefix is set
+type environment struct {
+	prefix string
+}
 
-	return cfg, nil
+// get returns the value of the environment variable key, or of its prefixed
+// name when that is set
+func (e environment) get(key string) string {
+	if e.prefix != "" {
+		if value, ok := os.LookupEnv(e.prefix + "_" + strings.TrimPrefix(key, "MCP_")); ok {
+			return value
+		}
+	}
+	return os.Getenv(key)
 }
 
-func getBoolEnv(key string) bool {
-	value := os.Getenv(key)
+func (e environment) getBool(key string) bool {
+	value := e.get(key)
 	boolValue, err := strconv.ParseBool(value)
 	if err != nil {
 		return false
@@ -79,8 +618,8 @@ func getBoolEnv(key string) bool {
 	return boolValue
 }
 
-func getDurationEnv(key string) time.Duration {
-	value := os.Getenv(key)
+func (e environment) getDuration(key string) time.Duration {
+	value := e.get(key)
 	durationValue, err := time.ParseDuration(value)
 	if err != nil {
 		return 0
@@ -88,99 +627,1348 @@ func getDurationEnv(key string) time.Duration {
 	return durationValue
 }
 
-// Load loads configuration from environment variables and command-line flags.
-// Command-line flags take precedence over environment variables.
-func Load(logger *log.Logger) (*Config, error) {
-	// First load from environment
-	cfg, err := LoadFromEnv()
+func (e environment) getFloat(key string) float64 {
+	value := e.get(key)
+	floatValue, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return floatValue
+}
+
+func (e environment) getInt(key string) int {
+	value := e.get(key)
+	intValue, err := strconv.Atoi(value)
+	if err != nil {
+		return 0
+	}
+	return intValue
+}
+
+func (e environment) getInt64(key string) int64 {
+	value := e.get(key)
+	intValue, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return intValue
+}
+
+// getMap parses a comma delimited list of key=value pairs. Entries without
+// an "=" separator are ignored.
+func (e environment) getMap(key string) map[string]string {
+	var values map[string]string
+	for _, item := range e.getList(key) {
+		k, v, ok := strings.Cut(item, "=")
+		if !ok {
+			continue
+		}
+		if values == nil {
+			values = make(map[string]string)
+		}
+		values[k] = v
+	}
+	return values
+}
+
+// getDurationMap parses a comma delimited list of key=duration pairs. Entries
+// without an "=" separator or with an invalid duration are ignored.
+func (e environment) getDurationMap(key string) map[string]time.Duration {
+	var values map[string]time.Duration
+	for k, v := range e.getMap(key) {
+		duration, err := time.ParseDuration(v)
+		if err != nil {
+			continue
+		}
+		if values == nil {
+			values = make(map[string]time.Duration)
+		}
+		values[k] = duration
+	}
+	return values
+}
+
+// getWarmupCalls parses a JSON array of warmup calls. An invalid value is
+// logged and ignored.
+func (e environment) getWarmupCalls(key string) []WarmupCall {
+	value := e.get(key)
+	if value == "" {
+		return nil
+	}
+	calls, err := parseWarmupCalls(value)
+	if err != nil {
+		slog.Warn("ignoring invalid environment variable", "name", key, "error", err)
+		return nil
+	}
+	return calls
+}
+
+func (e environment) getList(key string) []string {
+	return splitList(e.get(key))
+}
+
+// splitList splits a comma delimited list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Load loads configuration from environment variables, an optional configuration
+// file, an optional config source, and command-line flags. Command-line flags
+// take precedence over the config source, which takes precedence over the
+// configuration file, which takes precedence over environment variables.
+// Environment variables are read with the --env-prefix prefix, as in
+// LoadFromEnvWithPrefix.
+func Load(logger *slog.Logger) (*Config, error) {
+	builder := CommandLineBuilder()
+	cfg, err := builder.Build()
 	if err != nil {
-		logger.Printf("load environment variables failed")
+		return nil, err
 	}
+	for _, field := range slices.Sorted(maps.Keys(builder.fieldSources)) {
+		logger.Debug("configuration field set", "field", field, "source", builder.fieldSources[field])
+	}
+	return cfg, nil
+}
+
+// commandLine holds the parsed command-line flags
+type commandLine struct {
+	// envPrefix is the --env-prefix flag
+	envPrefix string
+
+	// configFile is the --config-file flag
+	configFile string
+
+	// configSource is the --config-source flag
+	configSource string
 
-	// Define and parse command-line flags
+	// apply overrides the fields of cfg whose flags are set
+	apply func(cfg *Config)
+}
+
+// parseFlags defines the configuration flags on the default flag set and
+// parses the command line
+func parseFlags() *commandLine {
+	envPrefix := flag.String("env-prefix", "", "read environment variables as PREFIX_NAME before the unprefixed names")
 	targetURL := flag.String("target-url", "", "Target MCP server endpoint URL")
 	region := flag.String("region", "", "AWS region for signing")
 	serviceName := flag.String("service-name", "", "AWS service name for signing (e.g., execute-api)")
 	sigVersion := flag.String("sig-version", "", "Signature version (v4 or v4a)")
 	profile := flag.String("profile", "", "AWS credential profile name")
+	roleARN := flag.String("role-arn", "", "IAM role to assume with STS AssumeRole before signing")
+	externalID := flag.String("external-id", "", "external ID passed to AssumeRole")
+	roleSessionName := flag.String("role-session-name", "", "session name for the assumed role (default mcp-sigv4-proxy)")
+	roleSessionDuration := flag.Duration("role-session-duration", 0, "lifetime of assumed-role credentials (default 1h, maximum 12h)")
+	roleSessionTags := make(map[string]string)
+	flag.Func("role-session-tag", "session tag for the assumed role as Key=Value (repeatable)", func(value string) error {
+		key, tagValue, ok := strings.Cut(value, "=")
+		if !ok || key == "" {
+			return errors.New("session tag must be Key=Value")
+		}
+		roleSessionTags[key] = tagValue
+		return nil
+	})
+	credentialProcess := flag.String("credential-process", "", "command that prints AWS credential process JSON")
+	credentialSources := flag.String("credential-sources", "", "comma delimited list of credential sources to try in order (env, profile, process, sso, webidentity, ecs, ec2)")
+	vaultAddr := flag.String("vault-addr", "", "HashiCorp Vault server URL (default VAULT_ADDR)")
+	vaultPath := flag.String("vault-path", "", "Vault AWS secrets engine credentials path, such as aws/creds/my-role")
+	credentialWarnCheckInterval := flag.Duration("credential-warn-check-interval", 0, "how often credential expiry is checked (default 1m, negative disables)")
+	credentialWarnThreshold := flag.Duration("credential-warn-threshold", 0, "warn when credentials expire within this duration (default 5m)")
+	ssoStartURL := flag.String("sso-start-url", "", "AWS SSO portal URL")
+	ssoAccountID := flag.String("sso-account-id", "", "AWS account of the SSO role")
+	ssoRoleName := flag.String("sso-role-name", "", "SSO permission set role name")
+	ssoRegion := flag.String("sso-region", "", "region hosting the AWS SSO portal")
+	webIdentityTokenFile := flag.String("web-identity-token-file", "", "file holding an OIDC token exchanged for the web identity role's credentials")
+	webIdentityRoleARN := flag.String("web-identity-role-arn", "", "IAM role assumed with the web identity token")
 	enableSSE := flag.Bool("sse", false, "enable server-side events")
 	timeout := flag.Duration("timeout", 0, "mcp client timeout (default no timeout)")
+	dialTimeout := flag.Duration("dial-timeout", 0, "timeout for connecting to the target (default 30s)")
+	tlsHandshakeTimeout := flag.Duration("tls-handshake-timeout", 0, "timeout for the TLS handshake with the target (default 10s)")
+	responseHeaderTimeout := flag.Duration("response-header-timeout", 0, "timeout waiting for the target's response headers (default no timeout)")
+	idleConnectionTimeout := flag.Duration("idle-connection-timeout", 0, "how long idle connections to the target are kept open (default 90s)")
+	dnsCacheTTL := flag.Duration("dns-cache-ttl", 0, "how long resolved target addresses are cached (default no caching)")
 	headers := flag.String("headers", "", "comma delimited list of headers (key=value)")
+	extraSignedHeaders := flag.String("extra-signed-headers", "", "comma delimited list of headers that must be present and signed")
+	forwardResponseHeaders := flag.String("forward-response-headers", "", "comma delimited list of target response headers added to result _meta (default X-Amzn-Requestid)")
+	refreshOn401 := flag.Bool("refresh-on-401", false, "refresh credentials and retry once when the target responds 401 Unauthorized")
+	signingHost := flag.String("signing-host", "", "host to sign in place of the target URL's host, such as the public hostname behind a VPC endpoint")
+	tlsCAFile := flag.String("tls-ca-file", "", "PEM file of additional CA certificates to trust")
+	tlsCertFile := flag.String("tls-cert-file", "", "PEM client certificate for mutual TLS")
+	tlsKeyFile := flag.String("tls-key-file", "", "PEM private key for the client certificate")
+	proxyURL := flag.String("proxy-url", "", "outbound proxy URL (http, https, or socks5)")
+	stripPathPrefix := flag.String("strip-path-prefix", "", "prefix removed from request paths before signing")
+	pathRewritePattern := flag.String("path-rewrite-pattern", "", "regular expression matched against request paths before signing")
+	pathRewriteReplacement := flag.String("path-rewrite-replacement", "", "replacement for path rewrite matches ($1 refers to capture groups)")
+	maxRequestBody := flag.Int64("max-request-body-bytes", 0, "maximum request body size in bytes (default 64 MB, negative disables)")
+	maxResponseBody := flag.Int64("max-response-body-bytes", 0, "maximum response body size in bytes (default 64 MB, negative disables)")
+	maxSSEEventBytes := flag.Int64("max-sse-event-bytes", 0, "maximum SSE event size in bytes (default 10 MB, negative disables)")
+	maxSSETotalBytes := flag.Int64("max-sse-total-bytes", 0, "bytes read from an SSE stream before reconnecting (default 10 MB, negative disables)")
+	gzipRequests := flag.Bool("gzip-requests", false, "gzip request bodies before signing")
+	gzipResponses := flag.Bool("gzip-responses", false, "request gzip-encoded responses and decompress them")
+	binaryContentTypes := flag.String("binary-content-types", "", "comma delimited response media types passed through as raw bytes (default application/octet-stream,image/*,audio/*)")
+	tcpKeepAlive := flag.Duration("tcp-keepalive", 0, "idle time before TCP keep-alive probes are sent (default 30s with SSE, otherwise OS default; negative disables)")
+	tcpProbeInterval := flag.Duration("tcp-keepalive-interval", 0, "time between TCP keep-alive probes (default OS default)")
+	tcpProbeCount := flag.Int("tcp-keepalive-count", 0, "unanswered TCP keep-alive probes before the connection is dropped (default OS default)")
+	maxIdleConns := flag.Int("max-idle-conns", 0, "maximum idle connections kept open across all hosts (default 100)")
+	maxIdleConnsPerHost := flag.Int("max-idle-conns-per-host", 0, "maximum idle connections kept open to each host (default 10)")
+	maxConnsPerHost := flag.Int("max-conns-per-host", 0, "maximum connections to each host (default no limit)")
+	maxConcurrentRequests := flag.Int("max-concurrent-requests", 0, "maximum requests in flight to the target; others wait for a slot (default no limit)")
+	forceHTTP2 := flag.Bool("http2", false, "negotiate HTTP/2 over TLS with the target even with a custom TLS configuration")
+	allowH2C := flag.Bool("h2c", false, "send requests to http:// targets over cleartext HTTP/2")
+	http2PingInterval := flag.Duration("http2-ping-interval", 0, "idle time before an HTTP/2 connection is checked with a PING (default 30s, negative disables)")
+	http2PingTimeout := flag.Duration("http2-ping-timeout", 0, "time to wait for an HTTP/2 PING response before the connection is closed (default 15s)")
+	injectRequestID := flag.Bool("request-id", false, "add a unique X-Request-ID header to every signed request")
+	xrayEnabled := flag.Bool("xray", false, "record an AWS X-Ray subsegment for every request sent to the target")
+	dryRun := flag.Bool("dry-run", false, "sign the initialize request and log it without sending it, then exit")
+	debugMode := flag.Bool("debug", false, "log a dump of every signed request and response (implies --log-level debug)")
+	enableTimeline := flag.Bool("enable-timeline", false, "log a timing breakdown of every request to the target at debug level")
+	fallbackURLs := flag.String("fallback-urls", "", "comma delimited list of fallback target URLs")
+	failoverTimeout := flag.Duration("failover-timeout", 0, "timeout for each fallback attempt (default no timeout)")
+	shadowTargetURL := flag.String("shadow-target-url", "", "URL of a secondary target that receives a copy of every request, with responses discarded")
+	sseMaxReconnects := flag.Int("sse-max-reconnects", 0, "maximum attempts to reconnect a dropped SSE stream (default no reconnection by the proxy)")
+	sseRetryDelay := flag.Duration("sse-reconnect-delay", 0, "initial delay between SSE reconnect attempts (default 1s)")
+	sseMaxRetryDelay := flag.Duration("sse-max-reconnect-delay", 0, "maximum delay between SSE reconnect attempts (default 30s)")
+	sseHeartbeat := flag.Duration("sse-heartbeat-timeout", 0, "time without SSE data before the stream is reconnected (default 60s, negative disables)")
+	rateLimitRPS := flag.Float64("rate-limit-rps", 0, "maximum signed requests per second (default no limit)")
+	rateLimitBurst := flag.Int("rate-limit-burst", 0, "maximum burst of signed requests (default rate limit rounded up)")
+	clientRateLimitRPS := flag.Float64("client-rate-limit-rps", 0, "maximum requests per second from each MCP client (default no limit)")
+	clientRateLimitBurst := flag.Int("client-rate-limit-burst", 0, "maximum burst of requests from each MCP client (default client rate limit rounded up)")
+	clientLimiterIdle := flag.Duration("client-limiter-idle", 0, "how long the rate limiter of an idle client is kept (default 10m)")
+	skipHealthCheck := flag.Bool("skip-health-check", false, "skip the unsigned connectivity check at startup")
+	skipIdentityCheck := flag.Bool("skip-identity-check", false, "skip the STS GetCallerIdentity check at startup")
+	healthCheckPath := flag.String("health-check-path", "", "path appended to the target URL for the startup connectivity check")
+	probeAddr := flag.String("probe-addr", "", "address serving the /live and /ready health probe endpoints (default disabled)")
+	metricsAddr := flag.String("metrics-addr", "", "address serving Prometheus metrics at /metrics (default disabled)")
+	listenAddr := flag.String("listen-addr", "", "address accepting MCP clients over WebSocket, such as 127.0.0.1:8080 (default stdio)")
+	toolNamePrefix := flag.String("tool-name-prefix", "", "prefix prepended to the names of the forwarded tools")
+	promptNamePrefix := flag.String("prompt-name-prefix", "", "prefix prepended to the names of the forwarded prompts")
+	refreshInterval := flag.Duration("refresh-interval", 0, "interval for refreshing the target server's capabilities (default no refresh)")
+	pingInterval := flag.Duration("ping-interval", 0, "interval for pinging the target server to keep the session alive (default no pings)")
+	pingTimeout := flag.Duration("ping-timeout", 0, "time to wait for a ping response before reconnecting to the target server (default 10s)")
+	enableSampling := flag.Bool("sampling", false, "forward sampling requests from the target server to the MCP client")
+	enableRoots := flag.Bool("roots", false, "forward the MCP client's roots to the target server")
+	isolatedSessions := flag.Bool("isolated-sessions", false, "connect each MCP client to the target server with its own session")
+	enableLogForwarding := flag.Bool("log-forwarding", false, "forward log notifications from the target server to the MCP client")
+	validateToolArguments := flag.Bool("validate-tool-arguments", false, "reject tool calls whose arguments do not match the tool's input schema")
+	enableDeduplication := flag.Bool("deduplicate", false, "share one target call between concurrent identical calls to read-only or idempotent tools")
+	responseCacheMaxEntries := flag.Int("response-cache-max-entries", 0, "maximum number of cached target responses (default no caching)")
+	responseCacheTTL := flag.Duration("response-cache-ttl", 0, "how long a cached response is served (default until evicted)")
+	responseCacheMethods := flag.String("response-cache-methods", "", "comma delimited list of MCP methods whose responses are cached")
+	toolCacheMaxEntries := flag.Int("tool-cache-max-entries", 0, "maximum number of cached tool call results (default no caching)")
+	toolCacheTTL := flag.Duration("tool-cache-ttl", 0, "how long a cached tool call result is served (default until evicted)")
+	toolCacheToolTTLs := make(map[string]time.Duration)
+	flag.Func("tool-cache-tool-ttl", "cache TTL for one tool as Name=Duration (repeatable)", func(value string) error {
+		name, ttl, ok := strings.Cut(value, "=")
+		if !ok || name == "" {
+			return errors.New("tool cache TTL must be Name=Duration")
+		}
+		duration, err := time.ParseDuration(ttl)
+		if err != nil {
+			return fmt.Errorf("invalid tool cache TTL for %s: %w", name, err)
+		}
+		toolCacheToolTTLs[name] = duration
+		return nil
+	})
+	toolCacheExclude := flag.String("tool-cache-exclude", "", "comma delimited list of tools whose results are never cached")
+	var toolCacheWarmup []WarmupCall
+	flag.Func("tool-cache-warmup", `JSON array of tool calls made at startup to fill the tool result cache, such as [{"tool":"get_weather","arguments":{"city":"Paris"}}]`, func(value string) error {
+		calls, err := parseWarmupCalls(value)
+		if err != nil {
+			return err
+		}
+		toolCacheWarmup = calls
+		return nil
+	})
+	toolCallTimeout := flag.Duration("tool-call-timeout", 0, "timeout for tools/call requests (default the request timeout)")
+	listTimeout := flag.Duration("list-timeout", 0, "timeout for tools, resources, and prompts list requests (default the request timeout)")
+	drainTimeout := flag.Duration("drain-timeout", 0, "time in-flight requests may run after a shutdown signal (default cancel immediately)")
+	auditLogPath := flag.String("audit-log", "", "file that receives an NDJSON record of every signed request")
+	statsDAddr := flag.String("statsd-addr", "", "host:port of a StatsD server that receives request metrics")
+	statsDPrefix := flag.String("statsd-prefix", "", "prefix for StatsD metric names (default \"sigv4proxy\")")
+	statsDSampleRate := flag.Float64("statsd-sample-rate", 0, "fraction of StatsD metrics sent, from 0 to 1 (default 1)")
+	configFile := flag.String("config-file", "", "JSON configuration file (reloaded on SIGHUP)")
+	configSource := flag.String("config-source", "", "remote configuration source: ssm://prefix or asm://secret-id")
+	configSourcePollInterval := flag.Duration("config-source-poll-interval", 0, "how often an asm:// config source is checked for rotation (default 5m, negative disables)")
+	tlsSkipVerify := flag.Bool("tls-skip-verify", false, "skip target server certificate verification (insecure)")
 
 	flag.Parse()
 
-	// Override with command-line flags if provided
-	if *targetURL != "" {
-		cfg.TargetURL = *targetURL
+	return &commandLine{
+		envPrefix:    *envPrefix,
+		configFile:   *configFile,
+		configSource: *configSource,
+		apply: func(cfg *Config) {
+			if *targetURL != "" {
+				cfg.TargetURL = *targetURL
+			}
+			if *region != "" {
+				cfg.Region = *region
+			}
+			if *serviceName != "" {
+				cfg.ServiceName = *serviceName
+			}
+			if *sigVersion != "" {
+				cfg.SignatureVersion = *sigVersion
+			}
+			if *profile != "" {
+				cfg.Profile = *profile
+			}
+			if *roleARN != "" {
+				cfg.RoleARN = *roleARN
+			}
+			if *externalID != "" {
+				cfg.ExternalID = *externalID
+			}
+			if *roleSessionName != "" {
+				cfg.RoleSessionName = *roleSessionName
+			}
+			if *roleSessionDuration != 0 {
+				cfg.RoleSessionDuration = *roleSessionDuration
+			}
+			if len(roleSessionTags) > 0 {
+				cfg.RoleSessionTags = roleSessionTags
+			}
+			if *credentialProcess != "" {
+				cfg.CredentialProcess = *credentialProcess
+			}
+			if *credentialSources != "" {
+				cfg.CredentialSources = splitList(*credentialSources)
+			}
+			if *vaultAddr != "" {
+				cfg.VaultAddr = *vaultAddr
+			}
+			if *vaultPath != "" {
+				cfg.VaultPath = *vaultPath
+			}
+			if *credentialWarnCheckInterval != 0 {
+				cfg.CredentialWarnCheckInterval = *credentialWarnCheckInterval
+			}
+			if *credentialWarnThreshold != 0 {
+				cfg.CredentialWarnThreshold = *credentialWarnThreshold
+			}
+			if *ssoStartURL != "" {
+				cfg.SSOStartURL = *ssoStartURL
+			}
+			if *ssoAccountID != "" {
+				cfg.SSOAccountID = *ssoAccountID
+			}
+			if *ssoRoleName != "" {
+				cfg.SSORoleName = *ssoRoleName
+			}
+			if *ssoRegion != "" {
+				cfg.SSORegion = *ssoRegion
+			}
+			if *webIdentityTokenFile != "" {
+				cfg.WebIdentityTokenFile = *webIdentityTokenFile
+			}
+			if *webIdentityRoleARN != "" {
+				cfg.WebIdentityRoleARN = *webIdentityRoleARN
+			}
+			if *enableSSE {
+				cfg.EnableSSE = *enableSSE
+			}
+			if *timeout > 0 {
+				cfg.Timeout = *timeout
+			}
+			if *dialTimeout > 0 {
+				cfg.DialTimeout = *dialTimeout
+			}
+			if *tlsHandshakeTimeout > 0 {
+				cfg.TLSHandshakeTimeout = *tlsHandshakeTimeout
+			}
+			if *responseHeaderTimeout > 0 {
+				cfg.ResponseHeaderTimeout = *responseHeaderTimeout
+			}
+			if *idleConnectionTimeout > 0 {
+				cfg.IdleConnectionTimeout = *idleConnectionTimeout
+			}
+			if *dnsCacheTTL > 0 {
+				cfg.DNSCacheTTL = *dnsCacheTTL
+			}
+			if *headers != "" {
+				cfg.Headers = *headers
+			}
+			if *extraSignedHeaders != "" {
+				cfg.ExtraSignedHeaders = splitList(*extraSignedHeaders)
+			}
+			if *forwardResponseHeaders != "" {
+				cfg.ForwardResponseHeaders = splitList(*forwardResponseHeaders)
+			}
+			if *signingHost != "" {
+				cfg.SigningHost = *signingHost
+			}
+			if *refreshOn401 {
+				cfg.RefreshOn401 = *refreshOn401
+			}
+			if *tlsCAFile != "" {
+				cfg.TLSCAFile = *tlsCAFile
+			}
+			if *tlsCertFile != "" {
+				cfg.ClientCertFile = *tlsCertFile
+			}
+			if *tlsKeyFile != "" {
+				cfg.ClientKeyFile = *tlsKeyFile
+			}
+			if *tlsSkipVerify {
+				cfg.TLSSkipVerify = *tlsSkipVerify
+			}
+			if *proxyURL != "" {
+				cfg.ProxyURL = *proxyURL
+			}
+			if *stripPathPrefix != "" {
+				cfg.StripPathPrefix = *stripPathPrefix
+			}
+			if *pathRewritePattern != "" {
+				cfg.PathRewrite.Pattern = *pathRewritePattern
+			}
+			if *pathRewriteReplacement != "" {
+				cfg.PathRewrite.Replacement = *pathRewriteReplacement
+			}
+			if *maxRequestBody != 0 {
+				cfg.MaxRequestBody = *maxRequestBody
+			}
+			if *maxResponseBody != 0 {
+				cfg.MaxResponseBody = *maxResponseBody
+			}
+			if *maxSSEEventBytes != 0 {
+				cfg.MaxSSEEventBytes = *maxSSEEventBytes
+			}
+			if *maxSSETotalBytes != 0 {
+				cfg.MaxSSETotalBytes = *maxSSETotalBytes
+			}
+			if *gzipRequests {
+				cfg.GzipRequests = *gzipRequests
+			}
+			if *gzipResponses {
+				cfg.GzipResponses = *gzipResponses
+			}
+			if *binaryContentTypes != "" {
+				cfg.BinaryContentTypes = splitList(*binaryContentTypes)
+			}
+			if *tcpKeepAlive != 0 {
+				cfg.TCPKeepAlive = *tcpKeepAlive
+			}
+			if *tcpProbeInterval > 0 {
+				cfg.TCPProbeInterval = *tcpProbeInterval
+			}
+			if *tcpProbeCount > 0 {
+				cfg.TCPProbeCount = *tcpProbeCount
+			}
+			if *maxIdleConns > 0 {
+				cfg.MaxIdleConns = *maxIdleConns
+			}
+			if *maxIdleConnsPerHost > 0 {
+				cfg.MaxIdleConnsPerHost = *maxIdleConnsPerHost
+			}
+			if *maxConnsPerHost > 0 {
+				cfg.MaxConnsPerHost = *maxConnsPerHost
+			}
+			if *maxConcurrentRequests > 0 {
+				cfg.MaxConcurrentRequests = *maxConcurrentRequests
+			}
+			if *forceHTTP2 {
+				cfg.ForceHTTP2 = *forceHTTP2
+			}
+			if *allowH2C {
+				cfg.AllowH2C = *allowH2C
+			}
+			if *http2PingInterval != 0 {
+				cfg.HTTP2PingInterval = *http2PingInterval
+			}
+			if *http2PingTimeout > 0 {
+				cfg.HTTP2PingTimeout = *http2PingTimeout
+			}
+			if *injectRequestID {
+				cfg.InjectRequestID = *injectRequestID
+			}
+			if *xrayEnabled {
+				cfg.XRayEnabled = *xrayEnabled
+			}
+			if *debugMode {
+				cfg.DebugMode = *debugMode
+			}
+			if *enableTimeline {
+				cfg.EnableTimeline = *enableTimeline
+			}
+			if *dryRun {
+				cfg.DryRun = *dryRun
+			}
+			if *fallbackURLs != "" {
+				cfg.FallbackURLs = splitList(*fallbackURLs)
+			}
+			if *failoverTimeout > 0 {
+				cfg.FailoverTimeout = *failoverTimeout
+			}
+			if *shadowTargetURL != "" {
+				cfg.ShadowTargetURL = *shadowTargetURL
+			}
+			if *sseMaxReconnects > 0 {
+				cfg.SSEMaxReconnects = *sseMaxReconnects
+			}
+			if *sseRetryDelay > 0 {
+				cfg.SSERetryDelay = *sseRetryDelay
+			}
+			if *sseMaxRetryDelay > 0 {
+				cfg.SSEMaxRetryDelay = *sseMaxRetryDelay
+			}
+			if *sseHeartbeat != 0 {
+				cfg.SSEHeartbeat = *sseHeartbeat
+			}
+			if *rateLimitRPS > 0 {
+				cfg.RateLimitRPS = *rateLimitRPS
+			}
+			if *rateLimitBurst > 0 {
+				cfg.RateLimitBurst = *rateLimitBurst
+			}
+			if *clientRateLimitRPS > 0 {
+				cfg.ClientRateLimitRPS = *clientRateLimitRPS
+			}
+			if *clientRateLimitBurst > 0 {
+				cfg.ClientRateLimitBurst = *clientRateLimitBurst
+			}
+			if *clientLimiterIdle > 0 {
+				cfg.ClientLimiterIdle = *clientLimiterIdle
+			}
+			if *skipHealthCheck {
+				cfg.SkipHealthCheck = *skipHealthCheck
+			}
+			if *skipIdentityCheck {
+				cfg.SkipIdentityValidation = *skipIdentityCheck
+			}
+			if *healthCheckPath != "" {
+				cfg.HealthCheckPath = *healthCheckPath
+			}
+			if *probeAddr != "" {
+				cfg.ProbeAddr = *probeAddr
+			}
+			if *metricsAddr != "" {
+				cfg.MetricsAddr = *metricsAddr
+			}
+			if *listenAddr != "" {
+				cfg.ListenAddr = *listenAddr
+			}
+			if *toolNamePrefix != "" {
+				cfg.ToolNamePrefix = *toolNamePrefix
+			}
+			if *promptNamePrefix != "" {
+				cfg.PromptNamePrefix = *promptNamePrefix
+			}
+			if *refreshInterval > 0 {
+				cfg.RefreshInterval = *refreshInterval
+			}
+			if *pingInterval > 0 {
+				cfg.PingInterval = *pingInterval
+			}
+			if *pingTimeout > 0 {
+				cfg.PingTimeout = *pingTimeout
+			}
+			if *enableSampling {
+				cfg.EnableSampling = *enableSampling
+			}
+			if *enableRoots {
+				cfg.EnableRoots = *enableRoots
+			}
+			if *enableLogForwarding {
+				cfg.EnableLogForwarding = *enableLogForwarding
+			}
+			if *isolatedSessions {
+				cfg.IsolatedSessions = *isolatedSessions
+			}
+			if *validateToolArguments {
+				cfg.ValidateToolArguments = *validateToolArguments
+			}
+			if *enableDeduplication {
+				cfg.EnableDeduplication = *enableDeduplication
+			}
+			if *responseCacheMaxEntries > 0 {
+				cfg.ResponseCacheMaxEntries = *responseCacheMaxEntries
+			}
+			if *responseCacheTTL > 0 {
+				cfg.ResponseCacheTTL = *responseCacheTTL
+			}
+			if *responseCacheMethods != "" {
+				cfg.ResponseCacheMethods = splitList(*responseCacheMethods)
+			}
+			if *toolCacheMaxEntries > 0 {
+				cfg.ToolCacheMaxEntries = *toolCacheMaxEntries
+			}
+			if *toolCacheTTL > 0 {
+				cfg.ToolCacheTTL = *toolCacheTTL
+			}
+			if len(toolCacheToolTTLs) > 0 {
+				cfg.ToolCacheToolTTLs = toolCacheToolTTLs
+			}
+			if *toolCacheExclude != "" {
+				cfg.ToolCacheExclude = splitList(*toolCacheExclude)
+			}
+			if len(toolCacheWarmup) > 0 {
+				cfg.ToolCacheWarmup = toolCacheWarmup
+			}
+			cfg.SetMethodTimeout(*toolCallTimeout, "tools/call")
+			cfg.SetMethodTimeout(*listTimeout, ListMethods...)
+			if *drainTimeout > 0 {
+				cfg.DrainTimeout = *drainTimeout
+			}
+			if *configSourcePollInterval != 0 {
+				cfg.ConfigSourcePollInterval = *configSourcePollInterval
+			}
+			if *auditLogPath != "" {
+				cfg.AuditLogPath = *auditLogPath
+			}
+			if *statsDAddr != "" {
+				cfg.StatsDAddr = *statsDAddr
+			}
+			if *statsDPrefix != "" {
+				cfg.StatsDPrefix = *statsDPrefix
+			}
+			if *statsDSampleRate > 0 {
+				cfg.StatsDSampleRate = *statsDSampleRate
+			}
+			if *configFile != "" {
+				cfg.ConfigFile = *configFile
+			}
+			if *configSource != "" {
+				cfg.ConfigSource = *configSource
+			}
+		},
 	}
-	if *region != "" {
-		cfg.Region = *region
+}
+
+// HeaderMap parses the comma delimited Headers list (key=value pairs) into a map.
+// Values are split from names at the first "=", so values may contain "=".
+// Entries without an "=" separator are ignored; Validate reports them.
+func (c *Config) HeaderMap() map[string]string {
+	headers := make(map[string]string)
+	if c.Headers == "" {
+		return headers
 	}
-	if *serviceName != "" {
-		cfg.ServiceName = *serviceName
+
+	for _, token := range strings.Split(c.Headers, ",") {
+		key, value, ok := strings.Cut(token, "=")
+		if !ok {
+			continue
+		}
+		headers[key] = value
 	}
-	if *sigVersion != "" {
-		cfg.SignatureVersion = *sigVersion
+	return headers
+}
+
+// validateHeaders checks each Headers entry for a key=value form, an RFC 7230
+// token name, and a printable ASCII value
+func (c *Config) validateHeaders() []error {
+	var errs []error
+	for _, token := range strings.Split(c.Headers, ",") {
+		if token == "" {
+			continue
+		}
+		problem := ""
+		name, value, ok := strings.Cut(token, "=")
+		switch {
+		case !ok:
+			problem = fmt.Sprintf("invalid header entry '%s': expected key=value", token)
+		case headerNameProblem(name) != "":
+			problem = fmt.Sprintf("invalid header name '%s': %s", name, headerNameProblem(name))
+		case headerValueProblem(value) != "":
+			problem = fmt.Sprintf("invalid value for header '%s': %s", name, headerValueProblem(value))
+		default:
+			continue
+		}
+		errs = append(errs, &FieldError{
+			Field:       "Headers",
+			Value:       token,
+			Problem:     problem,
+			Remediation: "set MCP_HEADERS or --headers to comma delimited Name=value pairs, such as X-Team=platform",
+		})
 	}
-	if *profile != "" {
-		cfg.Profile = *profile
+	return errs
+}
+
+// unsignedHeaders are the headers the AWS SDK leaves out of SigV4 signatures
+var unsignedHeaders = map[string]bool{
+	"Authorization":     true,
+	"User-Agent":        true,
+	"X-Amzn-Trace-Id":   true,
+	"Expect":            true,
+	"Transfer-Encoding": true,
+}
+
+// validateExtraSignedHeaders checks that each ExtraSignedHeaders entry is a
+// valid header name the SigV4 signer can sign
+func (c *Config) validateExtraSignedHeaders() []error {
+	var errs []error
+	for _, name := range c.ExtraSignedHeaders {
+		problem := headerNameProblem(name)
+		if problem == "" && unsignedHeaders[http.CanonicalHeaderKey(name)] {
+			problem = "the header is never included in SigV4 signatures"
+		}
+		if problem == "" {
+			continue
+		}
+		errs = append(errs, &FieldError{
+			Field:       "ExtraSignedHeaders",
+			Value:       name,
+			Problem:     fmt.Sprintf("invalid signed header '%s': %s", name, problem),
+			Remediation: "set MCP_EXTRA_SIGNED_HEADERS to a comma delimited list of header names sent with --headers, such as X-Tenant-ID",
+		})
 	}
-	if *enableSSE {
-		cfg.EnableSSE = *enableSSE
+	return errs
+}
+
+// validateForwardResponseHeaders checks that each ForwardResponseHeaders
+// entry is a valid header name
+func (c *Config) validateForwardResponseHeaders() []error {
+	var errs []error
+	for _, name := range c.ForwardResponseHeaders {
+		if problem := headerNameProblem(name); problem != "" {
+			errs = append(errs, &FieldError{
+				Field:       "ForwardResponseHeaders",
+				Value:       name,
+				Problem:     fmt.Sprintf("invalid response header '%s': %s", name, problem),
+				Remediation: "set MCP_FORWARD_RESPONSE_HEADERS to a comma delimited list of header names, such as X-Amzn-Requestid,X-Cache",
+			})
+		}
 	}
-	if *timeout > 0 {
-		cfg.Timeout = *timeout
+	return errs
+}
+
+// validateCredentialSources checks that each CredentialSources entry is a
+// supported source listed once
+func (c *Config) validateCredentialSources() []error {
+	var errs []error
+	seen := make(map[string]bool, len(c.CredentialSources))
+	for _, source := range c.CredentialSources {
+		var problem string
+		switch {
+		case !slices.Contains(credentials.SupportedCredentialSources, source):
+			problem = fmt.Sprintf("unknown credential source '%s'", source)
+		case seen[source]:
+			problem = fmt.Sprintf("credential source '%s' is listed more than once", source)
+		}
+		seen[source] = true
+		if problem == "" {
+			continue
+		}
+		errs = append(errs, &FieldError{
+			Field:       "CredentialSources",
+			Value:       source,
+			Problem:     problem,
+			Remediation: fmt.Sprintf("set MCP_CREDENTIAL_SOURCES to a comma delimited list of: %s", strings.Join(credentials.SupportedCredentialSources, ", ")),
+		})
 	}
-	if *headers != "" {
-		cfg.Headers = *headers
+	return errs
+}
+
+// headerNameProblem describes why name is not an RFC 7230 token, or returns ""
+func headerNameProblem(name string) string {
+	if name == "" {
+		return "name is empty"
 	}
+	for _, r := range name {
+		switch {
+		case r == ' ':
+			return "contains space"
+		case r > unicode.MaxASCII || !isTokenChar(byte(r)):
+			return fmt.Sprintf("contains invalid character %q", r)
+		}
+	}
+	return ""
+}
 
-	// Set default signature version if not specified
-	if cfg.SignatureVersion == "" {
-		cfg.SignatureVersion = "v4"
+// isTokenChar reports whether c is an RFC 7230 tchar
+func isTokenChar(c byte) bool {
+	if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' {
+		return true
 	}
+	return strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0
+}
 
-	// Set default profile if not specified
-	if cfg.Profile == "" {
-		cfg.Profile = "default"
+// headerValueProblem describes why value is not printable ASCII, or returns ""
+func headerValueProblem(value string) string {
+	for _, r := range value {
+		if r != '\t' && (r < ' ' || r > '~') {
+			return fmt.Sprintf("contains non-printable character %q", r)
+		}
 	}
+	return ""
+}
 
-	// Validate configuration
-	if err := cfg.Validate(); err != nil {
-		return nil, err
+// samePort reports whether the listen addresses a and b use the same port.
+// Hosts are not compared, since ":8080" and "0.0.0.0:8080" both bind every
+// interface. Port 0 picks a free port and never conflicts.
+func samePort(a, b string) bool {
+	_, portA, errA := net.SplitHostPort(a)
+	_, portB, errB := net.SplitHostPort(b)
+	return errA == nil && errB == nil && portA == portB && portA != "0"
+}
+
+// namePrefixProblem describes why prefix would make forwarded tool names
+// invalid per the MCP specification, which allows 1 to 128 letters, digits,
+// '_', '-', and '.', or returns ""
+func namePrefixProblem(prefix string) string {
+	if len(prefix) >= 128 {
+		return fmt.Sprintf("prefix must be shorter than 128 characters, got %d", len(prefix))
+	}
+	for _, r := range prefix {
+		if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '_' || r == '-' || r == '.') {
+			return fmt.Sprintf("prefix contains invalid character %q", r)
+		}
 	}
+	return ""
+}
 
-	return cfg, nil
+// ListMethods are the MCP list methods bounded by the list timeout shorthand
+var ListMethods = []string{"tools/list", "resources/list", "resources/templates/list", "prompts/list"}
+
+// SetMethodTimeout sets the timeout for each of the given MCP methods. Non-positive
+// timeouts are ignored. The map is copied first so configs sharing it are unaffected.
+func (c *Config) SetMethodTimeout(timeout time.Duration, methods ...string) {
+	if timeout <= 0 {
+		return
+	}
+	timeouts := make(map[string]time.Duration, len(c.MethodTimeouts)+len(methods))
+	maps.Copy(timeouts, c.MethodTimeouts)
+	for _, method := range methods {
+		timeouts[method] = timeout
+	}
+	c.MethodTimeouts = timeouts
 }
 
 // Validate checks that all required configuration fields are present and valid.
+// Each problem is reported as a *FieldError; use ValidationErrors to list them.
+// A missing service name is inferred from the target URL when possible, as in
+// InferServiceName.
 func (c *Config) Validate() error {
 	var errs []error
 
 	// Check required fields
 	if c.TargetURL == "" {
-		errs = append(errs, errors.New("target URL is required (set MCP_TARGET_URL or --target-url)"))
+		errs = append(errs, &FieldError{
+			Field:       "TargetURL",
+			Problem:     "target URL is required",
+			Remediation: "set MCP_TARGET_URL or --target-url",
+		})
 	} else {
 		// Validate URL format
 		parsedURL, err := url.Parse(c.TargetURL)
 		if err != nil {
-			errs = append(errs, fmt.Errorf("invalid target URL: %w", err))
+			errs = append(errs, &FieldError{
+				Field:       "TargetURL",
+				Value:       c.TargetURL,
+				Problem:     fmt.Sprintf("invalid target URL: %v", err),
+				Remediation: "set MCP_TARGET_URL to a valid https:// URL",
+			})
 		} else if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
-			errs = append(errs, fmt.Errorf("target URL must use http or https scheme, got: %s", parsedURL.Scheme))
+			errs = append(errs, &FieldError{
+				Field:       "TargetURL",
+				Value:       c.TargetURL,
+				Problem:     fmt.Sprintf("target URL must use http or https scheme, got: %s", parsedURL.Scheme),
+				Remediation: "set MCP_TARGET_URL to a valid https:// URL",
+			})
 		}
 	}
 
 	if c.Region == "" {
-		errs = append(errs, errors.New("region is required (set AWS_REGION or --region)"))
+		errs = append(errs, &FieldError{
+			Field:       "Region",
+			Problem:     "region is required",
+			Remediation: "set AWS_REGION or --region",
+		})
 	}
 
+	// Warn about a region that differs from the target's, which fails signature
+	// checks unless the target's custom domain is intentionally in another region
+	if c.Region != "" && c.SignatureVersion != "v4a" {
+		if urlRegion, ok := ExtractRegionFromURL(c.TargetURL); ok && urlRegion != c.Region {
+			slog.Warn(fmt.Sprintf("Configured region '%s' may not match URL region '%s'", c.Region, urlRegion),
+				"remediation", "set AWS_REGION or --region to the target's region")
+		}
+	}
+
+	// Infer a missing service name from AWS endpoint hostnames
+	if c.ServiceName == "" {
+		if service, ok := InferServiceName(c.TargetURL); ok {
+			c.ServiceName = service
+			slog.Info("inferred service name from target URL", "service_name", service)
+		}
+	}
 	if c.ServiceName == "" {
-		errs = append(errs, errors.New("service name is required (set AWS_SERVICE_NAME or --service-name)"))
+		errs = append(errs, &FieldError{
+			Field:       "ServiceName",
+			Problem:     "service name is required",
+			Remediation: "set AWS_SERVICE_NAME or --service-name",
+		})
 	}
 
 	// Validate signature version
 	if c.SignatureVersion != "v4" && c.SignatureVersion != "v4a" {
-		errs = append(errs, fmt.Errorf("signature version must be 'v4' or 'v4a', got: %s", c.SignatureVersion))
+		errs = append(errs, &FieldError{
+			Field:       "SignatureVersion",
+			Value:       c.SignatureVersion,
+			Problem:     fmt.Sprintf("signature version must be 'v4' or 'v4a', got: %s", c.SignatureVersion),
+			Remediation: "set AWS_SIG_VERSION or --sig-version to v4 or v4a",
+		})
+	}
+
+	// Validate the role to assume
+	if c.RoleARN != "" && !strings.HasPrefix(c.RoleARN, "arn:aws") {
+		errs = append(errs, &FieldError{
+			Field:       "RoleARN",
+			Value:       c.RoleARN,
+			Problem:     "role ARN must start with arn:aws",
+			Remediation: "set MCP_ROLE_ARN or --role-arn to an IAM role ARN such as arn:aws:iam::123456789012:role/name",
+		})
+	}
+
+	// STS accepts assumed-role sessions from 15 minutes to 12 hours
+	if c.RoleSessionDuration != 0 && (c.RoleSessionDuration < 15*time.Minute || c.RoleSessionDuration > 12*time.Hour) {
+		errs = append(errs, &FieldError{
+			Field:       "RoleSessionDuration",
+			Value:       c.RoleSessionDuration.String(),
+			Problem:     "role session duration must be between 15m and 12h",
+			Remediation: "set MCP_ROLE_SESSION_DURATION or --role-session-duration to a duration such as 1h",
+		})
+	}
+
+	// Validate that SSO is configured completely or not at all
+	ssoSet := 0
+	for _, field := range []string{c.SSOStartURL, c.SSOAccountID, c.SSORoleName, c.SSORegion} {
+		if field != "" {
+			ssoSet++
+		}
+	}
+	if ssoSet > 0 && ssoSet < 4 {
+		errs = append(errs, &FieldError{
+			Field:       "SSOStartURL",
+			Value:       c.SSOStartURL,
+			Problem:     "SSO requires a start URL, account ID, role name, and region",
+			Remediation: "set all of --sso-start-url, --sso-account-id, --sso-role-name, and --sso-region, or none of them",
+		})
+	}
+
+	// Validate that web identity is configured completely or not at all
+	if (c.WebIdentityTokenFile == "") != (c.WebIdentityRoleARN == "") {
+		errs = append(errs, &FieldError{
+			Field:       "WebIdentityTokenFile",
+			Value:       c.WebIdentityTokenFile,
+			Problem:     "web identity requires a token file and a role ARN",
+			Remediation: "set both --web-identity-token-file and --web-identity-role-arn, or neither of them",
+		})
+	}
+
+	// Validate the Vault address format
+	if c.VaultAddr != "" {
+		parsedURL, err := url.Parse(c.VaultAddr)
+		if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") {
+			errs = append(errs, &FieldError{
+				Field:       "VaultAddr",
+				Value:       c.VaultAddr,
+				Problem:     "vault address must be an http or https URL",
+				Remediation: "set MCP_VAULT_ADDR or --vault-addr to a URL such as https://vault.example.com:8200",
+			})
+		}
+	}
+
+	// Validate fallback URL formats
+	for _, fallbackURL := range c.FallbackURLs {
+		parsedURL, err := url.Parse(fallbackURL)
+		if err != nil {
+			errs = append(errs, &FieldError{
+				Field:       "FallbackURLs",
+				Value:       fallbackURL,
+				Problem:     fmt.Sprintf("invalid fallback URL: %v", err),
+				Remediation: "set MCP_FALLBACK_URLS to a comma delimited list of https:// URLs",
+			})
+		} else if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+			errs = append(errs, &FieldError{
+				Field:       "FallbackURLs",
+				Value:       fallbackURL,
+				Problem:     fmt.Sprintf("fallback URL must use http or https scheme, got: %s", parsedURL.Scheme),
+				Remediation: "set MCP_FALLBACK_URLS to a comma delimited list of https:// URLs",
+			})
+		}
+	}
+
+	// Validate the shadow target URL format
+	if c.ShadowTargetURL != "" {
+		parsedURL, err := url.Parse(c.ShadowTargetURL)
+		if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") {
+			errs = append(errs, &FieldError{
+				Field:       "ShadowTargetURL",
+				Value:       c.ShadowTargetURL,
+				Problem:     "shadow target URL must be an http or https URL",
+				Remediation: "set MCP_SHADOW_TARGET_URL or --shadow-target-url to a URL such as https://staging.example.com/mcp",
+			})
+		}
+	}
+
+	// Validate rate limit settings
+	if c.StatsDSampleRate < 0 || c.StatsDSampleRate > 1 {
+		errs = append(errs, &FieldError{
+			Field:       "StatsDSampleRate",
+			Value:       fmt.Sprint(c.StatsDSampleRate),
+			Problem:     fmt.Sprintf("StatsD sample rate must be between 0 and 1, got: %g", c.StatsDSampleRate),
+			Remediation: "set MCP_STATSD_SAMPLE_RATE to a fraction such as 0.1, or 0 to send every metric",
+		})
+	}
+
+	if c.RateLimitRPS < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "RateLimitRPS",
+			Value:       fmt.Sprint(c.RateLimitRPS),
+			Problem:     fmt.Sprintf("rate limit must not be negative, got: %g", c.RateLimitRPS),
+			Remediation: "set MCP_RATE_LIMIT_RPS to a positive number, or 0 to disable rate limiting",
+		})
+	}
+	if c.RateLimitBurst < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "RateLimitBurst",
+			Value:       fmt.Sprint(c.RateLimitBurst),
+			Problem:     fmt.Sprintf("rate limit burst must not be negative, got: %d", c.RateLimitBurst),
+			Remediation: "set MCP_RATE_LIMIT_BURST to a positive integer, or 0 for the default",
+		})
+	}
+	if c.ClientRateLimitRPS < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "ClientRateLimitRPS",
+			Value:       fmt.Sprint(c.ClientRateLimitRPS),
+			Problem:     fmt.Sprintf("client rate limit must not be negative, got: %g", c.ClientRateLimitRPS),
+			Remediation: "set MCP_CLIENT_RATE_LIMIT_RPS to a positive number, or 0 to disable per-client rate limiting",
+		})
+	}
+	if c.ClientRateLimitBurst < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "ClientRateLimitBurst",
+			Value:       fmt.Sprint(c.ClientRateLimitBurst),
+			Problem:     fmt.Sprintf("client rate limit burst must not be negative, got: %d", c.ClientRateLimitBurst),
+			Remediation: "set MCP_CLIENT_RATE_LIMIT_BURST to a positive integer, or 0 for the default",
+		})
+	}
+	if c.ClientLimiterIdle < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "ClientLimiterIdle",
+			Value:       c.ClientLimiterIdle.String(),
+			Problem:     fmt.Sprintf("client limiter idle timeout must not be negative, got: %s", c.ClientLimiterIdle),
+			Remediation: "set MCP_CLIENT_LIMITER_IDLE to a positive duration, or 0 for the default",
+		})
+	}
+
+	// Validate SSE reconnect settings
+	if c.SSEMaxReconnects < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "SSEMaxReconnects",
+			Value:       fmt.Sprint(c.SSEMaxReconnects),
+			Problem:     fmt.Sprintf("SSE max reconnects must not be negative, got: %d", c.SSEMaxReconnects),
+			Remediation: "set MCP_SSE_MAX_RECONNECTS to a positive integer, or 0 to disable reconnection",
+		})
+	}
+	if c.SSERetryDelay < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "SSERetryDelay",
+			Value:       c.SSERetryDelay.String(),
+			Problem:     fmt.Sprintf("SSE reconnect delays must not be negative, got: %s", c.SSERetryDelay),
+			Remediation: "set MCP_SSE_RECONNECT_DELAY to a positive duration such as 1s",
+		})
+	}
+	if c.SSEMaxRetryDelay < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "SSEMaxRetryDelay",
+			Value:       c.SSEMaxRetryDelay.String(),
+			Problem:     fmt.Sprintf("SSE reconnect delays must not be negative, got: %s", c.SSEMaxRetryDelay),
+			Remediation: "set MCP_SSE_MAX_RECONNECT_DELAY to a positive duration such as 30s",
+		})
+	}
+
+	if c.RefreshInterval < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "RefreshInterval",
+			Value:       c.RefreshInterval.String(),
+			Problem:     fmt.Sprintf("refresh interval must not be negative, got: %s", c.RefreshInterval),
+			Remediation: "set MCP_REFRESH_INTERVAL to a positive duration, or 0 to disable refresh",
+		})
+	}
+	if c.PingInterval < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "PingInterval",
+			Value:       c.PingInterval.String(),
+			Problem:     fmt.Sprintf("ping interval must not be negative, got: %s", c.PingInterval),
+			Remediation: "set MCP_PING_INTERVAL to a positive duration, or 0 to disable pings",
+		})
+	}
+	if c.PingTimeout < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "PingTimeout",
+			Value:       c.PingTimeout.String(),
+			Problem:     fmt.Sprintf("ping timeout must not be negative, got: %s", c.PingTimeout),
+			Remediation: "set MCP_PING_TIMEOUT to a positive duration such as 10s",
+		})
+	}
+	for method, timeout := range c.MethodTimeouts {
+		if timeout < 0 {
+			errs = append(errs, &FieldError{
+				Field:       "MethodTimeouts",
+				Value:       method + "=" + timeout.String(),
+				Problem:     fmt.Sprintf("timeout for method %s must not be negative, got: %s", method, timeout),
+				Remediation: "set a positive duration in method_timeouts",
+			})
+		}
+	}
+	if c.TCPProbeInterval < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "TCPProbeInterval",
+			Value:       c.TCPProbeInterval.String(),
+			Problem:     fmt.Sprintf("TCP keep-alive probe settings must not be negative, got: %s", c.TCPProbeInterval),
+			Remediation: "set MCP_TCP_KEEPALIVE_INTERVAL to a positive duration, or 0 for the OS default",
+		})
+	}
+	if c.TCPProbeCount < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "TCPProbeCount",
+			Value:       fmt.Sprint(c.TCPProbeCount),
+			Problem:     fmt.Sprintf("TCP keep-alive probe settings must not be negative, got: %d", c.TCPProbeCount),
+			Remediation: "set MCP_TCP_KEEPALIVE_COUNT to a positive integer, or 0 for the OS default",
+		})
+	}
+	if c.MaxIdleConns < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "MaxIdleConns",
+			Value:       fmt.Sprint(c.MaxIdleConns),
+			Problem:     fmt.Sprintf("connection pool limits must not be negative, got: %d", c.MaxIdleConns),
+			Remediation: "set MCP_MAX_IDLE_CONNS to a positive integer, or 0 for the default of 100",
+		})
+	}
+	if c.MaxIdleConnsPerHost < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "MaxIdleConnsPerHost",
+			Value:       fmt.Sprint(c.MaxIdleConnsPerHost),
+			Problem:     fmt.Sprintf("connection pool limits must not be negative, got: %d", c.MaxIdleConnsPerHost),
+			Remediation: "set MCP_MAX_IDLE_CONNS_PER_HOST to a positive integer, or 0 for the default of 10",
+		})
+	}
+	if c.MaxConnsPerHost < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "MaxConnsPerHost",
+			Value:       fmt.Sprint(c.MaxConnsPerHost),
+			Problem:     fmt.Sprintf("connection pool limits must not be negative, got: %d", c.MaxConnsPerHost),
+			Remediation: "set MCP_MAX_CONNS_PER_HOST to a positive integer, or 0 for no limit",
+		})
+	}
+	if c.HTTP2PingTimeout < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "HTTP2PingTimeout",
+			Value:       c.HTTP2PingTimeout.String(),
+			Problem:     fmt.Sprintf("HTTP/2 ping timeout must not be negative, got: %s", c.HTTP2PingTimeout),
+			Remediation: "set MCP_HTTP2_PING_TIMEOUT to a positive duration, or 0 for the default of 15s",
+		})
+	}
+	if c.MaxConcurrentRequests < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "MaxConcurrentRequests",
+			Value:       fmt.Sprint(c.MaxConcurrentRequests),
+			Problem:     fmt.Sprintf("max concurrent requests must not be negative, got: %d", c.MaxConcurrentRequests),
+			Remediation: "set MCP_MAX_CONCURRENT_REQUESTS to a positive integer, or 0 for no limit",
+		})
+	}
+	if c.DialTimeout < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "DialTimeout",
+			Value:       c.DialTimeout.String(),
+			Problem:     fmt.Sprintf("connection timeouts must not be negative, got: %s", c.DialTimeout),
+			Remediation: "set MCP_DIAL_TIMEOUT to a positive duration, or 0 for the default",
+		})
+	}
+	if c.TLSHandshakeTimeout < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "TLSHandshakeTimeout",
+			Value:       c.TLSHandshakeTimeout.String(),
+			Problem:     fmt.Sprintf("connection timeouts must not be negative, got: %s", c.TLSHandshakeTimeout),
+			Remediation: "set MCP_TLS_HANDSHAKE_TIMEOUT to a positive duration, or 0 for the default",
+		})
+	}
+	if c.ResponseHeaderTimeout < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "ResponseHeaderTimeout",
+			Value:       c.ResponseHeaderTimeout.String(),
+			Problem:     fmt.Sprintf("connection timeouts must not be negative, got: %s", c.ResponseHeaderTimeout),
+			Remediation: "set MCP_RESPONSE_HEADER_TIMEOUT to a positive duration, or 0 for the default",
+		})
+	}
+	if c.IdleConnectionTimeout < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "IdleConnectionTimeout",
+			Value:       c.IdleConnectionTimeout.String(),
+			Problem:     fmt.Sprintf("connection timeouts must not be negative, got: %s", c.IdleConnectionTimeout),
+			Remediation: "set MCP_IDLE_CONNECTION_TIMEOUT to a positive duration, or 0 for the default",
+		})
+	}
+	if c.DNSCacheTTL < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "DNSCacheTTL",
+			Value:       c.DNSCacheTTL.String(),
+			Problem:     fmt.Sprintf("DNS cache TTL must not be negative, got: %s", c.DNSCacheTTL),
+			Remediation: "set MCP_DNS_CACHE_TTL to a positive duration, or 0 to disable the cache",
+		})
+	}
+	if c.ProbeAddr != "" {
+		if _, _, err := net.SplitHostPort(c.ProbeAddr); err != nil {
+			errs = append(errs, &FieldError{
+				Field:       "ProbeAddr",
+				Value:       c.ProbeAddr,
+				Problem:     fmt.Sprintf("invalid probe address: %v", err),
+				Remediation: "set MCP_PROBE_ADDR to a host:port address, such as :8081",
+			})
+		}
+	}
+	if c.MetricsAddr != "" {
+		if _, _, err := net.SplitHostPort(c.MetricsAddr); err != nil {
+			errs = append(errs, &FieldError{
+				Field:       "MetricsAddr",
+				Value:       c.MetricsAddr,
+				Problem:     fmt.Sprintf("invalid metrics address: %v", err),
+				Remediation: "set MCP_METRICS_ADDR or --metrics-addr to a host:port address, such as :9090",
+			})
+		} else if samePort(c.MetricsAddr, c.ProbeAddr) || samePort(c.MetricsAddr, c.ListenAddr) {
+			errs = append(errs, &FieldError{
+				Field:       "MetricsAddr",
+				Value:       c.MetricsAddr,
+				Problem:     "metrics address is also the probe or listen address",
+				Remediation: "set MCP_METRICS_ADDR to a port not used by MCP_PROBE_ADDR or MCP_LISTEN_ADDR",
+			})
+		}
+	}
+	if c.ListenAddr != "" {
+		if _, _, err := net.SplitHostPort(c.ListenAddr); err != nil {
+			errs = append(errs, &FieldError{
+				Field:       "ListenAddr",
+				Value:       c.ListenAddr,
+				Problem:     fmt.Sprintf("invalid listen address: %v", err),
+				Remediation: "set MCP_LISTEN_ADDR or --listen-addr to a host:port address, such as 127.0.0.1:8080",
+			})
+		} else if samePort(c.ListenAddr, c.ProbeAddr) {
+			errs = append(errs, &FieldError{
+				Field:       "ListenAddr",
+				Value:       c.ListenAddr,
+				Problem:     "listen address is also the probe address",
+				Remediation: "set MCP_LISTEN_ADDR and MCP_PROBE_ADDR to different ports",
+			})
+		}
+	}
+	if problem := namePrefixProblem(c.ToolNamePrefix); problem != "" {
+		errs = append(errs, &FieldError{
+			Field:       "ToolNamePrefix",
+			Value:       c.ToolNamePrefix,
+			Problem:     problem,
+			Remediation: "set MCP_TOOL_NAME_PREFIX to letters, digits, '_', '-', or '.', such as billing_",
+		})
+	}
+	if problem := namePrefixProblem(c.PromptNamePrefix); problem != "" {
+		errs = append(errs, &FieldError{
+			Field:       "PromptNamePrefix",
+			Value:       c.PromptNamePrefix,
+			Problem:     problem,
+			Remediation: "set MCP_PROMPT_NAME_PREFIX to letters, digits, '_', '-', or '.', such as billing_",
+		})
+	}
+	if c.DrainTimeout < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "DrainTimeout",
+			Value:       c.DrainTimeout.String(),
+			Problem:     fmt.Sprintf("drain timeout must not be negative, got: %s", c.DrainTimeout),
+			Remediation: "set MCP_DRAIN_TIMEOUT to a positive duration, or 0 to cancel immediately",
+		})
+	}
+	errs = append(errs, c.validateFeatureFlags()...)
+	errs = append(errs, c.validateResponseCache()...)
+	errs = append(errs, c.validateToolCache()...)
+	errs = append(errs, c.validateBinaryContentTypes()...)
+
+	// Validate custom headers
+	errs = append(errs, c.validateHeaders()...)
+	errs = append(errs, c.validateExtraSignedHeaders()...)
+	errs = append(errs, c.validateForwardResponseHeaders()...)
+	errs = append(errs, c.validateCredentialSources()...)
+	if c.SigningHost != "" {
+		if parsed, err := url.Parse("//" + c.SigningHost); err != nil || parsed.Host != c.SigningHost || parsed.Hostname() == "" {
+			errs = append(errs, &FieldError{
+				Field:       "SigningHost",
+				Value:       c.SigningHost,
+				Problem:     fmt.Sprintf("signing host must be a host name with an optional port, got: %s", c.SigningHost),
+				Remediation: "set MCP_SIGNING_HOST to the target's public host name without a scheme or path, such as abc123.execute-api.us-east-1.amazonaws.com",
+			})
+		} else if c.SignatureVersion == "v4a" {
+			errs = append(errs, &FieldError{
+				Field:       "SigningHost",
+				Value:       c.SigningHost,
+				Problem:     "signing host is only supported with signature version 'v4'",
+				Remediation: "remove --signing-host or use --sig-version v4",
+			})
+		}
+	}
+
+	// Validate proxy URL format
+	if c.ProxyURL != "" {
+		parsedURL, err := url.Parse(c.ProxyURL)
+		if err != nil {
+			errs = append(errs, &FieldError{
+				Field:       "ProxyURL",
+				Value:       c.ProxyURL,
+				Problem:     fmt.Sprintf("invalid proxy URL: %v", err),
+				Remediation: "set MCP_PROXY_URL to a valid http://, https://, or socks5:// URL",
+			})
+		} else if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" && parsedURL.Scheme != "socks5" {
+			errs = append(errs, &FieldError{
+				Field:       "ProxyURL",
+				Value:       c.ProxyURL,
+				Problem:     fmt.Sprintf("proxy URL must use http, https, or socks5 scheme, got: %s", parsedURL.Scheme),
+				Remediation: "set MCP_PROXY_URL to a valid http://, https://, or socks5:// URL",
+			})
+		}
+	}
+
+	// Validate the path rewrite pattern
+	if c.PathRewrite.Pattern != "" {
+		if _, err := regexp.Compile(c.PathRewrite.Pattern); err != nil {
+			errs = append(errs, &FieldError{
+				Field:       "PathRewrite",
+				Value:       c.PathRewrite.Pattern,
+				Problem:     fmt.Sprintf("invalid path rewrite pattern: %v", err),
+				Remediation: "set MCP_PATH_REWRITE_PATTERN or --path-rewrite-pattern to a valid regular expression",
+			})
+		}
+	}
+	if c.PathRewrite.Replacement != "" && c.PathRewrite.Pattern == "" {
+		errs = append(errs, &FieldError{
+			Field:       "PathRewrite",
+			Value:       c.PathRewrite.Replacement,
+			Problem:     "path rewrite replacement requires a pattern",
+			Remediation: "set MCP_PATH_REWRITE_PATTERN or --path-rewrite-pattern",
+		})
+	}
+
+	// Validate TLS settings
+	if (c.ClientCertFile == "") != (c.ClientKeyFile == "") {
+		field, value := "ClientKeyFile", c.ClientKeyFile
+		if c.ClientCertFile == "" {
+			field, value = "ClientCertFile", c.ClientCertFile
+		}
+		errs = append(errs, &FieldError{
+			Field:       field,
+			Value:       value,
+			Problem:     "client certificate and key must be set together",
+			Remediation: "set both --tls-cert-file and --tls-key-file",
+		})
+	}
+	if c.TLSSkipVerify && c.SignatureVersion == "v4a" {
+		errs = append(errs, &FieldError{
+			Field:       "TLSSkipVerify",
+			Value:       "true",
+			Problem:     "TLS verification cannot be skipped with signature version 'v4a'",
+			Remediation: "remove --tls-skip-verify or use --sig-version v4",
+		})
 	}
 
 	// Combine all errors
@@ -190,3 +1978,111 @@ func (c *Config) Validate() error {
 
 	return nil
 }
+
+// cacheableMethods are the MCP methods whose responses may be cached
+var cacheableMethods = map[string]bool{
+	"tools/call":     true,
+	"resources/read": true,
+	"prompts/get":    true,
+}
+
+// validateResponseCache checks the response cache size, TTL, and methods
+func (c *Config) validateResponseCache() []error {
+	var errs []error
+	if c.ResponseCacheMaxEntries < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "ResponseCacheMaxEntries",
+			Value:       fmt.Sprint(c.ResponseCacheMaxEntries),
+			Problem:     fmt.Sprintf("response cache size must not be negative, got: %d", c.ResponseCacheMaxEntries),
+			Remediation: "set MCP_RESPONSE_CACHE_MAX_ENTRIES to a positive integer, or 0 to disable the cache",
+		})
+	}
+	if c.ResponseCacheTTL < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "ResponseCacheTTL",
+			Value:       c.ResponseCacheTTL.String(),
+			Problem:     fmt.Sprintf("response cache TTL must not be negative, got: %s", c.ResponseCacheTTL),
+			Remediation: "set MCP_RESPONSE_CACHE_TTL to a positive duration, or 0 to keep responses until evicted",
+		})
+	}
+	for _, method := range c.ResponseCacheMethods {
+		if !cacheableMethods[method] {
+			errs = append(errs, &FieldError{
+				Field:       "ResponseCacheMethods",
+				Value:       method,
+				Problem:     fmt.Sprintf("responses to %s cannot be cached", method),
+				Remediation: "set MCP_RESPONSE_CACHE_METHODS to a comma delimited list of tools/call, resources/read, and prompts/get",
+			})
+		}
+	}
+	return errs
+}
+
+// validateToolCache checks the tool result cache size, TTLs, and warmup calls
+func (c *Config) validateToolCache() []error {
+	var errs []error
+	if c.ToolCacheMaxEntries < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "ToolCacheMaxEntries",
+			Value:       fmt.Sprint(c.ToolCacheMaxEntries),
+			Problem:     fmt.Sprintf("tool cache size must not be negative, got: %d", c.ToolCacheMaxEntries),
+			Remediation: "set MCP_TOOL_CACHE_MAX_ENTRIES to a positive integer, or 0 to disable the cache",
+		})
+	}
+	if c.ToolCacheTTL < 0 {
+		errs = append(errs, &FieldError{
+			Field:       "ToolCacheTTL",
+			Value:       c.ToolCacheTTL.String(),
+			Problem:     fmt.Sprintf("tool cache TTL must not be negative, got: %s", c.ToolCacheTTL),
+			Remediation: "set MCP_TOOL_CACHE_TTL to a positive duration, or 0 to keep results until evicted",
+		})
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.ToolCacheToolTTLs)) {
+		if ttl := c.ToolCacheToolTTLs[name]; ttl < 0 {
+			errs = append(errs, &FieldError{
+				Field:       "ToolCacheToolTTLs",
+				Value:       name + "=" + ttl.String(),
+				Problem:     fmt.Sprintf("cache TTL for tool %s must not be negative, got: %s", name, ttl),
+				Remediation: "set MCP_TOOL_CACHE_TOOL_TTLS to a comma delimited list of Name=Duration pairs with positive durations",
+			})
+		}
+	}
+	if len(c.ToolCacheWarmup) > 0 && c.ToolCacheMaxEntries == 0 {
+		errs = append(errs, &FieldError{
+			Field:       "ToolCacheWarmup",
+			Value:       fmt.Sprintf("%d calls", len(c.ToolCacheWarmup)),
+			Problem:     "tool cache warmup requires the tool result cache",
+			Remediation: "set MCP_TOOL_CACHE_MAX_ENTRIES to a positive integer, or remove MCP_TOOL_CACHE_WARMUP",
+		})
+	}
+	for _, call := range c.ToolCacheWarmup {
+		if call.Tool == "" {
+			errs = append(errs, &FieldError{
+				Field:       "ToolCacheWarmup",
+				Value:       string(call.Arguments),
+				Problem:     "tool cache warmup call is missing a tool name",
+				Remediation: `set the "tool" of each MCP_TOOL_CACHE_WARMUP entry`,
+			})
+		}
+	}
+	return errs
+}
+
+// validateBinaryContentTypes checks that each binary content type is a media
+// type, with * allowed only as a whole subtype
+func (c *Config) validateBinaryContentTypes() []error {
+	var errs []error
+	for _, contentType := range c.BinaryContentTypes {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		mainType, subtype, ok := strings.Cut(mediaType, "/")
+		if err != nil || !ok || mainType == "*" || (strings.Contains(subtype, "*") && subtype != "*") {
+			errs = append(errs, &FieldError{
+				Field:       "BinaryContentTypes",
+				Value:       contentType,
+				Problem:     "binary content type must be a media type such as application/pdf or image/*",
+				Remediation: "set MCP_BINARY_CONTENT_TYPES or --binary-content-types to a comma delimited list of media types",
+			})
+		}
+	}
+	return errs
+}
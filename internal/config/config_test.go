@@ -2,7 +2,9 @@ package config
 
 import (
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -111,6 +113,163 @@ func TestConfig_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "target URL must use http or https scheme",
 		},
+		{
+			name: "client cert without key",
+			config: Config{
+				TargetURL:        "https://example.com",
+				Region:           "us-east-1",
+				ServiceName:      "execute-api",
+				SignatureVersion: "v4",
+				ClientCertFile:   "client.pem",
+			},
+			wantErr: true,
+			errMsg:  "client certificate and key must be set together",
+		},
+		{
+			name: "skip verify with v4a",
+			config: Config{
+				TargetURL:        "https://example.com",
+				Region:           "us-east-1",
+				ServiceName:      "execute-api",
+				SignatureVersion: "v4a",
+				TLSSkipVerify:    true,
+			},
+			wantErr: true,
+			errMsg:  "TLS verification cannot be skipped",
+		},
+		{
+			name: "invalid fallback URL scheme",
+			config: Config{
+				TargetURL:        "https://example.com",
+				Region:           "us-east-1",
+				ServiceName:      "execute-api",
+				SignatureVersion: "v4",
+				FallbackURLs:     []string{"https://backup.example.com", "ws://backup2.example.com"},
+			},
+			wantErr: true,
+			errMsg:  "fallback URL must use http or https scheme",
+		},
+		{
+			name: "invalid proxy URL scheme",
+			config: Config{
+				TargetURL:        "https://example.com",
+				Region:           "us-east-1",
+				ServiceName:      "execute-api",
+				SignatureVersion: "v4",
+				ProxyURL:         "ftp://proxy.example.com:8080",
+			},
+			wantErr: true,
+			errMsg:  "proxy URL must use http, https, or socks5 scheme",
+		},
+		{
+			name: "invalid role ARN",
+			config: Config{
+				TargetURL:        "https://example.com",
+				Region:           "us-east-1",
+				ServiceName:      "execute-api",
+				SignatureVersion: "v4",
+				RoleARN:          "role/deploy",
+			},
+			wantErr: true,
+			errMsg:  "role ARN must start with arn:aws",
+		},
+		{
+			name: "header value containing equals signs",
+			config: Config{
+				TargetURL:        "https://example.com",
+				Region:           "us-east-1",
+				ServiceName:      "execute-api",
+				SignatureVersion: "v4",
+				Headers:          "X-Token=dGVzdA==,X-Team=platform,",
+			},
+			wantErr: false,
+		},
+		{
+			name: "header name with space",
+			config: Config{
+				TargetURL:        "https://example.com",
+				Region:           "us-east-1",
+				ServiceName:      "execute-api",
+				SignatureVersion: "v4",
+				Headers:          "X Bad=value",
+			},
+			wantErr: true,
+			errMsg:  "invalid header name 'X Bad': contains space",
+		},
+		{
+			name: "header name with invalid character",
+			config: Config{
+				TargetURL:        "https://example.com",
+				Region:           "us-east-1",
+				ServiceName:      "execute-api",
+				SignatureVersion: "v4",
+				Headers:          "X-Team:=platform",
+			},
+			wantErr: true,
+			errMsg:  "invalid header name 'X-Team:': contains invalid character ':'",
+		},
+		{
+			name: "header without separator",
+			config: Config{
+				TargetURL:        "https://example.com",
+				Region:           "us-east-1",
+				ServiceName:      "execute-api",
+				SignatureVersion: "v4",
+				Headers:          "X-Team=platform,malformed",
+			},
+			wantErr: true,
+			errMsg:  "invalid header entry 'malformed': expected key=value",
+		},
+		{
+			name: "header value with control character",
+			config: Config{
+				TargetURL:        "https://example.com",
+				Region:           "us-east-1",
+				ServiceName:      "execute-api",
+				SignatureVersion: "v4",
+				Headers:          "X-Team=plat\nform",
+			},
+			wantErr: true,
+			errMsg:  "invalid value for header 'X-Team': contains non-printable character '\\n'",
+		},
+		{
+			name: "role session duration too long",
+			config: Config{
+				TargetURL:           "https://example.com",
+				Region:              "us-east-1",
+				ServiceName:         "execute-api",
+				SignatureVersion:    "v4",
+				RoleARN:             "arn:aws:iam::123456789012:role/deploy",
+				RoleSessionDuration: 13 * time.Hour,
+			},
+			wantErr: true,
+			errMsg:  "role session duration must be between 15m and 12h",
+		},
+		{
+			name: "incomplete SSO settings",
+			config: Config{
+				TargetURL:        "https://example.com",
+				Region:           "us-east-1",
+				ServiceName:      "execute-api",
+				SignatureVersion: "v4",
+				SSOStartURL:      "https://example.awsapps.com/start",
+				SSORoleName:      "Developer",
+			},
+			wantErr: true,
+			errMsg:  "SSO requires a start URL, account ID, role name, and region",
+		},
+		{
+			name: "web identity token file without role",
+			config: Config{
+				TargetURL:            "https://example.com",
+				Region:               "us-east-1",
+				ServiceName:          "execute-api",
+				SignatureVersion:     "v4",
+				WebIdentityTokenFile: "/var/run/secrets/token",
+			},
+			wantErr: true,
+			errMsg:  "web identity requires a token file and a role ARN",
+		},
 		{
 			name: "multiple validation errors",
 			config: Config{
@@ -442,6 +601,33 @@ func TestConfig_Validate_WithNewFeatures(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "valid config with TLS settings",
+			config: Config{
+				TargetURL:        "https://example.com",
+				Region:           "us-east-1",
+				ServiceName:      "execute-api",
+				SignatureVersion: "v4",
+				Profile:          "default",
+				TLSCAFile:        "ca.pem",
+				ClientCertFile:   "client.pem",
+				ClientKeyFile:    "client-key.pem",
+				TLSSkipVerify:    true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid config with socks5 proxy",
+			config: Config{
+				TargetURL:        "https://example.com",
+				Region:           "us-east-1",
+				ServiceName:      "execute-api",
+				SignatureVersion: "v4",
+				Profile:          "default",
+				ProxyURL:         "socks5://proxy.example.com:1080",
+			},
+			wantErr: false,
+		},
 		{
 			name: "valid config with all new features",
 			config: Config{
@@ -469,3 +655,735 @@ func TestConfig_Validate_WithNewFeatures(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_HeaderMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers string
+		want    map[string]string
+	}{
+		{
+			name:    "no headers",
+			headers: "",
+			want:    map[string]string{},
+		},
+		{
+			name:    "multiple headers",
+			headers: "X-Custom-Header=value,X-API-Version=v2",
+			want:    map[string]string{"X-Custom-Header": "value", "X-API-Version": "v2"},
+		},
+		{
+			name:    "value containing equals sign",
+			headers: "X-Token=abc=def",
+			want:    map[string]string{"X-Token": "abc=def"},
+		},
+		{
+			name:    "entry without separator is ignored",
+			headers: "X-Custom-Header=value,malformed",
+			want:    map[string]string{"X-Custom-Header": "value"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{Headers: tt.headers}
+			assert.Equal(t, tt.want, cfg.HeaderMap())
+		})
+	}
+}
+
+func TestLoadFromEnv_WithFallbackURLs(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_FALLBACK_URLS", "https://backup1.example.com, https://backup2.example.com,")
+	t.Setenv("MCP_FAILOVER_TIMEOUT", "5s")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://backup1.example.com", "https://backup2.example.com"}, cfg.FallbackURLs)
+	assert.Equal(t, "5s", cfg.FailoverTimeout.String())
+}
+
+func TestLoadFromEnv_WithRateLimit(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_RATE_LIMIT_RPS", "2.5")
+	t.Setenv("MCP_RATE_LIMIT_BURST", "5")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 2.5, cfg.RateLimitRPS)
+	assert.Equal(t, 5, cfg.RateLimitBurst)
+
+	t.Setenv("MCP_RATE_LIMIT_RPS", "-1")
+	_, err = LoadFromEnv()
+	assert.ErrorContains(t, err, "rate limit must not be negative")
+}
+
+func TestLoadFromEnv_WithClientRateLimit(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_CLIENT_RATE_LIMIT_RPS", "5")
+	t.Setenv("MCP_CLIENT_RATE_LIMIT_BURST", "10")
+	t.Setenv("MCP_CLIENT_LIMITER_IDLE", "30m")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 5.0, cfg.ClientRateLimitRPS)
+	assert.Equal(t, 10, cfg.ClientRateLimitBurst)
+	assert.Equal(t, 30*time.Minute, cfg.ClientLimiterIdle)
+
+	t.Setenv("MCP_CLIENT_RATE_LIMIT_RPS", "-1")
+	_, err = LoadFromEnv()
+	assert.ErrorContains(t, err, "client rate limit must not be negative")
+}
+
+func TestLoadFromEnv_WithSSEReconnect(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_SSE_MAX_RECONNECTS", "5")
+	t.Setenv("MCP_SSE_RECONNECT_DELAY", "500ms")
+	t.Setenv("MCP_SSE_MAX_RECONNECT_DELAY", "10s")
+	t.Setenv("MCP_SSE_HEARTBEAT_TIMEOUT", "2m")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 5, cfg.SSEMaxReconnects)
+	assert.Equal(t, 500*time.Millisecond, cfg.SSERetryDelay)
+	assert.Equal(t, 10*time.Second, cfg.SSEMaxRetryDelay)
+	assert.Equal(t, 2*time.Minute, cfg.SSEHeartbeat)
+
+	t.Setenv("MCP_SSE_MAX_RECONNECTS", "-1")
+	_, err = LoadFromEnv()
+	assert.ErrorContains(t, err, "SSE max reconnects must not be negative")
+}
+
+func TestLoadFromEnv_WithMethodTimeouts(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_TOOL_CALL_TIMEOUT", "5m")
+	t.Setenv("MCP_LIST_TIMEOUT", "2s")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{
+		"tools/call":               5 * time.Minute,
+		"tools/list":               2 * time.Second,
+		"resources/list":           2 * time.Second,
+		"resources/templates/list": 2 * time.Second,
+		"prompts/list":             2 * time.Second,
+	}, cfg.MethodTimeouts)
+}
+
+func TestConfig_SetMethodTimeout(t *testing.T) {
+	base := &Config{}
+	base.SetMethodTimeout(time.Second, "tools/call")
+
+	// Updating a copy must not change the original's timeouts
+	next := *base
+	next.SetMethodTimeout(time.Minute, "tools/call")
+	next.SetMethodTimeout(0, "prompts/get")
+
+	assert.Equal(t, map[string]time.Duration{"tools/call": time.Second}, base.MethodTimeouts)
+	assert.Equal(t, map[string]time.Duration{"tools/call": time.Minute}, next.MethodTimeouts)
+}
+
+func TestValidationErrors(t *testing.T) {
+	cfg := &Config{
+		TargetURL:        "ftp://example.com",
+		ServiceName:      "execute-api",
+		SignatureVersion: "v4",
+	}
+
+	fieldErrs := ValidationErrors(cfg.Validate())
+	require.Len(t, fieldErrs, 2)
+
+	assert.Equal(t, "TargetURL", fieldErrs[0].Field)
+	assert.Equal(t, "ftp://example.com", fieldErrs[0].Value)
+	assert.Equal(t, "set MCP_TARGET_URL to a valid https:// URL", fieldErrs[0].Remediation)
+	assert.Equal(t, "Region", fieldErrs[1].Field)
+	assert.Empty(t, fieldErrs[1].Value)
+	assert.Equal(t, "region is required (set AWS_REGION or --region)", fieldErrs[1].Error())
+
+	assert.Nil(t, ValidationErrors(nil))
+	_, err := LoadFromFile("does-not-exist.json", nil)
+	assert.Empty(t, ValidationErrors(err))
+}
+
+func TestLoadFromEnv_WithAssumeRole(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_ROLE_ARN", "arn:aws-us-gov:iam::123456789012:role/deploy")
+	t.Setenv("MCP_EXTERNAL_ID", "partner-id")
+	t.Setenv("MCP_ROLE_SESSION_NAME", "ci")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "arn:aws-us-gov:iam::123456789012:role/deploy", cfg.RoleARN)
+	assert.Equal(t, "partner-id", cfg.ExternalID)
+	assert.Equal(t, "ci", cfg.RoleSessionName)
+}
+
+func TestLoadFromEnv_WithRoleSessionDurationAndTags(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_ROLE_ARN", "arn:aws:iam::123456789012:role/deploy")
+	t.Setenv("MCP_ROLE_SESSION_DURATION", "4h")
+	t.Setenv("MCP_ROLE_SESSION_TAGS", "team=platform, cost-center=1234,invalid")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 4*time.Hour, cfg.RoleSessionDuration)
+	assert.Equal(t, map[string]string{"team": "platform", "cost-center": "1234"}, cfg.RoleSessionTags)
+}
+
+func TestLoadFromEnv_WithCredentialProcess(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_CREDENTIAL_PROCESS", `vault-aws-creds --role "mcp proxy"`)
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, `vault-aws-creds --role "mcp proxy"`, cfg.CredentialProcess)
+}
+
+func TestLoadFromEnv_WithWebIdentity(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_WEB_IDENTITY_TOKEN_FILE", "/var/run/secrets/token")
+	t.Setenv("MCP_WEB_IDENTITY_ROLE_ARN", "arn:aws:iam::123456789012:role/irsa")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "/var/run/secrets/token", cfg.WebIdentityTokenFile)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/irsa", cfg.WebIdentityRoleARN)
+}
+
+func TestLoadFromEnv_WithConnectionTimeouts(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_DIAL_TIMEOUT", "5s")
+	t.Setenv("MCP_TLS_HANDSHAKE_TIMEOUT", "3s")
+	t.Setenv("MCP_RESPONSE_HEADER_TIMEOUT", "20s")
+	t.Setenv("MCP_IDLE_CONNECTION_TIMEOUT", "1m")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, cfg.DialTimeout)
+	assert.Equal(t, 3*time.Second, cfg.TLSHandshakeTimeout)
+	assert.Equal(t, 20*time.Second, cfg.ResponseHeaderTimeout)
+	assert.Equal(t, time.Minute, cfg.IdleConnectionTimeout)
+
+	t.Setenv("MCP_DIAL_TIMEOUT", "-1s")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connection timeouts must not be negative")
+}
+
+func TestLoadFromEnv_WithPoolLimits(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_MAX_IDLE_CONNS", "50")
+	t.Setenv("MCP_MAX_IDLE_CONNS_PER_HOST", "20")
+	t.Setenv("MCP_MAX_CONNS_PER_HOST", "32")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 50, cfg.MaxIdleConns)
+	assert.Equal(t, 20, cfg.MaxIdleConnsPerHost)
+	assert.Equal(t, 32, cfg.MaxConnsPerHost)
+
+	t.Setenv("MCP_MAX_CONNS_PER_HOST", "-1")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connection pool limits must not be negative")
+}
+
+func TestLoadFromEnv_WithMaxConcurrentRequests(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_MAX_CONCURRENT_REQUESTS", "64")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 64, cfg.MaxConcurrentRequests)
+
+	t.Setenv("MCP_MAX_CONCURRENT_REQUESTS", "-1")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max concurrent requests must not be negative")
+}
+
+func TestLoadFromEnv_WithHTTP2(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_FORCE_HTTP2", "true")
+	t.Setenv("MCP_ALLOW_H2C", "true")
+	t.Setenv("MCP_HTTP2_PING_INTERVAL", "1m")
+	t.Setenv("MCP_HTTP2_PING_TIMEOUT", "5s")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.True(t, cfg.ForceHTTP2)
+	assert.True(t, cfg.AllowH2C)
+	assert.Equal(t, time.Minute, cfg.HTTP2PingInterval)
+	assert.Equal(t, 5*time.Second, cfg.HTTP2PingTimeout)
+
+	t.Setenv("MCP_HTTP2_PING_TIMEOUT", "-5s")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "HTTP/2 ping timeout must not be negative")
+}
+
+func TestLoadFromEnv_WithXRay(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_XRAY", "true")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.True(t, cfg.XRayEnabled)
+}
+
+func TestLoadFromEnv_WithLogForwarding(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_ENABLE_LOG_FORWARDING", "true")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.True(t, cfg.EnableLogForwarding)
+}
+
+func TestLoadFromEnv_WithStatsD(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_STATSD_ADDR", "localhost:8125")
+	t.Setenv("MCP_STATSD_PREFIX", "mcp")
+	t.Setenv("MCP_STATSD_SAMPLE_RATE", "0.5")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:8125", cfg.StatsDAddr)
+	assert.Equal(t, "mcp", cfg.StatsDPrefix)
+	assert.Equal(t, 0.5, cfg.StatsDSampleRate)
+
+	t.Setenv("MCP_STATSD_SAMPLE_RATE", "2")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "StatsD sample rate must be between 0 and 1")
+}
+
+func TestLoadFromEnv_WithShadowTargetURL(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_SHADOW_TARGET_URL", "https://staging.example.com/mcp")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "https://staging.example.com/mcp", cfg.ShadowTargetURL)
+
+	t.Setenv("MCP_SHADOW_TARGET_URL", "ftp://staging.example.com")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "shadow target URL must be an http or https URL")
+}
+
+func TestLoadFromEnv_WithIsolatedSessions(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_ISOLATED_SESSIONS", "true")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.True(t, cfg.IsolatedSessions)
+}
+
+func TestLoadFromEnv_WithPingInterval(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_PING_INTERVAL", "30s")
+	t.Setenv("MCP_PING_TIMEOUT", "5s")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, cfg.PingInterval)
+	assert.Equal(t, 5*time.Second, cfg.PingTimeout)
+}
+
+func TestLoadFromEnv_WithDNSCacheTTL(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_DNS_CACHE_TTL", "30s")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, cfg.DNSCacheTTL)
+
+	t.Setenv("MCP_DNS_CACHE_TTL", "-1s")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "DNS cache TTL must not be negative")
+}
+
+func TestLoadFromEnv_WithResponseCache(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_RESPONSE_CACHE_MAX_ENTRIES", "500")
+	t.Setenv("MCP_RESPONSE_CACHE_TTL", "5m")
+	t.Setenv("MCP_RESPONSE_CACHE_METHODS", "resources/read, prompts/get")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 500, cfg.ResponseCacheMaxEntries)
+	assert.Equal(t, 5*time.Minute, cfg.ResponseCacheTTL)
+	assert.Equal(t, []string{"resources/read", "prompts/get"}, cfg.ResponseCacheMethods)
+
+	t.Setenv("MCP_RESPONSE_CACHE_METHODS", "tools/list")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "responses to tools/list cannot be cached")
+}
+
+func TestLoadFromEnv_WithToolCache(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_TOOL_CACHE_MAX_ENTRIES", "200")
+	t.Setenv("MCP_TOOL_CACHE_TTL", "5m")
+	t.Setenv("MCP_TOOL_CACHE_TOOL_TTLS", "get_weather=30s, search=invalid")
+	t.Setenv("MCP_TOOL_CACHE_EXCLUDE", "create_order, delete_order")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 200, cfg.ToolCacheMaxEntries)
+	assert.Equal(t, 5*time.Minute, cfg.ToolCacheTTL)
+	assert.Equal(t, map[string]time.Duration{"get_weather": 30 * time.Second}, cfg.ToolCacheToolTTLs)
+	assert.Equal(t, []string{"create_order", "delete_order"}, cfg.ToolCacheExclude)
+
+	t.Setenv("MCP_TOOL_CACHE_TOOL_TTLS", "get_weather=-1s")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cache TTL for tool get_weather must not be negative")
+}
+
+func TestLoadFromEnv_WithToolCacheWarmup(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_TOOL_CACHE_MAX_ENTRIES", "100")
+	t.Setenv("MCP_TOOL_CACHE_WARMUP", `[{"tool": "get_weather", "arguments": {"city": "Paris"}}, {"tool": "list_regions"}]`)
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	require.Len(t, cfg.ToolCacheWarmup, 2)
+	assert.Equal(t, "get_weather", cfg.ToolCacheWarmup[0].Tool)
+	assert.JSONEq(t, `{"city": "Paris"}`, string(cfg.ToolCacheWarmup[0].Arguments))
+	assert.Equal(t, WarmupCall{Tool: "list_regions"}, cfg.ToolCacheWarmup[1])
+
+	// Warmup calls need the tool result cache
+	t.Setenv("MCP_TOOL_CACHE_MAX_ENTRIES", "")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tool cache warmup requires the tool result cache")
+
+	// Invalid JSON is ignored
+	t.Setenv("MCP_TOOL_CACHE_WARMUP", "get_weather")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.ToolCacheWarmup)
+}
+
+func TestLoadFromEnv_WithExtraSignedHeaders(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_EXTRA_SIGNED_HEADERS", "X-Tenant-ID, X-API-Version")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"X-Tenant-ID", "X-API-Version"}, cfg.ExtraSignedHeaders)
+
+	t.Setenv("MCP_EXTRA_SIGNED_HEADERS", "user-agent")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the header is never included in SigV4 signatures")
+}
+
+func TestLoadFromEnv_WithSigningHost(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://vpce-0123-abcd.execute-api.us-east-1.vpce.amazonaws.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_SIGNING_HOST", "abc123.execute-api.us-east-1.amazonaws.com")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "abc123.execute-api.us-east-1.amazonaws.com", cfg.SigningHost)
+
+	t.Setenv("MCP_SIGNING_HOST", "https://abc123.execute-api.us-east-1.amazonaws.com/prod")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "signing host must be a host name with an optional port")
+
+	t.Setenv("MCP_SIGNING_HOST", "abc123.execute-api.us-east-1.amazonaws.com")
+	t.Setenv("AWS_SIG_VERSION", "v4a")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "signing host is only supported with signature version 'v4'")
+}
+
+func TestLoadFromEnv_WithRefreshOn401(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.False(t, cfg.RefreshOn401)
+
+	t.Setenv("MCP_REFRESH_ON_401", "true")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.True(t, cfg.RefreshOn401)
+}
+
+func TestLoadFromEnv_WithCredentialSources(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.CredentialSources)
+
+	t.Setenv("MCP_CREDENTIAL_SOURCES", "env, profile")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"env", "profile"}, cfg.CredentialSources)
+
+	t.Setenv("MCP_CREDENTIAL_SOURCES", "env,imds,env")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown credential source 'imds'")
+	assert.Contains(t, err.Error(), "credential source 'env' is listed more than once")
+}
+
+func TestLoadFromEnv_WithEnableTimeline(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.False(t, cfg.EnableTimeline)
+
+	t.Setenv("MCP_ENABLE_TIMELINE", "true")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.True(t, cfg.EnableTimeline)
+}
+
+func TestLoadFromEnv_WithProbeAddr(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_PROBE_ADDR", ":8081")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, ":8081", cfg.ProbeAddr)
+
+	t.Setenv("MCP_PROBE_ADDR", "8081")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid probe address")
+}
+
+func TestLoadFromEnv_WithListenAddr(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.ListenAddr)
+
+	t.Setenv("MCP_LISTEN_ADDR", ":8080")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, ":8080", cfg.ListenAddr)
+
+	t.Setenv("MCP_LISTEN_ADDR", "8080")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid listen address")
+
+	t.Setenv("MCP_LISTEN_ADDR", ":8081")
+	t.Setenv("MCP_PROBE_ADDR", ":8081")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "listen address is also the probe address")
+
+	// Addresses written differently still conflict on the same port
+	t.Setenv("MCP_LISTEN_ADDR", ":8081")
+	t.Setenv("MCP_PROBE_ADDR", "0.0.0.0:8081")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "listen address is also the probe address")
+}
+
+func TestLoadFromEnv_WithNamePrefixes(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_TOOL_NAME_PREFIX", "billing_")
+	t.Setenv("MCP_PROMPT_NAME_PREFIX", "billing.")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "billing_", cfg.ToolNamePrefix)
+	assert.Equal(t, "billing.", cfg.PromptNamePrefix)
+
+	t.Setenv("MCP_TOOL_NAME_PREFIX", "billing/")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `prefix contains invalid character '/'`)
+
+	t.Setenv("MCP_TOOL_NAME_PREFIX", "")
+	t.Setenv("MCP_PROMPT_NAME_PREFIX", strings.Repeat("p", 128))
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "MCP_PROMPT_NAME_PREFIX")
+}
+
+func TestLoadFromEnv_WithForwardResponseHeaders(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Nil(t, cfg.ForwardResponseHeaders)
+
+	t.Setenv("MCP_FORWARD_RESPONSE_HEADERS", "X-Amzn-Requestid, X-Cache,X-RateLimit-Remaining")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"X-Amzn-Requestid", "X-Cache", "X-RateLimit-Remaining"}, cfg.ForwardResponseHeaders)
+
+	t.Setenv("MCP_FORWARD_RESPONSE_HEADERS", "X-Cache,X Amzn")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid response header 'X Amzn'")
+}
+
+func TestLoadFromEnv_WithMetricsAddr(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Empty(t, cfg.MetricsAddr)
+
+	t.Setenv("MCP_METRICS_ADDR", ":9090")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, ":9090", cfg.MetricsAddr)
+
+	t.Setenv("MCP_METRICS_ADDR", "9090")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid metrics address")
+
+	t.Setenv("MCP_METRICS_ADDR", ":8081")
+	t.Setenv("MCP_PROBE_ADDR", ":8081")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "metrics address is also the probe or listen address")
+
+	t.Setenv("MCP_METRICS_ADDR", "127.0.0.1:8080")
+	t.Setenv("MCP_PROBE_ADDR", "")
+	t.Setenv("MCP_LISTEN_ADDR", ":8080")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "metrics address is also the probe or listen address")
+}
+
+func TestLoadFromEnv_WithSSELimits(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://example.com/mcp")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_MAX_SSE_EVENT_BYTES", "1048576")
+	t.Setenv("MCP_MAX_SSE_TOTAL_BYTES", "-1")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1048576), cfg.MaxSSEEventBytes)
+	assert.Equal(t, int64(-1), cfg.MaxSSETotalBytes)
+}
+
+func TestLoadFromEnvWithPrefix(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://default.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_TIMEOUT", "30s")
+	t.Setenv("STAGING_TARGET_URL", "https://staging.example.com")
+	t.Setenv("STAGING_AWS_REGION", "us-west-2")
+	t.Setenv("STAGING_ENABLE_SSE", "true")
+
+	cfg, err := LoadFromEnvWithPrefix("staging_")
+	require.NoError(t, err)
+	assert.Equal(t, "https://staging.example.com", cfg.TargetURL)
+	assert.Equal(t, "us-west-2", cfg.Region)
+	assert.True(t, cfg.EnableSSE)
+
+	// Unset prefixed variables fall back to the unprefixed names
+	assert.Equal(t, "execute-api", cfg.ServiceName)
+	assert.Equal(t, 30*time.Second, cfg.Timeout)
+
+	// Without a prefix only the unprefixed names are read
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "https://default.example.com", cfg.TargetURL)
+	assert.Equal(t, "us-east-1", cfg.Region)
+	assert.False(t, cfg.EnableSSE)
+}
+
+func TestLoadFromEnv_WithBinaryContentTypes(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+	t.Setenv("MCP_BINARY_CONTENT_TYPES", "application/pdf, video/*")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"application/pdf", "video/*"}, cfg.BinaryContentTypes)
+
+	for _, invalid := range []string{"*/*", "image/p*", "pdf"} {
+		t.Setenv("MCP_BINARY_CONTENT_TYPES", invalid)
+		_, err = LoadFromEnv()
+		require.Error(t, err, invalid)
+		assert.Contains(t, err.Error(), "binary content type must be a media type")
+	}
+}
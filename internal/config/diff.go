@@ -0,0 +1,176 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FieldChange describes a configuration field whose value differs between two configs
+type FieldChange struct {
+	// Field is the Config struct field name
+	Field string
+
+	// Old is the formatted previous value
+	Old string
+
+	// New is the formatted updated value
+	New string
+}
+
+// String formats the change as "Field: old -> new", or as "Field: (redacted)"
+// for sensitive fields
+func (c FieldChange) String() string {
+	if c.Sensitive() {
+		return c.Field + ": (redacted)"
+	}
+	return fmt.Sprintf("%s: %q -> %q", c.Field, c.Old, c.New)
+}
+
+// restartRequiredFields lists fields that cannot be changed without restarting the proxy
+var restartRequiredFields = map[string]bool{
+	"TargetURL":                   true,
+	"Region":                      true,
+	"ServiceName":                 true,
+	"SignatureVersion":            true,
+	"Profile":                     true,
+	"RoleARN":                     true,
+	"ExternalID":                  true,
+	"RoleSessionName":             true,
+	"RoleSessionDuration":         true,
+	"RoleSessionTags":             true,
+	"CredentialWarnCheckInterval": true,
+	"CredentialWarnThreshold":     true,
+	"CredentialProcess":           true,
+	"CredentialSources":           true,
+	"VaultAddr":                   true,
+	"VaultPath":                   true,
+	"SSOStartURL":                 true,
+	"SSOAccountID":                true,
+	"SSORoleName":                 true,
+	"SSORegion":                   true,
+	"WebIdentityTokenFile":        true,
+	"WebIdentityRoleARN":          true,
+	"ExtraSignedHeaders":          true,
+	"ForwardResponseHeaders":      true,
+	"SigningHost":                 true,
+	"RefreshOn401":                true,
+	"TLSCAFile":                   true,
+	"ClientCertFile":              true,
+	"ClientKeyFile":               true,
+	"TLSSkipVerify":               true,
+	"ProxyURL":                    true,
+	"StripPathPrefix":             true,
+	"PathRewrite":                 true,
+	"MaxRequestBody":              true,
+	"MaxResponseBody":             true,
+	"MaxSSEEventBytes":            true,
+	"MaxSSETotalBytes":            true,
+	"GzipRequests":                true,
+	"GzipResponses":               true,
+	"BinaryContentTypes":          true,
+	"TCPKeepAlive":                true,
+	"TCPProbeInterval":            true,
+	"TCPProbeCount":               true,
+	"MaxIdleConns":                true,
+	"MaxIdleConnsPerHost":         true,
+	"MaxConnsPerHost":             true,
+	"MaxConcurrentRequests":       true,
+	"ForceHTTP2":                  true,
+	"AllowH2C":                    true,
+	"HTTP2PingInterval":           true,
+	"HTTP2PingTimeout":            true,
+	"InjectRequestID":             true,
+	"XRayEnabled":                 true,
+	"DebugMode":                   true,
+	"EnableTimeline":              true,
+	"DryRun":                      true,
+	"FallbackURLs":                true,
+	"ShadowTargetURL":             true,
+	"FailoverTimeout":             true,
+	"DialTimeout":                 true,
+	"TLSHandshakeTimeout":         true,
+	"ResponseHeaderTimeout":       true,
+	"IdleConnectionTimeout":       true,
+	"DNSCacheTTL":                 true,
+	"SSEMaxReconnects":            true,
+	"SSERetryDelay":               true,
+	"SSEMaxRetryDelay":            true,
+	"SSEHeartbeat":                true,
+	"SkipHealthCheck":             true,
+	"SkipIdentityValidation":      true,
+	"HealthCheckPath":             true,
+	"ProbeAddr":                   true,
+	"MetricsAddr":                 true,
+	"ListenAddr":                  true,
+	"ToolNamePrefix":              true,
+	"PromptNamePrefix":            true,
+	"RefreshInterval":             true,
+	"PingInterval":                true,
+	"PingTimeout":                 true,
+	"EnableSampling":              true,
+	"EnableRoots":                 true,
+	"EnableLogForwarding":         true,
+	"IsolatedSessions":            true,
+	"ValidateToolArguments":       true,
+	"FeatureFlags":                true,
+	"EnableDeduplication":         true,
+	"ResponseCacheMaxEntries":     true,
+	"ResponseCacheTTL":            true,
+	"ResponseCacheMethods":        true,
+	"ToolCacheMaxEntries":         true,
+	"ToolCacheTTL":                true,
+	"ToolCacheToolTTLs":           true,
+	"ToolCacheExclude":            true,
+	"ToolCacheWarmup":             true,
+	"ClientRateLimitRPS":          true,
+	"ClientRateLimitBurst":        true,
+	"ClientLimiterIdle":           true,
+	"DrainTimeout":                true,
+	"AuditLogPath":                true,
+	"StatsDAddr":                  true,
+	"StatsDPrefix":                true,
+	"StatsDSampleRate":            true,
+	"MethodTimeouts":              true,
+}
+
+// RequiresRestart reports whether the changed field only takes effect after a restart
+func (c FieldChange) RequiresRestart() bool {
+	return restartRequiredFields[c.Field]
+}
+
+// Sensitive reports whether the changed field's values may hold secrets, such
+// as API keys in headers, and must not be logged
+func (c FieldChange) Sensitive() bool {
+	return sensitiveFields[c.Field]
+}
+
+// Diff returns the fields whose values differ between oldCfg and newCfg, in
+// struct field order. A nil config is treated as the zero value.
+func Diff(oldCfg, newCfg *Config) []FieldChange {
+	if oldCfg == nil {
+		oldCfg = &Config{}
+	}
+	if newCfg == nil {
+		newCfg = &Config{}
+	}
+
+	oldValue := reflect.ValueOf(*oldCfg)
+	newValue := reflect.ValueOf(*newCfg)
+	fields := oldValue.Type()
+
+	var changes []FieldChange
+	for i := 0; i < fields.NumField(); i++ {
+		before := oldValue.Field(i).Interface()
+		after := newValue.Field(i).Interface()
+		if reflect.DeepEqual(before, after) {
+			continue
+		}
+		changes = append(changes, FieldChange{
+			Field: fields.Field(i).Name,
+			Old:   fmt.Sprint(before),
+			New:   fmt.Sprint(after),
+		})
+	}
+
+	return changes
+}
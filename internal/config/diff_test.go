@@ -0,0 +1,81 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	base := Config{
+		TargetURL:        "https://example.com",
+		Region:           "us-east-1",
+		ServiceName:      "execute-api",
+		SignatureVersion: "v4",
+		Timeout:          10 * time.Second,
+	}
+
+	tests := []struct {
+		name        string
+		modify      func(c *Config)
+		wantChanges []FieldChange
+		wantRestart []bool
+	}{
+		{
+			name:        "identical configs",
+			modify:      func(c *Config) {},
+			wantChanges: nil,
+		},
+		{
+			name: "reloadable fields",
+			modify: func(c *Config) {
+				c.Headers = "X-Team=platform"
+				c.Timeout = 30 * time.Second
+			},
+			wantChanges: []FieldChange{
+				{Field: "Headers", Old: "", New: "X-Team=platform"},
+				{Field: "Timeout", Old: "10s", New: "30s"},
+			},
+			wantRestart: []bool{false, false},
+		},
+		{
+			name: "restart required fields",
+			modify: func(c *Config) {
+				c.TargetURL = "https://other.example.com"
+				c.Region = "eu-west-1"
+			},
+			wantChanges: []FieldChange{
+				{Field: "TargetURL", Old: "https://example.com", New: "https://other.example.com"},
+				{Field: "Region", Old: "us-east-1", New: "eu-west-1"},
+			},
+			wantRestart: []bool{true, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := base
+			tt.modify(&next)
+
+			changes := Diff(&base, &next)
+			assert.Equal(t, tt.wantChanges, changes)
+			for i, change := range changes {
+				assert.Equal(t, tt.wantRestart[i], change.RequiresRestart(), change.Field)
+			}
+		})
+	}
+}
+
+func TestDiff_NilConfig(t *testing.T) {
+	changes := Diff(nil, &Config{Region: "us-east-1"})
+	assert.Equal(t, []FieldChange{{Field: "Region", Old: "", New: "us-east-1"}}, changes)
+	assert.Equal(t, `Region: "" -> "us-east-1"`, changes[0].String())
+}
+
+func TestFieldChange_Sensitive(t *testing.T) {
+	change := FieldChange{Field: "Headers", Old: "Authorization=Bearer old", New: "Authorization=Bearer new"}
+	assert.True(t, change.Sensitive())
+	assert.Equal(t, "Headers: (redacted)", change.String())
+	assert.False(t, FieldChange{Field: "Timeout"}.Sensitive())
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Configuration sources reported by Builder.Source
+const (
+	SourceDefault      = "default"
+	SourceEnv          = "env"
+	SourceFile         = "file"
+	SourceConfigSource = "config_source"
+	SourceFlag         = "flag"
+)
+
+// sensitiveFields lists fields left out of AsMap because their values may
+// carry credentials, such as an Authorization header
+var sensitiveFields = map[string]bool{
+	"Headers": true,
+}
+
+// fieldKeys names the fields that cannot be set from a configuration file
+var fieldKeys = map[string]string{
+	"ConfigFile":               "config_file",
+	"ConfigSource":             "config_source",
+	"ConfigSourcePollInterval": "config_source_poll_interval",
+}
+
+// fieldKey returns the key of a Config field in AsMap, which is its
+// configuration file key
+func fieldKey(field string) string {
+	if key, ok := fieldKeys[field]; ok {
+		return key
+	}
+	if f, ok := reflect.TypeFor[fileConfig]().FieldByName(field); ok {
+		key, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		return key
+	}
+	return field
+}
+
+// AsMap returns every field that is not sensitive as a string, keyed by its
+// configuration file key. Lists and maps are comma delimited, as in
+// environment variables, and durations use time.Duration formatting.
+func (c *Config) AsMap() map[string]string {
+	value := reflect.ValueOf(*c)
+	fields := value.Type()
+
+	values := make(map[string]string, fields.NumField())
+	for i := range fields.NumField() {
+		name := fields.Field(i).Name
+		if sensitiveFields[name] {
+			continue
+		}
+		values[fieldKey(name)] = formatField(value.Field(i).Interface())
+	}
+	return values
+}
+
+// formatField formats a Config field value for AsMap
+func formatField(v any) string {
+	switch v := v.(type) {
+	case []string:
+		return strings.Join(v, ",")
+	case map[string]string:
+		return formatPairs(v)
+	case map[string]time.Duration:
+		pairs := make(map[string]string, len(v))
+		for k, d := range v {
+			pairs[k] = d.String()
+		}
+		return formatPairs(pairs)
+	case map[string]bool:
+		pairs := make(map[string]string, len(v))
+		for k, enabled := range v {
+			pairs[k] = strconv.FormatBool(enabled)
+		}
+		return formatPairs(pairs)
+	case []WarmupCall:
+		if len(v) == 0 {
+			return ""
+		}
+		data, _ := json.Marshal(v)
+		return string(data)
+	case PathRewrite:
+		if v.Pattern == "" {
+			return ""
+		}
+		return v.Pattern + " -> " + v.Replacement
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// formatPairs formats a map as comma delimited key=value pairs sorted by key
+func formatPairs(m map[string]string) string {
+	pairs := make([]string, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, k+"="+v)
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, ",")
+}
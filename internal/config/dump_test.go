@@ -0,0 +1,38 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_AsMap(t *testing.T) {
+	cfg := &Config{
+		TargetURL:       "https://test.example.com",
+		Profile:         "staging",
+		Headers:         "Authorization=Bearer secret",
+		Timeout:         30 * time.Second,
+		FallbackURLs:    []string{"https://a.example.com", "https://b.example.com"},
+		RoleSessionTags: map[string]string{"team": "platform", "env": "prod"},
+		PathRewrite:     PathRewrite{Pattern: "^/v1/", Replacement: "/"},
+		ConfigFile:      "proxy.json",
+	}
+	cfg.SetMethodTimeout(5*time.Minute, "tools/call")
+
+	values := cfg.AsMap()
+	assert.Equal(t, "https://test.example.com", values["target_url"])
+	assert.Equal(t, "staging", values["profile"])
+	assert.Equal(t, "30s", values["timeout"])
+	assert.Equal(t, "https://a.example.com,https://b.example.com", values["fallback_urls"])
+	assert.Equal(t, "env=prod,team=platform", values["role_session_tags"])
+	assert.Equal(t, "^/v1/ -> /", values["path_rewrite"])
+	assert.Equal(t, "tools/call=5m0s", values["method_timeouts"])
+	assert.Equal(t, "proxy.json", values["config_file"])
+	assert.Equal(t, "false", values["sse"])
+
+	// Sensitive fields are left out
+	assert.NotContains(t, values, "headers")
+	assert.Len(t, values, reflect.TypeFor[Config]().NumField()-len(sensitiveFields))
+}
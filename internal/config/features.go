@@ -0,0 +1,112 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Feature flags gate experimental features, which stay off until their flag
+// is enabled even when their own options are set
+const (
+	// FeatureDeduplication gates EnableDeduplication
+	FeatureDeduplication = "deduplication"
+
+	// FeatureResponseCache gates the response cache (ResponseCacheMaxEntries)
+	FeatureResponseCache = "response-cache"
+)
+
+// featureFlagDefaults maps each supported feature flag to its default value
+var featureFlagDefaults = map[string]bool{
+	FeatureDeduplication: false,
+	FeatureResponseCache: false,
+}
+
+// featureEnvPrefix starts the name of the environment variable of each
+// feature flag, such as MCP_FEATURE_RESPONSE_CACHE for "response-cache"
+const featureEnvPrefix = "MCP_FEATURE_"
+
+// ListFeatureFlags returns every supported feature flag with its default value.
+func ListFeatureFlags() map[string]bool {
+	return maps.Clone(featureFlagDefaults)
+}
+
+// FeatureEnabled reports whether the named feature flag is on, falling back to
+// its default when it is not set.
+func (c *Config) FeatureEnabled(name string) bool {
+	if enabled, ok := c.FeatureFlags[name]; ok {
+		return enabled
+	}
+	return featureFlagDefaults[name]
+}
+
+// ActiveFeatureFlags returns the names of the feature flags that are on, sorted.
+func (c *Config) ActiveFeatureFlags() []string {
+	var active []string
+	for name := range featureFlagDefaults {
+		if c.FeatureEnabled(name) {
+			active = append(active, name)
+		}
+	}
+	slices.Sort(active)
+	return active
+}
+
+// featureFlagName converts the part of a feature flag's environment variable
+// name after MCP_FEATURE_ to the flag name, such as RESPONSE_CACHE to
+// "response-cache"
+func featureFlagName(suffix string) string {
+	return strings.ReplaceAll(strings.ToLower(suffix), "_", "-")
+}
+
+// getFeatureFlags reads every MCP_FEATURE_<NAME> variable, and its prefixed
+// name when a prefix is set, which takes precedence. Values that are not
+// booleans are logged and ignored.
+func (e environment) getFeatureFlags() map[string]bool {
+	prefixes := []string{featureEnvPrefix}
+	if e.prefix != "" {
+		prefixes = append(prefixes, e.prefix+"_"+strings.TrimPrefix(featureEnvPrefix, "MCP_"))
+	}
+
+	var flags map[string]bool
+	for _, prefix := range prefixes {
+		for _, entry := range os.Environ() {
+			key, value, _ := strings.Cut(entry, "=")
+			suffix, ok := strings.CutPrefix(key, prefix)
+			if !ok || suffix == "" {
+				continue
+			}
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				slog.Warn("ignoring invalid environment variable", "name", key, "error", err)
+				continue
+			}
+			if flags == nil {
+				flags = make(map[string]bool)
+			}
+			flags[featureFlagName(suffix)] = enabled
+		}
+	}
+	return flags
+}
+
+// validateFeatureFlags checks that every feature flag set is supported
+func (c *Config) validateFeatureFlags() []error {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(c.FeatureFlags)) {
+		if _, ok := featureFlagDefaults[name]; ok {
+			continue
+		}
+		errs = append(errs, &FieldError{
+			Field:       "FeatureFlags",
+			Value:       name,
+			Problem:     fmt.Sprintf("unknown feature flag '%s'", name),
+			Remediation: fmt.Sprintf("use one of the supported feature flags: %s", strings.Join(slices.Sorted(maps.Keys(featureFlagDefaults)), ", ")),
+		})
+	}
+	return errs
+}
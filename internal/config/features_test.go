@@ -0,0 +1,65 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListFeatureFlags(t *testing.T) {
+	flags := ListFeatureFlags()
+	assert.Equal(t, map[string]bool{
+		FeatureDeduplication: false,
+		FeatureResponseCache: false,
+	}, flags)
+
+	// The defaults cannot be changed through the returned map
+	flags[FeatureDeduplication] = true
+	assert.False(t, ListFeatureFlags()[FeatureDeduplication])
+}
+
+func TestLoadFromEnv_WithFeatureFlags(t *testing.T) {
+	t.Setenv("MCP_TARGET_URL", "https://test.example.com")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("AWS_SERVICE_NAME", "execute-api")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+	assert.False(t, cfg.FeatureEnabled(FeatureResponseCache))
+	assert.Empty(t, cfg.ActiveFeatureFlags())
+
+	t.Setenv("MCP_FEATURE_RESPONSE_CACHE", "true")
+	t.Setenv("MCP_FEATURE_DEDUPLICATION", "not-a-bool")
+	cfg, err = LoadFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{FeatureResponseCache: true}, cfg.FeatureFlags)
+	assert.True(t, cfg.FeatureEnabled(FeatureResponseCache))
+	assert.False(t, cfg.FeatureEnabled(FeatureDeduplication), "invalid values are ignored")
+	assert.Equal(t, []string{FeatureResponseCache}, cfg.ActiveFeatureFlags())
+
+	// Prefixed variables take precedence
+	t.Setenv("STAGING_FEATURE_RESPONSE_CACHE", "false")
+	cfg, err = LoadFromEnvWithPrefix("STAGING")
+	require.NoError(t, err)
+	assert.False(t, cfg.FeatureEnabled(FeatureResponseCache))
+
+	t.Setenv("MCP_FEATURE_ADAPTIVE_TIMEOUT", "true")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown feature flag 'adaptive-timeout'")
+}
+
+func TestLoadFromFile_FeatureFlags(t *testing.T) {
+	path := writeConfigFile(t, `{
+		"target_url": "https://file.example.com",
+		"region": "us-west-2",
+		"service_name": "lambda",
+		"feature_flags": {"deduplication": true}
+	}`)
+
+	cfg, err := LoadFromFile(path, nil)
+	require.NoError(t, err)
+	assert.True(t, cfg.FeatureEnabled(FeatureDeduplication))
+	assert.False(t, cfg.FeatureEnabled(FeatureResponseCache))
+}
@@ -0,0 +1,532 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// fileConfig is the JSON representation of a configuration file.
+// Pointer fields distinguish keys that are absent from keys set to their zero value.
+type fileConfig struct {
+	TargetURL                   *string           `json:"target_url"`
+	Region                      *string           `json:"region"`
+	ServiceName                 *string           `json:"service_name"`
+	SignatureVersion            *string           `json:"sig_version"`
+	Profile                     *string           `json:"profile"`
+	RoleARN                     *string           `json:"role_arn"`
+	ExternalID                  *string           `json:"external_id"`
+	RoleSessionName             *string           `json:"role_session_name"`
+	RoleSessionDuration         *string           `json:"role_session_duration"`
+	RoleSessionTags             map[string]string `json:"role_session_tags"`
+	CredentialWarnCheckInterval *string           `json:"credential_warn_check_interval"`
+	CredentialWarnThreshold     *string           `json:"credential_warn_threshold"`
+	CredentialProcess           *string           `json:"credential_process"`
+	CredentialSources           *[]string         `json:"credential_sources"`
+	VaultAddr                   *string           `json:"vault_addr"`
+	VaultPath                   *string           `json:"vault_path"`
+	SSOStartURL                 *string           `json:"sso_start_url"`
+	SSOAccountID                *string           `json:"sso_account_id"`
+	SSORoleName                 *string           `json:"sso_role_name"`
+	SSORegion                   *string           `json:"sso_region"`
+	WebIdentityTokenFile        *string           `json:"web_identity_token_file"`
+	WebIdentityRoleARN          *string           `json:"web_identity_role_arn"`
+	Headers                     *string           `json:"headers"`
+	ExtraSignedHeaders          *[]string         `json:"extra_signed_headers"`
+	ForwardResponseHeaders      *[]string         `json:"forward_response_headers"`
+	SigningHost                 *string           `json:"signing_host"`
+	RefreshOn401                *bool             `json:"refresh_on_401"`
+	Timeout                     *string           `json:"timeout"`
+	DialTimeout                 *string           `json:"dial_timeout"`
+	TLSHandshakeTimeout         *string           `json:"tls_handshake_timeout"`
+	ResponseHeaderTimeout       *string           `json:"response_header_timeout"`
+	IdleConnectionTimeout       *string           `json:"idle_connection_timeout"`
+	DNSCacheTTL                 *string           `json:"dns_cache_ttl"`
+	EnableSSE                   *bool             `json:"sse"`
+	TLSCAFile                   *string           `json:"tls_ca_file"`
+	ClientCertFile              *string           `json:"tls_cert_file"`
+	ClientKeyFile               *string           `json:"tls_key_file"`
+	TLSSkipVerify               *bool             `json:"tls_skip_verify"`
+	ProxyURL                    *string           `json:"proxy_url"`
+	StripPathPrefix             *string           `json:"strip_path_prefix"`
+	PathRewrite                 *filePathRewrite  `json:"path_rewrite"`
+	MaxRequestBody              *int64            `json:"max_request_body_bytes"`
+	MaxResponseBody             *int64            `json:"max_response_body_bytes"`
+	MaxSSEEventBytes            *int64            `json:"max_sse_event_bytes"`
+	MaxSSETotalBytes            *int64            `json:"max_sse_total_bytes"`
+	GzipRequests                *bool             `json:"gzip_requests"`
+	GzipResponses               *bool             `json:"gzip_responses"`
+	BinaryContentTypes          *[]string         `json:"binary_content_types"`
+	TCPKeepAlive                *string           `json:"tcp_keepalive"`
+	TCPProbeInterval            *string           `json:"tcp_keepalive_interval"`
+	TCPProbeCount               *int              `json:"tcp_keepalive_count"`
+	MaxIdleConns                *int              `json:"max_idle_conns"`
+	MaxIdleConnsPerHost         *int              `json:"max_idle_conns_per_host"`
+	MaxConnsPerHost             *int              `json:"max_conns_per_host"`
+	MaxConcurrentRequests       *int              `json:"max_concurrent_requests"`
+	ForceHTTP2                  *bool             `json:"force_http2"`
+	AllowH2C                    *bool             `json:"allow_h2c"`
+	HTTP2PingInterval           *string           `json:"http2_ping_interval"`
+	HTTP2PingTimeout            *string           `json:"http2_ping_timeout"`
+	InjectRequestID             *bool             `json:"request_id"`
+	XRayEnabled                 *bool             `json:"xray"`
+	DebugMode                   *bool             `json:"debug"`
+	EnableTimeline              *bool             `json:"enable_timeline"`
+	DryRun                      *bool             `json:"dry_run"`
+	FallbackURLs                *[]string         `json:"fallback_urls"`
+	ShadowTargetURL             *string           `json:"shadow_target_url"`
+	FailoverTimeout             *string           `json:"failover_timeout"`
+	SSEMaxReconnects            *int              `json:"sse_max_reconnects"`
+	SSERetryDelay               *string           `json:"sse_reconnect_delay"`
+	SSEMaxRetryDelay            *string           `json:"sse_max_reconnect_delay"`
+	SSEHeartbeat                *string           `json:"sse_heartbeat_timeout"`
+	RateLimitRPS                *float64          `json:"rate_limit_rps"`
+	RateLimitBurst              *int              `json:"rate_limit_burst"`
+	ClientRateLimitRPS          *float64          `json:"client_rate_limit_rps"`
+	ClientRateLimitBurst        *int              `json:"client_rate_limit_burst"`
+	ClientLimiterIdle           *string           `json:"client_limiter_idle"`
+	SkipHealthCheck             *bool             `json:"skip_health_check"`
+	SkipIdentityValidation      *bool             `json:"skip_identity_check"`
+	HealthCheckPath             *string           `json:"health_check_path"`
+	ProbeAddr                   *string           `json:"probe_addr"`
+	MetricsAddr                 *string           `json:"metrics_addr"`
+	ListenAddr                  *string           `json:"listen_addr"`
+	ToolNamePrefix              *string           `json:"tool_name_prefix"`
+	PromptNamePrefix            *string           `json:"prompt_name_prefix"`
+	RefreshInterval             *string           `json:"refresh_interval"`
+	PingInterval                *string           `json:"ping_interval"`
+	PingTimeout                 *string           `json:"ping_timeout"`
+	EnableSampling              *bool             `json:"sampling"`
+	EnableRoots                 *bool             `json:"roots"`
+	EnableLogForwarding         *bool             `json:"log_forwarding"`
+	IsolatedSessions            *bool             `json:"isolated_sessions"`
+	ValidateToolArguments       *bool             `json:"validate_tool_arguments"`
+	EnableDeduplication         *bool             `json:"deduplicate"`
+	ResponseCacheMaxEntries     *int              `json:"response_cache_max_entries"`
+	ResponseCacheTTL            *string           `json:"response_cache_ttl"`
+	ResponseCacheMethods        *[]string         `json:"response_cache_methods"`
+	ToolCacheMaxEntries         *int              `json:"tool_cache_max_entries"`
+	ToolCacheTTL                *string           `json:"tool_cache_ttl"`
+	ToolCacheExclude            *[]string         `json:"tool_cache_exclude"`
+	ToolCacheWarmup             *[]WarmupCall     `json:"tool_cache_warmup"`
+	DrainTimeout                *string           `json:"drain_timeout"`
+	AuditLogPath                *string           `json:"audit_log"`
+	StatsDAddr                  *string           `json:"statsd_addr"`
+	StatsDPrefix                *string           `json:"statsd_prefix"`
+	StatsDSampleRate            *float64          `json:"statsd_sample_rate"`
+
+	// MethodTimeouts maps MCP method names to durations, such as {"tools/call": "5m"}
+	MethodTimeouts map[string]string `json:"method_timeouts"`
+
+	// ToolCacheToolTTLs maps tool names to cache TTLs, such as {"get_weather": "30s"}
+	ToolCacheToolTTLs map[string]string `json:"tool_cache_tool_ttls"`
+
+	// FeatureFlags maps feature flag names to whether they are on, such as
+	// {"response-cache": true}
+	FeatureFlags map[string]bool `json:"feature_flags"`
+}
+
+// filePathRewrite is the "path_rewrite" object of a configuration file
+type filePathRewrite struct {
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement"`
+}
+
+// LoadFromFile reads a JSON configuration file and applies the keys it contains
+// over a copy of base. Keys that are absent from the file keep their base values.
+// If base is nil, an empty configuration is used. The result is validated before
+// it is returned; on validation failure the merged configuration is still returned
+// alongside the error, matching LoadFromEnv.
+func LoadFromFile(path string, base *Config) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	var fc fileConfig
+	if err := json.Unmarshal(data, &fc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	cfg := &Config{}
+	if base != nil {
+		*cfg = *base
+	}
+	if err := fc.apply(cfg); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	cfg.ConfigFile = path
+
+	// Set default signature version if not specified
+	if cfg.SignatureVersion == "" {
+		cfg.SignatureVersion = "v4"
+	}
+
+	// Set default profile if not specified
+	if cfg.Profile == "" {
+		cfg.Profile = "default"
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+// apply copies every key present in the file onto cfg
+func (fc *fileConfig) apply(cfg *Config) error {
+	setString(&cfg.TargetURL, fc.TargetURL)
+	setString(&cfg.Region, fc.Region)
+	setString(&cfg.ServiceName, fc.ServiceName)
+	setString(&cfg.SignatureVersion, fc.SignatureVersion)
+	setString(&cfg.Profile, fc.Profile)
+	setString(&cfg.RoleARN, fc.RoleARN)
+	setString(&cfg.ExternalID, fc.ExternalID)
+	setString(&cfg.RoleSessionName, fc.RoleSessionName)
+	setString(&cfg.CredentialProcess, fc.CredentialProcess)
+	setString(&cfg.VaultAddr, fc.VaultAddr)
+	setString(&cfg.VaultPath, fc.VaultPath)
+	setString(&cfg.SSOStartURL, fc.SSOStartURL)
+	setString(&cfg.SSOAccountID, fc.SSOAccountID)
+	setString(&cfg.SSORoleName, fc.SSORoleName)
+	setString(&cfg.SSORegion, fc.SSORegion)
+	setString(&cfg.WebIdentityTokenFile, fc.WebIdentityTokenFile)
+	setString(&cfg.WebIdentityRoleARN, fc.WebIdentityRoleARN)
+	setString(&cfg.Headers, fc.Headers)
+	setString(&cfg.TLSCAFile, fc.TLSCAFile)
+	setString(&cfg.ClientCertFile, fc.ClientCertFile)
+	setString(&cfg.ClientKeyFile, fc.ClientKeyFile)
+	setString(&cfg.ProxyURL, fc.ProxyURL)
+	setString(&cfg.StripPathPrefix, fc.StripPathPrefix)
+	if fc.PathRewrite != nil {
+		cfg.PathRewrite = PathRewrite{Pattern: fc.PathRewrite.Pattern, Replacement: fc.PathRewrite.Replacement}
+	}
+	setString(&cfg.HealthCheckPath, fc.HealthCheckPath)
+	setString(&cfg.ProbeAddr, fc.ProbeAddr)
+	setString(&cfg.MetricsAddr, fc.MetricsAddr)
+	setString(&cfg.ListenAddr, fc.ListenAddr)
+	setString(&cfg.ToolNamePrefix, fc.ToolNamePrefix)
+	setString(&cfg.PromptNamePrefix, fc.PromptNamePrefix)
+	setString(&cfg.AuditLogPath, fc.AuditLogPath)
+	setString(&cfg.StatsDAddr, fc.StatsDAddr)
+	setString(&cfg.StatsDPrefix, fc.StatsDPrefix)
+	setBool(&cfg.EnableSSE, fc.EnableSSE)
+	setBool(&cfg.TLSSkipVerify, fc.TLSSkipVerify)
+	setBool(&cfg.ForceHTTP2, fc.ForceHTTP2)
+	setBool(&cfg.AllowH2C, fc.AllowH2C)
+	setBool(&cfg.InjectRequestID, fc.InjectRequestID)
+	setBool(&cfg.XRayEnabled, fc.XRayEnabled)
+	setBool(&cfg.DebugMode, fc.DebugMode)
+	setBool(&cfg.EnableTimeline, fc.EnableTimeline)
+	setBool(&cfg.DryRun, fc.DryRun)
+	setBool(&cfg.SkipHealthCheck, fc.SkipHealthCheck)
+	setBool(&cfg.SkipIdentityValidation, fc.SkipIdentityValidation)
+	setBool(&cfg.EnableSampling, fc.EnableSampling)
+	setBool(&cfg.EnableRoots, fc.EnableRoots)
+	setBool(&cfg.EnableLogForwarding, fc.EnableLogForwarding)
+	setBool(&cfg.IsolatedSessions, fc.IsolatedSessions)
+	setBool(&cfg.ValidateToolArguments, fc.ValidateToolArguments)
+	setBool(&cfg.EnableDeduplication, fc.EnableDeduplication)
+	setBool(&cfg.RefreshOn401, fc.RefreshOn401)
+	setBool(&cfg.GzipRequests, fc.GzipRequests)
+	setBool(&cfg.GzipResponses, fc.GzipResponses)
+	if fc.BinaryContentTypes != nil {
+		cfg.BinaryContentTypes = *fc.BinaryContentTypes
+	}
+
+	if fc.MaxRequestBody != nil {
+		cfg.MaxRequestBody = *fc.MaxRequestBody
+	}
+	if fc.MaxResponseBody != nil {
+		cfg.MaxResponseBody = *fc.MaxResponseBody
+	}
+	if fc.MaxSSEEventBytes != nil {
+		cfg.MaxSSEEventBytes = *fc.MaxSSEEventBytes
+	}
+	if fc.MaxSSETotalBytes != nil {
+		cfg.MaxSSETotalBytes = *fc.MaxSSETotalBytes
+	}
+
+	if fc.TCPKeepAlive != nil {
+		keepAlive, err := time.ParseDuration(*fc.TCPKeepAlive)
+		if err != nil {
+			return fmt.Errorf("invalid TCP keep-alive: %w", err)
+		}
+		cfg.TCPKeepAlive = keepAlive
+	}
+	if fc.TCPProbeInterval != nil {
+		interval, err := time.ParseDuration(*fc.TCPProbeInterval)
+		if err != nil {
+			return fmt.Errorf("invalid TCP keep-alive interval: %w", err)
+		}
+		cfg.TCPProbeInterval = interval
+	}
+	if fc.TCPProbeCount != nil {
+		cfg.TCPProbeCount = *fc.TCPProbeCount
+	}
+	if fc.MaxIdleConns != nil {
+		cfg.MaxIdleConns = *fc.MaxIdleConns
+	}
+	if fc.MaxIdleConnsPerHost != nil {
+		cfg.MaxIdleConnsPerHost = *fc.MaxIdleConnsPerHost
+	}
+	if fc.MaxConnsPerHost != nil {
+		cfg.MaxConnsPerHost = *fc.MaxConnsPerHost
+	}
+	if fc.MaxConcurrentRequests != nil {
+		cfg.MaxConcurrentRequests = *fc.MaxConcurrentRequests
+	}
+
+	if fc.SSEMaxReconnects != nil {
+		cfg.SSEMaxReconnects = *fc.SSEMaxReconnects
+	}
+	if fc.SSERetryDelay != nil {
+		delay, err := time.ParseDuration(*fc.SSERetryDelay)
+		if err != nil {
+			return fmt.Errorf("invalid SSE reconnect delay: %w", err)
+		}
+		cfg.SSERetryDelay = delay
+	}
+	if fc.SSEMaxRetryDelay != nil {
+		delay, err := time.ParseDuration(*fc.SSEMaxRetryDelay)
+		if err != nil {
+			return fmt.Errorf("invalid SSE max reconnect delay: %w", err)
+		}
+		cfg.SSEMaxRetryDelay = delay
+	}
+	if fc.SSEHeartbeat != nil {
+		timeout, err := time.ParseDuration(*fc.SSEHeartbeat)
+		if err != nil {
+			return fmt.Errorf("invalid SSE heartbeat timeout: %w", err)
+		}
+		cfg.SSEHeartbeat = timeout
+	}
+
+	if fc.RateLimitRPS != nil {
+		cfg.RateLimitRPS = *fc.RateLimitRPS
+	}
+	if fc.StatsDSampleRate != nil {
+		cfg.StatsDSampleRate = *fc.StatsDSampleRate
+	}
+	if fc.RateLimitBurst != nil {
+		cfg.RateLimitBurst = *fc.RateLimitBurst
+	}
+	if fc.ClientRateLimitRPS != nil {
+		cfg.ClientRateLimitRPS = *fc.ClientRateLimitRPS
+	}
+	if fc.ClientRateLimitBurst != nil {
+		cfg.ClientRateLimitBurst = *fc.ClientRateLimitBurst
+	}
+	if fc.ClientLimiterIdle != nil {
+		idle, err := time.ParseDuration(*fc.ClientLimiterIdle)
+		if err != nil {
+			return fmt.Errorf("invalid client limiter idle timeout: %w", err)
+		}
+		cfg.ClientLimiterIdle = idle
+	}
+	if fc.CredentialSources != nil {
+		cfg.CredentialSources = *fc.CredentialSources
+	}
+	if fc.ExtraSignedHeaders != nil {
+		cfg.ExtraSignedHeaders = *fc.ExtraSignedHeaders
+	}
+	if fc.ForwardResponseHeaders != nil {
+		cfg.ForwardResponseHeaders = *fc.ForwardResponseHeaders
+	}
+	if fc.SigningHost != nil {
+		cfg.SigningHost = *fc.SigningHost
+	}
+	if fc.FallbackURLs != nil {
+		cfg.FallbackURLs = *fc.FallbackURLs
+	}
+	setString(&cfg.ShadowTargetURL, fc.ShadowTargetURL)
+	if fc.FailoverTimeout != nil {
+		timeout, err := time.ParseDuration(*fc.FailoverTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid failover timeout: %w", err)
+		}
+		cfg.FailoverTimeout = timeout
+	}
+
+	if fc.HTTP2PingInterval != nil {
+		interval, err := time.ParseDuration(*fc.HTTP2PingInterval)
+		if err != nil {
+			return fmt.Errorf("invalid HTTP/2 ping interval: %w", err)
+		}
+		cfg.HTTP2PingInterval = interval
+	}
+	if fc.HTTP2PingTimeout != nil {
+		timeout, err := time.ParseDuration(*fc.HTTP2PingTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid HTTP/2 ping timeout: %w", err)
+		}
+		cfg.HTTP2PingTimeout = timeout
+	}
+	if fc.DialTimeout != nil {
+		timeout, err := time.ParseDuration(*fc.DialTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid dial timeout: %w", err)
+		}
+		cfg.DialTimeout = timeout
+	}
+	if fc.TLSHandshakeTimeout != nil {
+		timeout, err := time.ParseDuration(*fc.TLSHandshakeTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid TLS handshake timeout: %w", err)
+		}
+		cfg.TLSHandshakeTimeout = timeout
+	}
+	if fc.ResponseHeaderTimeout != nil {
+		timeout, err := time.ParseDuration(*fc.ResponseHeaderTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid response header timeout: %w", err)
+		}
+		cfg.ResponseHeaderTimeout = timeout
+	}
+	if fc.IdleConnectionTimeout != nil {
+		timeout, err := time.ParseDuration(*fc.IdleConnectionTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid idle connection timeout: %w", err)
+		}
+		cfg.IdleConnectionTimeout = timeout
+	}
+	if fc.DNSCacheTTL != nil {
+		ttl, err := time.ParseDuration(*fc.DNSCacheTTL)
+		if err != nil {
+			return fmt.Errorf("invalid DNS cache TTL: %w", err)
+		}
+		cfg.DNSCacheTTL = ttl
+	}
+
+	if fc.ResponseCacheMaxEntries != nil {
+		cfg.ResponseCacheMaxEntries = *fc.ResponseCacheMaxEntries
+	}
+	if fc.ResponseCacheTTL != nil {
+		ttl, err := time.ParseDuration(*fc.ResponseCacheTTL)
+		if err != nil {
+			return fmt.Errorf("invalid response cache TTL: %w", err)
+		}
+		cfg.ResponseCacheTTL = ttl
+	}
+	if fc.ResponseCacheMethods != nil {
+		cfg.ResponseCacheMethods = *fc.ResponseCacheMethods
+	}
+	if fc.ToolCacheMaxEntries != nil {
+		cfg.ToolCacheMaxEntries = *fc.ToolCacheMaxEntries
+	}
+	if fc.ToolCacheTTL != nil {
+		ttl, err := time.ParseDuration(*fc.ToolCacheTTL)
+		if err != nil {
+			return fmt.Errorf("invalid tool cache TTL: %w", err)
+		}
+		cfg.ToolCacheTTL = ttl
+	}
+	if len(fc.ToolCacheToolTTLs) > 0 {
+		ttls := make(map[string]time.Duration, len(fc.ToolCacheToolTTLs))
+		for name, value := range fc.ToolCacheToolTTLs {
+			ttl, err := time.ParseDuration(value)
+			if err != nil {
+				return fmt.Errorf("invalid cache TTL for tool %s: %w", name, err)
+			}
+			ttls[name] = ttl
+		}
+		cfg.ToolCacheToolTTLs = ttls
+	}
+	if len(fc.FeatureFlags) > 0 {
+		cfg.FeatureFlags = fc.FeatureFlags
+	}
+	if fc.ToolCacheExclude != nil {
+		cfg.ToolCacheExclude = *fc.ToolCacheExclude
+	}
+	if fc.ToolCacheWarmup != nil {
+		cfg.ToolCacheWarmup = *fc.ToolCacheWarmup
+	}
+
+	if fc.RefreshInterval != nil {
+		interval, err := time.ParseDuration(*fc.RefreshInterval)
+		if err != nil {
+			return fmt.Errorf("invalid refresh interval: %w", err)
+		}
+		cfg.RefreshInterval = interval
+	}
+	if fc.PingInterval != nil {
+		interval, err := time.ParseDuration(*fc.PingInterval)
+		if err != nil {
+			return fmt.Errorf("invalid ping interval: %w", err)
+		}
+		cfg.PingInterval = interval
+	}
+	if fc.PingTimeout != nil {
+		timeout, err := time.ParseDuration(*fc.PingTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid ping timeout: %w", err)
+		}
+		cfg.PingTimeout = timeout
+	}
+
+	for method, value := range fc.MethodTimeouts {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid timeout for method %s: %w", method, err)
+		}
+		cfg.SetMethodTimeout(timeout, method)
+	}
+
+	if fc.DrainTimeout != nil {
+		timeout, err := time.ParseDuration(*fc.DrainTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid drain timeout: %w", err)
+		}
+		cfg.DrainTimeout = timeout
+	}
+
+	if fc.RoleSessionDuration != nil {
+		duration, err := time.ParseDuration(*fc.RoleSessionDuration)
+		if err != nil {
+			return fmt.Errorf("invalid role session duration: %w", err)
+		}
+		cfg.RoleSessionDuration = duration
+	}
+	if fc.RoleSessionTags != nil {
+		cfg.RoleSessionTags = fc.RoleSessionTags
+	}
+
+	if fc.CredentialWarnCheckInterval != nil {
+		interval, err := time.ParseDuration(*fc.CredentialWarnCheckInterval)
+		if err != nil {
+			return fmt.Errorf("invalid credential warn check interval: %w", err)
+		}
+		cfg.CredentialWarnCheckInterval = interval
+	}
+	if fc.CredentialWarnThreshold != nil {
+		threshold, err := time.ParseDuration(*fc.CredentialWarnThreshold)
+		if err != nil {
+			return fmt.Errorf("invalid credential warn threshold: %w", err)
+		}
+		cfg.CredentialWarnThreshold = threshold
+	}
+
+	if fc.Timeout != nil {
+		timeout, err := time.ParseDuration(*fc.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout: %w", err)
+		}
+		cfg.Timeout = timeout
+	}
+
+	return nil
+}
+
+func setString(dst *string, src *string) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+func setBool(dst *bool, src *bool) {
+	if src != nil {
+		*dst = *src
+	}
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o600))
+	return path
+}
+
+func TestLoadFromFile(t *testing.T) {
+	base := &Config{
+		TargetURL:        "https://base.example.com",
+		Region:           "us-east-1",
+		ServiceName:      "execute-api",
+		SignatureVersion: "v4",
+		Profile:          "default",
+		EnableSSE:        true,
+		Timeout:          10 * time.Second,
+	}
+
+	tests := []struct {
+		name     string
+		contents string
+		base     *Config
+		wantErr  string
+		check    func(t *testing.T, cfg *Config)
+	}{
+		{
+			name:     "file values override base",
+			contents: `{"timeout": "30s", "headers": "X-Team=platform", "sse": false}`,
+			base:     base,
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "https://base.example.com", cfg.TargetURL)
+				assert.Equal(t, 30*time.Second, cfg.Timeout)
+				assert.Equal(t, "X-Team=platform", cfg.Headers)
+				assert.False(t, cfg.EnableSSE)
+			},
+		},
+		{
+			name: "complete file without base",
+			contents: `{
+				"target_url": "https://file.example.com",
+				"region": "us-west-2",
+				"service_name": "lambda"
+			}`,
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "https://file.example.com", cfg.TargetURL)
+				assert.Equal(t, "us-west-2", cfg.Region)
+				assert.Equal(t, "lambda", cfg.ServiceName)
+				assert.Equal(t, "v4", cfg.SignatureVersion)
+				assert.Equal(t, "default", cfg.Profile)
+			},
+		},
+		{
+			name:     "rate limit",
+			contents: `{"rate_limit_rps": 10, "rate_limit_burst": 20}`,
+			base:     base,
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, float64(10), cfg.RateLimitRPS)
+				assert.Equal(t, 20, cfg.RateLimitBurst)
+			},
+		},
+		{
+			name:     "web identity",
+			contents: `{"web_identity_token_file": "/var/run/secrets/token", "web_identity_role_arn": "arn:aws:iam::123456789012:role/irsa"}`,
+			base:     base,
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "/var/run/secrets/token", cfg.WebIdentityTokenFile)
+				assert.Equal(t, "arn:aws:iam::123456789012:role/irsa", cfg.WebIdentityRoleARN)
+			},
+		},
+		{
+			name:     "method timeouts",
+			contents: `{"method_timeouts": {"tools/call": "5m"}}`,
+			base:     base,
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, map[string]time.Duration{"tools/call": 5 * time.Minute}, cfg.MethodTimeouts)
+			},
+		},
+		{
+			name:     "invalid method timeout",
+			contents: `{"method_timeouts": {"tools/call": "soon"}}`,
+			base:     base,
+			wantErr:  "invalid timeout for method tools/call",
+		},
+		{
+			name:     "path rewriting",
+			contents: `{"strip_path_prefix": "/mcp", "path_rewrite": {"pattern": "^/v(\\d+)/", "replacement": "/api/v$1/"}}`,
+			base:     base,
+			check: func(t *testing.T, cfg *Config) {
+				assert.Equal(t, "/mcp", cfg.StripPathPrefix)
+				assert.Equal(t, PathRewrite{Pattern: `^/v(\d+)/`, Replacement: "/api/v$1/"}, cfg.PathRewrite)
+			},
+		},
+		{
+			name:     "invalid path rewrite pattern",
+			contents: `{"path_rewrite": {"pattern": "^/v(\\d+"}}`,
+			base:     base,
+			wantErr:  "invalid path rewrite pattern",
+		},
+		{
+			name:     "invalid JSON",
+			contents: `{"timeout": `,
+			base:     base,
+			wantErr:  "failed to parse config file",
+		},
+		{
+			name:     "invalid timeout",
+			contents: `{"timeout": "soon"}`,
+			base:     base,
+			wantErr:  "invalid timeout",
+		},
+		{
+			name:     "validation failure",
+			contents: `{"sig_version": "v5"}`,
+			base:     base,
+			wantErr:  "signature version must be 'v4' or 'v4a'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, tt.contents)
+
+			cfg, err := LoadFromFile(path, tt.base)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, path, cfg.ConfigFile)
+			tt.check(t, cfg)
+		})
+	}
+
+	// The base configuration must not be modified
+	assert.Equal(t, 10*time.Second, base.Timeout)
+	assert.True(t, base.EnableSSE)
+}
+
+func TestLoadFromFile_MissingFile(t *testing.T) {
+	cfg, err := LoadFromFile(filepath.Join(t.TempDir(), "missing.json"), nil)
+	require.Error(t, err)
+	assert.Nil(t, cfg)
+	assert.Contains(t, err.Error(), "failed to read config file")
+}
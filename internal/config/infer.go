@@ -0,0 +1,76 @@
+package config
+
+import (
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// awsRegionPattern matches AWS region names such as us-east-1 and us-gov-west-1
+var awsRegionPattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-\d+$`)
+
+// awsDomains are the domains of AWS service endpoints
+var awsDomains = []string{".amazonaws.com", ".amazonaws.com.cn"}
+
+// InferServiceName returns the AWS service name in the hostname of targetURL,
+// such as execute-api for https://abc123.execute-api.us-east-1.amazonaws.com.
+// The service is the label before the region, or the last label of a global
+// endpoint without a region, such as iam.amazonaws.com. Lambda function URLs
+// on lambda-url.<region>.on.aws infer lambda. It reports false for hostnames
+// that are not AWS endpoints.
+func InferServiceName(targetURL string) (service string, ok bool) {
+	parsed, err := url.Parse(targetURL)
+	if err != nil {
+		return "", false
+	}
+	host := strings.ToLower(parsed.Hostname())
+
+	if strings.HasSuffix(host, ".on.aws") {
+		labels := strings.Split(strings.TrimSuffix(host, ".on.aws"), ".")
+		if len(labels) >= 2 && labels[len(labels)-2] == "lambda-url" {
+			return "lambda", true
+		}
+		return "", false
+	}
+
+	for _, domain := range awsDomains {
+		if !strings.HasSuffix(host, domain) {
+			continue
+		}
+		labels := strings.Split(strings.TrimSuffix(host, domain), ".")
+		last := len(labels) - 1
+		if awsRegionPattern.MatchString(labels[last]) {
+			last--
+		}
+		if last < 0 || labels[last] == "" {
+			return "", false
+		}
+		return labels[last], true
+	}
+	return "", false
+}
+
+// ExtractRegionFromURL returns the AWS region in the hostname of targetURL,
+// such as eu-west-1 for https://abc123.execute-api.eu-west-1.amazonaws.com or
+// https://abc123.lambda-url.eu-west-1.on.aws. It reports false for global
+// endpoints and hostnames that are not AWS endpoints, such as custom domains.
+func ExtractRegionFromURL(targetURL string) (region string, ok bool) {
+	parsed, err := url.Parse(targetURL)
+	if err != nil {
+		return "", false
+	}
+	host := strings.ToLower(parsed.Hostname())
+
+	for _, domain := range slices.Concat(awsDomains, []string{".on.aws"}) {
+		if !strings.HasSuffix(host, domain) {
+			continue
+		}
+		labels := strings.Split(strings.TrimSuffix(host, domain), ".")
+		if last := labels[len(labels)-1]; awsRegionPattern.MatchString(last) {
+			return last, true
+		}
+		return "", false
+	}
+	return "", false
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInferServiceName(t *testing.T) {
+	tests := []struct {
+		name        string
+		targetURL   string
+		wantService string
+		wantOK      bool
+	}{
+		{name: "API Gateway", targetURL: "https://abc123.execute-api.us-east-1.amazonaws.com/prod/mcp", wantService: "execute-api", wantOK: true},
+		{name: "Lambda", targetURL: "https://abc123.lambda.us-west-2.amazonaws.com", wantService: "lambda", wantOK: true},
+		{name: "S3 virtual host", targetURL: "https://bucket.s3.eu-west-1.amazonaws.com/key", wantService: "s3", wantOK: true},
+		{name: "regional endpoint", targetURL: "https://bedrock-agentcore.us-east-1.amazonaws.com", wantService: "bedrock-agentcore", wantOK: true},
+		{name: "GovCloud", targetURL: "https://abc123.execute-api.us-gov-west-1.amazonaws.com", wantService: "execute-api", wantOK: true},
+		{name: "China", targetURL: "https://abc123.execute-api.cn-north-1.amazonaws.com.cn", wantService: "execute-api", wantOK: true},
+		{name: "global endpoint", targetURL: "https://iam.amazonaws.com", wantService: "iam", wantOK: true},
+		{name: "port", targetURL: "https://abc123.execute-api.us-east-1.amazonaws.com:443", wantService: "execute-api", wantOK: true},
+		{name: "Lambda function URL", targetURL: "https://abc123.lambda-url.us-east-1.on.aws/", wantService: "lambda", wantOK: true},
+		{name: "custom domain", targetURL: "https://mcp.example.com", wantOK: false},
+		{name: "region only", targetURL: "https://us-east-1.amazonaws.com", wantOK: false},
+		{name: "other on.aws host", targetURL: "https://example.on.aws", wantOK: false},
+		{name: "empty", targetURL: "", wantOK: false},
+		{name: "invalid", targetURL: "://bad", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, ok := InferServiceName(tt.targetURL)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantService, service)
+		})
+	}
+}
+
+func TestValidate_InfersServiceName(t *testing.T) {
+	cfg := &Config{
+		TargetURL:        "https://abc123.execute-api.us-east-1.amazonaws.com",
+		Region:           "us-east-1",
+		SignatureVersion: "v4",
+	}
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, "execute-api", cfg.ServiceName)
+
+	// A configured service name is not replaced
+	cfg.ServiceName = "lambda"
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, "lambda", cfg.ServiceName)
+}
+
+func TestExtractRegionFromURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		targetURL  string
+		wantRegion string
+		wantOK     bool
+	}{
+		{name: "API Gateway", targetURL: "https://abc123.execute-api.eu-west-1.amazonaws.com/prod/mcp", wantRegion: "eu-west-1", wantOK: true},
+		{name: "regional endpoint", targetURL: "https://bedrock-agentcore.us-east-1.amazonaws.com", wantRegion: "us-east-1", wantOK: true},
+		{name: "GovCloud", targetURL: "https://abc123.execute-api.us-gov-west-1.amazonaws.com", wantRegion: "us-gov-west-1", wantOK: true},
+		{name: "China", targetURL: "https://abc123.execute-api.cn-north-1.amazonaws.com.cn", wantRegion: "cn-north-1", wantOK: true},
+		{name: "port", targetURL: "https://abc123.execute-api.ap-southeast-2.amazonaws.com:443", wantRegion: "ap-southeast-2", wantOK: true},
+		{name: "Lambda function URL", targetURL: "https://abc123.lambda-url.us-west-2.on.aws/", wantRegion: "us-west-2", wantOK: true},
+		{name: "global endpoint", targetURL: "https://iam.amazonaws.com", wantOK: false},
+		{name: "custom domain", targetURL: "https://mcp.example.com", wantOK: false},
+		{name: "empty", targetURL: "", wantOK: false},
+		{name: "invalid", targetURL: "://bad", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			region, ok := ExtractRegionFromURL(tt.targetURL)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantRegion, region)
+		})
+	}
+}
+
+func TestValidate_WarnsOnRegionMismatch(t *testing.T) {
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	cfg := &Config{
+		TargetURL:        "https://abc123.execute-api.eu-west-1.amazonaws.com",
+		Region:           "us-east-1",
+		ServiceName:      "execute-api",
+		SignatureVersion: "v4",
+	}
+	require.NoError(t, cfg.Validate(), "a mismatch does not fail validation")
+	assert.Contains(t, logs.String(), "Configured region 'us-east-1' may not match URL region 'eu-west-1'")
+
+	logs.Reset()
+	cfg.Region = "eu-west-1"
+	require.NoError(t, cfg.Validate())
+	assert.NotContains(t, logs.String(), "may not match")
+
+	cfg.TargetURL = "https://mcp.example.com"
+	cfg.Region = "us-east-1"
+	require.NoError(t, cfg.Validate())
+	assert.NotContains(t, logs.String(), "may not match")
+}
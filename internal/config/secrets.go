@@ -0,0 +1,145 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+)
+
+// SecretsManagerScheme prefixes a config source that reads from AWS Secrets
+// Manager, as in asm://mcp-proxy/prod
+const SecretsManagerScheme = "asm://"
+
+// Secrets Manager staging labels
+const (
+	secretStageCurrent = "AWSCURRENT"
+	secretStagePending = "AWSPENDING"
+)
+
+// secretsManagerAPI is the subset of the Secrets Manager client used to read
+// configuration secrets
+type secretsManagerAPI interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// LoadFromSecretsManager reads a secret from AWS Secrets Manager and applies
+// it over the default configuration. The secret value is a JSON object with
+// the same keys as a configuration file. A version staged as AWSPENDING by a
+// rotation in progress is adopted when it is a valid configuration; otherwise
+// the AWSCURRENT version is used. The result is validated before it is
+// returned; on validation failure the configuration is still returned
+// alongside the error, matching LoadFromFile.
+func LoadFromSecretsManager(ctx context.Context, secretID string, awsCfg aws.Config) (*Config, error) {
+	return loadFromSecretsManager(ctx, secretsmanager.NewFromConfig(awsCfg), secretID, nil)
+}
+
+// loadFromSecretsManager applies the secret over a copy of base, or over an
+// empty configuration when base is nil
+func loadFromSecretsManager(ctx context.Context, client secretsManagerAPI, secretID string, base *Config) (*Config, error) {
+	if pending, err := getSecretValue(ctx, client, secretID, secretStagePending); err == nil {
+		if cfg, err := applySecret(pending, secretID, base); err == nil {
+			return cfg, nil
+		}
+	} else if !isSecretNotFound(err) {
+		return nil, fmt.Errorf("failed to read secret %s: %w", secretID, err)
+	}
+
+	current, err := getSecretValue(ctx, client, secretID, secretStageCurrent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret %s: %w", secretID, err)
+	}
+	return applySecret(current, secretID, base)
+}
+
+// getSecretValue returns the secret version with the staging label stage
+func getSecretValue(ctx context.Context, client secretsManagerAPI, secretID, stage string) (*secretsmanager.GetSecretValueOutput, error) {
+	return client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId:     aws.String(secretID),
+		VersionStage: aws.String(stage),
+	})
+}
+
+// isSecretNotFound reports whether err means the secret or the requested
+// version does not exist
+func isSecretNotFound(err error) bool {
+	var notFound *types.ResourceNotFoundException
+	return errors.As(err, &notFound)
+}
+
+// applySecret decodes the secret's JSON value and applies it over a copy of base
+func applySecret(secret *secretsmanager.GetSecretValueOutput, secretID string, base *Config) (*Config, error) {
+	if secret.SecretString == nil {
+		return nil, fmt.Errorf("secret %s has no string value", secretID)
+	}
+
+	var fc fileConfig
+	if err := json.Unmarshal([]byte(*secret.SecretString), &fc); err != nil {
+		return nil, fmt.Errorf("failed to parse secret %s: %w", secretID, err)
+	}
+
+	cfg := &Config{}
+	if base != nil {
+		*cfg = *base
+	}
+	if err := fc.apply(cfg); err != nil {
+		return nil, fmt.Errorf("invalid secret %s: %w", secretID, err)
+	}
+
+	// Set default signature version if not specified
+	if cfg.SignatureVersion == "" {
+		cfg.SignatureVersion = "v4"
+	}
+
+	// Set default profile if not specified
+	if cfg.Profile == "" {
+		cfg.Profile = "default"
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+// currentSecretVersion returns the version ID staged as AWSCURRENT
+func currentSecretVersion(ctx context.Context, client secretsManagerAPI, secretID string) (string, error) {
+	secret, err := getSecretValue(ctx, client, secretID, secretStageCurrent)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret %s: %w", secretID, err)
+	}
+	return aws.ToString(secret.VersionId), nil
+}
+
+// watchSecretRotation checks the AWSCURRENT version of the secret every
+// interval and calls onRotate when it changes, which is when a rotation
+// succeeds. Failed checks are logged and retried on the next tick.
+func watchSecretRotation(ctx context.Context, client secretsManagerAPI, secretID, version string, interval time.Duration, logger *slog.Logger, onRotate func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current, err := currentSecretVersion(ctx, client, secretID)
+			if err != nil {
+				logger.Warn("failed to check configuration secret for rotation", "secret", secretID, "error", err)
+				continue
+			}
+			if current == version {
+				continue
+			}
+			logger.Info("configuration secret rotated", "secret", secretID, "version", current)
+			version = current
+			onRotate()
+		}
+	}
+}
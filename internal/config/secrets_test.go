@@ -0,0 +1,131 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSecretsManager serves secret versions by staging label
+type fakeSecretsManager struct {
+	mu       sync.Mutex
+	versions map[string]*secretsmanager.GetSecretValueOutput
+}
+
+func (f *fakeSecretsManager) GetSecretValue(ctx context.Context, input *secretsmanager.GetSecretValueInput, _ ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	secret, ok := f.versions[aws.ToString(input.VersionStage)]
+	if !ok {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("version not found")}
+	}
+	return secret, nil
+}
+
+func (f *fakeSecretsManager) stage(label, versionID, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.versions == nil {
+		f.versions = make(map[string]*secretsmanager.GetSecretValueOutput)
+	}
+	f.versions[label] = &secretsmanager.GetSecretValueOutput{VersionId: aws.String(versionID), SecretString: aws.String(value)}
+}
+
+const testSecret = `{"target_url": "https://current.example.com", "region": "us-east-1", "service_name": "execute-api", "timeout": "30s"}`
+
+func TestLoadFromSecretsManager(t *testing.T) {
+	tests := []struct {
+		name          string
+		pending       string
+		wantTargetURL string
+	}{
+		{
+			name:          "current version",
+			wantTargetURL: "https://current.example.com",
+		},
+		{
+			name:          "pending version is adopted",
+			pending:       `{"target_url": "https://rotated.example.com", "region": "us-east-1", "service_name": "execute-api"}`,
+			wantTargetURL: "https://rotated.example.com",
+		},
+		{
+			name:          "invalid pending version is ignored",
+			pending:       `{"target_url": "ftp://rotated.example.com"}`,
+			wantTargetURL: "https://current.example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeSecretsManager{}
+			client.stage(secretStageCurrent, "v1", testSecret)
+			if tt.pending != "" {
+				client.stage(secretStagePending, "v2", tt.pending)
+			}
+
+			cfg, err := loadFromSecretsManager(context.Background(), client, "mcp-proxy/prod", nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTargetURL, cfg.TargetURL)
+			assert.Equal(t, "v4", cfg.SignatureVersion)
+		})
+	}
+}
+
+func TestLoadFromSecretsManager_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		wantErr string
+	}{
+		{name: "missing secret", wantErr: "failed to read secret mcp-proxy/prod"},
+		{name: "not JSON", secret: "target_url=https://example.com", wantErr: "failed to parse secret mcp-proxy/prod"},
+		{name: "invalid duration", secret: `{"timeout": "soon"}`, wantErr: "invalid secret mcp-proxy/prod"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeSecretsManager{}
+			if tt.secret != "" {
+				client.stage(secretStageCurrent, "v1", tt.secret)
+			}
+
+			cfg, err := loadFromSecretsManager(context.Background(), client, "mcp-proxy/prod", nil)
+			require.Error(t, err)
+			assert.Nil(t, cfg)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestWatchSecretRotation(t *testing.T) {
+	client := &fakeSecretsManager{}
+	client.stage(secretStageCurrent, "v1", testSecret)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	rotated := make(chan struct{}, 1)
+	go watchSecretRotation(ctx, client, "mcp-proxy/prod", "v1", 10*time.Millisecond, slog.New(slog.DiscardHandler), func() {
+		rotated <- struct{}{}
+	})
+
+	select {
+	case <-rotated:
+		t.Fatal("onRotate called before the secret rotated")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	client.stage(secretStageCurrent, "v2", testSecret)
+	select {
+	case <-rotated:
+	case <-time.After(time.Second):
+		t.Fatal("onRotate not called after the secret rotated")
+	}
+}
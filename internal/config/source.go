@@ -0,0 +1,85 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// defaultConfigSourcePollInterval is how often an asm:// config source is
+// checked for rotation when ConfigSourcePollInterval is zero
+const defaultConfigSourcePollInterval = 5 * time.Minute
+
+// LoadFromConfigSource applies the configuration held by source over a copy
+// of base. The source is ssm://prefix for AWS Systems Manager Parameter Store
+// or asm://secret-id for AWS Secrets Manager, and is read with the default AWS
+// configuration for the region and profile in base.
+func LoadFromConfigSource(ctx context.Context, source string, base *Config) (*Config, error) {
+	if prefix, ok := strings.CutPrefix(source, ParameterStoreScheme); ok {
+		awsCfg, err := sourceAWSConfig(ctx, source, base)
+		if err != nil {
+			return nil, err
+		}
+		return loadFromParameterStore(ctx, ssm.NewFromConfig(awsCfg), prefix, base)
+	}
+	if secretID, ok := strings.CutPrefix(source, SecretsManagerScheme); ok {
+		awsCfg, err := sourceAWSConfig(ctx, source, base)
+		if err != nil {
+			return nil, err
+		}
+		return loadFromSecretsManager(ctx, secretsmanager.NewFromConfig(awsCfg), secretID, base)
+	}
+	return nil, fmt.Errorf("unsupported config source %q: expected %sprefix or %ssecret-id", source, ParameterStoreScheme, SecretsManagerScheme)
+}
+
+// WatchConfigSource polls an asm:// config source every interval and calls
+// onRotate when a rotated secret version becomes current. Other sources are
+// not watched. It returns once the watch has started; polling stops when ctx
+// is cancelled.
+func WatchConfigSource(ctx context.Context, source string, base *Config, logger *slog.Logger, onRotate func()) error {
+	secretID, ok := strings.CutPrefix(source, SecretsManagerScheme)
+	interval := base.ConfigSourcePollInterval
+	if !ok || interval < 0 {
+		return nil
+	}
+	if interval == 0 {
+		interval = defaultConfigSourcePollInterval
+	}
+
+	awsCfg, err := sourceAWSConfig(ctx, source, base)
+	if err != nil {
+		return err
+	}
+	client := secretsmanager.NewFromConfig(awsCfg)
+	version, err := currentSecretVersion(ctx, client, secretID)
+	if err != nil {
+		return err
+	}
+
+	go watchSecretRotation(ctx, client, secretID, version, interval, logger, onRotate)
+	return nil
+}
+
+// sourceAWSConfig loads the default AWS configuration used to read source,
+// honoring the region and profile in base
+func sourceAWSConfig(ctx context.Context, source string, base *Config) (aws.Config, error) {
+	var opts []func(*awsconfig.LoadOptions) error
+	if base != nil && base.Region != "" {
+		opts = append(opts, awsconfig.WithRegion(base.Region))
+	}
+	if base != nil && base.Profile != "" && base.Profile != "default" {
+		opts = append(opts, awsconfig.WithSharedConfigProfile(base.Profile))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("failed to load AWS configuration for %s: %w", source, err)
+	}
+	return awsCfg, nil
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// ParameterStoreScheme prefixes a config source that reads from AWS Systems
+// Manager Parameter Store, as in ssm:///mcp-proxy/prod/
+const ParameterStoreScheme = "ssm://"
+
+// LoadFromParameterStore reads the parameters under prefix from AWS Systems
+// Manager Parameter Store and applies them over the default configuration.
+// Each parameter name has the prefix stripped and "/" replaced with "_", and
+// names the configuration file key it sets, so /mcp-proxy/prod/target_url
+// sets target_url. SecureString parameters are decrypted. The result is
+// validated before it is returned; on validation failure the configuration is
+// still returned alongside the error, matching LoadFromFile.
+func LoadFromParameterStore(ctx context.Context, prefix string, awsCfg aws.Config) (*Config, error) {
+	return loadFromParameterStore(ctx, ssm.NewFromConfig(awsCfg), prefix, nil)
+}
+
+// loadFromParameterStore applies the parameters under prefix over a copy of
+// base, or over an empty configuration when base is nil
+func loadFromParameterStore(ctx context.Context, client ssm.GetParametersByPathAPIClient, prefix string, base *Config) (*Config, error) {
+	prefix = "/" + strings.Trim(prefix, "/") + "/"
+
+	values := make(map[string]json.RawMessage)
+	paginator := ssm.NewGetParametersByPathPaginator(client, &ssm.GetParametersByPathInput{
+		Path:           aws.String(prefix),
+		Recursive:      aws.Bool(true),
+		WithDecryption: aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read parameters under %s: %w", prefix, err)
+		}
+		for _, param := range page.Parameters {
+			name := strings.ReplaceAll(strings.TrimPrefix(aws.ToString(param.Name), prefix), "/", "_")
+			value, err := parameterValue(name, aws.ToString(param.Value))
+			if err != nil {
+				return nil, fmt.Errorf("invalid parameter %s: %w", aws.ToString(param.Name), err)
+			}
+			if value != nil {
+				values[name] = value
+			}
+		}
+	}
+
+	// Decode through the file representation so parameters and configuration
+	// files accept the same keys and values
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	var fc fileConfig
+	if err := json.Unmarshal(data, &fc); err != nil {
+		return nil, fmt.Errorf("invalid parameters under %s: %w", prefix, err)
+	}
+
+	cfg := &Config{}
+	if base != nil {
+		*cfg = *base
+	}
+	if err := fc.apply(cfg); err != nil {
+		return nil, fmt.Errorf("invalid parameters under %s: %w", prefix, err)
+	}
+
+	// Set default signature version if not specified
+	if cfg.SignatureVersion == "" {
+		cfg.SignatureVersion = "v4"
+	}
+
+	// Set default profile if not specified
+	if cfg.Profile == "" {
+		cfg.Profile = "default"
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+// parameterValue converts a parameter's string value to the JSON value of the
+// configuration file key it names. It returns nil for names that are not
+// configuration keys. String lists are comma delimited, and maps are comma
+// delimited key=value pairs.
+func parameterValue(key, value string) (json.RawMessage, error) {
+	field, ok := fileConfigField(key)
+	if !ok {
+		return nil, nil
+	}
+	if field.Kind() == reflect.Pointer {
+		field = field.Elem()
+	}
+
+	var decoded any
+	switch field.Kind() {
+	case reflect.String:
+		decoded = value
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("expected true or false, got %q", value)
+		}
+		decoded = b
+	case reflect.Int, reflect.Int64, reflect.Float64:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number, got %q", value)
+		}
+		decoded = n
+	case reflect.Slice:
+		decoded = splitList(value)
+	case reflect.Map:
+		pairs := make(map[string]string)
+		for _, entry := range splitList(value) {
+			k, v, ok := strings.Cut(entry, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid entry %q: expected key=value", entry)
+			}
+			pairs[k] = v
+		}
+		decoded = pairs
+	default:
+		return nil, fmt.Errorf("%s cannot be set from a parameter", key)
+	}
+	return json.Marshal(decoded)
+}
+
+// fileConfigField returns the type of the fileConfig field with the JSON key
+func fileConfigField(key string) (reflect.Type, bool) {
+	t := reflect.TypeFor[fileConfig]()
+	for i := range t.NumField() {
+		if tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ","); tag == key {
+			return t.Field(i).Type, true
+		}
+	}
+	return nil, false
+}
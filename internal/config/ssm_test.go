@@ -0,0 +1,135 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeParameterStore serves parameters one page at a time
+type fakeParameterStore struct {
+	pages [][]types.Parameter
+	err   error
+	input *ssm.GetParametersByPathInput
+}
+
+func (f *fakeParameterStore) GetParametersByPath(ctx context.Context, input *ssm.GetParametersByPathInput, _ ...func(*ssm.Options)) (*ssm.GetParametersByPathOutput, error) {
+	f.input = input
+	if f.err != nil {
+		return nil, f.err
+	}
+	page := 0
+	if input.NextToken != nil {
+		page = int(aws.ToString(input.NextToken)[0] - '0')
+	}
+	out := &ssm.GetParametersByPathOutput{Parameters: f.pages[page]}
+	if page+1 < len(f.pages) {
+		out.NextToken = aws.String(string(rune('0' + page + 1)))
+	}
+	return out, nil
+}
+
+func parameter(name, value string) types.Parameter {
+	return types.Parameter{Name: aws.String(name), Value: aws.String(value)}
+}
+
+func TestLoadFromParameterStore(t *testing.T) {
+	store := &fakeParameterStore{pages: [][]types.Parameter{
+		{
+			parameter("/mcp-proxy/prod/target_url", "https://ssm.example.com"),
+			parameter("/mcp-proxy/prod/region", "us-west-2"),
+			parameter("/mcp-proxy/prod/service_name", "execute-api"),
+			parameter("/mcp-proxy/prod/timeout", "45s"),
+		},
+		{
+			parameter("/mcp-proxy/prod/sse", "true"),
+			parameter("/mcp-proxy/prod/max_idle_conns", "20"),
+			parameter("/mcp-proxy/prod/fallback_urls", "https://a.example.com, https://b.example.com"),
+			parameter("/mcp-proxy/prod/role_session_tags", "team=platform,env=prod"),
+			parameter("/mcp-proxy/prod/unknown_key", "ignored"),
+		},
+	}}
+
+	cfg, err := loadFromParameterStore(context.Background(), store, "mcp-proxy/prod", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/mcp-proxy/prod/", aws.ToString(store.input.Path))
+	assert.True(t, aws.ToBool(store.input.WithDecryption))
+	assert.Equal(t, "https://ssm.example.com", cfg.TargetURL)
+	assert.Equal(t, "us-west-2", cfg.Region)
+	assert.Equal(t, 45*time.Second, cfg.Timeout)
+	assert.True(t, cfg.EnableSSE)
+	assert.Equal(t, 20, cfg.MaxIdleConns)
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, cfg.FallbackURLs)
+	assert.Equal(t, map[string]string{"team": "platform", "env": "prod"}, cfg.RoleSessionTags)
+	assert.Equal(t, "v4", cfg.SignatureVersion)
+	assert.Equal(t, "default", cfg.Profile)
+}
+
+func TestLoadFromParameterStore_NestedNames(t *testing.T) {
+	store := &fakeParameterStore{pages: [][]types.Parameter{{
+		parameter("/proxy/target_url", "https://ssm.example.com"),
+		parameter("/proxy/sse/max_reconnects", "5"),
+	}}}
+	base := &Config{Region: "us-east-1", ServiceName: "execute-api"}
+
+	cfg, err := loadFromParameterStore(context.Background(), store, "/proxy/", base)
+	require.NoError(t, err)
+	assert.Equal(t, 5, cfg.SSEMaxReconnects)
+	assert.Equal(t, "us-east-1", cfg.Region, "keys absent from the store keep their base values")
+}
+
+func TestLoadFromParameterStore_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		store   *fakeParameterStore
+		wantErr string
+	}{
+		{
+			name:    "request fails",
+			store:   &fakeParameterStore{err: errors.New("AccessDeniedException")},
+			wantErr: "failed to read parameters under /app/",
+		},
+		{
+			name:    "invalid boolean",
+			store:   &fakeParameterStore{pages: [][]types.Parameter{{parameter("/app/sse", "maybe")}}},
+			wantErr: "invalid parameter /app/sse: expected true or false",
+		},
+		{
+			name:    "invalid duration",
+			store:   &fakeParameterStore{pages: [][]types.Parameter{{parameter("/app/timeout", "soon")}}},
+			wantErr: "invalid parameters under /app/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := loadFromParameterStore(context.Background(), tt.store, "/app", nil)
+			require.Error(t, err)
+			assert.Nil(t, cfg)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoadFromParameterStore_Validates(t *testing.T) {
+	store := &fakeParameterStore{pages: [][]types.Parameter{{parameter("/app/target_url", "ftp://example.com")}}}
+
+	cfg, err := loadFromParameterStore(context.Background(), store, "/app", nil)
+	require.Error(t, err)
+	require.NotNil(t, cfg, "the merged configuration is returned alongside validation errors")
+	assert.Equal(t, "ftp://example.com", cfg.TargetURL)
+}
+
+func TestLoadFromConfigSource_UnsupportedScheme(t *testing.T) {
+	_, err := LoadFromConfigSource(context.Background(), "s3://bucket/config.json", &Config{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported config source")
+}
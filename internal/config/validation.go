@@ -0,0 +1,52 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FieldError describes a configuration field that failed validation
+type FieldError struct {
+	// Field is the Config struct field name
+	Field string
+
+	// Value is the formatted invalid value (empty when the field is missing)
+	Value string
+
+	// Problem describes what is wrong with the value
+	Problem string
+
+	// Remediation suggests how to fix the problem (optional)
+	Remediation string
+}
+
+// Error formats the problem followed by the remediation in parentheses
+func (e *FieldError) Error() string {
+	if e.Remediation == "" {
+		return e.Problem
+	}
+	return fmt.Sprintf("%s (%s)", e.Problem, e.Remediation)
+}
+
+// ValidationErrors returns the field errors contained in an error returned by
+// Validate, Load, LoadFromEnv, or LoadFromFile. Errors that are not field errors
+// are skipped.
+func ValidationErrors(err error) []*FieldError {
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrs []*FieldError
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			fieldErrs = append(fieldErrs, ValidationErrors(e)...)
+		}
+		return fieldErrs
+	}
+
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		fieldErrs = append(fieldErrs, fieldErr)
+	}
+	return fieldErrs
+}
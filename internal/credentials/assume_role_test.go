@@ -0,0 +1,180 @@
+package credentials
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/proxyerr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAssumeRole returns temporary credentials that expire after ttl
+type fakeAssumeRole struct {
+	ttl   time.Duration
+	calls []*sts.AssumeRoleInput
+}
+
+func (f *fakeAssumeRole) AssumeRole(ctx context.Context, params *sts.AssumeRoleInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleOutput, error) {
+	f.calls = append(f.calls, params)
+	return &sts.AssumeRoleOutput{Credentials: &types.Credentials{
+		AccessKeyId:     aws.String("ASIAASSUMEDROLEKEY"),
+		SecretAccessKey: aws.String("assumed-secret"),
+		SessionToken:    aws.String("assumed-token"),
+		Expiration:      aws.Time(time.Now().Add(f.ttl)),
+	}}, nil
+}
+
+func TestProvider_AssumeRole(t *testing.T) {
+	client := &fakeAssumeRole{ttl: time.Hour}
+	p := &Provider{
+		AssumeRoleARN:        "arn:aws:iam::123456789012:role/deploy",
+		AssumeRoleExternalID: "external-id",
+	}
+
+	cache := p.assumeRole(client, DefaultRefreshBeforeExpiry)
+	creds, err := cache.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ASIAASSUMEDROLEKEY", creds.AccessKeyID)
+	assert.Equal(t, "assumed-token", creds.SessionToken)
+
+	require.Len(t, client.calls, 1)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/deploy", aws.ToString(client.calls[0].RoleArn))
+	assert.Equal(t, "external-id", aws.ToString(client.calls[0].ExternalId))
+	assert.Equal(t, DefaultRoleSessionName, aws.ToString(client.calls[0].RoleSessionName))
+	assert.Equal(t, int32(3600), aws.ToInt32(client.calls[0].DurationSeconds))
+	assert.Empty(t, client.calls[0].Tags)
+
+	// Cached credentials are reused until they near expiry
+	_, err = cache.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, client.calls, 1)
+}
+
+func TestProvider_AssumeRoleRefreshesBeforeExpiry(t *testing.T) {
+	// Credentials that expire inside the refresh window are refreshed on every retrieval
+	client := &fakeAssumeRole{ttl: 2 * time.Minute}
+	p := &Provider{AssumeRoleARN: "arn:aws:iam::123456789012:role/deploy", AssumeRoleSessionName: "ci"}
+
+	cache := p.assumeRole(client, 5*time.Minute)
+	for range 2 {
+		_, err := cache.Retrieve(context.Background())
+		require.NoError(t, err)
+	}
+	require.Len(t, client.calls, 2)
+	assert.Equal(t, "ci", aws.ToString(client.calls[1].RoleSessionName))
+	assert.Nil(t, client.calls[0].ExternalId)
+}
+
+func TestProvider_AssumeRoleDurationAndSessionTags(t *testing.T) {
+	client := &fakeAssumeRole{ttl: time.Hour}
+	p := &Provider{
+		AssumeRoleARN:         "arn:aws:iam::210987654321:role/cross-account",
+		AssumeRoleDuration:    4 * time.Hour,
+		AssumeRoleSessionTags: map[string]string{"team": "platform", "env": "prod"},
+	}
+
+	_, err := p.assumeRole(client, DefaultRefreshBeforeExpiry).Retrieve(context.Background())
+	require.NoError(t, err)
+	require.Len(t, client.calls, 1)
+	assert.Equal(t, int32(4*3600), aws.ToInt32(client.calls[0].DurationSeconds))
+
+	// Tags are sent in key order so requests are deterministic
+	require.Len(t, client.calls[0].Tags, 2)
+	assert.Equal(t, "env", aws.ToString(client.calls[0].Tags[0].Key))
+	assert.Equal(t, "prod", aws.ToString(client.calls[0].Tags[0].Value))
+	assert.Equal(t, "team", aws.ToString(client.calls[0].Tags[1].Key))
+	assert.Equal(t, "platform", aws.ToString(client.calls[0].Tags[1].Value))
+}
+
+func TestProvider_AssumeRoleDurationTooLong(t *testing.T) {
+	p := &Provider{
+		Region:             "us-east-1",
+		AssumeRoleARN:      "arn:aws:iam::210987654321:role/cross-account",
+		AssumeRoleDuration: 13 * time.Hour,
+	}
+	_, err := p.LoadConfig(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds the 12h0m0s maximum")
+
+	var proxyErr *proxyerr.ProxyError
+	require.True(t, errors.As(err, &proxyErr))
+	assert.Equal(t, proxyerr.InvalidConfig, proxyErr.Code)
+}
+
+// fakeWebIdentity records the token sent with each AssumeRoleWithWebIdentity call
+type fakeWebIdentity struct {
+	ttl   time.Duration
+	calls []*sts.AssumeRoleWithWebIdentityInput
+}
+
+func (f *fakeWebIdentity) AssumeRoleWithWebIdentity(ctx context.Context, params *sts.AssumeRoleWithWebIdentityInput, optFns ...func(*sts.Options)) (*sts.AssumeRoleWithWebIdentityOutput, error) {
+	f.calls = append(f.calls, params)
+	return &sts.AssumeRoleWithWebIdentityOutput{Credentials: &types.Credentials{
+		AccessKeyId:     aws.String("ASIAWEBIDENTITYKEY"),
+		SecretAccessKey: aws.String("web-identity-secret"),
+		SessionToken:    aws.String("web-identity-token"),
+		Expiration:      aws.Time(time.Now().Add(f.ttl)),
+	}}, nil
+}
+
+func TestProvider_WebIdentityRereadsTokenFile(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("first-token"), 0o600))
+
+	client := &fakeWebIdentity{ttl: 2 * time.Minute}
+	p := &Provider{}
+	cache := p.webIdentityRole(client, tokenFile, "arn:aws:iam::123456789012:role/irsa", 5*time.Minute)
+
+	creds, err := cache.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ASIAWEBIDENTITYKEY", creds.AccessKeyID)
+
+	// The token is rotated in place; the refresh must send the new one
+	require.NoError(t, os.WriteFile(tokenFile, []byte("second-token"), 0o600))
+	_, err = cache.Retrieve(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, client.calls, 2)
+	assert.Equal(t, "first-token", aws.ToString(client.calls[0].WebIdentityToken))
+	assert.Equal(t, "second-token", aws.ToString(client.calls[1].WebIdentityToken))
+	assert.Equal(t, "arn:aws:iam::123456789012:role/irsa", aws.ToString(client.calls[1].RoleArn))
+	assert.Equal(t, DefaultRoleSessionName, aws.ToString(client.calls[1].RoleSessionName))
+}
+
+func TestProvider_WebIdentityFromEnvironment(t *testing.T) {
+	t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", "/var/run/secrets/token")
+	t.Setenv("AWS_ROLE_ARN", "arn:aws:iam::123456789012:role/from-env")
+
+	tokenFile, roleARN := (&Provider{}).webIdentity()
+	assert.Equal(t, "/var/run/secrets/token", tokenFile)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/from-env", roleARN)
+
+	// Explicit fields take precedence over the environment
+	tokenFile, roleARN = (&Provider{
+		WebIdentityTokenFile: "/tmp/token",
+		WebIdentityRoleARN:   "arn:aws:iam::123456789012:role/explicit",
+	}).webIdentity()
+	assert.Equal(t, "/tmp/token", tokenFile)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/explicit", roleARN)
+}
+
+func TestProvider_StaticCredentialsPrecedeWebIdentityEnvironment(t *testing.T) {
+	isolateCredentialEnv(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "env-access-key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "env-secret-key")
+	t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", filepath.Join(t.TempDir(), "token"))
+	t.Setenv("AWS_ROLE_ARN", "arn:aws:iam::123456789012:role/irsa")
+
+	// The default chain tries static environment credentials before web identity
+	creds, err := (&Provider{Region: "us-east-1"}).LoadCredentials(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "env-access-key", creds.AccessKeyID)
+}
@@ -0,0 +1,56 @@
+package credentials
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// Identity is the AWS principal that loaded credentials resolve to. The JSON
+// field names match the output of "aws sts get-caller-identity".
+type Identity struct {
+	// Account is the AWS account ID
+	Account string `json:"Account"`
+
+	// Arn is the ARN of the calling principal
+	Arn string `json:"Arn"`
+
+	// UserID is the unique identifier of the calling principal
+	UserID string `json:"UserId"`
+}
+
+// callerIdentityAPI is the subset of the STS client used to resolve an identity
+type callerIdentityAPI interface {
+	GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
+}
+
+// CallerIdentity loads the AWS config and asks STS which principal its
+// credentials belong to. It requires network access to the STS endpoint.
+func (p *Provider) CallerIdentity(ctx context.Context) (Identity, error) {
+	cfg, err := p.LoadConfig(ctx)
+	if err != nil {
+		return Identity{}, err
+	}
+	return IdentityFromConfig(ctx, cfg)
+}
+
+// IdentityFromConfig asks STS which principal the credentials in cfg belong to
+func IdentityFromConfig(ctx context.Context, cfg aws.Config) (Identity, error) {
+	return getCallerIdentity(ctx, sts.NewFromConfig(cfg))
+}
+
+// getCallerIdentity calls GetCallerIdentity and converts the result
+func getCallerIdentity(ctx context.Context, client callerIdentityAPI) (Identity, error) {
+	out, err := client.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return Identity{}, fmt.Errorf("failed to get caller identity from STS: %w", err)
+	}
+
+	return Identity{
+		Account: aws.ToString(out.Account),
+		Arn:     aws.ToString(out.Arn),
+		UserID:  aws.ToString(out.UserId),
+	}, nil
+}
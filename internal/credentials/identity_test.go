@@ -0,0 +1,47 @@
+package credentials
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSTS returns a fixed GetCallerIdentity result
+type fakeSTS struct {
+	out *sts.GetCallerIdentityOutput
+	err error
+}
+
+func (f *fakeSTS) GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error) {
+	return f.out, f.err
+}
+
+func TestGetCallerIdentity(t *testing.T) {
+	client := &fakeSTS{out: &sts.GetCallerIdentityOutput{
+		Account: aws.String("123456789012"),
+		Arn:     aws.String("arn:aws:sts::123456789012:assumed-role/deploy/session"),
+		UserId:  aws.String("AROAEXAMPLE:session"),
+	}}
+
+	identity, err := getCallerIdentity(context.Background(), client)
+	require.NoError(t, err)
+	assert.Equal(t, Identity{
+		Account: "123456789012",
+		Arn:     "arn:aws:sts::123456789012:assumed-role/deploy/session",
+		UserID:  "AROAEXAMPLE:session",
+	}, identity)
+}
+
+func TestGetCallerIdentity_Error(t *testing.T) {
+	client := &fakeSTS{err: errors.New("access denied")}
+
+	_, err := getCallerIdentity(context.Background(), client)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get caller identity from STS")
+	assert.Contains(t, err.Error(), "access denied")
+}
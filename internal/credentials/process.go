@@ -0,0 +1,157 @@
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// processCredentials is version 1 of the AWS credential process output format
+type processCredentials struct {
+	Version         int        `json:"Version"`
+	AccessKeyID     string     `json:"AccessKeyId"`
+	SecretAccessKey string     `json:"SecretAccessKey"`
+	SessionToken    string     `json:"SessionToken"`
+	Expiration      *time.Time `json:"Expiration"`
+}
+
+// processProvider runs an external command and parses its stdout as AWS
+// credentials, like credential_process in the shared config file
+type processProvider struct {
+	args   []string
+	logger *slog.Logger
+}
+
+// newProcessProvider splits command into arguments, respecting quotes
+func newProcessProvider(command string, logger *slog.Logger) (*processProvider, error) {
+	args, err := splitCommand(command)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credential process: %w", err)
+	}
+	if len(args) == 0 {
+		return nil, errors.New("invalid credential process: command is empty")
+	}
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	return &processProvider{args: args, logger: logger}, nil
+}
+
+// Retrieve runs the credential process and returns the credentials it prints
+func (p *processProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	// Arguments may contain secrets, so only the command name is logged
+	name := p.args[0]
+	p.logger.Debug("running credential process", "command", name)
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, p.args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return aws.Credentials{}, fmt.Errorf("credential process %s failed: %w: %s", name, err, msg)
+		}
+		return aws.Credentials{}, fmt.Errorf("credential process %s failed: %w", name, err)
+	}
+
+	return parseProcessCredentials(stdout.Bytes())
+}
+
+// parseProcessCredentials decodes version 1 credential process output,
+// reporting the line of any JSON error
+func parseProcessCredentials(out []byte) (aws.Credentials, error) {
+	var pc processCredentials
+	if err := json.Unmarshal(out, &pc); err != nil {
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &syntaxErr):
+			return aws.Credentials{}, fmt.Errorf("credential process returned invalid JSON at line %d: %w", lineAt(out, syntaxErr.Offset), err)
+		case errors.As(err, &typeErr):
+			return aws.Credentials{}, fmt.Errorf("credential process returned invalid JSON at line %d: %w", lineAt(out, typeErr.Offset), err)
+		}
+		return aws.Credentials{}, fmt.Errorf("credential process returned invalid JSON: %w", err)
+	}
+
+	if pc.Version != 1 {
+		return aws.Credentials{}, fmt.Errorf("credential process returned unsupported Version %d (expected 1)", pc.Version)
+	}
+	if pc.AccessKeyID == "" || pc.SecretAccessKey == "" {
+		return aws.Credentials{}, errors.New("credential process output is missing AccessKeyId or SecretAccessKey")
+	}
+
+	creds := aws.Credentials{
+		AccessKeyID:     pc.AccessKeyID,
+		SecretAccessKey: pc.SecretAccessKey,
+		SessionToken:    pc.SessionToken,
+		Source:          "CredentialProcess",
+	}
+	if pc.Expiration != nil {
+		creds.CanExpire = true
+		creds.Expires = *pc.Expiration
+	}
+	return creds, nil
+}
+
+// lineAt returns the 1-based line containing byte offset in data
+func lineAt(data []byte, offset int64) int {
+	offset = min(max(offset, 0), int64(len(data)))
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+// splitCommand splits s on whitespace. Single and double quotes group words,
+// and a backslash escapes the next character outside single quotes.
+func splitCommand(s string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range s {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				args = append(args, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			current.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, errors.New("trailing backslash")
+	}
+	if inWord {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,136 @@
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    []string
+		wantErr string
+	}{
+		{name: "plain words", command: "vault-creds  --role deploy", want: []string{"vault-creds", "--role", "deploy"}},
+		{name: "double quotes", command: `op read "op://Private/AWS Key/credential"`, want: []string{"op", "read", "op://Private/AWS Key/credential"}},
+		{name: "single quotes keep backslashes", command: `sh -c 'echo \n'`, want: []string{"sh", "-c", `echo \n`}},
+		{name: "escaped space", command: `/opt/my\ tools/creds`, want: []string{"/opt/my tools/creds"}},
+		{name: "empty quotes", command: `creds ""`, want: []string{"creds", ""}},
+		{name: "unterminated quote", command: `creds "profile`, wantErr: "unterminated \" quote"},
+		{name: "trailing backslash", command: `creds \`, wantErr: "trailing backslash"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitCommand(tt.command)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseProcessCredentials(t *testing.T) {
+	creds, err := parseProcessCredentials([]byte(`{
+  "Version": 1,
+  "AccessKeyId": "ASIAPROCESSKEY",
+  "SecretAccessKey": "process-secret",
+  "SessionToken": "process-token",
+  "Expiration": "2030-01-02T03:04:05Z"
+}`))
+	require.NoError(t, err)
+	assert.Equal(t, "ASIAPROCESSKEY", creds.AccessKeyID)
+	assert.Equal(t, "process-token", creds.SessionToken)
+	assert.True(t, creds.CanExpire)
+	assert.Equal(t, time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC), creds.Expires.UTC())
+
+	// Long-term credentials omit Expiration
+	creds, err = parseProcessCredentials([]byte(`{"Version": 1, "AccessKeyId": "AKIA", "SecretAccessKey": "secret"}`))
+	require.NoError(t, err)
+	assert.False(t, creds.CanExpire)
+}
+
+func TestParseProcessCredentials_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		wantErr string
+	}{
+		{
+			name:    "syntax error",
+			output:  "{\n  \"Version\": 1,\n  \"AccessKeyId\": \"AKIA\" \"SecretAccessKey\": \"secret\"\n}",
+			wantErr: "credential process returned invalid JSON at line 3",
+		},
+		{
+			name:    "wrong type",
+			output:  "{\n  \"Version\": \"1\"\n}",
+			wantErr: "credential process returned invalid JSON at line 2",
+		},
+		{
+			name:    "unsupported version",
+			output:  `{"Version": 2, "AccessKeyId": "AKIA", "SecretAccessKey": "secret"}`,
+			wantErr: "unsupported Version 2",
+		},
+		{
+			name:    "missing secret",
+			output:  `{"Version": 1, "AccessKeyId": "AKIA"}`,
+			wantErr: "missing AccessKeyId or SecretAccessKey",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseProcessCredentials([]byte(tt.output))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestProvider_CredentialProcessRefreshesBeforeExpiry(t *testing.T) {
+	dir := t.TempDir()
+	runs := filepath.Join(dir, "runs")
+
+	// The script records each run and returns credentials that expire inside the refresh window
+	expiration := time.Now().Add(time.Minute).UTC().Format(time.RFC3339)
+	script := filepath.Join(dir, "creds.sh")
+	body := fmt.Sprintf(`#!/bin/sh
+echo "$1" >> %q
+printf '{"Version": 1, "AccessKeyId": "ASIAPROCESSKEY", "SecretAccessKey": "secret", "SessionToken": "token", "Expiration": "%s"}'
+`, runs, expiration)
+	require.NoError(t, os.WriteFile(script, []byte(body), 0o700))
+
+	p := &Provider{Region: "us-east-1", CredentialProcess: fmt.Sprintf("%q 'profile one'", script)}
+	cfg, err := p.LoadConfig(context.Background())
+	require.NoError(t, err)
+
+	creds, err := cfg.Credentials.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ASIAPROCESSKEY", creds.AccessKeyID)
+	assert.Equal(t, "token", creds.SessionToken)
+
+	// LoadConfig validates the credentials, then each retrieval re-runs the process
+	data, err := os.ReadFile(runs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"profile one", "profile one"}, strings.Split(strings.TrimSpace(string(data)), "\n"))
+}
+
+func TestProvider_CredentialProcessFailure(t *testing.T) {
+	p := &Provider{Region: "us-east-1", CredentialProcess: `sh -c 'echo "token expired" >&2; exit 3'`}
+	_, err := p.LoadConfig(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "credential process sh failed")
+	assert.Contains(t, err.Error(), "token expired")
+}
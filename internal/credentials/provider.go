@@ -2,12 +2,38 @@ package credentials
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sso"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/aws-sdk-go-v2/service/sts/types"
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/proxyerr"
 )
 
+// DefaultRefreshBeforeExpiry is how long before they expire temporary
+// credentials are refreshed when Provider.RefreshBeforeExpiry is zero
+const DefaultRefreshBeforeExpiry = 5 * time.Minute
+
+// DefaultAssumeRoleDuration is how long assumed-role credentials last when
+// Provider.AssumeRoleDuration is zero
+const DefaultAssumeRoleDuration = time.Hour
+
+// MaxAssumeRoleDuration is the longest session STS AssumeRole allows
+const MaxAssumeRoleDuration = 12 * time.Hour
+
+// DefaultRoleSessionName names assumed-role sessions when Provider.AssumeRoleSessionName is empty
+const DefaultRoleSessionName = "mcp-sigv4-proxy"
+
 // Provider loads AWS credentials using the SDK's default credential chain.
 // It supports environment variables, shared config files, IAM roles, and profiles.
 type Provider struct {
@@ -16,6 +42,68 @@ type Provider struct {
 
 	// Region is the AWS region (optional, can be loaded from config)
 	Region string
+
+	// CredentialSources replaces the default chain with the listed sources,
+	// tried in order, such as ["env", "profile"] to never fall through to
+	// instance metadata (optional, see SupportedCredentialSources)
+	CredentialSources []string
+
+	// CredentialProcess is a command whose stdout is AWS credential process
+	// JSON, replacing the default chain (optional, e.g. a secret manager CLI)
+	CredentialProcess string
+
+	// Vault retrieves credentials from the HashiCorp Vault AWS secrets engine,
+	// replacing the default chain (optional)
+	Vault *VaultProvider
+
+	// SSOStartURL is the AWS IAM Identity Center (SSO) portal URL. Together with
+	// SSOAccountID, SSORoleName, and SSORegion it replaces the default chain with
+	// the role's credentials, using the token cached by 'aws sso login' (optional)
+	SSOStartURL string
+
+	// SSOAccountID is the AWS account of the SSO role (optional)
+	SSOAccountID string
+
+	// SSORoleName is the permission set role assigned in SSOAccountID (optional)
+	SSORoleName string
+
+	// SSORegion is the region hosting the SSO portal (optional)
+	SSORegion string
+
+	// WebIdentityTokenFile is a file holding an OIDC token (such as a Kubernetes
+	// service account token) exchanged with STS AssumeRoleWithWebIdentity for
+	// WebIdentityRoleARN's credentials, replacing the default chain (optional).
+	// The default chain already honours AWS_WEB_IDENTITY_TOKEN_FILE and
+	// AWS_ROLE_ARN, after static environment credentials.
+	WebIdentityTokenFile string
+
+	// WebIdentityRoleARN is the role assumed with WebIdentityTokenFile (optional)
+	WebIdentityRoleARN string
+
+	// AssumeRoleARN is an IAM role assumed with STS AssumeRole using the
+	// credentials from the default chain (optional)
+	AssumeRoleARN string
+
+	// AssumeRoleExternalID is passed to AssumeRole for roles whose trust policy requires it (optional)
+	AssumeRoleExternalID string
+
+	// AssumeRoleSessionName names the assumed-role session (defaults to DefaultRoleSessionName)
+	AssumeRoleSessionName string
+
+	// AssumeRoleDuration is how long assumed-role credentials last, up to
+	// MaxAssumeRoleDuration (defaults to DefaultAssumeRoleDuration)
+	AssumeRoleDuration time.Duration
+
+	// AssumeRoleSessionTags are attached to the assumed-role session; they
+	// appear in CloudTrail and can be matched by IAM conditions (optional)
+	AssumeRoleSessionTags map[string]string
+
+	// RefreshBeforeExpiry is how long before expiry temporary credentials are
+	// refreshed (0 defaults to DefaultRefreshBeforeExpiry)
+	RefreshBeforeExpiry time.Duration
+
+	// Logger records credential process runs and role assumptions (optional)
+	Logger *slog.Logger
 }
 
 // LoadCredentials loads AWS credentials using the default credential chain.
@@ -27,10 +115,34 @@ type Provider struct {
 // 5. IAM role for ECS tasks
 //
 // If a profile is specified, credentials are loaded from that profile.
+// If CredentialSources is set, only those sources are tried, in their order.
+// If a credential process, Vault path, SSO role, or web identity token file and role are set, they replace the chain;
+// the AWS_WEB_IDENTITY_TOKEN_FILE and AWS_ROLE_ARN variables are left to the default chain.
+// If AssumeRoleARN is set, the chain's credentials are exchanged for the role's.
 // Session tokens are automatically included if present in the credentials.
 func (p *Provider) LoadCredentials(ctx context.Context) (aws.Credentials, error) {
+	cfg, err := p.LoadConfig(ctx)
+	if err != nil {
+		return aws.Credentials{}, err
+	}
+	return cfg.Credentials.Retrieve(ctx)
+}
+
+// LoadConfig loads the full AWS config including credentials.
+// This is useful when you need both credentials and other AWS configuration.
+// The config's Credentials are cached and refreshed RefreshBeforeExpiry ahead
+// of expiry, so signers should retrieve them for each request.
+func (p *Provider) LoadConfig(ctx context.Context) (aws.Config, error) {
 	// Build config options
-	var opts []func(*config.LoadOptions) error
+	refreshWindow := p.RefreshBeforeExpiry
+	if refreshWindow <= 0 {
+		refreshWindow = DefaultRefreshBeforeExpiry
+	}
+	opts := []func(*config.LoadOptions) error{
+		config.WithCredentialsCacheOptions(func(o *aws.CredentialsCacheOptions) {
+			o.ExpiryWindow = refreshWindow
+		}),
+	}
 
 	// Add profile if specified
 	if p.Profile != "" {
@@ -45,54 +157,162 @@ func (p *Provider) LoadCredentials(ctx context.Context) (aws.Credentials, error)
 	// Load AWS config using the default credential chain
 	cfg, err := config.LoadDefaultConfig(ctx, opts...)
 	if err != nil {
-		return aws.Credentials{}, fmt.Errorf("failed to load AWS config: %w", err)
+		return aws.Config{}, proxyerr.Wrap(proxyerr.CredentialError, err, "failed to load AWS config")
+	}
+
+	// Replace the default chain with an explicitly configured credential source
+	switch {
+	case len(p.CredentialSources) > 0:
+		// Try only the listed sources, in order
+		cfg.Credentials, err = p.credentialSources(ctx, cfg, refreshWindow)
+		if err != nil {
+			return aws.Config{}, err
+		}
+	case p.CredentialProcess != "":
+		// Run the external credential process again whenever its credentials near expiry
+		process, err := newProcessProvider(p.CredentialProcess, p.Logger)
+		if err != nil {
+			return aws.Config{}, err
+		}
+		cfg.Credentials = aws.NewCredentialsCache(process, func(o *aws.CredentialsCacheOptions) {
+			o.ExpiryWindow = refreshWindow
+		})
+	case p.Vault != nil:
+		// Request new credentials from Vault before the lease ends
+		cfg.Credentials = aws.NewCredentialsCache(p.Vault, func(o *aws.CredentialsCacheOptions) {
+			o.ExpiryWindow = refreshWindow
+		})
+	case p.ssoConfigured():
+		client := sso.NewFromConfig(cfg, func(o *sso.Options) {
+			o.Region = p.SSORegion
+		})
+		cfg.Credentials = p.ssoRole(client, refreshWindow)
+	case p.webIdentityConfigured():
+		// Exchange a web identity token for the role's temporary credentials
+		cfg.Credentials = p.webIdentityRole(sts.NewFromConfig(cfg), p.WebIdentityTokenFile, p.WebIdentityRoleARN, refreshWindow)
+	}
+
+	// Exchange the chain's credentials for the role's temporary credentials
+	if p.AssumeRoleARN != "" {
+		if p.AssumeRoleDuration > MaxAssumeRoleDuration {
+			return aws.Config{}, proxyerr.New(proxyerr.InvalidConfig, "assume role duration %s exceeds the %s maximum", p.AssumeRoleDuration, MaxAssumeRoleDuration)
+		}
+		cfg.Credentials = p.assumeRole(sts.NewFromConfig(cfg), refreshWindow)
 	}
 
-	// Retrieve credentials
+	// Validate credentials
 	creds, err := cfg.Credentials.Retrieve(ctx)
 	if err != nil {
-		return aws.Credentials{}, fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+		var ssoErr *ssocreds.InvalidTokenError
+		if errors.As(err, &ssoErr) {
+			profile := p.Profile
+			if profile == "" {
+				profile = "default"
+			}
+			return aws.Config{}, proxyerr.Wrap(proxyerr.CredentialError, err, "AWS SSO session expired; run 'aws sso login --profile %s' to renew", profile)
+		}
+
+		switch {
+		case p.AssumeRoleARN != "":
+			return aws.Config{}, proxyerr.Wrap(proxyerr.CredentialError, err, "failed to assume role %s", p.AssumeRoleARN)
+		case len(p.CredentialSources) > 0:
+			return aws.Config{}, proxyerr.Wrap(proxyerr.CredentialError, err, "failed to retrieve AWS credentials from sources %s", strings.Join(p.CredentialSources, ", "))
+		case p.CredentialProcess != "":
+			return aws.Config{}, proxyerr.Wrap(proxyerr.CredentialError, err, "failed to retrieve AWS credentials from credential process")
+		case p.Vault != nil:
+			return aws.Config{}, proxyerr.Wrap(proxyerr.CredentialError, err, "failed to retrieve AWS credentials from vault path %s", p.Vault.VaultPath)
+		case p.ssoConfigured():
+			return aws.Config{}, proxyerr.Wrap(proxyerr.CredentialError, err, "failed to retrieve AWS SSO credentials for role %s in account %s", p.SSORoleName, p.SSOAccountID)
+		case p.webIdentityConfigured():
+			return aws.Config{}, proxyerr.Wrap(proxyerr.CredentialError, err, "failed to assume role %s with web identity token %s", p.WebIdentityRoleARN, p.WebIdentityTokenFile)
+		}
+		return aws.Config{}, proxyerr.Wrap(proxyerr.CredentialError, err, "failed to retrieve AWS credentials")
 	}
 
-	// Validate that we have credentials
 	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
-		return aws.Credentials{}, fmt.Errorf("AWS credentials are incomplete: missing access key or secret key")
+		return aws.Config{}, proxyerr.New(proxyerr.CredentialError, "AWS credentials are incomplete: missing access key or secret key")
 	}
 
-	return creds, nil
-}
+	if p.AssumeRoleARN != "" && p.Logger != nil {
+		p.Logger.Info("assumed IAM role", "role_arn", p.AssumeRoleARN, "session_name", p.roleSessionName())
+	}
 
-// LoadConfig loads the full AWS config including credentials.
-// This is useful when you need both credentials and other AWS configuration.
-func (p *Provider) LoadConfig(ctx context.Context) (aws.Config, error) {
-	// Build config options
-	var opts []func(*config.LoadOptions) error
+	return cfg, nil
+}
 
-	// Add profile if specified
-	if p.Profile != "" {
-		opts = append(opts, config.WithSharedConfigProfile(p.Profile))
+// assumeRole returns a cached provider of AssumeRoleARN's credentials that
+// calls AssumeRole again when they are within refreshWindow of expiry
+func (p *Provider) assumeRole(client stscreds.AssumeRoleAPIClient, refreshWindow time.Duration) *aws.CredentialsCache {
+	duration := p.AssumeRoleDuration
+	if duration <= 0 {
+		duration = DefaultAssumeRoleDuration
 	}
 
-	// Add region if specified
-	if p.Region != "" {
-		opts = append(opts, config.WithRegion(p.Region))
-	}
+	provider := stscreds.NewAssumeRoleProvider(client, p.AssumeRoleARN, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = p.roleSessionName()
+		o.Duration = duration
+		if p.AssumeRoleExternalID != "" {
+			o.ExternalID = aws.String(p.AssumeRoleExternalID)
+		}
+		for _, key := range slices.Sorted(maps.Keys(p.AssumeRoleSessionTags)) {
+			o.Tags = append(o.Tags, types.Tag{Key: aws.String(key), Value: aws.String(p.AssumeRoleSessionTags[key])})
+		}
+	})
+	return aws.NewCredentialsCache(provider, func(o *aws.CredentialsCacheOptions) {
+		o.ExpiryWindow = refreshWindow
+	})
+}
 
-	// Load AWS config using the default credential chain
-	cfg, err := config.LoadDefaultConfig(ctx, opts...)
-	if err != nil {
-		return aws.Config{}, fmt.Errorf("failed to load AWS config: %w", err)
+// roleSessionName returns AssumeRoleSessionName or DefaultRoleSessionName
+func (p *Provider) roleSessionName() string {
+	if p.AssumeRoleSessionName == "" {
+		return DefaultRoleSessionName
 	}
+	return p.AssumeRoleSessionName
+}
 
-	// Validate credentials
-	creds, err := cfg.Credentials.Retrieve(ctx)
-	if err != nil {
-		return aws.Config{}, fmt.Errorf("failed to retrieve AWS credentials: %w", err)
-	}
+// webIdentityConfigured reports whether both web identity fields are set
+func (p *Provider) webIdentityConfigured() bool {
+	return p.WebIdentityTokenFile != "" && p.WebIdentityRoleARN != ""
+}
 
-	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
-		return aws.Config{}, fmt.Errorf("AWS credentials are incomplete: missing access key or secret key")
+// webIdentity returns the web identity token file and role ARN, falling back
+// to the AWS_WEB_IDENTITY_TOKEN_FILE and AWS_ROLE_ARN environment variables.
+// Only the webidentity credential source uses the fallback; otherwise the
+// default chain reads the variables itself.
+func (p *Provider) webIdentity() (tokenFile, roleARN string) {
+	tokenFile = p.WebIdentityTokenFile
+	if tokenFile == "" {
+		tokenFile = os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE")
+	}
+	roleARN = p.WebIdentityRoleARN
+	if roleARN == "" {
+		roleARN = os.Getenv("AWS_ROLE_ARN")
 	}
+	return tokenFile, roleARN
+}
 
-	return cfg, nil
+// webIdentityRole returns a cached provider of roleARN's credentials. The token
+// file is read again on every refresh because it is rotated in place.
+func (p *Provider) webIdentityRole(client stscreds.AssumeRoleWithWebIdentityAPIClient, tokenFile, roleARN string, refreshWindow time.Duration) *aws.CredentialsCache {
+	provider := stscreds.NewWebIdentityRoleProvider(client, roleARN, stscreds.IdentityTokenFile(tokenFile), func(o *stscreds.WebIdentityRoleOptions) {
+		o.RoleSessionName = p.roleSessionName()
+	})
+	return aws.NewCredentialsCache(provider, func(o *aws.CredentialsCacheOptions) {
+		o.ExpiryWindow = refreshWindow
+	})
+}
+
+// ssoConfigured reports whether all four SSO fields are set
+func (p *Provider) ssoConfigured() bool {
+	return p.SSOStartURL != "" && p.SSOAccountID != "" && p.SSORoleName != "" && p.SSORegion != ""
+}
+
+// ssoRole returns a cached provider of the SSO role's credentials. The access
+// token is read from ~/.aws/sso/cache/, where 'aws sso login' stores it.
+func (p *Provider) ssoRole(client ssocreds.GetRoleCredentialsAPIClient, refreshWindow time.Duration) *aws.CredentialsCache {
+	provider := ssocreds.New(client, p.SSOAccountID, p.SSORoleName, p.SSOStartURL)
+	return aws.NewCredentialsCache(provider, func(o *aws.CredentialsCacheOptions) {
+		o.ExpiryWindow = refreshWindow
+	})
 }
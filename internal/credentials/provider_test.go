@@ -2,9 +2,11 @@ package credentials
 
 import (
 	"context"
+	"errors"
 	"os"
 	"testing"
 
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/proxyerr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -67,6 +69,10 @@ func TestProvider_LoadCredentials_MissingCredentials(t *testing.T) {
 	_, err := provider.LoadCredentials(context.Background())
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to retrieve AWS credentials")
+
+	var proxyErr *proxyerr.ProxyError
+	require.True(t, errors.As(err, &proxyErr))
+	assert.Equal(t, proxyerr.CredentialError, proxyErr.Code)
 }
 
 func TestProvider_LoadConfig_FromEnvironment(t *testing.T) {
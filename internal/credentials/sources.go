@@ -0,0 +1,224 @@
+package credentials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/ec2rolecreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/endpointcreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sso"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/proxyerr"
+)
+
+// Credential sources that Provider.CredentialSources can list
+const (
+	// SourceEnv reads AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, and AWS_SESSION_TOKEN
+	SourceEnv = "env"
+
+	// SourceProfile reads the access keys of the shared config profile
+	SourceProfile = "profile"
+
+	// SourceProcess runs Provider.CredentialProcess, or the profile's credential_process
+	SourceProcess = "process"
+
+	// SourceWebIdentity exchanges a web identity token file for a role's credentials
+	SourceWebIdentity = "webidentity"
+
+	// SourceECS requests the ECS or EKS Pod Identity container credentials endpoint
+	SourceECS = "ecs"
+
+	// SourceEC2 requests the EC2 instance metadata service
+	SourceEC2 = "ec2"
+
+	// SourceSSO retrieves the SSO role of Provider's SSO fields, or of the profile
+	SourceSSO = "sso"
+)
+
+// SupportedCredentialSources lists every credential source in the order the
+// SDK's default chain roughly follows
+var SupportedCredentialSources = []string{
+	SourceEnv, SourceProfile, SourceProcess, SourceSSO, SourceWebIdentity, SourceECS, SourceEC2,
+}
+
+// ecsContainerEndpoint is the ECS task metadata host that
+// AWS_CONTAINER_CREDENTIALS_RELATIVE_URI is relative to
+const ecsContainerEndpoint = "http://169.254.170.2"
+
+// sourceChain retrieves credentials from the first of its sources that
+// provides them, in order
+type sourceChain struct {
+	names   []string
+	sources []aws.CredentialsProvider
+	logger  *slog.Logger
+}
+
+// Retrieve tries each source in order, returning the errors of all of them
+// when none provides credentials
+func (c *sourceChain) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	var errs []error
+	for i, source := range c.sources {
+		creds, err := source.Retrieve(ctx)
+		if err == nil {
+			c.logger.Debug("retrieved AWS credentials", "source", c.names[i])
+			return creds, nil
+		}
+		c.logger.Debug("credential source unavailable", "source", c.names[i], "error", err)
+		errs = append(errs, fmt.Errorf("%s: %w", c.names[i], err))
+	}
+	return aws.Credentials{}, fmt.Errorf("no credential source in [%s] provided credentials: %w", strings.Join(c.names, ", "), errors.Join(errs...))
+}
+
+// unavailableSource is a credential source that is not configured
+func unavailableSource(format string, args ...any) aws.CredentialsProvider {
+	err := fmt.Errorf(format, args...)
+	return aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+		return aws.Credentials{}, err
+	})
+}
+
+// credentialSources returns a cached chain of CredentialSources that retrieves
+// credentials again when they are within refreshWindow of expiry
+func (p *Provider) credentialSources(ctx context.Context, cfg aws.Config, refreshWindow time.Duration) (*aws.CredentialsCache, error) {
+	logger := p.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	chain := &sourceChain{logger: logger}
+
+	// The profile is read once for the sources that fall back to it, from the
+	// files named by AWS_SHARED_CREDENTIALS_FILE and AWS_CONFIG_FILE if set
+	profile := p.profile()
+	shared, sharedErr := config.LoadSharedConfigProfile(ctx, profile, func(o *config.LoadSharedConfigOptions) {
+		if env, err := config.NewEnvConfig(); err == nil {
+			if env.SharedCredentialsFile != "" {
+				o.CredentialsFiles = []string{env.SharedCredentialsFile}
+			}
+			if env.SharedConfigFile != "" {
+				o.ConfigFiles = []string{env.SharedConfigFile}
+			}
+		}
+	})
+
+	for _, name := range p.CredentialSources {
+		var source aws.CredentialsProvider
+		switch name {
+		case SourceEnv:
+			source = aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+				env, err := config.NewEnvConfig()
+				if err != nil {
+					return aws.Credentials{}, err
+				}
+				if !env.Credentials.HasKeys() {
+					return aws.Credentials{}, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are not set")
+				}
+				return env.Credentials, nil
+			})
+		case SourceProfile:
+			switch {
+			case sharedErr != nil:
+				source = unavailableSource("failed to load profile %s: %w", profile, sharedErr)
+			case !shared.Credentials.HasKeys():
+				source = unavailableSource("profile %s has no access keys", profile)
+			default:
+				source = aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+					return shared.Credentials, nil
+				})
+			}
+		case SourceProcess:
+			command := p.CredentialProcess
+			if command == "" {
+				command = shared.CredentialProcess
+			}
+			if command == "" {
+				source = unavailableSource("no credential process is configured")
+				break
+			}
+			process, err := newProcessProvider(command, p.Logger)
+			if err != nil {
+				return nil, err
+			}
+			source = process
+		case SourceWebIdentity:
+			tokenFile, roleARN := p.webIdentity()
+			if tokenFile == "" || roleARN == "" {
+				source = unavailableSource("AWS_WEB_IDENTITY_TOKEN_FILE and AWS_ROLE_ARN are not set")
+				break
+			}
+			source = stscreds.NewWebIdentityRoleProvider(sts.NewFromConfig(cfg), roleARN, stscreds.IdentityTokenFile(tokenFile), func(o *stscreds.WebIdentityRoleOptions) {
+				o.RoleSessionName = p.roleSessionName()
+			})
+		case SourceECS:
+			source = ecsSource()
+		case SourceEC2:
+			source = ec2rolecreds.New()
+		case SourceSSO:
+			startURL, accountID, roleName, region := p.SSOStartURL, p.SSOAccountID, p.SSORoleName, p.SSORegion
+			if !p.ssoConfigured() {
+				startURL, accountID, roleName, region = shared.SSOStartURL, shared.SSOAccountID, shared.SSORoleName, shared.SSORegion
+			}
+			if startURL == "" || accountID == "" || roleName == "" || region == "" {
+				source = unavailableSource("no SSO role is configured")
+				break
+			}
+			client := sso.NewFromConfig(cfg, func(o *sso.Options) {
+				o.Region = region
+			})
+			source = ssocreds.New(client, accountID, roleName, startURL)
+		default:
+			return nil, proxyerr.New(proxyerr.InvalidConfig, "unknown credential source %q; use one of %s", name, strings.Join(SupportedCredentialSources, ", "))
+		}
+		chain.names = append(chain.names, name)
+		chain.sources = append(chain.sources, source)
+	}
+
+	return aws.NewCredentialsCache(chain, func(o *aws.CredentialsCacheOptions) {
+		o.ExpiryWindow = refreshWindow
+	}), nil
+}
+
+// ecsSource returns the container credentials endpoint source, configured by
+// the same environment variables the SDK's default chain reads
+func ecsSource() aws.CredentialsProvider {
+	endpoint := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI")
+	if path := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); path != "" {
+		endpoint = ecsContainerEndpoint + path
+	}
+	if endpoint == "" {
+		return unavailableSource("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI and AWS_CONTAINER_CREDENTIALS_FULL_URI are not set")
+	}
+
+	return endpointcreds.New(endpoint, func(o *endpointcreds.Options) {
+		o.AuthorizationToken = os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN")
+		if tokenFile := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"); tokenFile != "" {
+			// EKS Pod Identity rotates the token file in place
+			o.AuthorizationTokenProvider = endpointcreds.TokenProviderFunc(func() (string, error) {
+				token, err := os.ReadFile(tokenFile)
+				if err != nil {
+					return "", fmt.Errorf("failed to read authorization token from %s: %w", tokenFile, err)
+				}
+				return strings.TrimSpace(string(token)), nil
+			})
+		}
+	})
+}
+
+// profile returns Profile, falling back to AWS_PROFILE and then "default"
+func (p *Provider) profile() string {
+	if p.Profile != "" {
+		return p.Profile
+	}
+	if profile := os.Getenv("AWS_PROFILE"); profile != "" {
+		return profile
+	}
+	return "default"
+}
@@ -0,0 +1,103 @@
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/proxyerr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// isolateCredentialEnv clears the credential environment and points the
+// shared config files at a temporary directory holding a default profile
+func isolateCredentialEnv(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	credentialsFile := filepath.Join(dir, "credentials")
+	require.NoError(t, os.WriteFile(credentialsFile, []byte("[default]\naws_access_key_id = profile-access-key\naws_secret_access_key = profile-secret-key\n"), 0o600))
+
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", credentialsFile)
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	for _, name := range []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "AWS_PROFILE",
+		"AWS_CONTAINER_CREDENTIALS_FULL_URI", "AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "AWS_CONTAINER_AUTHORIZATION_TOKEN",
+		"AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE", "AWS_WEB_IDENTITY_TOKEN_FILE", "AWS_ROLE_ARN"} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestProvider_CredentialSources_Order(t *testing.T) {
+	isolateCredentialEnv(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "env-access-key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "env-secret-key")
+
+	creds, err := (&Provider{Region: "us-east-1", CredentialSources: []string{SourceEnv, SourceProfile}}).LoadCredentials(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "env-access-key", creds.AccessKeyID)
+
+	creds, err = (&Provider{Region: "us-east-1", CredentialSources: []string{SourceProfile, SourceEnv}}).LoadCredentials(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "profile-access-key", creds.AccessKeyID)
+}
+
+func TestProvider_CredentialSources_NoFallThrough(t *testing.T) {
+	isolateCredentialEnv(t)
+	t.Setenv("AWS_ACCESS_KEY_ID", "env-access-key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "env-secret-key")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+
+	// The environment's credentials are not used unless env is listed
+	p := &Provider{Region: "us-east-1", CredentialSources: []string{SourceProfile, SourceProcess}}
+	_, err := p.LoadCredentials(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to retrieve AWS credentials from sources profile, process")
+	assert.Contains(t, err.Error(), "no credential process is configured")
+
+	var proxyErr *proxyerr.ProxyError
+	require.True(t, errors.As(err, &proxyErr))
+	assert.Equal(t, proxyerr.CredentialError, proxyErr.Code)
+}
+
+func TestProvider_CredentialSources_ECS(t *testing.T) {
+	isolateCredentialEnv(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "container-token", r.Header.Get("Authorization"))
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"AccessKeyId":     "ASIACONTAINERKEY",
+			"SecretAccessKey": "container-secret",
+			"Token":           "container-session",
+			"Expiration":      time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+		})
+	}))
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("container-token\n"), 0o600))
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", server.URL)
+	t.Setenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE", tokenFile)
+
+	creds, err := (&Provider{Region: "us-east-1", CredentialSources: []string{SourceEnv, SourceECS}}).LoadCredentials(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ASIACONTAINERKEY", creds.AccessKeyID)
+	assert.Equal(t, "container-session", creds.SessionToken)
+}
+
+func TestProvider_CredentialSources_Unknown(t *testing.T) {
+	isolateCredentialEnv(t)
+
+	_, err := (&Provider{Region: "us-east-1", CredentialSources: []string{"env", "imds"}}).LoadCredentials(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown credential source "imds"`)
+
+	var proxyErr *proxyerr.ProxyError
+	require.True(t, errors.As(err, &proxyErr))
+	assert.Equal(t, proxyerr.InvalidConfig, proxyErr.Code)
+}
@@ -0,0 +1,89 @@
+package credentials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/aws-sdk-go-v2/service/sso"
+	"github.com/aws/aws-sdk-go-v2/service/sso/types"
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/proxyerr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSSO returns role credentials for any access token
+type fakeSSO struct {
+	calls []*sso.GetRoleCredentialsInput
+}
+
+func (f *fakeSSO) GetRoleCredentials(ctx context.Context, params *sso.GetRoleCredentialsInput, optFns ...func(*sso.Options)) (*sso.GetRoleCredentialsOutput, error) {
+	f.calls = append(f.calls, params)
+	return &sso.GetRoleCredentialsOutput{RoleCredentials: &types.RoleCredentials{
+		AccessKeyId:     aws.String("ASIASSOROLEKEY"),
+		SecretAccessKey: aws.String("sso-secret"),
+		SessionToken:    aws.String("sso-token"),
+		Expiration:      time.Now().Add(time.Hour).UnixMilli(),
+	}}, nil
+}
+
+// writeSSOToken caches an 'aws sso login' access token for startURL under a temporary home directory
+func writeSSOToken(t *testing.T, startURL string, expiresAt time.Time) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	path, err := ssocreds.StandardCachedTokenFilepath(startURL)
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	token := fmt.Sprintf(`{"accessToken": "portal-token", "expiresAt": %q}`, expiresAt.UTC().Format(time.RFC3339))
+	require.NoError(t, os.WriteFile(path, []byte(token), 0o600))
+}
+
+func TestProvider_SSORole(t *testing.T) {
+	const startURL = "https://example.awsapps.com/start"
+	writeSSOToken(t, startURL, time.Now().Add(time.Hour))
+
+	client := &fakeSSO{}
+	p := &Provider{SSOStartURL: startURL, SSOAccountID: "123456789012", SSORoleName: "Developer", SSORegion: "us-west-2"}
+	require.True(t, p.ssoConfigured())
+
+	creds, err := p.ssoRole(client, DefaultRefreshBeforeExpiry).Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ASIASSOROLEKEY", creds.AccessKeyID)
+	assert.Equal(t, "sso-token", creds.SessionToken)
+
+	require.Len(t, client.calls, 1)
+	assert.Equal(t, "portal-token", aws.ToString(client.calls[0].AccessToken))
+	assert.Equal(t, "123456789012", aws.ToString(client.calls[0].AccountId))
+	assert.Equal(t, "Developer", aws.ToString(client.calls[0].RoleName))
+}
+
+func TestProvider_SSOSessionExpired(t *testing.T) {
+	const startURL = "https://example.awsapps.com/start"
+	writeSSOToken(t, startURL, time.Now().Add(-time.Hour))
+
+	p := &Provider{
+		Region:       "us-east-1",
+		SSOStartURL:  startURL,
+		SSOAccountID: "123456789012",
+		SSORoleName:  "Developer",
+		SSORegion:    "us-west-2",
+	}
+	_, err := p.LoadConfig(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "AWS SSO session expired; run 'aws sso login --profile default' to renew")
+
+	var proxyErr *proxyerr.ProxyError
+	require.True(t, errors.As(err, &proxyErr))
+	assert.Equal(t, proxyerr.CredentialError, proxyErr.Code)
+}
+
+func TestProvider_SSOConfiguredRequiresAllFields(t *testing.T) {
+	p := &Provider{SSOStartURL: "https://example.awsapps.com/start", SSOAccountID: "123456789012", SSORoleName: "Developer"}
+	assert.False(t, p.ssoConfigured())
+}
@@ -0,0 +1,119 @@
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// vaultResponse is the response of a HashiCorp Vault AWS secrets engine
+// credentials endpoint, such as GET /v1/aws/creds/my-role
+type vaultResponse struct {
+	LeaseDuration int `json:"lease_duration"`
+	Data          struct {
+		AccessKey     string `json:"access_key"`
+		SecretKey     string `json:"secret_key"`
+		SecurityToken string `json:"security_token"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+// VaultProvider retrieves AWS credentials from the HashiCorp Vault AWS secrets
+// engine. The lease duration of each response is used as the credentials'
+// expiry, so an aws.CredentialsCache requests new credentials before the
+// lease ends.
+type VaultProvider struct {
+	// VaultAddr is the Vault server URL (defaults to VAULT_ADDR)
+	VaultAddr string
+
+	// VaultToken authenticates the request (defaults to VAULT_TOKEN)
+	VaultToken string
+
+	// VaultPath is the credentials endpoint below /v1/, such as aws/creds/my-role
+	VaultPath string
+
+	// HTTPClient sends the request (optional, defaults to http.DefaultClient)
+	HTTPClient *http.Client
+}
+
+// Retrieve requests credentials from Vault
+func (v *VaultProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	addr := v.VaultAddr
+	if addr == "" {
+		addr = os.Getenv("VAULT_ADDR")
+	}
+	token := v.VaultToken
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	if addr == "" || v.VaultPath == "" {
+		return aws.Credentials{}, errors.New("vault address and path are required")
+	}
+	if token == "" {
+		return aws.Credentials{}, errors.New("vault token is required (set VAULT_TOKEN)")
+	}
+
+	url := strings.TrimSuffix(addr, "/") + "/v1/" + strings.TrimPrefix(v.VaultPath, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return aws.Credentials{}, fmt.Errorf("invalid vault address: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+
+	client := v.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return aws.Credentials{}, fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return aws.Credentials{}, fmt.Errorf("failed to read vault response: %w", err)
+	}
+	return parseVaultCredentials(resp.StatusCode, body, time.Now())
+}
+
+// parseVaultCredentials decodes a Vault response received at now. Vault
+// reports failures as a JSON list of errors.
+func parseVaultCredentials(status int, body []byte, now time.Time) (aws.Credentials, error) {
+	var vr vaultResponse
+	if err := json.Unmarshal(body, &vr); err != nil {
+		if status != http.StatusOK {
+			return aws.Credentials{}, fmt.Errorf("vault returned %d", status)
+		}
+		return aws.Credentials{}, fmt.Errorf("vault returned invalid JSON: %w", err)
+	}
+	if status != http.StatusOK {
+		if len(vr.Errors) > 0 {
+			return aws.Credentials{}, fmt.Errorf("vault returned %d: %s", status, strings.Join(vr.Errors, "; "))
+		}
+		return aws.Credentials{}, fmt.Errorf("vault returned %d", status)
+	}
+	if vr.Data.AccessKey == "" || vr.Data.SecretKey == "" {
+		return aws.Credentials{}, errors.New("vault response is missing access_key or secret_key")
+	}
+
+	creds := aws.Credentials{
+		AccessKeyID:     vr.Data.AccessKey,
+		SecretAccessKey: vr.Data.SecretKey,
+		SessionToken:    vr.Data.SecurityToken,
+		Source:          "Vault",
+	}
+	if vr.LeaseDuration > 0 {
+		creds.CanExpire = true
+		creds.Expires = now.Add(time.Duration(vr.LeaseDuration) * time.Second)
+	}
+	return creds, nil
+}
@@ -0,0 +1,156 @@
+package credentials
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultProvider_Retrieve(t *testing.T) {
+	var gotPath, gotToken string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotToken = r.URL.Path, r.Header.Get("X-Vault-Token")
+		w.Write([]byte(`{
+  "lease_id": "aws/creds/deploy/abc123",
+  "lease_duration": 900,
+  "data": {"access_key": "ASIAVAULTKEY", "secret_key": "vault-secret", "security_token": "vault-token"}
+}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("VAULT_TOKEN", "s.env-token")
+	provider := &VaultProvider{VaultAddr: server.URL + "/", VaultPath: "/aws/creds/deploy"}
+
+	before := time.Now()
+	creds, err := provider.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "/v1/aws/creds/deploy", gotPath)
+	assert.Equal(t, "s.env-token", gotToken, "VAULT_TOKEN is used when VaultToken is empty")
+	assert.Equal(t, "ASIAVAULTKEY", creds.AccessKeyID)
+	assert.Equal(t, "vault-secret", creds.SecretAccessKey)
+	assert.Equal(t, "vault-token", creds.SessionToken)
+	assert.True(t, creds.CanExpire)
+	assert.WithinDuration(t, before.Add(15*time.Minute), creds.Expires, 5*time.Second)
+
+	provider.VaultToken = "s.explicit-token"
+	_, err = provider.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "s.explicit-token", gotToken)
+}
+
+func TestParseVaultCredentials(t *testing.T) {
+	now := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+		check   func(t *testing.T, creds aws.Credentials)
+	}{
+		{
+			name:   "static credentials without a lease",
+			status: http.StatusOK,
+			body:   `{"data": {"access_key": "AKIA", "secret_key": "secret"}}`,
+			check: func(t *testing.T, creds aws.Credentials) {
+				assert.False(t, creds.CanExpire)
+				assert.Empty(t, creds.SessionToken)
+			},
+		},
+		{
+			name:   "lease sets the expiry",
+			status: http.StatusOK,
+			body:   `{"lease_duration": 3600, "data": {"access_key": "ASIA", "secret_key": "secret", "security_token": "token"}}`,
+			check: func(t *testing.T, creds aws.Credentials) {
+				assert.Equal(t, now.Add(time.Hour), creds.Expires)
+			},
+		},
+		{
+			name:    "vault errors",
+			status:  http.StatusForbidden,
+			body:    `{"errors": ["permission denied"]}`,
+			wantErr: "vault returned 403: permission denied",
+		},
+		{
+			name:    "non-JSON error",
+			status:  http.StatusBadGateway,
+			body:    `<html>bad gateway</html>`,
+			wantErr: "vault returned 502",
+		},
+		{
+			name:    "missing keys",
+			status:  http.StatusOK,
+			body:    `{"data": {"access_key": "AKIA"}}`,
+			wantErr: "missing access_key or secret_key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds, err := parseVaultCredentials(tt.status, []byte(tt.body), now)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Vault", creds.Source)
+			tt.check(t, creds)
+		})
+	}
+}
+
+func TestVaultProvider_MissingSettings(t *testing.T) {
+	t.Setenv("VAULT_ADDR", "")
+	t.Setenv("VAULT_TOKEN", "")
+
+	_, err := (&VaultProvider{VaultPath: "aws/creds/deploy"}).Retrieve(context.Background())
+	assert.ErrorContains(t, err, "vault address and path are required")
+
+	_, err = (&VaultProvider{VaultAddr: "http://127.0.0.1:8200", VaultPath: "aws/creds/deploy"}).Retrieve(context.Background())
+	assert.ErrorContains(t, err, "vault token is required")
+}
+
+func TestProvider_VaultCachesUntilLeaseEnds(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"lease_duration": 3600, "data": {"access_key": "ASIAVAULTKEY", "secret_key": "secret", "security_token": "token"}}`))
+	}))
+	defer server.Close()
+
+	p := &Provider{
+		Region: "us-east-1",
+		Vault:  &VaultProvider{VaultAddr: server.URL, VaultToken: "s.token", VaultPath: "aws/creds/deploy"},
+	}
+	cfg, err := p.LoadConfig(context.Background())
+	require.NoError(t, err)
+
+	creds, err := cfg.Credentials.Retrieve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ASIAVAULTKEY", creds.AccessKeyID)
+	assert.Equal(t, 1, requests, "credentials are cached for the lease")
+}
+
+func TestProvider_VaultFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errors": ["permission denied"]}`))
+	}))
+	defer server.Close()
+
+	p := &Provider{
+		Region: "us-east-1",
+		Vault:  &VaultProvider{VaultAddr: server.URL, VaultToken: "s.token", VaultPath: "aws/creds/deploy"},
+	}
+	_, err := p.LoadConfig(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "vault path aws/creds/deploy")
+	assert.Contains(t, err.Error(), "permission denied")
+}
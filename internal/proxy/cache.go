@@ -0,0 +1,238 @@
+package proxy
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ResponseCacheConfig configures caching of target responses by MCP method
+type ResponseCacheConfig struct {
+	// MaxEntries caps the number of cached responses; the least recently used
+	// response is evicted when it is reached (0 disables the cache)
+	MaxEntries int
+
+	// TTL is how long a cached response is served (0 means responses are only
+	// evicted to make room)
+	TTL time.Duration
+
+	// CacheableMethods lists the methods whose responses are cached, from
+	// "tools/call", "resources/read", and "prompts/get"
+	CacheableMethods []string
+}
+
+// CacheStats reports the activity of the response cache
+type CacheStats struct {
+	// Hits counts requests answered from the cache
+	Hits int64
+
+	// Misses counts cacheable requests forwarded to the target server
+	Misses int64
+
+	// Evictions counts responses removed to make room for new ones
+	Evictions int64
+
+	// Entries is the number of responses currently cached
+	Entries int
+}
+
+// cacheEntry is a cached response, held in the LRU list. A zero expiresAt
+// never expires.
+type cacheEntry struct {
+	key       string
+	value     any
+	expiresAt time.Time
+}
+
+// responseCache is an LRU cache of target responses. The list is ordered from
+// most to least recently used and the map indexes its elements by key.
+type responseCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ttl        time.Duration
+	methods    map[string]bool
+	order      *list.List
+	entries    map[string]*list.Element
+	stats      CacheStats
+
+	// onEvict, when set, is called with the key of each response evicted to
+	// make room, while the cache is locked
+	onEvict func(key string)
+
+	// now returns the current time (replaced in tests)
+	now func() time.Time
+}
+
+// newResponseCache creates a response cache, or returns nil when cfg leaves
+// caching disabled
+func newResponseCache(cfg ResponseCacheConfig) *responseCache {
+	if cfg.MaxEntries <= 0 || len(cfg.CacheableMethods) == 0 {
+		return nil
+	}
+	methods := make(map[string]bool, len(cfg.CacheableMethods))
+	for _, method := range cfg.CacheableMethods {
+		methods[method] = true
+	}
+	return &responseCache{
+		maxEntries: cfg.MaxEntries,
+		ttl:        cfg.TTL,
+		methods:    methods,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		now:        time.Now,
+	}
+}
+
+// cacheable reports whether responses to method are cached. It is safe to
+// call on a nil cache.
+func (c *responseCache) cacheable(method string) bool {
+	return c != nil && c.methods[method]
+}
+
+// get returns the cached response for key, counting a hit or a miss.
+// Expired responses are removed and count as misses.
+func (c *responseCache) get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if ok {
+		entry := elem.Value.(*cacheEntry)
+		if entry.expiresAt.IsZero() || c.now().Before(entry.expiresAt) {
+			c.order.MoveToFront(elem)
+			c.stats.Hits++
+			return entry.value, true
+		}
+		c.remove(elem)
+	}
+	c.stats.Misses++
+	return nil, false
+}
+
+// put caches value under key for the cache's TTL, evicting the least recently
+// used responses when the cache is full
+func (c *responseCache) put(key string, value any) {
+	c.putTTL(key, value, c.ttl)
+}
+
+// putTTL caches value under key for ttl (0 means until it is evicted),
+// evicting the least recently used responses when the cache is full
+func (c *responseCache) putTTL(key string, value any, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = c.now().Add(ttl)
+	}
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.value, entry.expiresAt = value, expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.remove(oldest)
+		c.stats.Evictions++
+		if c.onEvict != nil {
+			c.onEvict(oldest.Value.(*cacheEntry).key)
+		}
+	}
+}
+
+// remove deletes elem from the list and the index
+func (c *responseCache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry).key)
+}
+
+// snapshot returns the cache statistics
+func (c *responseCache) snapshot() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = c.order.Len()
+	return stats
+}
+
+// keys returns the keys of the cached responses
+func (c *responseCache) keys() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]string, 0, len(c.entries))
+	for key := range c.entries {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// cacheKey identifies a request by its method and a hash of its params. The
+// _meta field is left out, since progress tokens and trace context differ
+// between otherwise identical requests, and the params are re-encoded with
+// sorted keys so argument order does not matter. Numbers keep their exact
+// text, as large integers that differ would otherwise round to the same float64.
+func cacheKey(method string, params any) (string, error) {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return "", err
+	}
+	var fields map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return "", err
+	}
+	delete(fields, "_meta")
+	if data, err = json.Marshal(fields); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return method + ":" + hex.EncodeToString(sum[:]), nil
+}
+
+// cachedCall answers a request from the proxy's response cache when method is
+// cacheable, otherwise calling the target with call and caching its result.
+// Errors and tool results flagged as errors are never cached.
+func cachedCall[T any](p *Proxy, method string, params any, call func() (T, error)) (T, error) {
+	if !p.responseCache.cacheable(method) {
+		return call()
+	}
+
+	key, err := cacheKey(method, params)
+	if err != nil {
+		p.logger.Debug("request could not be cached", "method", method, "error", err)
+		return call()
+	}
+	if value, ok := p.responseCache.get(key); ok {
+		p.logger.Debug("response cache hit", "method", method)
+		return value.(T), nil
+	}
+
+	result, err := call()
+	if err != nil {
+		return result, err
+	}
+	if toolResult, ok := any(result).(*mcp.CallToolResult); ok && toolResult.IsError {
+		return result, nil
+	}
+	p.responseCache.put(key, result)
+	return result, nil
+}
+
+// CacheStats returns the hit, miss, and eviction counts of the response
+// cache, or zero values when caching is disabled
+func (p *Proxy) CacheStats() CacheStats {
+	if p.responseCache == nil {
+		return CacheStats{}
+	}
+	return p.responseCache.snapshot()
+}
@@ -0,0 +1,126 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestResponseCache_LRU(t *testing.T) {
+	cache := newResponseCache(ResponseCacheConfig{MaxEntries: 2, CacheableMethods: []string{"resources/read"}})
+
+	cache.put("a", 1)
+	cache.put("b", 2)
+	if _, ok := cache.get("a"); !ok {
+		t.Fatal("get(a) missed, want hit")
+	}
+
+	// b is now the least recently used entry and is evicted
+	cache.put("c", 3)
+	if _, ok := cache.get("b"); ok {
+		t.Error("get(b) hit, want it evicted")
+	}
+	if value, ok := cache.get("c"); !ok || value != 3 {
+		t.Errorf("get(c) = %v, %v, want 3, true", value, ok)
+	}
+
+	want := CacheStats{Hits: 2, Misses: 1, Evictions: 1, Entries: 2}
+	if got := cache.snapshot(); got != want {
+		t.Errorf("snapshot() = %+v, want %+v", got, want)
+	}
+}
+
+func TestResponseCache_TTL(t *testing.T) {
+	cache := newResponseCache(ResponseCacheConfig{MaxEntries: 10, TTL: time.Minute, CacheableMethods: []string{"prompts/get"}})
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	cache.put("a", 1)
+	if _, ok := cache.get("a"); !ok {
+		t.Fatal("get(a) missed within the TTL, want hit")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := cache.get("a"); ok {
+		t.Error("get(a) hit after the TTL, want miss")
+	}
+	if got := cache.snapshot().Entries; got != 0 {
+		t.Errorf("Entries = %d, want expired entries removed", got)
+	}
+}
+
+func TestNewResponseCache_Disabled(t *testing.T) {
+	if cache := newResponseCache(ResponseCacheConfig{CacheableMethods: []string{"resources/read"}}); cache != nil {
+		t.Error("newResponseCache() without MaxEntries should return nil")
+	}
+	if cache := newResponseCache(ResponseCacheConfig{MaxEntries: 10}); cache != nil {
+		t.Error("newResponseCache() without methods should return nil")
+	}
+
+	var cache *responseCache
+	if cache.cacheable("resources/read") {
+		t.Error("a nil cache should not cache any method")
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	key := func(params any) string {
+		t.Helper()
+		k, err := cacheKey("tools/call", params)
+		if err != nil {
+			t.Fatalf("cacheKey() unexpected error: %v", err)
+		}
+		return k
+	}
+
+	base := key(&mcp.CallToolParams{Name: "lookup", Arguments: map[string]any{"id": "42", "kind": "user"}})
+	withMeta := &mcp.CallToolParams{Meta: mcp.Meta{"progressToken": "token-1"}, Name: "lookup", Arguments: map[string]any{"kind": "user", "id": "42"}}
+	if got := key(withMeta); got != base {
+		t.Error("_meta and argument order must not change the key")
+	}
+	if got := key(&mcp.CallToolParams{Name: "lookup", Arguments: map[string]any{"id": "43"}}); got == base {
+		t.Error("different arguments must change the key")
+	}
+
+	// Integers beyond float64 precision must not share a key
+	exact := key(&mcp.CallToolParamsRaw{Name: "lookup", Arguments: json.RawMessage(`{"id":9007199254740993}`)})
+	if got := key(&mcp.CallToolParamsRaw{Name: "lookup", Arguments: json.RawMessage(`{"id":9007199254740992}`)}); got == exact {
+		t.Error("large integer arguments that differ must change the key")
+	}
+}
+
+func TestProxy_ResponseCache(t *testing.T) {
+	var reads atomic.Int64
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddResource(&mcp.Resource{URI: "file:///static.txt", Name: "static"},
+		func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			reads.Add(1)
+			return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: req.Params.URI, Text: "contents"}}}, nil
+		})
+
+	p, session := newInMemoryProxy(t, target, Config{
+		ResponseCache: ResponseCacheConfig{MaxEntries: 10, TTL: time.Minute, CacheableMethods: []string{"resources/read"}},
+	}, nil)
+
+	for range 3 {
+		result, err := session.ReadResource(context.Background(), &mcp.ReadResourceParams{URI: "file:///static.txt"})
+		if err != nil {
+			t.Fatalf("ReadResource() unexpected error: %v", err)
+		}
+		if got := result.Contents[0].Text; got != "contents" {
+			t.Errorf("ReadResource() text = %q, want %q", got, "contents")
+		}
+	}
+
+	if got := reads.Load(); got != 1 {
+		t.Errorf("target reads = %d, want 1", got)
+	}
+	want := CacheStats{Hits: 2, Misses: 1, Entries: 1}
+	if got := p.CacheStats(); got != want {
+		t.Errorf("CacheStats() = %+v, want %+v", got, want)
+	}
+}
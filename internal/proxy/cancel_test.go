@@ -0,0 +1,50 @@
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestProxy_ForwardsCancellation(t *testing.T) {
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "wait", InputSchema: map[string]any{"type": "object"}},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			close(started)
+			<-ctx.Done()
+			close(cancelled)
+			return nil, ctx.Err()
+		})
+
+	_, session := newInMemoryProxy(t, target, Config{}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() {
+		_, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "wait"})
+		errc <- err
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("tool call did not reach the target")
+	}
+
+	// Cancelling the client call sends notifications/cancelled to the proxy,
+	// which must abort the in-flight target call
+	cancel()
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the target call to be cancelled")
+	}
+	if err := <-errc; err == nil {
+		t.Error("expected the cancelled call to return an error")
+	}
+}
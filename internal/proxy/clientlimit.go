@@ -0,0 +1,132 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/time/rate"
+)
+
+// codeRateLimitExceeded is the JSON-RPC error code returned to clients whose
+// rate limit is exceeded
+const codeRateLimitExceeded = -32000
+
+// defaultClientLimiterEvictionIdle is how long an idle client's rate limiter
+// is kept when ClientLimiterEvictionIdle is not set
+const defaultClientLimiterEvictionIdle = 10 * time.Minute
+
+// PerClientRateLimit limits the requests of each client separately, so one
+// busy client cannot use up the rate allowed to the others
+type PerClientRateLimit struct {
+	// RPS is the number of requests per second allowed to each client
+	// (0 disables per-client rate limiting)
+	RPS float64
+
+	// Burst is the maximum number of requests a client may make at once
+	// (0 defaults to RPS rounded up)
+	Burst int
+}
+
+// clientLimiter is the token bucket of one client
+type clientLimiter struct {
+	limiter *rate.Limiter
+
+	// lastUsed is when the client last made a request, in Unix nanoseconds
+	lastUsed atomic.Int64
+}
+
+// clientLimiters keeps a token bucket for each client identity, evicting
+// buckets of clients that have been idle for longer than idle
+type clientLimiters struct {
+	limit rate.Limit
+	burst int
+	idle  time.Duration
+
+	limiters sync.Map // client identity -> *clientLimiter
+
+	// lastSweep is when idle limiters were last evicted, in Unix nanoseconds
+	lastSweep atomic.Int64
+
+	// now returns the current time (replaced in tests)
+	now func() time.Time
+}
+
+// newClientLimiters creates the per-client rate limiters, or returns nil when
+// cfg leaves per-client rate limiting disabled
+func newClientLimiters(cfg PerClientRateLimit, idle time.Duration) *clientLimiters {
+	if cfg.RPS <= 0 {
+		return nil
+	}
+	burst := cfg.Burst
+	if burst <= 0 {
+		burst = int(math.Max(1, math.Ceil(cfg.RPS)))
+	}
+	if idle <= 0 {
+		idle = defaultClientLimiterEvictionIdle
+	}
+	return &clientLimiters{limit: rate.Limit(cfg.RPS), burst: burst, idle: idle, now: time.Now}
+}
+
+// allow reports whether the client may make a request now, creating its
+// limiter on its first request
+func (c *clientLimiters) allow(client string) bool {
+	now := c.now()
+	c.evictIdle(now)
+
+	value, ok := c.limiters.Load(client)
+	if !ok {
+		value, _ = c.limiters.LoadOrStore(client, &clientLimiter{limiter: rate.NewLimiter(c.limit, c.burst)})
+	}
+	limiter := value.(*clientLimiter)
+	limiter.lastUsed.Store(now.UnixNano())
+	return limiter.limiter.AllowN(now, 1)
+}
+
+// evictIdle removes the limiters of clients idle for longer than the idle
+// timeout. It scans at most once per idle timeout.
+func (c *clientLimiters) evictIdle(now time.Time) {
+	last := c.lastSweep.Load()
+	if now.UnixNano()-last < int64(c.idle) || !c.lastSweep.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+	cutoff := now.Add(-c.idle).UnixNano()
+	c.limiters.Range(func(key, value any) bool {
+		if value.(*clientLimiter).lastUsed.Load() < cutoff {
+			c.limiters.Delete(key)
+		}
+		return true
+	})
+}
+
+// clientIdentity identifies the client of a session: its session ID when the
+// transport assigns one, otherwise the session itself, as over stdio
+func clientIdentity(session mcp.Session) string {
+	if s, ok := session.(*mcp.ServerSession); ok && s.ID() != "" {
+		return s.ID()
+	}
+	return fmt.Sprintf("session-%p", session)
+}
+
+// limitClients is server middleware that rejects requests from clients that
+// have exceeded their rate limit. Notifications and initialize requests are
+// not limited, so a client can always connect.
+func (p *Proxy) limitClients(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if method == "initialize" || strings.HasPrefix(method, "notifications/") {
+			return next(ctx, method, req)
+		}
+		client := clientIdentity(req.GetSession())
+		if !p.clientLimiters.allow(client) {
+			p.logger.Debug("client rate limit exceeded", "client", client, "method", method)
+			return nil, &jsonrpc.Error{Code: codeRateLimitExceeded, Message: "rate limit exceeded"}
+		}
+		return next(ctx, method, req)
+	}
+}
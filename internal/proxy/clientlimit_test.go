@@ -0,0 +1,89 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestClientLimiters_Allow(t *testing.T) {
+	limiters := newClientLimiters(PerClientRateLimit{RPS: 1, Burst: 2}, time.Minute)
+	now := time.Now()
+	limiters.now = func() time.Time { return now }
+
+	for i := range 2 {
+		if !limiters.allow("a") {
+			t.Fatalf("allow(a) request %d rejected within the burst", i+1)
+		}
+	}
+	if limiters.allow("a") {
+		t.Error("allow(a) accepted a request over the burst")
+	}
+	if !limiters.allow("b") {
+		t.Error("allow(b) rejected, want each client to have its own bucket")
+	}
+
+	now = now.Add(time.Second)
+	if !limiters.allow("a") {
+		t.Error("allow(a) rejected after a token was refilled")
+	}
+}
+
+func TestClientLimiters_EvictIdle(t *testing.T) {
+	limiters := newClientLimiters(PerClientRateLimit{RPS: 1}, time.Minute)
+	now := time.Now()
+	limiters.now = func() time.Time { return now }
+
+	limiters.allow("idle")
+	now = now.Add(30 * time.Second)
+	limiters.allow("active")
+	now = now.Add(45 * time.Second)
+	limiters.allow("active")
+
+	if _, ok := limiters.limiters.Load("idle"); ok {
+		t.Error("limiter of the idle client was not evicted")
+	}
+	if _, ok := limiters.limiters.Load("active"); !ok {
+		t.Error("limiter of the active client was evicted")
+	}
+}
+
+func TestNewClientLimiters_Disabled(t *testing.T) {
+	if limiters := newClientLimiters(PerClientRateLimit{Burst: 5}, 0); limiters != nil {
+		t.Error("newClientLimiters() without RPS should return nil")
+	}
+}
+
+func TestProxy_PerClientRateLimit(t *testing.T) {
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "lookup", InputSchema: map[string]any{"type": "object"}},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{}, nil
+		})
+
+	p := connectTarget(t, target, Config{PerClientRateLimit: PerClientRateLimit{RPS: 0.001, Burst: 1}})
+	newClient := func() *mcp.ClientSession {
+		return connectClient(t, p, mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v1.0.0"}, nil))
+	}
+	first, second := newClient(), newClient()
+	ctx := context.Background()
+	params := &mcp.CallToolParams{Name: "lookup"}
+
+	if _, err := first.CallTool(ctx, params); err != nil {
+		t.Fatalf("first CallTool() unexpected error: %v", err)
+	}
+	_, err := first.CallTool(ctx, params)
+	var rpcErr *jsonrpc.Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != codeRateLimitExceeded {
+		t.Errorf("CallTool() over the limit error = %v, want code %d", err, codeRateLimitExceeded)
+	}
+
+	// Another client is not held back by the first
+	if _, err := second.CallTool(ctx, params); err != nil {
+		t.Errorf("CallTool() from a second client unexpected error: %v", err)
+	}
+}
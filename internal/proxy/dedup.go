@@ -0,0 +1,116 @@
+package proxy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// toolCallFunc forwards a tool call to the target server
+type toolCallFunc func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error)
+
+// sharedCallKey identifies an in-flight deduplicated tool call. Calls are only
+// shared within one target session, so isolated sessions never share calls.
+type sharedCallKey struct {
+	target *mcp.ClientSession
+	call   string
+}
+
+// sharedCall is an in-flight tool call shared by its waiting callers
+type sharedCall struct {
+	// done is closed once result and err are set
+	done   chan struct{}
+	result *mcp.CallToolResult
+	err    error
+
+	// waiters counts the callers still waiting for the call, which is
+	// cancelled when the last of them gives up
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// deduplicates reports whether concurrent identical calls to tool may share one
+// call to the target server. Only tools annotated as read-only or idempotent
+// qualify, and calls requesting progress notifications never do, since the
+// notifications would only reach the first caller.
+func (p *Proxy) deduplicates(tool *mcp.Tool, req *mcp.CallToolRequest) bool {
+	if !p.enableDeduplication || req.Params.GetProgressToken() != nil {
+		return false
+	}
+	return tool.Annotations != nil && (tool.Annotations.ReadOnlyHint || tool.Annotations.IdempotentHint)
+}
+
+// toolCallKey identifies a tool call by the tool name and a hash of its arguments
+func toolCallKey(name string, arguments json.RawMessage) string {
+	sum := sha256.Sum256(arguments)
+	return name + ":" + hex.EncodeToString(sum[:])
+}
+
+// deduplicateToolCall forwards the call with call unless an identical call is
+// already in flight on the same target session, in which case it waits for
+// that call and shares its result. Waiting callers stop waiting when their own
+// context is done, and the shared call is cancelled once no caller is left
+// waiting for it.
+func (p *Proxy) deduplicateToolCall(ctx context.Context, req *mcp.CallToolRequest, call toolCallFunc) (*mcp.CallToolResult, error) {
+	key := sharedCallKey{
+		target: p.targetSession(req.Session),
+		call:   toolCallKey(req.Params.Name, req.Params.Arguments),
+	}
+
+	p.sharedCallsMu.Lock()
+	shared, ok := p.sharedCalls[key]
+	if !ok {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		shared = &sharedCall{done: make(chan struct{}), cancel: cancel}
+		if p.sharedCalls == nil {
+			p.sharedCalls = make(map[sharedCallKey]*sharedCall)
+		}
+		p.sharedCalls[key] = shared
+		go func() {
+			shared.result, shared.err = call(callCtx, req)
+			cancel()
+			p.forgetSharedCall(key, shared)
+			close(shared.done)
+		}()
+	}
+	shared.waiters++
+	p.sharedCallsMu.Unlock()
+
+	if ok {
+		p.DeduplicationHits.Add(1)
+		p.logger.Debug("shared in-flight tool call", "tool", req.Params.Name)
+	} else {
+		p.DeduplicationMisses.Add(1)
+	}
+
+	select {
+	case <-shared.done:
+		return shared.result, shared.err
+	case <-ctx.Done():
+		p.sharedCallsMu.Lock()
+		shared.waiters--
+		if shared.waiters == 0 {
+			shared.cancel()
+			p.forgetSharedCallLocked(key, shared)
+		}
+		p.sharedCallsMu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// forgetSharedCall stops new callers from joining shared
+func (p *Proxy) forgetSharedCall(key sharedCallKey, shared *sharedCall) {
+	p.sharedCallsMu.Lock()
+	defer p.sharedCallsMu.Unlock()
+	p.forgetSharedCallLocked(key, shared)
+}
+
+// forgetSharedCallLocked is forgetSharedCall for callers holding sharedCallsMu
+func (p *Proxy) forgetSharedCallLocked(key sharedCallKey, shared *sharedCall) {
+	if p.sharedCalls[key] == shared {
+		delete(p.sharedCalls, key)
+	}
+}
@@ -0,0 +1,239 @@
+package proxy
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// newDedupTarget creates a target server with a read-only "lookup" tool and an
+// unannotated "create" tool. Both count their calls and block until release
+// is closed.
+func newDedupTarget(calls *atomic.Int64, release chan struct{}) *mcp.Server {
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls.Add(1)
+		<-release
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "done"}}}, nil
+	}
+	schema := map[string]any{"type": "object"}
+	target.AddTool(&mcp.Tool{Name: "lookup", InputSchema: schema, Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true}}, handler)
+	target.AddTool(&mcp.Tool{Name: "create", InputSchema: schema}, handler)
+	return target
+}
+
+func TestProxy_Deduplication(t *testing.T) {
+	tests := []struct {
+		name       string
+		tool       string
+		dedup      bool
+		wantCalls  int64
+		wantHits   int64
+		wantMisses int64
+	}{
+		{name: "read-only tool is deduplicated", tool: "lookup", dedup: true, wantCalls: 1, wantHits: 1, wantMisses: 1},
+		{name: "unannotated tool is not deduplicated", tool: "create", dedup: true, wantCalls: 2},
+		{name: "deduplication disabled", tool: "lookup", wantCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int64
+			release := make(chan struct{})
+			p := connectTarget(t, newDedupTarget(&calls, release), Config{EnableDeduplication: tt.dedup})
+			sessions := []*mcp.ClientSession{
+				connectClient(t, p, mcp.NewClient(&mcp.Implementation{Name: "client-1", Version: "v1.0.0"}, nil)),
+				connectClient(t, p, mcp.NewClient(&mcp.Implementation{Name: "client-2", Version: "v1.0.0"}, nil)),
+			}
+
+			var wg sync.WaitGroup
+			errs := make([]error, len(sessions))
+			for i, session := range sessions {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					params := &mcp.CallToolParams{Name: tt.tool, Arguments: map[string]any{"id": "42"}}
+					_, errs[i] = session.CallTool(context.Background(), params)
+				}()
+			}
+
+			// Wait for the expected calls to reach the target, then give any
+			// unexpected ones time to arrive before releasing them
+			deadline := time.Now().Add(2 * time.Second)
+			for calls.Load() < tt.wantCalls && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+			time.Sleep(100 * time.Millisecond)
+			close(release)
+			wg.Wait()
+
+			for _, err := range errs {
+				if err != nil {
+					t.Fatalf("CallTool() unexpected error: %v", err)
+				}
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("target calls = %d, want %d", got, tt.wantCalls)
+			}
+			if got := p.DeduplicationHits.Load(); got != tt.wantHits {
+				t.Errorf("DeduplicationHits = %d, want %d", got, tt.wantHits)
+			}
+			if got := p.DeduplicationMisses.Load(); got != tt.wantMisses {
+				t.Errorf("DeduplicationMisses = %d, want %d", got, tt.wantMisses)
+			}
+		})
+	}
+}
+
+func TestToolCallKey(t *testing.T) {
+	key := toolCallKey("lookup", []byte(`{"id":"42"}`))
+	if key != toolCallKey("lookup", []byte(`{"id":"42"}`)) {
+		t.Error("identical calls must have the same key")
+	}
+	if key == toolCallKey("lookup", []byte(`{"id":"43"}`)) {
+		t.Error("calls with different arguments must have different keys")
+	}
+	if key == toolCallKey("search", []byte(`{"id":"42"}`)) {
+		t.Error("calls to different tools must have different keys")
+	}
+}
+
+func TestProxy_DeduplicationFirstCallerCancels(t *testing.T) {
+	var calls atomic.Int64
+	release := make(chan struct{})
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "lookup", InputSchema: map[string]any{"type": "object"}, Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true}},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			calls.Add(1)
+			select {
+			case <-release:
+				return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "done"}}}, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		})
+
+	p := connectTarget(t, target, Config{EnableDeduplication: true})
+	first := connectClient(t, p, mcp.NewClient(&mcp.Implementation{Name: "client-1", Version: "v1.0.0"}, nil))
+	second := connectClient(t, p, mcp.NewClient(&mcp.Implementation{Name: "client-2", Version: "v1.0.0"}, nil))
+	params := &mcp.CallToolParams{Name: "lookup", Arguments: map[string]any{"id": "42"}}
+
+	// The first caller starts the shared call and the second waits for it
+	ctx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := first.CallTool(ctx, params)
+		firstErr <- err
+	}()
+	deadline := time.Now().Add(2 * time.Second)
+	for calls.Load() < 1 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	secondErr := make(chan error, 1)
+	go func() {
+		_, err := second.CallTool(context.Background(), params)
+		secondErr <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	// Cancelling the first caller must not cancel the call the second is waiting for
+	cancel()
+	if err := <-firstErr; err == nil {
+		t.Error("expected the cancelled call to return an error")
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	if err := <-secondErr; err != nil {
+		t.Errorf("CallTool() for the waiting caller unexpected error: %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("target calls = %d, want 1", got)
+	}
+}
+
+func TestProxy_DeduplicationAllCallersCancel(t *testing.T) {
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	stop := make(chan struct{})
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "lookup", InputSchema: map[string]any{"type": "object"}, Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true}},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			close(started)
+			select {
+			case <-ctx.Done():
+				close(cancelled)
+				return nil, ctx.Err()
+			case <-stop:
+				return &mcp.CallToolResult{}, nil
+			}
+		})
+
+	p := connectTarget(t, target, Config{EnableDeduplication: true})
+	session := connectClient(t, p, mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v1.0.0"}, nil))
+	t.Cleanup(func() { close(stop) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	callErr := make(chan error, 1)
+	go func() {
+		_, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "lookup", Arguments: map[string]any{"id": "42"}})
+		callErr <- err
+	}()
+	<-started
+
+	// With no caller left waiting, the shared call must be cancelled at the target
+	cancel()
+	if err := <-callErr; err == nil {
+		t.Error("expected the cancelled call to return an error")
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("target call was not cancelled after its only caller gave up")
+	}
+}
+
+func TestProxy_DeduplicationIsolatedSessions(t *testing.T) {
+	var calls atomic.Int64
+	release := make(chan struct{})
+	p := connectHTTPTarget(t, newDedupTarget(&calls, release), Config{EnableDeduplication: true, IsolatedSessions: true})
+	sessions := []*mcp.ClientSession{
+		connectClient(t, p, mcp.NewClient(&mcp.Implementation{Name: "client-1", Version: "v1.0.0"}, nil)),
+		connectClient(t, p, mcp.NewClient(&mcp.Implementation{Name: "client-2", Version: "v1.0.0"}, nil)),
+	}
+	waitForSessions(t, p, 2)
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(sessions))
+	for i, session := range sessions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			params := &mcp.CallToolParams{Name: "lookup", Arguments: map[string]any{"id": "42"}}
+			_, errs[i] = session.CallTool(context.Background(), params)
+		}()
+	}
+
+	// Each client's call must reach the target on its own isolated session
+	deadline := time.Now().Add(2 * time.Second)
+	for calls.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("CallTool() unexpected error: %v", err)
+		}
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("target calls = %d, want 2", got)
+	}
+	if got := p.DeduplicationHits.Load(); got != 0 {
+		t.Errorf("DeduplicationHits = %d, want 0", got)
+	}
+}
@@ -0,0 +1,97 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// errShuttingDown is returned for client requests received while the proxy drains
+var errShuttingDown = errors.New("proxy is shutting down")
+
+// requestTracker counts the client requests being handled so shutdown can wait for them
+type requestTracker struct {
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	inFlight int
+	draining bool
+}
+
+// start records a new request. It returns false once draining has begun.
+func (t *requestTracker) start() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.draining {
+		return false
+	}
+	t.inFlight++
+	t.wg.Add(1)
+	return true
+}
+
+// done records that a request finished
+func (t *requestTracker) done() {
+	t.mu.Lock()
+	t.inFlight--
+	t.mu.Unlock()
+	t.wg.Done()
+}
+
+// drain stops new requests from starting and waits up to timeout for the
+// in-flight ones to finish. It returns the number of requests that finished
+// and the number still running when the timeout expired.
+func (t *requestTracker) drain(timeout time.Duration) (completed, remaining int) {
+	t.mu.Lock()
+	t.draining = true
+	started := t.inFlight
+	t.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(finished)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-finished:
+	case <-timer.C:
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return started - t.inFlight, t.inFlight
+}
+
+// trackRequests is server middleware that counts in-flight client requests and
+// rejects new ones once the proxy starts draining. Notifications are not tracked.
+func (p *Proxy) trackRequests(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if strings.HasPrefix(method, "notifications/") {
+			return next(ctx, method, req)
+		}
+		if !p.requests.start() {
+			return nil, errShuttingDown
+		}
+		defer p.requests.done()
+		return next(ctx, method, req)
+	}
+}
+
+// drain waits up to the drain timeout for in-flight client requests to finish
+// and logs how many completed and how many are about to be cancelled.
+func (p *Proxy) drain() {
+	p.logger.Info("draining in-flight requests", "timeout", p.drainTimeout)
+	completed, remaining := p.requests.drain(p.drainTimeout)
+	if remaining > 0 {
+		p.logger.Warn("drain timeout expired, cancelling in-flight requests",
+			"completed", completed, "cancelled", remaining, "target_requests", p.transport.ActiveRequests())
+		return
+	}
+	p.logger.Info("in-flight requests drained", "completed", completed)
+}
@@ -0,0 +1,92 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestProxy_DrainWaitsForInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "slow", InputSchema: map[string]any{"type": "object"}},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			close(started)
+			<-release
+			return echoTool(ctx, req)
+		})
+
+	var logs bytes.Buffer
+	p, session := newInMemoryProxy(t, target, Config{
+		DrainTimeout: 2 * time.Second,
+		Logger:       slog.New(slog.NewTextHandler(&logs, nil)),
+	}, nil)
+
+	errc := make(chan error, 1)
+	go func() {
+		_, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "slow"})
+		errc <- err
+	}()
+	<-started
+
+	drained := make(chan struct{})
+	go func() {
+		p.drain()
+		close(drained)
+	}()
+
+	// New requests are rejected once draining starts
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		_, err := session.ListTools(context.Background(), &mcp.ListToolsParams{})
+		if err != nil {
+			if !strings.Contains(err.Error(), errShuttingDown.Error()) {
+				t.Fatalf("ListTools() error = %v, want %v", err, errShuttingDown)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected requests to be rejected while draining")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	close(release)
+	select {
+	case <-drained:
+	case <-time.After(2 * time.Second):
+		t.Fatal("drain did not return after the in-flight request finished")
+	}
+	if err := <-errc; err != nil {
+		t.Errorf("in-flight call failed: %v", err)
+	}
+	if !strings.Contains(logs.String(), "in-flight requests drained") || !strings.Contains(logs.String(), "completed=1") {
+		t.Errorf("expected a drain summary, got %q", logs.String())
+	}
+}
+
+func TestRequestTracker_DrainTimeout(t *testing.T) {
+	var tracker requestTracker
+	if !tracker.start() || !tracker.start() {
+		t.Fatal("expected requests to start before draining")
+	}
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		tracker.done()
+	}()
+
+	completed, remaining := tracker.drain(100 * time.Millisecond)
+	if completed != 1 || remaining != 1 {
+		t.Errorf("drain() = (%d, %d), want (1, 1)", completed, remaining)
+	}
+	if tracker.start() {
+		t.Error("expected new requests to be rejected after draining")
+	}
+}
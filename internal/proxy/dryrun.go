@@ -0,0 +1,63 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/transport"
+)
+
+// dryRunProtocolVersion is the MCP protocol version sent in the dry-run initialize request
+const dryRunProtocolVersion = "2025-06-18"
+
+// signDryRun signs the MCP initialize request the proxy would send to the target
+// without sending it, verifying the credentials and signing configuration.
+// The signed headers are logged by the transport.
+func (p *Proxy) signDryRun(ctx context.Context) error {
+	client, err := p.transport.DryRunClient()
+	if err != nil {
+		return fmt.Errorf("failed to create dry run client: %w", err)
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "initialize",
+		"params": &mcp.InitializeParams{
+			ProtocolVersion: dryRunProtocolVersion,
+			ClientInfo:      p.implementation,
+			Capabilities:    &mcp.ClientCapabilities{},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode initialize request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.transport.TargetURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid target URL %s: %w", p.transport.TargetURL, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("dry run signing failed: %w "+
+			"(check AWS credentials, region, and service name)", err)
+	}
+	defer resp.Body.Close()
+
+	var result transport.DryRunResult
+	if err := json.NewDecoder(io.LimitReader(resp.Body, transport.DefaultMaxBodyBytes)).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode dry run response: %w", err)
+	}
+
+	p.logger.Info("dry run complete, no requests were sent to the target",
+		"url", result.URL, "signed_headers", len(result.Headers))
+	return nil
+}
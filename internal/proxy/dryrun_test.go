@@ -0,0 +1,55 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/transport"
+)
+
+func TestProxy_DryRun(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	p, err := New(Config{
+		Transport: &transport.SigningTransport{
+			TargetURL: server.URL + "/mcp",
+			Signer:    noopSigner{},
+			Headers:   map[string]string{"X-Amz-Security-Token": "session-token"},
+			Logger:    logger,
+		},
+		Logger: logger,
+		DryRun: true,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if err := p.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if got := hits.Load(); got != 0 {
+		t.Errorf("target received %d requests, want 0", got)
+	}
+	output := logs.String()
+	for _, want := range []string{"dry run: request signed but not sent", "dry run complete", "method=POST", "[REDACTED]"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("logs missing %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "session-token") {
+		t.Errorf("logs contain the session token:\n%s", output)
+	}
+}
@@ -0,0 +1,81 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/proxyerr"
+)
+
+// defaultHealthCheckTimeout bounds the health check when the context has no deadline
+const defaultHealthCheckTimeout = 10 * time.Second
+
+// checkTargetHealth sends an unsigned HEAD request to the target server to verify
+// basic network connectivity before any signed requests are made.
+//
+// Any HTTP response means the server is reachable. IAM-protected servers reject
+// unsigned requests with 401 or 403, which is expected and logged at debug level
+// so that later signed failures can be attributed to credentials rather than networking.
+//
+// When fallback URLs are configured, they are checked in order after an
+// unreachable primary, and the check passes if any target is reachable, since
+// the transport fails over to it.
+func (p *Proxy) checkTargetHealth(ctx context.Context) error {
+	client, err := p.transport.UnsignedClient()
+	if err != nil {
+		return proxyerr.Wrap(proxyerr.InvalidConfig, err, "failed to create health check client")
+	}
+
+	primaryErr := p.checkURLHealth(ctx, client, p.transport.TargetURL)
+	if primaryErr == nil {
+		return nil
+	}
+	for _, fallbackURL := range p.transport.FallbackURLs {
+		p.logger.Warn("target server is unreachable, checking the next fallback URL", "error", primaryErr, "fallback", fallbackURL)
+		if p.checkURLHealth(ctx, client, fallbackURL) == nil {
+			return nil
+		}
+	}
+	return primaryErr
+}
+
+// checkURLHealth sends the unsigned HEAD request to one target URL
+func (p *Proxy) checkURLHealth(ctx context.Context, client *http.Client, baseURL string) error {
+	targetURL := strings.TrimSuffix(baseURL, "/")
+	if p.healthCheckPath != "" {
+		targetURL += "/" + strings.TrimPrefix(p.healthCheckPath, "/")
+	}
+
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, defaultHealthCheckTimeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, targetURL, nil)
+	if err != nil {
+		return proxyerr.Wrap(proxyerr.InvalidConfig, err, "invalid health check URL %s", targetURL)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return proxyerr.Wrap(proxyerr.ConnectionFailed, err,
+			"failed to connect to target MCP server: target server at %s is unreachable "+
+				"(check network connectivity, proxy settings, and target server availability)",
+			targetURL)
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		p.logger.Debug("target server is reachable and requires authentication", "url", targetURL, "status", resp.StatusCode)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		p.logger.Warn("target server is reachable but reported an error", "url", targetURL, "status", resp.StatusCode)
+	default:
+		p.logger.Debug("target server is reachable", "url", targetURL, "status", resp.StatusCode)
+	}
+
+	return nil
+}
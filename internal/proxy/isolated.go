@@ -0,0 +1,45 @@
+package proxy
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// targetSession returns the target session that serves requests from a client
+// session: its isolated session when it has one, otherwise the shared session
+func (p *Proxy) targetSession(session *mcp.ServerSession) *mcp.ClientSession {
+	if session != nil {
+		if target, ok := p.isolatedTargets.Load(session); ok {
+			return target.(*mcp.ClientSession)
+		}
+	}
+	return p.clientSession.Load()
+}
+
+// connectIsolated connects a new client to the target server for a client
+// session. If the connection fails, the session is served by the shared
+// target session instead. Capabilities are still discovered and registered
+// through the shared session, since every session reaches the same target.
+func (p *Proxy) connectIsolated(ctx context.Context, session *mcp.ServerSession) {
+	client := mcp.NewClient(p.implementation, p.clientOptions)
+	target, err := client.Connect(ctx, p.transport, nil)
+	if err != nil {
+		p.logger.Warn("failed to connect an isolated target session; using the shared session",
+			"session_id", session.ID(), "error", err)
+		return
+	}
+	p.isolatedTargets.Store(session, target)
+	p.logger.Debug("isolated target session connected", "session_id", session.ID(), "target_session_id", target.ID())
+}
+
+// closeIsolated closes the isolated target session of a client session that disconnected
+func (p *Proxy) closeIsolated(session *mcp.ServerSession) {
+	target, ok := p.isolatedTargets.LoadAndDelete(session)
+	if !ok {
+		return
+	}
+	if err := target.(*mcp.ClientSession).Close(); err != nil {
+		p.logger.Debug("failed to close isolated target session", "session_id", session.ID(), "error", err)
+	}
+}
@@ -0,0 +1,76 @@
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// targetSessionID calls the whoami tool and returns the target session it reached
+func targetSessionID(t *testing.T, session *mcp.ClientSession) string {
+	t.Helper()
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "whoami"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result.Content[0].(*mcp.TextContent).Text
+}
+
+func TestProxy_IsolatedSessions(t *testing.T) {
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "whoami", InputSchema: map[string]any{"type": "object"}},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: req.Session.ID()}}}, nil
+		})
+
+	p := connectHTTPTarget(t, target, Config{IsolatedSessions: true})
+	newClient := func() *mcp.Client {
+		return mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v1.0.0"}, nil)
+	}
+	first := connectClient(t, p, newClient())
+	second := connectClient(t, p, newClient())
+	waitForSessions(t, p, 2)
+
+	shared := p.clientSession.Load().ID()
+	firstID, secondID := targetSessionID(t, first), targetSessionID(t, second)
+	if firstID == shared || secondID == shared || firstID == secondID {
+		t.Errorf("target sessions = %q and %q (shared %q), want a distinct session per client", firstID, secondID, shared)
+	}
+	if got := targetSessionID(t, first); got != firstID {
+		t.Errorf("second call reached target session %q, want %q", got, firstID)
+	}
+
+	// A disconnected client's target session is closed
+	second.Close()
+	waitForSessions(t, p, 1)
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		count := 0
+		p.isolatedTargets.Range(func(_, _ any) bool { count++; return true })
+		if count == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("isolated target sessions = %d after a client disconnected, want 1", count)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestProxy_SharedSessionByDefault(t *testing.T) {
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "whoami", InputSchema: map[string]any{"type": "object"}},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: req.Session.ID()}}}, nil
+		})
+
+	p := connectHTTPTarget(t, target, Config{})
+	session := connectClient(t, p, mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v1.0.0"}, nil))
+	waitForSessions(t, p, 1)
+
+	if got, want := targetSessionID(t, session), p.clientSession.Load().ID(); got != want {
+		t.Errorf("call reached target session %q, want the shared session %q", got, want)
+	}
+}
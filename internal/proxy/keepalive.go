@@ -0,0 +1,64 @@
+package proxy
+
+import (
+	"context"
+	"time"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/proxyerr"
+)
+
+// pingLoop pings the target server every ping interval until the context is
+// cancelled. When a ping fails or goes unanswered for the ping timeout, the
+// target session is reconnected.
+func (p *Proxy) pingLoop(ctx context.Context) {
+	ticker := time.NewTicker(p.pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.pingTarget(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				p.targetActive.Store(false)
+				p.logger.Warn("target server did not respond to ping; reconnecting", "timeout", p.pingTimeout, "error", err)
+				if err := p.reconnectTarget(ctx); err != nil {
+					p.logger.Warn("failed to reconnect to target server", "error", err)
+					continue
+				}
+			}
+			if ctx.Err() == nil {
+				p.targetActive.Store(true)
+			}
+		}
+	}
+}
+
+// pingTarget pings the target server, waiting up to the ping timeout
+func (p *Proxy) pingTarget(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, p.pingTimeout)
+	defer cancel()
+	return p.clientSession.Load().Ping(ctx, nil)
+}
+
+// reconnectTarget replaces the target session with a new one, closes the old
+// session, and refreshes the forwarded capabilities from the new session. The
+// old session stays in place if the new one cannot be established, so the next
+// ping retries the reconnect.
+func (p *Proxy) reconnectTarget(ctx context.Context) error {
+	session, err := p.client.Connect(ctx, p.transport, nil)
+	if err != nil {
+		return proxyerr.Wrap(proxyerr.ConnectionFailed, err, "failed to reconnect to target MCP server at %s", p.transport.TargetURL)
+	}
+	old := p.clientSession.Swap(session)
+	_ = old.Close()
+	p.logger.Info("reconnected to target server", "session_id", session.ID())
+
+	if err := p.refreshForwarding(ctx); err != nil {
+		p.logger.Warn("failed to refresh target capabilities after reconnecting; keeping current registrations", "error", err)
+	}
+	return nil
+}
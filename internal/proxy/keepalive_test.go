@@ -0,0 +1,69 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestProxy_PingReconnectsUnresponsiveTarget(t *testing.T) {
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "alpha", InputSchema: map[string]any{"type": "object"}}, echoTool)
+
+	// An unresponsive target holds pings until they are abandoned
+	var unresponsive atomic.Bool
+	target.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method == "ping" && unresponsive.Load() {
+				select {
+				case <-ctx.Done():
+				case <-time.After(time.Second):
+				}
+				return nil, errors.New("ping abandoned")
+			}
+			return next(ctx, method, req)
+		}
+	})
+
+	p := connectHTTPTarget(t, target, Config{
+		PingInterval: 20 * time.Millisecond,
+		PingTimeout:  50 * time.Millisecond,
+	})
+	session := p.clientSession.Load()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.pingLoop(ctx)
+
+	// Answered pings keep the session
+	time.Sleep(100 * time.Millisecond)
+	if p.clientSession.Load() != session {
+		t.Fatal("expected a responsive target session to be kept")
+	}
+
+	unresponsive.Store(true)
+	target.AddTool(&mcp.Tool{Name: "beta", InputSchema: map[string]any{"type": "object"}}, echoTool)
+	deadline := time.Now().Add(2 * time.Second)
+	for p.clientSession.Load() == session && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	unresponsive.Store(false)
+	if p.clientSession.Load() == session {
+		t.Fatal("expected the target session to be reconnected after an unanswered ping")
+	}
+
+	// The new session reaches the target and the forwarded tools were refreshed
+	if err := p.pingTarget(ctx); err != nil {
+		t.Errorf("pingTarget() after reconnecting unexpected error: %v", err)
+	}
+	p.refreshMu.Lock()
+	_, ok := p.forwarded.tools["beta"]
+	p.refreshMu.Unlock()
+	if !ok {
+		t.Error("expected the forwarded tools to be refreshed after reconnecting")
+	}
+}
@@ -0,0 +1,50 @@
+package proxy
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// forwardLog relays a log notification from the target server to every
+// connected client session. Each session only receives messages at or above
+// the level its client set.
+func (p *Proxy) forwardLog(ctx context.Context, req *mcp.LoggingMessageRequest) {
+	sessions := p.activeSessions()
+	for _, session := range sessions {
+		if err := session.Log(ctx, req.Params); err != nil {
+			p.logger.Warn("failed to forward log notification", "session_id", session.ID(), "error", err)
+		}
+	}
+	p.logger.Debug("forwarded log notification", "level", req.Params.Level, "logger", req.Params.Logger, "sessions", len(sessions))
+}
+
+// propagateLogLevel is server middleware that passes a client's
+// logging/setLevel request on to the target server once the proxy has applied
+// it to the client session. The target has a single level for the proxy, so
+// the most recent request from any client wins. Failures are logged rather
+// than returned, since the client's own level was set.
+func (p *Proxy) propagateLogLevel(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		result, err := next(ctx, method, req)
+		if err != nil || method != "logging/setLevel" {
+			return result, err
+		}
+		params, ok := req.GetParams().(*mcp.SetLoggingLevelParams)
+		target := p.clientSession.Load()
+		if !ok || target == nil {
+			return result, nil
+		}
+		if init := target.InitializeResult(); init == nil || init.Capabilities == nil || init.Capabilities.Logging == nil {
+			p.logger.Debug("target server does not support logging; log level not propagated", "level", params.Level)
+			return result, nil
+		}
+
+		targetCtx, cancel := p.withMethodTimeout(ctx, method)
+		defer cancel()
+		if err := target.SetLoggingLevel(targetCtx, &mcp.SetLoggingLevelParams{Level: params.Level}); err != nil {
+			p.logger.Warn("failed to set the target server's log level", "level", params.Level, "error", err)
+		}
+		return result, nil
+	}
+}
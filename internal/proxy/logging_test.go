@@ -0,0 +1,44 @@
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestProxy_LogForwarding(t *testing.T) {
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "work", InputSchema: map[string]any{"type": "object"}},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			// The debug message is below the level propagated to the target
+			req.Session.Log(ctx, &mcp.LoggingMessageParams{Level: "debug", Logger: "target", Data: "details"})
+			req.Session.Log(ctx, &mcp.LoggingMessageParams{Level: "info", Logger: "target", Data: "working"})
+			return &mcp.CallToolResult{}, nil
+		})
+
+	messages := make(chan *mcp.LoggingMessageParams, 10)
+	_, session := newInMemoryProxy(t, target, Config{EnableLogForwarding: true}, &mcp.ClientOptions{
+		LoggingMessageHandler: func(ctx context.Context, req *mcp.LoggingMessageRequest) {
+			messages <- req.Params
+		},
+	})
+
+	ctx := context.Background()
+	if err := session.SetLoggingLevel(ctx, &mcp.SetLoggingLevelParams{Level: "info"}); err != nil {
+		t.Fatalf("SetLoggingLevel() unexpected error: %v", err)
+	}
+	if _, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "work"}); err != nil {
+		t.Fatalf("CallTool() unexpected error: %v", err)
+	}
+
+	select {
+	case msg := <-messages:
+		if msg.Level != "info" || msg.Logger != "target" || msg.Data != "working" {
+			t.Errorf("forwarded log = %+v, want the target's info message", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the forwarded log notification")
+	}
+}
@@ -0,0 +1,77 @@
+package proxy
+
+import (
+	"context"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// trackSession records an initialized client session until it closes
+func (p *Proxy) trackSession(ctx context.Context, req *mcp.InitializedRequest) {
+	session := req.Session
+
+	// Connect before the session is tracked so its requests are not sent over
+	// the shared target session once it has an isolated one
+	if p.isolatedSessions {
+		p.connectIsolated(context.WithoutCancel(ctx), session)
+	}
+
+	p.sessionsMu.Lock()
+	p.sessions = append(p.sessions, session)
+	p.sessionsMu.Unlock()
+	p.logger.Debug("client session initialized", "session_id", session.ID())
+
+	// Listing roots is a request to the client, which cannot be answered while
+	// this handler holds up the session
+	if p.enableRootsForwarding {
+		go p.forwardRoots(context.WithoutCancel(ctx), session)
+	}
+
+	go func() {
+		_ = session.Wait()
+
+		p.sessionsMu.Lock()
+		p.sessions = slices.DeleteFunc(p.sessions, func(s *mcp.ServerSession) bool { return s == session })
+		p.sessionsMu.Unlock()
+		p.closeIsolated(session)
+		p.logger.Debug("client session closed", "session_id", session.ID())
+	}()
+}
+
+// ActiveSessions returns the number of connected client sessions. With
+// isolated sessions, each one holds its own connection to the target server.
+func (p *Proxy) ActiveSessions() int {
+	return len(p.activeSessions())
+}
+
+// activeSessions returns a snapshot of the initialized client sessions
+func (p *Proxy) activeSessions() []*mcp.ServerSession {
+	p.sessionsMu.RLock()
+	defer p.sessionsMu.RUnlock()
+	return slices.Clone(p.sessions)
+}
+
+// handleListChanged responds to a list-changed notification from the target
+// server by refreshing the forwarded capabilities. The proxy server notifies
+// every connected client session when its registrations change, so refreshing
+// re-emits the notification to clients whenever the target's list actually differs.
+//
+// The refresh runs in the background because notification handlers run on the
+// target connection, which must stay free to deliver the list responses.
+func (p *Proxy) handleListChanged(ctx context.Context, method string) {
+	if !p.forwarding.Load() {
+		p.logger.Debug("ignoring notification received before forwarding was set up", "method", method)
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		if err := p.refreshForwarding(ctx); err != nil {
+			p.logger.Warn("failed to refresh target capabilities; keeping current registrations",
+				"method", method, "error", err)
+			return
+		}
+		p.logger.Debug("forwarded notification", "method", method, "sessions", len(p.activeSessions()))
+	}()
+}
@@ -0,0 +1,84 @@
+package proxy
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestProxy_ForwardsListChangedNotifications(t *testing.T) {
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "alpha", InputSchema: map[string]any{"type": "object"}}, echoTool)
+	target.AddPrompt(&mcp.Prompt{Name: "greeting"}, func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{}, nil
+	})
+
+	toolsChanged := make(chan struct{}, 1)
+	promptsChanged := make(chan struct{}, 1)
+	p, session := newInMemoryProxy(t, target, Config{}, &mcp.ClientOptions{
+		ToolListChangedHandler: func(ctx context.Context, req *mcp.ToolListChangedRequest) {
+			select {
+			case toolsChanged <- struct{}{}:
+			default:
+			}
+		},
+		PromptListChangedHandler: func(ctx context.Context, req *mcp.PromptListChangedRequest) {
+			select {
+			case promptsChanged <- struct{}{}:
+			default:
+			}
+		},
+	})
+
+	// The downstream client session is tracked once initialized
+	waitForSessions(t, p, 1)
+
+	// Drop the notifications sent for the initial registrations
+	time.Sleep(50 * time.Millisecond)
+	drain(toolsChanged)
+	drain(promptsChanged)
+
+	target.AddTool(&mcp.Tool{Name: "beta", InputSchema: map[string]any{"type": "object"}}, echoTool)
+	select {
+	case <-toolsChanged:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected tools/list_changed to be forwarded to the client")
+	}
+	if got := toolNames(t, session); !slices.Equal(got, []string{"alpha", "beta"}) {
+		t.Errorf("tools after notification = %v, want [alpha beta]", got)
+	}
+
+	target.RemovePrompts("greeting")
+	select {
+	case <-promptsChanged:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected prompts/list_changed to be forwarded to the client")
+	}
+
+	// Closed sessions are no longer tracked
+	session.Close()
+	waitForSessions(t, p, 0)
+}
+
+// waitForSessions waits for the proxy to track want client sessions
+func waitForSessions(t *testing.T, p *Proxy, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for len(p.activeSessions()) != want && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := len(p.activeSessions()); got != want {
+		t.Fatalf("active sessions = %d, want %d", got, want)
+	}
+}
+
+// drain discards a pending signal, if any
+func drain(ch chan struct{}) {
+	select {
+	case <-ch:
+	default:
+	}
+}
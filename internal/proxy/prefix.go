@@ -0,0 +1,42 @@
+package proxy
+
+import (
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/proxyerr"
+)
+
+// maxNameLength is the longest tool name the MCP specification allows
+const maxNameLength = 128
+
+// validateNamePrefix checks that prefix keeps forwarded names valid per the
+// MCP specification, which limits tool names to letters, digits, '_', '-',
+// and '.'. The same rule is applied to prompt names so a prefix behaves alike
+// for both. An empty prefix is valid.
+func validateNamePrefix(kind, prefix string) error {
+	if len(prefix) >= maxNameLength {
+		return proxyerr.New(proxyerr.InvalidConfig, "%s name prefix must be shorter than %d characters", kind, maxNameLength)
+	}
+	for _, r := range prefix {
+		if !validNameRune(r) {
+			return proxyerr.New(proxyerr.InvalidConfig, "%s name prefix %q contains invalid character %q; use letters, digits, '_', '-', or '.'", kind, prefix, r)
+		}
+	}
+	return nil
+}
+
+// validNameRune reports whether r may appear in a tool name
+func validNameRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+		r == '_' || r == '-' || r == '.'
+}
+
+// prefixNames returns names with prefix prepended to each
+func prefixNames(prefix string, names []string) []string {
+	if prefix == "" {
+		return names
+	}
+	prefixed := make([]string, len(names))
+	for i, name := range names {
+		prefixed[i] = prefix + name
+	}
+	return prefixed
+}
@@ -0,0 +1,90 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/proxyerr"
+)
+
+func TestProxy_NamePrefixes(t *testing.T) {
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "search", InputSchema: map[string]any{"type": "object"}}, echoTool)
+	target.AddTool(&mcp.Tool{Name: "lookup", InputSchema: map[string]any{"type": "object"}}, echoTool)
+	target.AddPrompt(&mcp.Prompt{Name: "greet"}, func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{Description: req.Params.Name}, nil
+	})
+
+	p, session := newInMemoryProxy(t, target, Config{ToolNamePrefix: "billing_", PromptNamePrefix: "billing."}, nil)
+	ctx := context.Background()
+
+	if got, want := toolNames(t, session), []string{"billing_lookup", "billing_search"}; !slices.Equal(got, want) {
+		t.Fatalf("tools = %v, want %v", got, want)
+	}
+
+	// The target receives the unprefixed name
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "billing_search"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; text != "search" {
+		t.Errorf("target tool name = %q, want %q", text, "search")
+	}
+	if _, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "search"}); err == nil {
+		t.Error("CallTool() with the unprefixed name succeeded, want error")
+	}
+
+	prompt, err := session.GetPrompt(ctx, &mcp.GetPromptParams{Name: "billing.greet"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prompt.Description != "greet" {
+		t.Errorf("target prompt name = %q, want %q", prompt.Description, "greet")
+	}
+
+	// Tools removed from the target are removed under their prefixed names
+	target.RemoveTools("lookup")
+	if err := p.refreshForwarding(ctx); err != nil {
+		t.Fatalf("refreshForwarding() unexpected error: %v", err)
+	}
+	if got, want := toolNames(t, session), []string{"billing_search"}; !slices.Equal(got, want) {
+		t.Errorf("tools after removal = %v, want %v", got, want)
+	}
+}
+
+func TestValidateNamePrefix(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefix  string
+		wantErr string
+	}{
+		{name: "empty", prefix: ""},
+		{name: "valid", prefix: "billing-v2.api_"},
+		{name: "space", prefix: "billing ", wantErr: `invalid character ' '`},
+		{name: "slash", prefix: "billing/", wantErr: `invalid character '/'`},
+		{name: "too long", prefix: strings.Repeat("a", maxNameLength), wantErr: "shorter than 128 characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateNamePrefix("tool", tt.prefix)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateNamePrefix() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validateNamePrefix() error = %v, want containing %q", err, tt.wantErr)
+			}
+			var proxyErr *proxyerr.ProxyError
+			if !errors.As(err, &proxyErr) || proxyErr.Code != proxyerr.InvalidConfig {
+				t.Errorf("validateNamePrefix() error = %v, want InvalidConfig", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package proxy
+
+import (
+	"net/http"
+)
+
+// Ready reports whether the proxy is serving clients: forwarding has been set
+// up and the target session is active. It is false before Run connects to the
+// target, while an unanswered ping is being recovered from, and once Run
+// begins shutting down.
+func (p *Proxy) Ready() bool {
+	return p.forwarding.Load() && p.targetActive.Load()
+}
+
+// ProbeHandler returns an HTTP handler for orchestrator health probes, such as
+// Kubernetes liveness and readiness probes, which cannot use the stdio MCP
+// protocol. GET /live answers 200 OK while the process is running; GET /ready
+// answers 200 OK when Ready reports true and 503 Service Unavailable otherwise.
+func (p *Proxy) ProbeHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /live", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok\n"))
+	})
+	mux.HandleFunc("GET /ready", func(w http.ResponseWriter, r *http.Request) {
+		if !p.Ready() {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok\n"))
+	})
+	return mux
+}
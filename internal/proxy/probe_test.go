@@ -0,0 +1,51 @@
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/transport"
+)
+
+// probe sends a request to the proxy's probe handler and returns the status code
+func probe(p *Proxy, method, path string) int {
+	rec := httptest.NewRecorder()
+	p.ProbeHandler().ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec.Code
+}
+
+func TestProxy_ProbeHandler(t *testing.T) {
+	// Before Run connects to the target, the proxy is live but not ready
+	p, err := New(Config{Transport: &transport.SigningTransport{TargetURL: "https://example.com", Signer: noopSigner{}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := probe(p, http.MethodGet, "/live"); got != http.StatusOK {
+		t.Errorf("GET /live before connecting = %d, want %d", got, http.StatusOK)
+	}
+	if got := probe(p, http.MethodGet, "/ready"); got != http.StatusServiceUnavailable {
+		t.Errorf("GET /ready before connecting = %d, want %d", got, http.StatusServiceUnavailable)
+	}
+
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "alpha", InputSchema: map[string]any{"type": "object"}}, echoTool)
+	p = connectTarget(t, target, Config{})
+	if got := probe(p, http.MethodGet, "/ready"); got != http.StatusOK {
+		t.Errorf("GET /ready after forwarding is set up = %d, want %d", got, http.StatusOK)
+	}
+
+	// An unresponsive target session makes the proxy unready until it is reconnected
+	p.targetActive.Store(false)
+	if got := probe(p, http.MethodGet, "/ready"); got != http.StatusServiceUnavailable {
+		t.Errorf("GET /ready with an inactive target session = %d, want %d", got, http.StatusServiceUnavailable)
+	}
+	if got := probe(p, http.MethodGet, "/live"); got != http.StatusOK {
+		t.Errorf("GET /live with an inactive target session = %d, want %d", got, http.StatusOK)
+	}
+
+	if got := probe(p, http.MethodPost, "/ready"); got != http.StatusMethodNotAllowed {
+		t.Errorf("POST /ready = %d, want %d", got, http.StatusMethodNotAllowed)
+	}
+}
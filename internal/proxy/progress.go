@@ -0,0 +1,56 @@
+package proxy
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// progressListener routes progress notifications for one in-flight request
+// back to the client session that made it
+type progressListener struct {
+	session *mcp.ServerSession
+	token   any
+}
+
+// progressListenerLinger is how long a listener outlives its request. Notifications
+// are handled separately from responses, so progress sent just before the target
+// responds can be processed after the call has returned.
+const progressListenerLinger = time.Second
+
+// listenForProgress registers a listener for progress notifications about an
+// in-flight target request and returns the token to send to the target along
+// with a function that removes the listener once the request completes.
+// Clients choose their own tokens, so each request gets a proxy-assigned token
+// to keep concurrent clients apart.
+func (p *Proxy) listenForProgress(session *mcp.ServerSession, token any) (any, func()) {
+	proxyToken := "sigv4-proxy-" + strconv.FormatInt(p.progressSeq.Add(1), 10)
+	p.progressListeners.Store(proxyToken, progressListener{session: session, token: token})
+	return proxyToken, func() {
+		time.AfterFunc(progressListenerLinger, func() { p.progressListeners.Delete(proxyToken) })
+	}
+}
+
+// forwardProgress relays a progress notification from the target server to the
+// client session that made the request, restoring the client's progress token.
+// Notifications for requests that have already completed are dropped.
+func (p *Proxy) forwardProgress(ctx context.Context, req *mcp.ProgressNotificationClientRequest) {
+	token, ok := req.Params.ProgressToken.(string)
+	if !ok {
+		return
+	}
+	value, ok := p.progressListeners.Load(token)
+	if !ok {
+		p.logger.Debug("dropping progress notification for completed request", "progress_token", token)
+		return
+	}
+	listener := value.(progressListener)
+
+	params := *req.Params
+	params.ProgressToken = listener.token
+	if err := listener.session.NotifyProgress(ctx, &params); err != nil {
+		p.logger.Warn("failed to forward progress notification", "session_id", listener.session.ID(), "error", err)
+	}
+}
@@ -0,0 +1,88 @@
+package proxy
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestProxy_ForwardsProgressNotifications(t *testing.T) {
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "slow", InputSchema: map[string]any{"type": "object"}},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			for i := 1; i <= 2; i++ {
+				if err := req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+					ProgressToken: req.Params.GetProgressToken(),
+					Progress:      float64(i),
+					Total:         2,
+				}); err != nil {
+					return nil, err
+				}
+			}
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "done"}}}, nil
+		})
+
+	var mu sync.Mutex
+	var received []*mcp.ProgressNotificationParams
+	p, session := newInMemoryProxy(t, target, Config{}, &mcp.ClientOptions{
+		ProgressNotificationHandler: func(ctx context.Context, req *mcp.ProgressNotificationClientRequest) {
+			mu.Lock()
+			defer mu.Unlock()
+			received = append(received, req.Params)
+		},
+	})
+	waitForSessions(t, p, 1)
+
+	params := &mcp.CallToolParams{Name: "slow", Meta: mcp.Meta{}}
+	params.SetProgressToken("client-token")
+	if _, err := session.CallTool(context.Background(), params); err != nil {
+		t.Fatalf("CallTool() unexpected error: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		n := len(received)
+		mu.Unlock()
+		if n >= 2 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 {
+		t.Fatalf("received %d progress notifications, want 2", len(received))
+	}
+	for i, params := range received {
+		if params.ProgressToken != "client-token" {
+			t.Errorf("notification %d token = %v, want the client's token", i, params.ProgressToken)
+		}
+		if params.Progress != float64(i+1) || params.Total != 2 {
+			t.Errorf("notification %d progress = %v/%v, want %d/2", i, params.Progress, params.Total, i+1)
+		}
+	}
+
+	// The listener is removed shortly after the call completes
+	deadline = time.Now().Add(progressListenerLinger + 2*time.Second)
+	for progressListenerCount(p) > 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	if n := progressListenerCount(p); n != 0 {
+		t.Errorf("%d progress listeners still registered after the call completed", n)
+	}
+}
+
+// progressListenerCount returns the number of registered progress listeners
+func progressListenerCount(p *Proxy) int {
+	n := 0
+	p.progressListeners.Range(func(key, value any) bool {
+		n++
+		return true
+	})
+	return n
+}
@@ -3,9 +3,16 @@ package proxy
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/config"
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/proxyerr"
 	"github.com/nisimpson/mcp-sigv4-proxy/internal/transport"
 )
 
@@ -13,23 +20,167 @@ import (
 // from clients to an IAM-authenticated target MCP server.
 //
 // The proxy acts as a transparent intermediary:
-// - It accepts MCP protocol messages from clients via stdio
+// - It accepts MCP protocol messages from clients via stdio or WebSocket
 // - It forwards messages to the target MCP server via HTTP with AWS SigV4/SigV4a signing
 // - It returns responses from the target server back to the client
 type Proxy struct {
-	// server is the MCP server that accepts client connections via stdio
+	// server is the MCP server that accepts client connections via stdio, or
+	// over WebSocket when listenAddr is set
 	server *mcp.Server
 
 	// client is the MCP client that connects to the target server
 	client *mcp.Client
 
+	// clientOptions configures client and the clients of isolated target sessions
+	clientOptions *mcp.ClientOptions
+
+	// isolatedSessions gives each client session its own target session
+	isolatedSessions bool
+
+	// listenAddr is the address clients connect to over WebSocket, or empty for stdio
+	listenAddr string
+
+	// toolNamePrefix and promptNamePrefix are prepended to the names of the
+	// forwarded tools and prompts
+	toolNamePrefix   string
+	promptNamePrefix string
+
+	// forwardResponseHeaders are the target response headers added to the
+	// _meta of forwarded results
+	forwardResponseHeaders []string
+
+	// isolatedTargets maps client sessions to their isolated target sessions
+	isolatedTargets sync.Map
+
 	// transport is the signing transport used to connect to the target
 	transport *transport.SigningTransport
 
-	// clientSession is the active session with the target server
-	clientSession *mcp.ClientSession
+	// clientSession is the active session with the target server, replaced
+	// when the session is reconnected
+	clientSession atomic.Pointer[mcp.ClientSession]
+
+	// logger records capability discovery and forwarding events
+	logger *slog.Logger
+
+	// config is the live proxy configuration, replaced on reload
+	config atomic.Pointer[config.Config]
+
+	// skipHealthCheck disables the connectivity check before connecting
+	skipHealthCheck bool
+
+	// healthCheckPath is appended to the target URL for the connectivity check
+	healthCheckPath string
+
+	// refreshInterval is how often the forwarded capabilities are refreshed (0 disables refresh)
+	refreshInterval time.Duration
+
+	// pingInterval is how often the target session is pinged (0 disables pings)
+	pingInterval time.Duration
+
+	// pingTimeout is how long a ping may take before the target session is reconnected
+	pingTimeout time.Duration
+
+	// maxToolPages caps the number of tool list pages fetched from the target
+	maxToolPages int
+
+	// maxResourcePages caps the number of resource list pages fetched from the target
+	maxResourcePages int
+
+	// maxPromptPages caps the number of prompt list pages fetched from the target
+	maxPromptPages int
+
+	// refreshMu serializes capability refreshes and guards the forwarded registrations
+	refreshMu sync.Mutex
+
+	// rootsMu serializes root forwarding and guards the forwarded roots
+	rootsMu sync.Mutex
+
+	// enableRootsForwarding relays client roots to the target server
+	enableRootsForwarding bool
+
+	// forwarded tracks the capabilities currently registered on the server
+	forwarded forwardedCapabilities
+
+	// lastRefresh is the time of the last successful capability refresh, in Unix nanoseconds
+	lastRefresh atomic.Int64
+
+	// forwarding is set once the client session is established and capabilities
+	// are registered; list-changed notifications received earlier are ignored
+	forwarding atomic.Bool
+
+	// targetActive is set while the target session answers requests; it is
+	// cleared when a ping goes unanswered until the session is reconnected,
+	// and when Run begins shutting down
+	targetActive atomic.Bool
+
+	// sessionsMu guards sessions
+	sessionsMu sync.RWMutex
+
+	// sessions are the initialized client sessions connected to the proxy server
+	sessions []*mcp.ServerSession
+
+	// progressListeners maps proxy-assigned progress tokens to progressListener values
+	progressListeners sync.Map
+
+	// progressSeq generates unique progress tokens for forwarded requests
+	progressSeq atomic.Int64
+
+	// methodTimeouts bounds target calls by MCP method name
+	methodTimeouts map[string]time.Duration
+
+	// drainTimeout is how long in-flight client requests may run after shutdown begins
+	drainTimeout time.Duration
+
+	// requests tracks the in-flight client requests
+	requests requestTracker
+
+	// implementation identifies the proxy to its clients and to the target server
+	implementation *mcp.Implementation
+
+	// dryRun signs the initialize request without sending it, then returns from Run
+	dryRun bool
+
+	// validateToolArguments rejects tool calls whose arguments do not match the tool's input schema
+	validateToolArguments bool
+
+	// toolSchemas caches compiled tool input schemas by tool name
+	toolSchemas sync.Map
+
+	// enableDeduplication shares one target call between concurrent identical
+	// calls to read-only or idempotent tools
+	enableDeduplication bool
+
+	// sharedCalls tracks the in-flight deduplicated tool calls
+	sharedCalls   map[sharedCallKey]*sharedCall
+	sharedCallsMu sync.Mutex
+
+	// DeduplicationHits counts tool calls answered with the result of an
+	// identical call already in flight
+	DeduplicationHits atomic.Int64
+
+	// DeduplicationMisses counts deduplicated tool calls that were forwarded
+	// to the target server
+	DeduplicationMisses atomic.Int64
+
+	// responseCache caches target responses for the configured methods (nil when disabled)
+	responseCache *responseCache
+
+	// toolCache caches tool call results with a TTL per tool (nil when disabled)
+	toolCache *toolResultCache
+
+	// warmupCalls are made at startup to fill the tool result cache
+	warmupCalls []WarmupCall
+
+	// clientLimiters limits the request rate of each client (nil when disabled)
+	clientLimiters *clientLimiters
 }
 
+// defaultMaxPages is the default cap on pages fetched when listing capabilities
+const defaultMaxPages = 100
+
+// defaultPingTimeout is how long a ping may take when no ping timeout is set
+const defaultPingTimeout = 10 * time.Second
+
 // Config holds the configuration for creating a new Proxy
 type Config struct {
 	// Transport is the signing transport for connecting to the target server
@@ -40,6 +191,133 @@ type Config struct {
 
 	// ServerVersion is the version of the proxy server
 	ServerVersion string
+
+	// Logger is the structured logger for proxy events (optional, defaults to discarding output)
+	Logger *slog.Logger
+
+	// ProxyConfig is the loaded proxy configuration, used as the baseline for
+	// hot reloads (optional)
+	ProxyConfig *config.Config
+
+	// SkipHealthCheck disables the unsigned connectivity check performed before
+	// connecting to the target server
+	SkipHealthCheck bool
+
+	// HealthCheckPath is appended to the target URL for the connectivity check
+	// (optional, defaults to the target URL itself)
+	HealthCheckPath string
+
+	// RefreshInterval is how often the target server's tools, resources, and
+	// prompts are re-listed and the forwarded set updated (optional, 0 disables refresh)
+	RefreshInterval time.Duration
+
+	// PingInterval is how often the target session is pinged to keep it alive
+	// through network intermediaries (optional, 0 disables pings)
+	PingInterval time.Duration
+
+	// PingTimeout is how long to wait for a ping response before the target
+	// session is closed and reconnected (optional, defaults to 10s)
+	PingTimeout time.Duration
+
+	// EnableSamplingForwarding advertises the sampling capability to the target
+	// server and forwards its sampling/createMessage requests to the connected
+	// client. This only works correctly when a single client is connected,
+	// since sampling requests cannot be addressed to a particular client.
+	EnableSamplingForwarding bool
+
+	// EnableRootsForwarding relays the roots announced by connected clients to
+	// the target server, keeping them in sync as clients report changes
+	EnableRootsForwarding bool
+
+	// EnableLogForwarding relays log notifications from the target server to
+	// connected clients and their logging/setLevel requests to the target server
+	EnableLogForwarding bool
+
+	// IsolatedSessions connects each client to the target server with its own
+	// session, so a slow or failing call from one client does not hold up the
+	// others. The trade-off is one target connection per connected client in
+	// addition to the shared session used to discover capabilities.
+	IsolatedSessions bool
+
+	// ListenAddr accepts any number of clients over WebSocket on this address,
+	// such as "127.0.0.1:8080", instead of a single client over stdio (optional)
+	ListenAddr string
+
+	// ToolNamePrefix is prepended to the name of every forwarded tool, such as
+	// "billing_" for billing_search, so a client connected to several proxies
+	// can tell their tools apart. The prefix is stripped before calls are
+	// forwarded to the target server (optional)
+	ToolNamePrefix string
+
+	// PromptNamePrefix is prepended to the name of every forwarded prompt and
+	// stripped before prompt requests are forwarded (optional)
+	PromptNamePrefix string
+
+	// ForwardResponseHeaders lists the target response headers, such as
+	// X-Amzn-Requestid or X-RateLimit-Remaining, added to the _meta of tool,
+	// resource, and prompt results under their lowercase names (optional,
+	// defaults to DefaultForwardResponseHeaders; an empty list forwards none)
+	ForwardResponseHeaders []string
+
+	// MaxToolPages caps the number of pages fetched when listing the target
+	// server's tools (optional, defaults to 100)
+	MaxToolPages int
+
+	// MaxResourcePages caps the number of pages fetched when listing the target
+	// server's resources (optional, defaults to 100)
+	MaxResourcePages int
+
+	// MaxPromptPages caps the number of pages fetched when listing the target
+	// server's prompts (optional, defaults to 100)
+	MaxPromptPages int
+
+	// MethodTimeouts bounds target calls by MCP method name, such as "tools/call"
+	// or "tools/list" (optional, methods without an entry use the transport timeout)
+	MethodTimeouts map[string]time.Duration
+
+	// DrainTimeout is how long in-flight client requests may run after the Run
+	// context is cancelled before they are cancelled too (optional, 0 cancels
+	// them immediately)
+	DrainTimeout time.Duration
+
+	// DryRun makes Run sign the initialize request for the target, log the
+	// signed headers, and return without sending it or serving clients
+	DryRun bool
+
+	// ValidateToolArguments validates tool call arguments against the tool's
+	// input schema before forwarding, answering invalid calls with an Invalid
+	// params (-32602) error that lists each schema violation
+	ValidateToolArguments bool
+
+	// EnableDeduplication lets concurrent calls to the same tool with the same
+	// arguments share a single call to the target server. Only tools annotated
+	// as read-only or idempotent are deduplicated.
+	EnableDeduplication bool
+
+	// ResponseCache caches target responses to the listed methods so repeated
+	// identical requests are answered without calling the target (optional,
+	// disabled by default)
+	ResponseCache ResponseCacheConfig
+
+	// ToolResultCache caches tool call results with a TTL for each tool, so
+	// repeated identical calls are answered without calling the target. When
+	// enabled, it replaces ResponseCache for tools/call (optional, disabled
+	// by default)
+	ToolResultCache ToolResultCacheConfig
+
+	// WarmCacheOnStartup lists tool calls made once forwarding is set up, so
+	// their results are in the tool result cache before the first client
+	// request (optional, requires ToolResultCache)
+	WarmCacheOnStartup []WarmupCall
+
+	// PerClientRateLimit limits the requests of each client, rejecting
+	// requests over the limit with a "rate limit exceeded" (-32000) error
+	// (optional, disabled by default)
+	PerClientRateLimit PerClientRateLimit
+
+	// ClientLimiterEvictionIdle is how long the rate limiter of an idle
+	// client is kept (optional, defaults to 10 minutes)
+	ClientLimiterEvictionIdle time.Duration
 }
 
 // New creates a new Proxy instance with the given configuration.
@@ -50,7 +328,7 @@ type Config struct {
 // - Wire up message forwarding between client and target
 func New(cfg Config) (*Proxy, error) {
 	if cfg.Transport == nil {
-		return nil, fmt.Errorf("transport is required")
+		return nil, proxyerr.New(proxyerr.InvalidConfig, "transport is required")
 	}
 
 	// Set defaults
@@ -60,36 +338,180 @@ func New(cfg Config) (*Proxy, error) {
 	if cfg.ServerVersion == "" {
 		cfg.ServerVersion = "v1.0.0"
 	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.New(slog.DiscardHandler)
+	}
+	if cfg.PingTimeout <= 0 {
+		cfg.PingTimeout = defaultPingTimeout
+	}
+	if cfg.MaxToolPages <= 0 {
+		cfg.MaxToolPages = defaultMaxPages
+	}
+	if cfg.MaxResourcePages <= 0 {
+		cfg.MaxResourcePages = defaultMaxPages
+	}
+	if cfg.MaxPromptPages <= 0 {
+		cfg.MaxPromptPages = defaultMaxPages
+	}
+	if cfg.ForwardResponseHeaders == nil {
+		cfg.ForwardResponseHeaders = DefaultForwardResponseHeaders
+	}
+	if err := validateNamePrefix("tool", cfg.ToolNamePrefix); err != nil {
+		return nil, err
+	}
+	if err := validateNamePrefix("prompt", cfg.PromptNamePrefix); err != nil {
+		return nil, err
+	}
+	if cfg.ToolNamePrefix != "" {
+		cfg.Logger.Info("prefixing forwarded tool names", "prefix", cfg.ToolNamePrefix)
+	}
+	if cfg.PromptNamePrefix != "" {
+		cfg.Logger.Info("prefixing forwarded prompt names", "prefix", cfg.PromptNamePrefix)
+	}
+
+	proxy := &Proxy{
+		transport:              cfg.Transport,
+		logger:                 cfg.Logger,
+		skipHealthCheck:        cfg.SkipHealthCheck,
+		healthCheckPath:        cfg.HealthCheckPath,
+		refreshInterval:        cfg.RefreshInterval,
+		pingInterval:           cfg.PingInterval,
+		pingTimeout:            cfg.PingTimeout,
+		maxToolPages:           cfg.MaxToolPages,
+		maxResourcePages:       cfg.MaxResourcePages,
+		maxPromptPages:         cfg.MaxPromptPages,
+		forwarded:              newForwardedCapabilities(),
+		enableRootsForwarding:  cfg.EnableRootsForwarding,
+		isolatedSessions:       cfg.IsolatedSessions,
+		listenAddr:             cfg.ListenAddr,
+		toolNamePrefix:         cfg.ToolNamePrefix,
+		promptNamePrefix:       cfg.PromptNamePrefix,
+		forwardResponseHeaders: cfg.ForwardResponseHeaders,
+		drainTimeout:           cfg.DrainTimeout,
+		methodTimeouts:         cfg.MethodTimeouts,
+		implementation:         &mcp.Implementation{Name: cfg.ServerName, Version: cfg.ServerVersion},
+		dryRun:                 cfg.DryRun,
+		validateToolArguments:  cfg.ValidateToolArguments,
+		enableDeduplication:    cfg.EnableDeduplication,
+		responseCache:          newResponseCache(cfg.ResponseCache),
+		toolCache:              newToolResultCache(cfg.ToolResultCache),
+		warmupCalls:            cfg.WarmCacheOnStartup,
+		clientLimiters:         newClientLimiters(cfg.PerClientRateLimit, cfg.ClientLimiterEvictionIdle),
+	}
 
 	// Create the MCP server for client-facing interface (stdio)
-	server := mcp.NewServer(&mcp.Implementation{
-		Name:    cfg.ServerName,
-		Version: cfg.ServerVersion,
-	}, nil)
+	proxy.server = mcp.NewServer(proxy.implementation, &mcp.ServerOptions{
+		InitializedHandler: proxy.trackSession,
+		RootsListChangedHandler: func(ctx context.Context, req *mcp.RootsListChangedRequest) {
+			if proxy.enableRootsForwarding {
+				go proxy.forwardRoots(context.WithoutCancel(ctx), req.Session)
+			}
+		},
+	})
+	proxy.server.AddReceivingMiddleware(proxy.trackRequests)
+	if proxy.clientLimiters != nil {
+		proxy.server.AddReceivingMiddleware(proxy.limitClients)
+	}
+	if cfg.EnableLogForwarding {
+		proxy.server.AddReceivingMiddleware(proxy.propagateLogLevel)
+	}
+	// Added last so it is the outermost middleware and recovers panics in the others too
+	proxy.server.AddReceivingMiddleware(proxy.panicRecovery)
 
 	// Create the MCP client for target connection with signing transport
-	client := mcp.NewClient(&mcp.Implementation{
-		Name:    cfg.ServerName,
-		Version: cfg.ServerVersion,
-	}, nil)
+	clientOptions := &mcp.ClientOptions{
+		ToolListChangedHandler: func(ctx context.Context, req *mcp.ToolListChangedRequest) {
+			proxy.handleListChanged(ctx, "notifications/tools/list_changed")
+		},
+		ResourceListChangedHandler: func(ctx context.Context, req *mcp.ResourceListChangedRequest) {
+			proxy.handleListChanged(ctx, "notifications/resources/list_changed")
+		},
+		PromptListChangedHandler: func(ctx context.Context, req *mcp.PromptListChangedRequest) {
+			proxy.handleListChanged(ctx, "notifications/prompts/list_changed")
+		},
+		ProgressNotificationHandler: proxy.forwardProgress,
+	}
+	if cfg.EnableSamplingForwarding {
+		// Setting the handler advertises the sampling capability to the target
+		clientOptions.CreateMessageHandler = proxy.forwardCreateMessage
+	}
+	if cfg.EnableLogForwarding {
+		clientOptions.LoggingMessageHandler = proxy.forwardLog
+	}
+	proxy.clientOptions = clientOptions
+	proxy.client = mcp.NewClient(proxy.implementation, clientOptions)
 
-	proxy := &Proxy{
-		server:    server,
-		client:    client,
-		transport: cfg.Transport,
+	if cfg.ProxyConfig != nil {
+		proxy.config.Store(cfg.ProxyConfig)
 	}
 
 	return proxy, nil
 }
 
+// Config returns the live proxy configuration, or nil if none was provided.
+func (p *Proxy) Config() *config.Config {
+	return p.config.Load()
+}
+
+// Reload applies a new configuration to the running proxy and returns the
+// fields that changed. Headers, Timeout, EnableSSE, and the rate limit are applied live;
+// changes to fields that require a restart are logged as warnings and
+// otherwise ignored until the proxy is restarted. The caller is responsible
+// for validating next before calling Reload.
+func (p *Proxy) Reload(next *config.Config) []config.FieldChange {
+	changes := config.Diff(p.config.Load(), next)
+	for _, change := range changes {
+		if change.RequiresRestart() {
+			p.logger.Warn("configuration change requires a restart to take effect", "field", change.Field)
+			continue
+		}
+		if change.Sensitive() {
+			p.logger.Info("configuration changed", "field", change.Field)
+			continue
+		}
+		p.logger.Info("configuration changed", "field", change.Field, "old", change.Old, "new", change.New)
+	}
+
+	// Keep the current limiter unless the rate changed so its token state is preserved
+	limiter := p.transport.CurrentSettings().RateLimiter
+	if current := p.config.Load(); current == nil ||
+		current.RateLimitRPS != next.RateLimitRPS || current.RateLimitBurst != next.RateLimitBurst {
+		limiter = nil
+		if next.RateLimitRPS > 0 {
+			limiter = transport.NewTokenBucketLimiter(next.RateLimitRPS, next.RateLimitBurst)
+		}
+	}
+
+	p.transport.UpdateSettings(transport.Settings{
+		Headers:     next.HeaderMap(),
+		Timeout:     next.Timeout,
+		EnableSSE:   next.EnableSSE,
+		RateLimiter: limiter,
+	})
+	p.config.Store(next)
+
+	return changes
+}
+
 // Run starts the proxy server and handles message forwarding.
 //
 // It performs the following steps:
-// 1. Connects to the target MCP server using the signing transport
-// 2. Discovers the target server's capabilities (tools, resources, prompts)
-// 3. Registers forwarding handlers for all discovered capabilities
-// 4. Accepts client connections via stdio and forwards messages
-// 5. Runs until the context is cancelled or an error occurs
+// 1. Verifies the target server is reachable with an unsigned request (unless skipped)
+// 2. Connects to the target MCP server using the signing transport
+// 3. Discovers the target server's capabilities (tools, resources, prompts)
+// 4. Registers forwarding handlers for all discovered capabilities
+// 5. Refreshes the forwarded capabilities periodically (if a refresh interval is set)
+// 6. Pings the target, reconnecting when it stops responding (if a ping interval is set)
+// 7. Accepts client connections via stdio, or over WebSocket on the listen
+// address, and forwards messages
+// 8. Runs until the context is cancelled or an error occurs
+//
+// When the context is cancelled, new client requests are rejected and the
+// in-flight ones are given up to the drain timeout to finish before the
+// server stops and cancels them.
+//
+// In dry-run mode, Run only signs the initialize request and logs it; no
+// requests reach the target and no clients are served.
 //
 // The proxy is transparent - it forwards all MCP protocol messages
 // (tools, resources, prompts, etc.) without modification.
@@ -98,32 +520,98 @@ func New(cfg Config) (*Proxy, error) {
 // - Returns descriptive errors if connection to target fails (network errors)
 // - Returns descriptive errors if signing fails (credential/configuration errors)
 // - Forwards target server errors to clients unchanged
+//
+// Cancellation:
+// The SDK cancels a handler's context when the client sends notifications/cancelled
+// for its request. Handlers pass that context to the target call, so the SDK
+// aborts the in-flight target request and sends notifications/cancelled upstream.
 func (p *Proxy) Run(ctx context.Context) error {
-	// Connect to the target MCP server using the signing transport
-	clientSession, err := p.client.Connect(ctx, p.transport, nil)
+	if p.dryRun {
+		return p.signDryRun(ctx)
+	}
+
+	// Verify basic connectivity before signing so network problems are not
+	// reported as credential or protocol errors
+	if !p.skipHealthCheck {
+		if err := p.checkTargetHealth(ctx); err != nil {
+			return err
+		}
+	}
+
+	// Connect to the target MCP server using the signing transport, noting the
+	// response headers to check the forwarded header names against
+	connectCtx, handshakeHeaders := p.collectResponseHeaders(ctx)
+	clientSession, err := p.client.Connect(connectCtx, p.transport, nil)
 	if err != nil {
+		if !p.skipHealthCheck {
+			// The target is reachable, so the failure is most likely authentication or configuration
+			return proxyerr.Wrap(proxyerr.ConnectionFailed, err,
+				"failed to connect to target MCP server at %s "+
+					"(the target is reachable; check AWS credentials, region, and service name)",
+				p.transport.TargetURL)
+		}
 		// Provide descriptive error message for connection failures
 		// This could be due to network issues, signing errors, or target server problems
-		return fmt.Errorf(
-			"failed to connect to target MCP server at %s: %w "+
+		return proxyerr.Wrap(proxyerr.ConnectionFailed, err,
+			"failed to connect to target MCP server at %s "+
 				"(check network connectivity, AWS credentials, and target server availability)",
-			p.transport.TargetURL, err)
+			p.transport.TargetURL)
+	}
+	if handshakeHeaders != nil {
+		p.warnUnseenResponseHeaders(handshakeHeaders.Header())
 	}
-	defer clientSession.Close()
 
 	// Store the client session for use in forwarding handlers
-	p.clientSession = clientSession
+	p.clientSession.Store(clientSession)
+	defer func() { p.clientSession.Load().Close() }()
 
 	// Discover and register the target server's capabilities
 	if err := p.setupForwarding(ctx); err != nil {
-		return fmt.Errorf("failed to setup message forwarding: %w", err)
+		return proxyerr.Wrap(proxyerr.TargetError, err, "failed to setup message forwarding")
+	}
+	p.forwarding.Store(true)
+	p.targetActive.Store(true)
+	defer p.targetActive.Store(false)
+
+	// Fill the tool result cache before accepting client requests
+	p.warmCache(ctx)
+
+	// Keep the forwarded capabilities in sync with the target until Run returns
+	if p.refreshInterval > 0 {
+		refreshCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go p.refreshLoop(refreshCtx)
+	}
+
+	// Keep the target session alive, reconnecting it when pings go unanswered
+	if p.pingInterval > 0 {
+		pingCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go p.pingLoop(pingCtx)
+	}
+
+	// Keep serving in-flight requests after ctx is cancelled until they drain
+	serverCtx, cancelServer := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelServer()
+	stopDrain := context.AfterFunc(ctx, func() {
+		p.targetActive.Store(false)
+		if p.drainTimeout > 0 {
+			p.drain()
+		}
+		cancelServer()
+	})
+	defer stopDrain()
+
+	// Accept any number of clients over WebSocket when a listen address is set
+	if p.listenAddr != "" {
+		return p.serveWebSocket(serverCtx)
 	}
 
 	// Run the server on stdio transport
 	// This will accept client connections and forward messages to the target
 	stdinTransport := &mcp.StdioTransport{}
-	if err := p.server.Run(ctx, stdinTransport); err != nil {
-		return fmt.Errorf("proxy server failed: %w", err)
+	if err := p.server.Run(serverCtx, stdinTransport); err != nil {
+		return proxyerr.Wrap(proxyerr.ConnectionFailed, err, "proxy server failed")
 	}
 
 	return nil
@@ -135,109 +623,210 @@ func (p *Proxy) Run(ctx context.Context) error {
 // This makes the proxy transparent - all message types are forwarded
 // without modification.
 func (p *Proxy) setupForwarding(ctx context.Context) error {
-	if p.clientSession == nil {
-		return fmt.Errorf("not connected to target server")
+	if p.clientSession.Load() == nil {
+		return proxyerr.New(proxyerr.ConnectionFailed, "not connected to target server")
 	}
 
-	// Discover and forward tools
-	toolsResult, err := p.clientSession.ListTools(ctx, &mcp.ListToolsParams{})
-	if err != nil {
-		// If listing tools fails, it might not be supported - continue anyway
-		// The error will be returned to clients when they try to use tools
-	} else {
-		for _, tool := range toolsResult.Tools {
-			// Create a handler that forwards to the target server
-			p.server.AddTool(tool, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-				// Convert raw params to CallToolParams
-				// The Arguments field is json.RawMessage, which we pass as-is
-				var args any
-				if len(req.Params.Arguments) > 0 {
-					if unmarshalErr := json.Unmarshal(req.Params.Arguments, &args); unmarshalErr != nil {
-						return nil, fmt.Errorf("failed to unmarshal tool arguments: %w", unmarshalErr)
-					}
-				}
-
-				params := &mcp.CallToolParams{
-					Name:      req.Params.Name,
-					Arguments: args,
-				}
-
-				progressToken := req.Params.GetProgressToken()
-				if progressToken != nil {
-					params.SetProgressToken(progressToken)
-				}
-
-				// Forward the tool call to the target server
-				// Errors from the target server are forwarded unchanged to the client
-				result, callErr := p.clientSession.CallTool(ctx, params)
-				if callErr != nil {
-					// Forward target server errors unchanged (Requirement 7.3)
-					return nil, callErr
-				}
-				return result, nil
-			})
-		}
+	// Capabilities that cannot be listed might not be supported - continue anyway
+	// The error will be returned to clients when they try to use them
+	if err := p.refreshForwarding(ctx); err != nil {
+		p.logger.Debug("target server did not list all capabilities", "error", err)
 	}
+	return nil
+}
 
-	// Discover and forward resources
-	resourcesResult, err := p.clientSession.ListResources(ctx, &mcp.ListResourcesParams{})
-	if err != nil {
-		// If listing resources fails, it might not be supported - continue anyway
-	} else {
-		for _, resource := range resourcesResult.Resources {
-			// Create a handler that forwards to the target server
-			p.server.AddResource(resource, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
-				// Forward the resource read to the target server
-				// Errors from the target server are forwarded unchanged to the client
-				result, readErr := p.clientSession.ReadResource(ctx, req.Params)
-				if readErr != nil {
-					// Forward target server errors unchanged (Requirement 7.3)
-					return nil, readErr
-				}
-				return result, nil
-			})
+// forwardTool registers a handler that forwards calls to the tool to the target server
+func (p *Proxy) forwardTool(tool *mcp.Tool) {
+	// The tool may have been registered before with a different schema
+	p.toolSchemas.Delete(tool.Name)
+
+	registered := tool
+	if p.toolNamePrefix != "" {
+		prefixed := *tool
+		prefixed.Name = p.toolNamePrefix + tool.Name
+		registered = &prefixed
+	}
+
+	p.server.AddTool(registered, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx = contextWithTraceMeta(ctx, req.Params.GetMeta())
+
+		if p.validateToolArguments {
+			if err := p.checkToolArguments(tool, req.Params.Arguments); err != nil {
+				return nil, err
+			}
+		}
+
+		call := func() (*mcp.CallToolResult, error) {
+			if p.deduplicates(tool, req) {
+				return p.deduplicateToolCall(ctx, req, p.callTool)
+			}
+			return p.callTool(ctx, req)
+		}
+
+		// The tool result cache replaces the response cache for tool calls,
+		// so results are cached at most once, under the tool's TTL
+		if p.toolCache != nil {
+			return p.cachedToolCall(tool.Name, req.Params, call)
+		}
+		return cachedCall(p, "tools/call", req.Params, call)
+	})
+}
+
+// removeTools removes the forwarded tools with the given target names
+func (p *Proxy) removeTools(names ...string) {
+	p.server.RemoveTools(prefixNames(p.toolNamePrefix, names)...)
+}
+
+// removePrompts removes the forwarded prompts with the given target names
+func (p *Proxy) removePrompts(names ...string) {
+	p.server.RemovePrompts(prefixNames(p.promptNamePrefix, names)...)
+}
+
+// callTool forwards a tool call to the target server, relaying its progress
+// notifications to the calling client
+func (p *Proxy) callTool(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	// Convert raw params to CallToolParams
+	// The Arguments field is json.RawMessage, which we pass as-is
+	var args any
+	if len(req.Params.Arguments) > 0 {
+		if unmarshalErr := json.Unmarshal(req.Params.Arguments, &args); unmarshalErr != nil {
+			return nil, proxyerr.Wrap(proxyerr.InvalidRequest, unmarshalErr, "failed to unmarshal tool arguments")
 		}
 	}
 
-	// Discover and forward resource templates
-	templatesResult, err := p.clientSession.ListResourceTemplates(ctx, &mcp.ListResourceTemplatesParams{})
-	if err != nil {
-		// If listing templates fails, it might not be supported - continue anyway
-	} else {
-		for _, template := range templatesResult.ResourceTemplates {
-			// Create a handler that forwards to the target server
-			p.server.AddResourceTemplate(template, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
-				// Forward the resource read to the target server
-				// Errors from the target server are forwarded unchanged to the client
-				result, readErr := p.clientSession.ReadResource(ctx, req.Params)
-				if readErr != nil {
-					// Forward target server errors unchanged (Requirement 7.3)
-					return nil, readErr
-				}
-				return result, nil
-			})
+	params := &mcp.CallToolParams{
+		Name:      strings.TrimPrefix(req.Params.Name, p.toolNamePrefix),
+		Arguments: args,
+	}
+
+	// Relay the target's progress notifications to this client until the call completes
+	progressToken := req.Params.GetProgressToken()
+	if progressToken != nil {
+		targetToken, stopListening := p.listenForProgress(req.Session, progressToken)
+		defer stopListening()
+		// SetProgressToken only updates an existing _meta map
+		params.Meta = mcp.Meta{}
+		params.SetProgressToken(targetToken)
+	}
+
+	ctx, cancel := p.withMethodTimeout(ctx, "tools/call")
+	defer cancel()
+	ctx, headers := p.collectResponseHeaders(ctx)
+
+	// Forward the tool call to the target server
+	// Errors from the target server are forwarded unchanged to the client
+	result, callErr := p.targetSession(req.Session).CallTool(ctx, params)
+	if callErr != nil {
+		// Forward target server errors unchanged (Requirement 7.3)
+		return nil, callErr
+	}
+	result.Meta = p.withResponseHeaderMeta(result.Meta, headers)
+	return result, nil
+}
+
+// forwardResource registers a handler that forwards reads of the resource to the target server
+func (p *Proxy) forwardResource(resource *mcp.Resource) {
+	p.server.AddResource(resource, p.readResource)
+}
+
+// forwardResourceTemplate registers a handler that forwards reads of resources
+// matching the template to the target server, rejecting URIs that leave
+// template variables unset
+func (p *Proxy) forwardResourceTemplate(template *mcp.ResourceTemplate) {
+	p.server.AddResourceTemplate(template, p.readTemplateResource(template))
+}
+
+// readResource forwards a resource read to the target server
+func (p *Proxy) readResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	ctx = contextWithTraceMeta(ctx, req.Params.GetMeta())
+	ctx, cancel := p.withMethodTimeout(ctx, "resources/read")
+	defer cancel()
+
+	// Forward the resource read to the target server
+	// Errors from the target server are forwarded unchanged to the client
+	result, readErr := cachedCall(p, "resources/read", req.Params, func() (*mcp.ReadResourceResult, error) {
+		ctx, headers := p.collectResponseHeaders(ctx)
+		result, err := p.targetSession(req.Session).ReadResource(ctx, req.Params)
+		if err != nil {
+			return nil, err
 		}
+		result.Meta = p.withResponseHeaderMeta(result.Meta, headers)
+		return result, nil
+	})
+	if readErr != nil {
+		// Forward target server errors unchanged (Requirement 7.3)
+		return nil, readErr
 	}
+	return result, nil
+}
 
-	// Discover and forward prompts
-	promptsResult, err := p.clientSession.ListPrompts(ctx, &mcp.ListPromptsParams{})
-	if err != nil {
-		// If listing prompts fails, it might not be supported - continue anyway
+// forwardPrompt registers a handler that forwards prompt requests to the target server
+func (p *Proxy) forwardPrompt(prompt *mcp.Prompt) {
+	registered := prompt
+	if p.promptNamePrefix != "" {
+		prefixed := *prompt
+		prefixed.Name = p.promptNamePrefix + prompt.Name
+		registered = &prefixed
+	}
+
+	p.server.AddPrompt(registered, func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		ctx = contextWithTraceMeta(ctx, req.Params.GetMeta())
+		ctx, cancel := p.withMethodTimeout(ctx, "prompts/get")
+		defer cancel()
+
+		// The target knows the prompt by its unprefixed name
+		params := *req.Params
+		params.Name = prompt.Name
+
+		// Forward the prompt request to the target server
+		// Errors from the target server are forwarded unchanged to the client
+		result, err := cachedCall(p, "prompts/get", &params, func() (*mcp.GetPromptResult, error) {
+			ctx, headers := p.collectResponseHeaders(ctx)
+			result, err := p.targetSession(req.Session).GetPrompt(ctx, &params)
+			if err != nil {
+				return nil, err
+			}
+			result.Meta = p.withResponseHeaderMeta(result.Meta, headers)
+			return result, nil
+		})
+		if err != nil {
+			// Forward target server errors unchanged (Requirement 7.3)
+			return nil, err
+		}
+		return result, nil
+	})
+}
+
+// withMethodTimeout bounds ctx by the timeout configured for an MCP method,
+// falling back to the transport's live request timeout. A method timeout also
+// replaces the transport timeout for the target requests made with ctx, so a
+// method may be given longer than the default.
+func (p *Proxy) withMethodTimeout(ctx context.Context, method string) (context.Context, context.CancelFunc) {
+	timeout, ok := p.methodTimeouts[method]
+	if ok {
+		ctx = transport.ContextWithRequestTimeout(ctx, timeout)
 	} else {
-		for _, prompt := range promptsResult.Prompts {
-			// Create a handler that forwards to the target server
-			p.server.AddPrompt(prompt, func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
-				// Forward the prompt request to the target server
-				// Errors from the target server are forwarded unchanged to the client
-				result, err := p.clientSession.GetPrompt(ctx, req.Params)
-				if err != nil {
-					// Forward target server errors unchanged (Requirement 7.3)
-					return nil, err
-				}
-				return result, nil
-			})
+		timeout = p.transport.CurrentSettings().Timeout
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// contextWithTraceMeta copies trace context entries (such as traceparent) from an
+// MCP request's _meta onto the context so the signing transport forwards them as
+// HTTP headers to the target server.
+func contextWithTraceMeta(ctx context.Context, meta map[string]any) context.Context {
+	headers := make(http.Header)
+	for _, name := range transport.DefaultPropagateHeaders {
+		if value, ok := meta[name].(string); ok && value != "" {
+			headers.Set(name, value)
 		}
 	}
 
-	return nil
+	if len(headers) == 0 {
+		return ctx
+	}
+	return transport.ContextWithPropagatedHeaders(ctx, headers)
 }
@@ -7,6 +7,8 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/proxyerr"
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
 	"github.com/nisimpson/mcp-sigv4-proxy/internal/transport"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -37,7 +39,7 @@ func TestErrorHandling_UnreachableTarget(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create a mock signer
-			signer := &mockErrorSigner{}
+			signer := &testutil.FakeSigner{}
 
 			// Create the signing transport with unreachable target
 			signingTransport := &transport.SigningTransport{
@@ -61,6 +63,10 @@ func TestErrorHandling_UnreachableTarget(t *testing.T) {
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), tt.wantErr)
 			assert.Contains(t, err.Error(), "check network connectivity")
+
+			var proxyErr *proxyerr.ProxyError
+			require.True(t, errors.As(err, &proxyErr))
+			assert.Equal(t, proxyerr.ConnectionFailed, proxyErr.Code)
 		})
 	}
 }
@@ -101,9 +107,7 @@ func TestErrorHandling_SigningFailure(t *testing.T) {
 			defer targetServer.Close()
 
 			// Create a signer that returns an error
-			signer := &mockErrorSigner{
-				signError: tt.signError,
-			}
+			signer := &testutil.FakeSigner{Err: tt.signError}
 
 			// Create the signing transport
 			signingTransport := &transport.SigningTransport{
@@ -155,6 +159,10 @@ func TestErrorHandling_MissingTransport(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "transport is required")
 	assert.Nil(t, proxy)
+
+	var proxyErr *proxyerr.ProxyError
+	require.True(t, errors.As(err, &proxyErr))
+	assert.Equal(t, proxyerr.InvalidConfig, proxyErr.Code)
 }
 
 // TestErrorHandling_NetworkErrorMessage tests that network errors include
@@ -172,7 +180,7 @@ func TestErrorHandling_NetworkErrorMessage(t *testing.T) {
 	server.Close() // Close the server to make it unreachable
 
 	// Create a mock signer
-	signer := &mockErrorSigner{}
+	signer := &testutil.FakeSigner{}
 
 	// Create the signing transport
 	signingTransport := &transport.SigningTransport{
@@ -192,27 +200,104 @@ func TestErrorHandling_NetworkErrorMessage(t *testing.T) {
 	ctx := context.Background()
 	err = proxy.Run(ctx)
 
-	// Verify the error message includes helpful information
+	// Verify the error message includes helpful information; the health check
+	// reports the target as unreachable without blaming AWS credentials
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to connect to target MCP server")
 	assert.Contains(t, err.Error(), server.URL)
+	assert.Contains(t, err.Error(), "is unreachable")
+	assert.Contains(t, err.Error(), "check network connectivity")
+	assert.Contains(t, err.Error(), "target server availability")
+	assert.NotContains(t, err.Error(), "AWS credentials")
+
+	// With the health check skipped, the connection error covers all causes
+	proxy, err = New(Config{
+		Transport:       signingTransport,
+		SkipHealthCheck: true,
+	})
+	require.NoError(t, err)
+
+	err = proxy.Run(ctx)
+	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "check network connectivity")
 	assert.Contains(t, err.Error(), "AWS credentials")
 	assert.Contains(t, err.Error(), "target server availability")
 }
 
-// mockErrorSigner is a test implementation of the Signer interface
-// that can be configured to return errors
-type mockErrorSigner struct {
-	signError error
+// TestErrorHandling_HealthCheck tests that the unsigned connectivity check
+// distinguishes unreachable targets from authentication failures.
+func TestErrorHandling_HealthCheck(t *testing.T) {
+	var healthCheckPath string
+	var signedAttempts int
+	targetServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			healthCheckPath = r.URL.Path
+			assert.Empty(t, r.Header.Get("Authorization"), "health check must not be signed")
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		signedAttempts++
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer targetServer.Close()
+
+	signingTransport := &transport.SigningTransport{
+		TargetURL: targetServer.URL + "/mcp",
+		Signer:    &testutil.FakeSigner{},
+	}
+
+	proxy, err := New(Config{
+		Transport:       signingTransport,
+		HealthCheckPath: "/ping",
+	})
+	require.NoError(t, err)
+
+	err = proxy.Run(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, "/mcp/ping", healthCheckPath)
+	assert.Positive(t, signedAttempts)
+	assert.Contains(t, err.Error(), "the target is reachable")
+	assert.Contains(t, err.Error(), "check AWS credentials")
 }
 
-func (m *mockErrorSigner) SignRequest(ctx context.Context, req *http.Request, payloadHash string) error {
-	if m.signError != nil {
-		return m.signError
+// TestErrorHandling_HealthCheckFallback tests that the connectivity check
+// passes when the primary target is down but a fallback is reachable.
+func TestErrorHandling_HealthCheckFallback(t *testing.T) {
+	dead := httptest.NewServer(http.NotFoundHandler())
+	dead.Close()
+	live := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer live.Close()
+
+	tests := []struct {
+		name      string
+		fallbacks []string
+		wantErr   bool
+	}{
+		{name: "live fallback", fallbacks: []string{dead.URL, live.URL}},
+		{name: "no live fallback", fallbacks: []string{dead.URL}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy, err := New(Config{
+				Transport: &transport.SigningTransport{
+					TargetURL:    dead.URL,
+					FallbackURLs: tt.fallbacks,
+					Signer:       &testutil.FakeSigner{},
+				},
+			})
+			require.NoError(t, err)
+
+			err = proxy.checkTargetHealth(context.Background())
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.True(t, proxyerr.HasCode(err, proxyerr.ConnectionFailed))
+			assert.Contains(t, err.Error(), dead.URL)
+		})
 	}
-	// Add a test signature header to verify signing occurred
-	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential=test/20240101/us-east-1/execute-api/aws4_request")
-	req.Header.Set("X-Amz-Date", "20240101T000000Z")
-	return nil
 }
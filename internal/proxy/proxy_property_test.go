@@ -10,6 +10,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
 	"pgregory.net/rapid"
 )
 
@@ -156,7 +157,7 @@ func TestProperty8_ResponseTransparency(t *testing.T) {
 		defer targetServer.Close()
 
 		// Create a mock signer
-		signer := &mockSigner{}
+		signer := &testutil.FakeSigner{}
 
 		// Create an HTTP client that uses the signing transport
 		client := &http.Client{
@@ -255,7 +256,7 @@ func TestProperty8_ErrorResponseTransparency(t *testing.T) {
 		defer targetServer.Close()
 
 		// Create a mock signer
-		signer := &mockSigner{}
+		signer := &testutil.FakeSigner{}
 
 		// Create an HTTP client that uses the signing transport
 		client := &http.Client{
@@ -365,7 +366,7 @@ func TestProperty8_ResponseStatusCodePreservation(t *testing.T) {
 		defer targetServer.Close()
 
 		// Create a mock signer
-		signer := &mockSigner{}
+		signer := &testutil.FakeSigner{}
 
 		// Create an HTTP client that uses the signing transport
 		client := &http.Client{
@@ -396,27 +397,10 @@ func TestProperty8_ResponseStatusCodePreservation(t *testing.T) {
 	})
 }
 
-// mockSigner is a test implementation of the Signer interface
-type mockSigner struct {
-	signedRequests []*http.Request
-	signError      error
-}
-
-func (m *mockSigner) SignRequest(ctx context.Context, req *http.Request, payloadHash string) error {
-	if m.signError != nil {
-		return m.signError
-	}
-	m.signedRequests = append(m.signedRequests, req)
-	// Add a test signature header to verify signing occurred
-	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential=test/20240101/us-east-1/execute-api/aws4_request")
-	req.Header.Set("X-Amz-Date", "20240101T000000Z")
-	return nil
-}
-
 // signingRoundTripper wraps an http.RoundTripper and signs all requests
 type signingRoundTripper struct {
 	transport http.RoundTripper
-	signer    *mockSigner
+	signer    *testutil.FakeSigner
 	ctx       context.Context
 }
 
@@ -0,0 +1,163 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/config"
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/transport"
+)
+
+// noopSigner is a signer that leaves requests unchanged
+type noopSigner struct{}
+
+func (noopSigner) SignRequest(ctx context.Context, req *http.Request, payloadHash string) error {
+	return nil
+}
+
+// captureRoundTripper records the headers of the last request instead of sending it
+type captureRoundTripper struct {
+	headers http.Header
+}
+
+func (c *captureRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.headers = req.Header.Clone()
+	return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: http.NoBody}, nil
+}
+
+func TestContextWithTraceMeta(t *testing.T) {
+	tests := []struct {
+		name        string
+		meta        map[string]any
+		wantHeaders http.Header
+	}{
+		{
+			name: "trace headers are copied",
+			meta: map[string]any{
+				"traceparent":   "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+				"tracestate":    "vendor=value",
+				"progressToken": "abc",
+			},
+			wantHeaders: http.Header{
+				"Traceparent": {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+				"Tracestate":  {"vendor=value"},
+			},
+		},
+		{
+			name:        "non-string values are ignored",
+			meta:        map[string]any{"traceparent": 42},
+			wantHeaders: nil,
+		},
+		{
+			name:        "nil meta",
+			meta:        nil,
+			wantHeaders: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := context.Background()
+			ctx := contextWithTraceMeta(parent, tt.meta)
+
+			if tt.wantHeaders == nil {
+				if ctx != parent {
+					t.Error("expected the original context when no trace metadata is present")
+				}
+				return
+			}
+
+			// Verify the headers reach the target by sending a request through the signing transport
+			capture := &captureRoundTripper{}
+			rt := transport.NewSigningRoundTripper(capture, noopSigner{}, nil)
+
+			req, err := http.NewRequestWithContext(ctx, "GET", "http://example.com", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := rt.RoundTrip(req); err != nil {
+				t.Fatal(err)
+			}
+			for key, values := range tt.wantHeaders {
+				if got := capture.headers.Get(key); got != values[0] {
+					t.Errorf("header %s = %q, want %q", key, got, values[0])
+				}
+			}
+		})
+	}
+}
+
+func TestProxy_Reload(t *testing.T) {
+	signingTransport := &transport.SigningTransport{
+		TargetURL: "https://example.com",
+		Signer:    noopSigner{},
+	}
+	initial := &config.Config{
+		TargetURL:        "https://example.com",
+		Region:           "us-east-1",
+		ServiceName:      "execute-api",
+		SignatureVersion: "v4",
+		Headers:          "X-Team=platform",
+		Timeout:          10 * time.Second,
+	}
+
+	var logs bytes.Buffer
+	p, err := New(Config{
+		Transport:   signingTransport,
+		Logger:      slog.New(slog.NewTextHandler(&logs, nil)),
+		ProxyConfig: initial,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Config() != initial {
+		t.Fatal("expected the initial configuration to be stored")
+	}
+
+	next := *initial
+	next.Headers = "X-Team=security"
+	next.Timeout = 30 * time.Second
+	next.EnableSSE = true
+	next.Region = "eu-west-1"
+	next.RateLimitRPS = 5
+
+	changes := p.Reload(&next)
+	if len(changes) != 5 {
+		t.Fatalf("expected 5 changes, got %d: %v", len(changes), changes)
+	}
+	if p.Config() != &next {
+		t.Error("expected the reloaded configuration to be stored")
+	}
+
+	settings := signingTransport.CurrentSettings()
+	if settings.Headers["X-Team"] != "security" {
+		t.Errorf("headers not applied: %v", settings.Headers)
+	}
+	if settings.Timeout != 30*time.Second {
+		t.Errorf("timeout not applied: %v", settings.Timeout)
+	}
+	if !settings.EnableSSE {
+		t.Error("EnableSSE not applied")
+	}
+	if settings.RateLimiter == nil {
+		t.Error("rate limiter not applied")
+	}
+
+	// Reloading without a rate change keeps the existing limiter
+	unchanged := next
+	p.Reload(&unchanged)
+	if signingTransport.CurrentSettings().RateLimiter != settings.RateLimiter {
+		t.Error("expected the rate limiter to be preserved when the rate is unchanged")
+	}
+	if !strings.Contains(logs.String(), "requires a restart") || !strings.Contains(logs.String(), "field=Region") {
+		t.Errorf("expected a restart warning for Region, got %q", logs.String())
+	}
+	if !strings.Contains(logs.String(), "field=Headers") || strings.Contains(logs.String(), "X-Team=") {
+		t.Errorf("expected the Headers change to be logged without its values, got %q", logs.String())
+	}
+}
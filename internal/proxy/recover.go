@@ -0,0 +1,45 @@
+package proxy
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// panicRecovery is server middleware that turns a panic in a request handler
+// into an Internal error (-32603) for that request, so one faulty handler does
+// not crash the proxy. The stack trace is logged with a correlation ID that is
+// also returned to the client, so the failure can be found in the logs.
+func (p *Proxy) panicRecovery(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (result mcp.Result, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				requestID := newCorrelationID()
+				p.logger.Error("recovered from panic in request handler",
+					"request_id", requestID,
+					"method", method,
+					"session_id", req.GetSession().ID(),
+					"panic", fmt.Sprint(r),
+					"stack", string(debug.Stack()))
+				result, err = nil, &jsonrpc.Error{
+					Code:    jsonrpc.CodeInternalError,
+					Message: fmt.Sprintf("internal proxy error (correlation ID %s)", requestID),
+				}
+			}
+		}()
+		return next(ctx, method, req)
+	}
+}
+
+// newCorrelationID returns a random ID that ties an error returned to a
+// client to its log record
+func newCorrelationID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
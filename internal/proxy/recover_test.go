@@ -0,0 +1,66 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestProxy_PanicRecovery(t *testing.T) {
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "lookup", InputSchema: map[string]any{"type": "object"}},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{}, nil
+		})
+
+	var logs bytes.Buffer
+	p := connectTarget(t, target, Config{Logger: slog.New(slog.NewJSONHandler(&logs, nil))})
+	p.server.AddTool(&mcp.Tool{Name: "broken", InputSchema: map[string]any{"type": "object"}},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var m map[string]int
+			m["boom"]++ // assignment to entry in nil map
+			return nil, nil
+		})
+	session := connectClient(t, p, mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v1.0.0"}, nil))
+	ctx := context.Background()
+
+	_, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "broken"})
+	var wireErr *jsonrpc.Error
+	if !errors.As(err, &wireErr) || wireErr.Code != jsonrpc.CodeInternalError {
+		t.Fatalf("CallTool(broken) error = %v, want code %d", err, jsonrpc.CodeInternalError)
+	}
+
+	var record struct {
+		Msg       string `json:"msg"`
+		RequestID string `json:"request_id"`
+		Method    string `json:"method"`
+		Stack     string `json:"stack"`
+	}
+	for line := range strings.Lines(logs.String()) {
+		if err := json.Unmarshal([]byte(line), &record); err == nil && record.Msg == "recovered from panic in request handler" {
+			break
+		}
+		record.Msg = ""
+	}
+	if record.Msg == "" {
+		t.Fatalf("panic was not logged, logs:\n%s", logs.String())
+	}
+	if record.Method != "tools/call" || record.Stack == "" {
+		t.Errorf("panic log method = %q, stack empty = %v, want tools/call with a stack", record.Method, record.Stack == "")
+	}
+	if record.RequestID == "" || !strings.Contains(wireErr.Message, record.RequestID) {
+		t.Errorf("error message %q does not contain the logged request ID %q", wireErr.Message, record.RequestID)
+	}
+
+	// The proxy keeps serving after the panic
+	if _, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "lookup"}); err != nil {
+		t.Errorf("CallTool(lookup) after a panic unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,269 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/sync/errgroup"
+)
+
+// forwardedCapabilities tracks the capabilities registered on the proxy server,
+// keyed by tool name, resource URI, template URI, and prompt name, and the
+// client roots relayed to the target server, keyed by URI
+type forwardedCapabilities struct {
+	tools             map[string]*mcp.Tool
+	resources         map[string]*mcp.Resource
+	resourceTemplates map[string]*mcp.ResourceTemplate
+	prompts           map[string]*mcp.Prompt
+	roots             map[string]*mcp.Root
+}
+
+func newForwardedCapabilities() forwardedCapabilities {
+	return forwardedCapabilities{
+		tools:             make(map[string]*mcp.Tool),
+		resources:         make(map[string]*mcp.Resource),
+		resourceTemplates: make(map[string]*mcp.ResourceTemplate),
+		prompts:           make(map[string]*mcp.Prompt),
+		roots:             make(map[string]*mcp.Root),
+	}
+}
+
+// LastRefreshTime returns the time the forwarded capabilities were last
+// successfully synchronized with the target server, or the zero time if they
+// never have been.
+func (p *Proxy) LastRefreshTime() time.Time {
+	nanos := p.lastRefresh.Load()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// refreshLoop refreshes the forwarded capabilities every refresh interval until
+// the context is cancelled. Failed refreshes are logged and the stale
+// registrations stay active.
+func (p *Proxy) refreshLoop(ctx context.Context) {
+	ticker := time.NewTicker(p.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.refreshForwarding(ctx); err != nil {
+				p.logger.Warn("failed to refresh target capabilities; keeping current registrations", "error", err)
+			}
+		}
+	}
+}
+
+// refreshForwarding lists the target server's tools, resources, resource
+// templates, and prompts, registers the ones that are new or changed, and
+// removes the ones that disappeared. Unchanged registrations are left in place.
+//
+// The four lists are fetched concurrently, so a target with slow list
+// endpoints delays startup by its slowest list rather than their sum.
+// Capabilities the target does not advertise are skipped. Lists are fetched
+// across all pages. If a page fails, the items from earlier pages are registered,
+// nothing of that kind is removed, and the error is returned.
+func (p *Proxy) refreshForwarding(ctx context.Context) error {
+	p.refreshMu.Lock()
+	defer p.refreshMu.Unlock()
+
+	caps := &mcp.ServerCapabilities{}
+	if result := p.clientSession.Load().InitializeResult(); result != nil && result.Capabilities != nil {
+		caps = result.Capabilities
+	}
+
+	// Each kind is synchronized independently, so failures are collected rather
+	// than returned to the group, which would report only the first of them
+	var g errgroup.Group
+	var errs [4]error
+
+	if caps.Tools != nil {
+		g.Go(func() error {
+			errs[0] = syncList(ctx, p, "tools", p.maxToolPages,
+				func(ctx context.Context, cursor string) ([]*mcp.Tool, string, error) {
+					ctx, cancel := p.withMethodTimeout(ctx, "tools/list")
+					defer cancel()
+					result, err := p.clientSession.Load().ListTools(ctx, &mcp.ListToolsParams{Cursor: cursor})
+					if err != nil {
+						return nil, "", err
+					}
+					return result.Tools, result.NextCursor, nil
+				},
+				p.forwarded.tools, func(tool *mcp.Tool) string { return tool.Name },
+				p.forwardTool, p.removeTools)
+			return nil
+		})
+	}
+
+	if caps.Resources != nil {
+		g.Go(func() error {
+			errs[1] = syncList(ctx, p, "resources", p.maxResourcePages,
+				func(ctx context.Context, cursor string) ([]*mcp.Resource, string, error) {
+					ctx, cancel := p.withMethodTimeout(ctx, "resources/list")
+					defer cancel()
+					result, err := p.clientSession.Load().ListResources(ctx, &mcp.ListResourcesParams{Cursor: cursor})
+					if err != nil {
+						return nil, "", err
+					}
+					return result.Resources, result.NextCursor, nil
+				},
+				p.forwarded.resources, func(resource *mcp.Resource) string { return resource.URI },
+				p.forwardResource, p.server.RemoveResources)
+			return nil
+		})
+
+		g.Go(func() error {
+			errs[2] = syncList(ctx, p, "resource templates", p.maxResourcePages,
+				func(ctx context.Context, cursor string) ([]*mcp.ResourceTemplate, string, error) {
+					ctx, cancel := p.withMethodTimeout(ctx, "resources/templates/list")
+					defer cancel()
+					result, err := p.clientSession.Load().ListResourceTemplates(ctx, &mcp.ListResourceTemplatesParams{Cursor: cursor})
+					if err != nil {
+						return nil, "", err
+					}
+					return result.ResourceTemplates, result.NextCursor, nil
+				},
+				p.forwarded.resourceTemplates, func(template *mcp.ResourceTemplate) string { return template.URITemplate },
+				p.forwardResourceTemplate, p.server.RemoveResourceTemplates)
+			return nil
+		})
+	}
+
+	if caps.Prompts != nil {
+		g.Go(func() error {
+			errs[3] = syncList(ctx, p, "prompts", p.maxPromptPages,
+				func(ctx context.Context, cursor string) ([]*mcp.Prompt, string, error) {
+					ctx, cancel := p.withMethodTimeout(ctx, "prompts/list")
+					defer cancel()
+					result, err := p.clientSession.Load().ListPrompts(ctx, &mcp.ListPromptsParams{Cursor: cursor})
+					if err != nil {
+						return nil, "", err
+					}
+					return result.Prompts, result.NextCursor, nil
+				},
+				p.forwarded.prompts, func(prompt *mcp.Prompt) string { return prompt.Name },
+				p.forwardPrompt, p.removePrompts)
+			return nil
+		})
+	}
+
+	g.Wait()
+	if err := errors.Join(errs[:]...); err != nil {
+		return err
+	}
+	p.lastRefresh.Store(time.Now().UnixNano())
+	return nil
+}
+
+// errPageLimit is returned by paginateList when pages remain after the page limit
+var errPageLimit = errors.New("pagination limit reached")
+
+// paginateList fetches a list page by page, starting from cursor and following
+// each page's next cursor for up to maxPages pages. It returns the items and the
+// number of pages fetched. If a fetch fails, the items from the earlier pages are
+// returned with the error; if pages remain after maxPages, the items fetched are
+// returned with errPageLimit.
+func paginateList[T any](ctx context.Context, fetch func(ctx context.Context, cursor string) ([]T, string, error), cursor string, maxPages int) ([]T, int, error) {
+	var items []T
+	for pages := 1; ; pages++ {
+		page, next, err := fetch(ctx, cursor)
+		if err != nil {
+			return items, pages - 1, fmt.Errorf("page %d: %w", pages, err)
+		}
+		items = append(items, page...)
+
+		if next == "" {
+			return items, pages, nil
+		}
+		if pages >= maxPages {
+			return items, pages, errPageLimit
+		}
+		cursor = next
+	}
+}
+
+// syncList lists one kind of capability across all pages and synchronizes its
+// registrations on the proxy server. If a page fails, the items from the earlier
+// pages are still registered but nothing is removed, since the complete list is unknown.
+func syncList[T any](
+	ctx context.Context, p *Proxy, kind string, maxPages int,
+	fetch func(ctx context.Context, cursor string) ([]T, string, error),
+	registered map[string]T, key func(T) string, register func(T), remove func(...string),
+) error {
+	items, pages, err := paginateList(ctx, fetch, "", maxPages)
+	if errors.Is(err, errPageLimit) {
+		p.logger.Warn(kind+" pagination limit reached; remaining "+kind+" are not forwarded", "pages", pages)
+		err = nil
+	}
+
+	seen := make(map[string]bool, len(items))
+	added := registerForwarded(registered, items, key, register, seen)
+	if err != nil {
+		p.logger.Warn("failed to list "+kind, "page", pages+1, "registered", len(items), "error", err)
+		return fmt.Errorf("failed to list %s: %w", kind, err)
+	}
+
+	removed := removeUnseen(registered, seen)
+	if len(removed) > 0 {
+		remove(removed...)
+	}
+	p.logSync(kind, len(items), pages, added, removed)
+	return nil
+}
+
+// logSync logs the result of synchronizing one kind of capability
+func (p *Proxy) logSync(kind string, count, pages, added int, removed []string) {
+	if added == 0 && len(removed) == 0 {
+		p.logger.Debug("forwarded "+kind+" unchanged", "count", count, "pages", pages)
+		return
+	}
+	p.logger.Info("forwarding "+kind, "count", count, "pages", pages, "added", added, "removed", len(removed))
+}
+
+// syncForwarded registers the items that are new or whose definition changed,
+// updates registered to match items, and returns the number of items registered
+// along with the sorted keys of items that are no longer present.
+func syncForwarded[T any](registered map[string]T, items []T, key func(T) string, register func(T)) (int, []string) {
+	seen := make(map[string]bool, len(items))
+	added := registerForwarded(registered, items, key, register, seen)
+	return added, removeUnseen(registered, seen)
+}
+
+// registerForwarded registers the items that are new or whose definition
+// changed, marks every item's key in seen, and returns the number of items registered.
+func registerForwarded[T any](registered map[string]T, items []T, key func(T) string, register func(T), seen map[string]bool) int {
+	added := 0
+	for _, item := range items {
+		k := key(item)
+		seen[k] = true
+		if existing, ok := registered[k]; ok && reflect.DeepEqual(existing, item) {
+			continue
+		}
+		register(item)
+		registered[k] = item
+		added++
+	}
+	return added
+}
+
+// removeUnseen deletes the registered keys missing from seen and returns them sorted
+func removeUnseen[T any](registered map[string]T, seen map[string]bool) []string {
+	var removed []string
+	for k := range registered {
+		if !seen[k] {
+			removed = append(removed, k)
+			delete(registered, k)
+		}
+	}
+	slices.Sort(removed)
+	return removed
+}
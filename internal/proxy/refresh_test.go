@@ -0,0 +1,467 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/transport"
+)
+
+// echoTool is a tool handler used by in-memory target servers
+func echoTool(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: req.Params.Name}}}, nil
+}
+
+// newInMemoryProxy creates a proxy whose client session is connected to target
+// over in-memory transports, and a client session connected to the proxy server
+// with the given options
+func newInMemoryProxy(t *testing.T, target *mcp.Server, cfg Config, opts *mcp.ClientOptions) (*Proxy, *mcp.ClientSession) {
+	t.Helper()
+	p := connectTarget(t, target, cfg)
+	session := connectClient(t, p, mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v1.0.0"}, opts))
+	return p, session
+}
+
+// connectTarget creates a proxy and connects its client session to target over
+// in-memory transports, registering forwarding handlers as Run does
+func connectTarget(t testing.TB, target *mcp.Server, cfg Config) *Proxy {
+	t.Helper()
+	ctx := context.Background()
+
+	cfg.Transport = &transport.SigningTransport{TargetURL: "https://example.com", Signer: noopSigner{}}
+	p, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targetTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := target.Connect(ctx, targetTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+	session, err := p.client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.clientSession.Store(session)
+	t.Cleanup(func() { p.clientSession.Load().Close() })
+
+	if err := p.setupForwarding(ctx); err != nil {
+		t.Fatal(err)
+	}
+	p.forwarding.Store(true)
+	p.targetActive.Store(true)
+	return p
+}
+
+// connectHTTPTarget creates a proxy whose client session is connected to target
+// over streamable HTTP, so the proxy can open further sessions with the target,
+// and registers forwarding handlers as Run does
+func connectHTTPTarget(t *testing.T, target *mcp.Server, cfg Config) *Proxy {
+	t.Helper()
+	ctx := context.Background()
+
+	server := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return target }, nil))
+	t.Cleanup(server.Close)
+
+	cfg.Transport = &transport.SigningTransport{TargetURL: server.URL, Signer: noopSigner{}}
+	p, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := p.client.Connect(ctx, p.transport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.clientSession.Store(session)
+	t.Cleanup(func() { p.clientSession.Load().Close() })
+
+	if err := p.setupForwarding(ctx); err != nil {
+		t.Fatal(err)
+	}
+	p.forwarding.Store(true)
+	p.targetActive.Store(true)
+	return p
+}
+
+// connectClient connects client to the proxy server over in-memory transports
+func connectClient(t *testing.T, p *Proxy, client *mcp.Client) *mcp.ClientSession {
+	t.Helper()
+	ctx := context.Background()
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := p.server.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+// toolNames lists the names of the tools the proxy server exposes
+func toolNames(t *testing.T, session *mcp.ClientSession) []string {
+	t.Helper()
+	result, err := session.ListTools(context.Background(), &mcp.ListToolsParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tool := range result.Tools {
+		names = append(names, tool.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestProxy_RefreshForwarding(t *testing.T) {
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "alpha", InputSchema: map[string]any{"type": "object"}}, echoTool)
+	target.AddTool(&mcp.Tool{Name: "beta", InputSchema: map[string]any{"type": "object"}}, echoTool)
+
+	p, session := newInMemoryProxy(t, target, Config{}, nil)
+	if got := toolNames(t, session); !slices.Equal(got, []string{"alpha", "beta"}) {
+		t.Fatalf("initial tools = %v, want [alpha beta]", got)
+	}
+	initialRefresh := p.LastRefreshTime()
+	if initialRefresh.IsZero() {
+		t.Fatal("expected LastRefreshTime to be set after setup")
+	}
+	alpha := p.forwarded.tools["alpha"]
+
+	// Remove one tool and add another on the target
+	target.RemoveTools("beta")
+	target.AddTool(&mcp.Tool{Name: "gamma", InputSchema: map[string]any{"type": "object"}}, echoTool)
+
+	if err := p.refreshForwarding(context.Background()); err != nil {
+		t.Fatalf("refreshForwarding() unexpected error: %v", err)
+	}
+	if got := toolNames(t, session); !slices.Equal(got, []string{"alpha", "gamma"}) {
+		t.Errorf("refreshed tools = %v, want [alpha gamma]", got)
+	}
+	if p.forwarded.tools["alpha"] != alpha {
+		t.Error("expected the unchanged tool registration to be left in place")
+	}
+	if !p.LastRefreshTime().After(initialRefresh) {
+		t.Error("expected LastRefreshTime to advance after a refresh")
+	}
+
+	// The forwarded tool still reaches the target
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "gamma"})
+	if err != nil {
+		t.Fatalf("CallTool() unexpected error: %v", err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; text != "gamma" {
+		t.Errorf("CallTool() = %q, want %q", text, "gamma")
+	}
+}
+
+func TestProxy_RefreshForwardingFailureKeepsRegistrations(t *testing.T) {
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "alpha", InputSchema: map[string]any{"type": "object"}}, echoTool)
+
+	p, session := newInMemoryProxy(t, target, Config{}, nil)
+	lastRefresh := p.LastRefreshTime()
+
+	// A cancelled context makes every list request fail
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := p.refreshForwarding(ctx); err == nil {
+		t.Fatal("refreshForwarding() expected an error with a cancelled context")
+	}
+
+	if got := toolNames(t, session); !slices.Equal(got, []string{"alpha"}) {
+		t.Errorf("tools after failed refresh = %v, want [alpha]", got)
+	}
+	if !p.LastRefreshTime().Equal(lastRefresh) {
+		t.Error("expected LastRefreshTime to be unchanged after a failed refresh")
+	}
+}
+
+func TestProxy_RefreshForwardingListsConcurrently(t *testing.T) {
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "alpha", InputSchema: map[string]any{"type": "object"}}, echoTool)
+	target.AddResource(&mcp.Resource{Name: "a", URI: "file:///a"}, readTestResource)
+	target.AddResourceTemplate(&mcp.ResourceTemplate{Name: "files", URITemplate: "file:///{name}"}, readTestResource)
+	target.AddPrompt(&mcp.Prompt{Name: "greet"}, func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{}, nil
+	})
+
+	// Each list only completes once all four are in flight
+	target.AddReceivingMiddleware(listBarrier(4, 2*time.Second))
+
+	p := connectTarget(t, target, Config{})
+	if len(p.forwarded.tools) != 1 || len(p.forwarded.resources) != 1 ||
+		len(p.forwarded.resourceTemplates) != 1 || len(p.forwarded.prompts) != 1 {
+		t.Errorf("forwarded %d tools, %d resources, %d templates, %d prompts, want one of each",
+			len(p.forwarded.tools), len(p.forwarded.resources), len(p.forwarded.resourceTemplates), len(p.forwarded.prompts))
+	}
+	if p.LastRefreshTime().IsZero() {
+		t.Error("expected LastRefreshTime to be set after setup")
+	}
+}
+
+// listBarrier returns middleware that holds list requests until n of them have
+// arrived, failing those still waiting after timeout
+func listBarrier(n int, timeout time.Duration) mcp.Middleware {
+	var mu sync.Mutex
+	arrived := 0
+	all := make(chan struct{})
+
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if strings.HasSuffix(method, "/list") {
+				mu.Lock()
+				if arrived++; arrived == n {
+					close(all)
+				}
+				mu.Unlock()
+
+				select {
+				case <-all:
+				case <-time.After(timeout):
+					return nil, errors.New("list requests were not concurrent")
+				}
+			}
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// BenchmarkProxy_RefreshForwarding refreshes against a target whose list
+// endpoints each take 10ms; fetched concurrently, a refresh takes about one
+// list's latency rather than four
+func BenchmarkProxy_RefreshForwarding(b *testing.B) {
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "alpha", InputSchema: map[string]any{"type": "object"}}, echoTool)
+	target.AddResource(&mcp.Resource{Name: "a", URI: "file:///a"}, readTestResource)
+	target.AddPrompt(&mcp.Prompt{Name: "greet"}, func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{}, nil
+	})
+	target.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if strings.HasSuffix(method, "/list") {
+				time.Sleep(10 * time.Millisecond)
+			}
+			return next(ctx, method, req)
+		}
+	})
+
+	p := connectTarget(b, target, Config{})
+	for b.Loop() {
+		if err := p.refreshForwarding(context.Background()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestProxy_RefreshLoop(t *testing.T) {
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "alpha", InputSchema: map[string]any{"type": "object"}}, echoTool)
+
+	p, session := newInMemoryProxy(t, target, Config{RefreshInterval: 10 * time.Millisecond}, nil)
+	target.AddTool(&mcp.Tool{Name: "beta", InputSchema: map[string]any{"type": "object"}}, echoTool)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.refreshLoop(ctx)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if slices.Equal(toolNames(t, session), []string{"alpha", "beta"}) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("tools = %v, want [alpha beta] after periodic refresh", toolNames(t, session))
+}
+
+func TestProxy_ToolPagination(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxToolPages int
+		wantTools    int
+	}{
+		{name: "all pages", wantTools: 5},
+		{name: "page limit", maxToolPages: 2, wantTools: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, &mcp.ServerOptions{PageSize: 2})
+			for _, name := range []string{"a", "b", "c", "d", "e"} {
+				target.AddTool(&mcp.Tool{Name: name, InputSchema: map[string]any{"type": "object"}}, echoTool)
+			}
+
+			_, session := newInMemoryProxy(t, target, Config{MaxToolPages: tt.maxToolPages}, nil)
+			if got := len(toolNames(t, session)); got != tt.wantTools {
+				t.Errorf("forwarded %d tools, want %d", got, tt.wantTools)
+			}
+		})
+	}
+}
+
+func TestProxy_ResourcePagination(t *testing.T) {
+	tests := []struct {
+		name             string
+		failPage2        bool
+		maxResourcePages int
+		wantResources    int
+		wantLog          string
+	}{
+		{name: "all pages", wantResources: 5},
+		{name: "page limit", maxResourcePages: 2, wantResources: 4},
+		{name: "later page fails", failPage2: true, wantResources: 2, wantLog: "page=2 registered=2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, &mcp.ServerOptions{PageSize: 2})
+			for _, name := range []string{"a", "b", "c", "d", "e"} {
+				target.AddResource(&mcp.Resource{Name: name, URI: "file:///" + name}, readTestResource)
+			}
+			if tt.failPage2 {
+				target.AddReceivingMiddleware(failCursorPages("resources/list"))
+			}
+
+			var logs bytes.Buffer
+			cfg := Config{MaxResourcePages: tt.maxResourcePages, Logger: slog.New(slog.NewTextHandler(&logs, nil))}
+			_, session := newInMemoryProxy(t, target, cfg, nil)
+
+			result, err := session.ListResources(context.Background(), &mcp.ListResourcesParams{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(result.Resources); got != tt.wantResources {
+				t.Errorf("forwarded %d resources, want %d", got, tt.wantResources)
+			}
+			if !strings.Contains(logs.String(), tt.wantLog) {
+				t.Errorf("expected log output to contain %q, got %q", tt.wantLog, logs.String())
+			}
+		})
+	}
+}
+
+// readTestResource is a resource handler used by in-memory target servers
+func readTestResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: req.Params.URI, Text: "contents"}}}, nil
+}
+
+// failCursorPages returns middleware that fails list requests for method that
+// carry a pagination cursor, so only the first page can be fetched
+func failCursorPages(method string) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, m string, req mcp.Request) (mcp.Result, error) {
+			if m == method && listCursor(req.GetParams()) != "" {
+				return nil, errors.New("page unavailable")
+			}
+			return next(ctx, m, req)
+		}
+	}
+}
+
+// listCursor returns the pagination cursor of list request params
+func listCursor(params mcp.Params) string {
+	switch params := params.(type) {
+	case *mcp.ListToolsParams:
+		return params.Cursor
+	case *mcp.ListResourcesParams:
+		return params.Cursor
+	case *mcp.ListPromptsParams:
+		return params.Cursor
+	}
+	return ""
+}
+
+func TestProxy_PromptPagination(t *testing.T) {
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, &mcp.ServerOptions{PageSize: 10})
+	for i := range 30 {
+		target.AddPrompt(&mcp.Prompt{Name: fmt.Sprintf("prompt-%02d", i)},
+			func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+				return &mcp.GetPromptResult{}, nil
+			})
+	}
+
+	p, session := newInMemoryProxy(t, target, Config{}, nil)
+	result, err := session.ListPrompts(context.Background(), &mcp.ListPromptsParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(result.Prompts); got != 30 {
+		t.Errorf("forwarded %d prompts, want 30", got)
+	}
+	if got := len(p.forwarded.prompts); got != 30 {
+		t.Errorf("tracked %d prompts, want 30", got)
+	}
+}
+
+func TestPaginateList(t *testing.T) {
+	pages := map[string]struct {
+		items []int
+		next  string
+	}{
+		"":   {items: []int{1, 2}, next: "p2"},
+		"p2": {items: []int{3, 4}, next: "p3"},
+		"p3": {items: []int{5}},
+	}
+	fetch := func(ctx context.Context, cursor string) ([]int, string, error) {
+		page, ok := pages[cursor]
+		if !ok {
+			return nil, "", errors.New("unknown cursor")
+		}
+		return page.items, page.next, nil
+	}
+
+	tests := []struct {
+		name      string
+		cursor    string
+		maxPages  int
+		wantItems []int
+		wantPages int
+		wantErr   error
+	}{
+		{name: "all pages", maxPages: 10, wantItems: []int{1, 2, 3, 4, 5}, wantPages: 3},
+		{name: "starting cursor", cursor: "p2", maxPages: 10, wantItems: []int{3, 4, 5}, wantPages: 2},
+		{name: "page limit", maxPages: 2, wantItems: []int{1, 2, 3, 4}, wantPages: 2, wantErr: errPageLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, n, err := paginateList(context.Background(), fetch, tt.cursor, tt.maxPages)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("paginateList() error = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(items, tt.wantItems) || n != tt.wantPages {
+				t.Errorf("paginateList() = %v, %d pages; want %v, %d pages", items, n, tt.wantItems, tt.wantPages)
+			}
+		})
+	}
+
+	t.Run("failed page keeps earlier items", func(t *testing.T) {
+		failing := func(ctx context.Context, cursor string) ([]int, string, error) {
+			if cursor == "p2" {
+				return nil, "", errors.New("page unavailable")
+			}
+			return fetch(ctx, cursor)
+		}
+		items, n, err := paginateList(context.Background(), failing, "", 10)
+		if err == nil {
+			t.Fatal("paginateList() expected an error")
+		}
+		if !slices.Equal(items, []int{1, 2}) || n != 1 {
+			t.Errorf("paginateList() = %v, %d pages; want [1 2], 1 page", items, n)
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/transport"
+)
+
+// DefaultForwardResponseHeaders are the target response headers added to the
+// _meta of forwarded results when Config.ForwardResponseHeaders is nil
+var DefaultForwardResponseHeaders = []string{"X-Amzn-Requestid"}
+
+// collectResponseHeaders returns a context that records the headers of the
+// target's responses, or ctx and nil when no headers are forwarded
+func (p *Proxy) collectResponseHeaders(ctx context.Context) (context.Context, *transport.ResponseHeaders) {
+	if len(p.forwardResponseHeaders) == 0 {
+		return ctx, nil
+	}
+	return transport.ContextWithResponseHeaders(ctx)
+}
+
+// withResponseHeaderMeta adds the forwarded headers found in headers to meta,
+// keyed by their lowercase names as trace headers are read from _meta.
+// Entries the target server set in meta are kept.
+func (p *Proxy) withResponseHeaderMeta(meta mcp.Meta, headers *transport.ResponseHeaders) mcp.Meta {
+	if headers == nil {
+		return meta
+	}
+	received := headers.Header()
+	for _, name := range p.forwardResponseHeaders {
+		values := received.Values(name)
+		key := strings.ToLower(name)
+		if len(values) == 0 {
+			continue
+		}
+		if _, ok := meta[key]; ok {
+			continue
+		}
+		if meta == nil {
+			meta = mcp.Meta{}
+		}
+		meta[key] = strings.Join(values, ", ")
+	}
+	return meta
+}
+
+// warnUnseenResponseHeaders warns about forwarded headers missing from the
+// target's responses to the connection handshake, which are often misspelled
+func (p *Proxy) warnUnseenResponseHeaders(received http.Header) {
+	for _, name := range p.forwardResponseHeaders {
+		if len(received.Values(name)) == 0 {
+			p.logger.Warn("forwarded response header not seen in the target's responses; check the name for a typo",
+				"header", name)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/transport"
+)
+
+func TestProxy_ForwardResponseHeaders(t *testing.T) {
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "echo", InputSchema: map[string]any{"type": "object"}}, echoTool)
+	target.AddPrompt(&mcp.Prompt{Name: "greet"}, func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{Meta: mcp.Meta{"x-cache": "from target"}}, nil
+	})
+
+	// The target's responses carry AWS-style headers
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return target }, nil)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Amzn-Requestid", "req-123")
+		w.Header().Set("X-Cache", "Miss from cloudfront")
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	p, err := New(Config{
+		Transport:              &transport.SigningTransport{TargetURL: server.URL, Signer: noopSigner{}},
+		ForwardResponseHeaders: []string{"X-Amzn-Requestid", "X-Cache"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := p.client.Connect(ctx, p.transport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.clientSession.Store(session)
+	defer session.Close()
+	if err := p.setupForwarding(ctx); err != nil {
+		t.Fatal(err)
+	}
+	client := connectClient(t, p, mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v1.0.0"}, nil))
+
+	result, err := client.CallTool(ctx, &mcp.CallToolParams{Name: "echo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := result.Meta["x-amzn-requestid"]; got != "req-123" {
+		t.Errorf("tool result _meta x-amzn-requestid = %v, want %q", got, "req-123")
+	}
+	if got := result.Meta["x-cache"]; got != "Miss from cloudfront" {
+		t.Errorf("tool result _meta x-cache = %v, want %q", got, "Miss from cloudfront")
+	}
+
+	// Entries set by the target are not overwritten
+	prompt, err := client.GetPrompt(ctx, &mcp.GetPromptParams{Name: "greet"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := prompt.Meta["x-cache"]; got != "from target" {
+		t.Errorf("prompt result _meta x-cache = %v, want %q", got, "from target")
+	}
+	if got := prompt.Meta["x-amzn-requestid"]; got != "req-123" {
+		t.Errorf("prompt result _meta x-amzn-requestid = %v, want %q", got, "req-123")
+	}
+}
+
+func TestNew_ForwardResponseHeadersDefault(t *testing.T) {
+	signing := &transport.SigningTransport{TargetURL: "https://example.com", Signer: noopSigner{}}
+
+	p, err := New(Config{Transport: signing})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(p.forwardResponseHeaders, DefaultForwardResponseHeaders) {
+		t.Errorf("forwardResponseHeaders = %v, want %v", p.forwardResponseHeaders, DefaultForwardResponseHeaders)
+	}
+
+	// An empty list forwards no headers
+	p, err = New(Config{Transport: signing, ForwardResponseHeaders: []string{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, headers := p.collectResponseHeaders(context.Background()); headers != nil {
+		t.Error("collectResponseHeaders() returned a collector with no headers forwarded")
+	}
+}
+
+func TestProxy_WarnUnseenResponseHeaders(t *testing.T) {
+	var buf bytes.Buffer
+	p := &Proxy{
+		logger:                 slog.New(slog.NewTextHandler(&buf, nil)),
+		forwardResponseHeaders: []string{"X-Amzn-Requestid", "X-Amzn-Reqeustid"},
+	}
+
+	p.warnUnseenResponseHeaders(http.Header{"X-Amzn-Requestid": {"req-123"}})
+
+	output := buf.String()
+	if !strings.Contains(output, "header=X-Amzn-Reqeustid") {
+		t.Errorf("expected a warning for the misspelled header, got %q", output)
+	}
+	if strings.Count(output, "level=WARN") != 1 {
+		t.Errorf("expected one warning, got %q", output)
+	}
+}
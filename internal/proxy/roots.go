@@ -0,0 +1,40 @@
+package proxy
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// forwardRoots lists the roots announced by a connected client and relays them
+// to the target server through the proxy's MCP client, which answers the
+// target's roots/list requests and notifies it when the list changes.
+//
+// Clients that do not support roots are silently skipped. With several clients
+// connected, the roots of the client that most recently reported them win.
+func (p *Proxy) forwardRoots(ctx context.Context, session *mcp.ServerSession) {
+	if params := session.InitializeParams(); params == nil || params.Capabilities == nil || params.Capabilities.RootsV2 == nil {
+		return
+	}
+
+	result, err := session.ListRoots(ctx, &mcp.ListRootsParams{})
+	if err != nil {
+		p.logger.Warn("failed to list client roots", "session_id", session.ID(), "error", err)
+		return
+	}
+
+	p.rootsMu.Lock()
+	defer p.rootsMu.Unlock()
+
+	_, removed := syncForwarded(p.forwarded.roots, result.Roots,
+		func(root *mcp.Root) string { return root.URI }, func(root *mcp.Root) { p.client.AddRoots(root) })
+	if len(removed) > 0 {
+		p.client.RemoveRoots(removed...)
+	}
+
+	uris := make([]string, 0, len(result.Roots))
+	for _, root := range result.Roots {
+		uris = append(uris, root.URI)
+	}
+	p.logger.Info("forwarding client roots", "roots", uris)
+}
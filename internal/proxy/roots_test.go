@@ -0,0 +1,56 @@
+package proxy
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// rootURIs lists the root URIs the target server sees
+func rootURIs(t *testing.T, target *mcp.Server) []string {
+	t.Helper()
+	var uris []string
+	for session := range target.Sessions() {
+		result, err := session.ListRoots(context.Background(), &mcp.ListRootsParams{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, root := range result.Roots {
+			uris = append(uris, root.URI)
+		}
+	}
+	slices.Sort(uris)
+	return uris
+}
+
+// waitForRoots waits for the target server to see the want root URIs
+func waitForRoots(t *testing.T, target *mcp.Server, want []string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !slices.Equal(rootURIs(t, target), want) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := rootURIs(t, target); !slices.Equal(got, want) {
+		t.Fatalf("target roots = %v, want %v", got, want)
+	}
+}
+
+func TestProxy_RootsForwarding(t *testing.T) {
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	p := connectTarget(t, target, Config{EnableRootsForwarding: true})
+
+	// The downstream client announces its roots before connecting
+	downstream := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v1.0.0"}, nil)
+	downstream.AddRoots(&mcp.Root{URI: "file:///workspace/a"}, &mcp.Root{URI: "file:///workspace/b"})
+	connectClient(t, p, downstream)
+
+	waitForRoots(t, target, []string{"file:///workspace/a", "file:///workspace/b"})
+
+	// Changes announced by the client are relayed to the target
+	downstream.RemoveRoots("file:///workspace/a")
+	downstream.AddRoots(&mcp.Root{URI: "file:///workspace/c"})
+	waitForRoots(t, target, []string{"file:///workspace/b", "file:///workspace/c"})
+}
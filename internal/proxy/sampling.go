@@ -0,0 +1,38 @@
+package proxy
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// forwardCreateMessage forwards a sampling/createMessage request from the target
+// server to a connected client that supports sampling.
+//
+// Sampling is a targeted request: the target server cannot say which client
+// should answer it, so forwarding is only correct when a single client is
+// connected. If several clients support sampling, the request goes to the one
+// that connected first.
+func (p *Proxy) forwardCreateMessage(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	var samplers []*mcp.ServerSession
+	for _, session := range p.activeSessions() {
+		if params := session.InitializeParams(); params != nil && params.Capabilities != nil && params.Capabilities.Sampling != nil {
+			samplers = append(samplers, session)
+		}
+	}
+
+	if len(samplers) == 0 {
+		return nil, &jsonrpc.Error{
+			Code:    jsonrpc.CodeMethodNotFound,
+			Message: "sampling capability not available: no connected client supports sampling",
+		}
+	}
+	if len(samplers) > 1 {
+		p.logger.Warn("multiple clients support sampling; forwarding to the first connected client",
+			"clients", len(samplers))
+	}
+
+	p.logger.Debug("forwarding sampling request", "session_id", samplers[0].ID())
+	return samplers[0].CreateMessage(ctx, req.Params)
+}
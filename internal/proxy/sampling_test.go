@@ -0,0 +1,72 @@
+package proxy
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// newSamplingTarget creates a target server with an "ask" tool that requests
+// a completion from its client and returns the sampled text
+func newSamplingTarget() *mcp.Server {
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "ask", InputSchema: map[string]any{"type": "object"}},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := req.Session.CreateMessage(ctx, &mcp.CreateMessageParams{
+				MaxTokens: 16,
+				Messages:  []*mcp.SamplingMessage{{Role: "user", Content: &mcp.TextContent{Text: "hello"}}},
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &mcp.CallToolResult{Content: []mcp.Content{result.Content}}, nil
+		})
+	return target
+}
+
+func TestProxy_SamplingForwarding(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     *mcp.ClientOptions
+		wantText string
+		wantErr  string
+	}{
+		{
+			name: "client supports sampling",
+			opts: &mcp.ClientOptions{
+				CreateMessageHandler: func(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+					text := req.Params.Messages[0].Content.(*mcp.TextContent).Text
+					return &mcp.CreateMessageResult{Model: "test", Role: "assistant", Content: &mcp.TextContent{Text: "sampled " + text}}, nil
+				},
+			},
+			wantText: "sampled hello",
+		},
+		{
+			name:    "client does not support sampling",
+			wantErr: "sampling capability not available",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, session := newInMemoryProxy(t, newSamplingTarget(), Config{EnableSamplingForwarding: true}, tt.opts)
+			waitForSessions(t, p, 1)
+
+			result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "ask"})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("CallTool() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CallTool() unexpected error: %v", err)
+			}
+			if text := result.Content[0].(*mcp.TextContent).Text; text != tt.wantText {
+				t.Errorf("CallTool() = %q, want %q", text, tt.wantText)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// checkToolArguments checks a tool call's arguments against the tool's
+// input schema. Violations are returned as an Invalid params error listing
+// each one, so malformed calls are rejected before they reach the target.
+func (p *Proxy) checkToolArguments(tool *mcp.Tool, arguments json.RawMessage) error {
+	schema, err := p.toolSchema(tool)
+	if err != nil {
+		// The target owns its schemas; an unusable one should not block calls
+		p.logger.Warn("tool input schema could not be compiled, arguments are not validated",
+			"tool", tool.Name, "error", err)
+		return nil
+	}
+
+	// Clients send omitted arguments as nothing or as null
+	document := arguments
+	if trimmed := bytes.TrimSpace(document); len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		document = json.RawMessage("{}")
+	}
+	result, err := schema.Validate(gojsonschema.NewBytesLoader(document))
+	if err != nil {
+		return &jsonrpc.Error{
+			Code:    jsonrpc.CodeInvalidParams,
+			Message: fmt.Sprintf("invalid arguments for tool %s: %v", tool.Name, err),
+		}
+	}
+	if result.Valid() {
+		return nil
+	}
+
+	violations := make([]string, 0, len(result.Errors()))
+	for _, violation := range result.Errors() {
+		violations = append(violations, violation.String())
+	}
+	return &jsonrpc.Error{
+		Code:    jsonrpc.CodeInvalidParams,
+		Message: fmt.Sprintf("invalid arguments for tool %s: %s", tool.Name, strings.Join(violations, "; ")),
+	}
+}
+
+// toolSchema returns the compiled input schema for tool, compiling and caching
+// it by tool name on first use. forwardTool drops the cached schema whenever
+// the tool is registered again, so changed schemas are recompiled.
+func (p *Proxy) toolSchema(tool *mcp.Tool) (*gojsonschema.Schema, error) {
+	if cached, ok := p.toolSchemas.Load(tool.Name); ok {
+		return cached.(*gojsonschema.Schema), nil
+	}
+
+	raw, err := json.Marshal(tool.InputSchema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode input schema: %w", err)
+	}
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	p.toolSchemas.Store(tool.Name, schema)
+	return schema, nil
+}
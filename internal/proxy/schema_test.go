@@ -0,0 +1,95 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// newSchemaTarget creates a target server with a "greet" tool that requires
+// a string name and counts the calls that reach it
+func newSchemaTarget(calls *int) *mcp.Server {
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name":  map[string]any{"type": "string"},
+			"times": map[string]any{"type": "integer", "minimum": 1},
+		},
+		"required": []any{"name"},
+	}
+	target.AddTool(&mcp.Tool{Name: "greet", InputSchema: schema},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			*calls++
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "hello"}}}, nil
+		})
+	return target
+}
+
+func TestProxy_ValidateToolArguments(t *testing.T) {
+	tests := []struct {
+		name      string
+		validate  bool
+		arguments map[string]any
+		wantErr   []string
+		wantCalls int
+	}{
+		{
+			name:      "valid arguments are forwarded",
+			validate:  true,
+			arguments: map[string]any{"name": "world", "times": 2},
+			wantCalls: 1,
+		},
+		{
+			name:      "each violation is reported",
+			validate:  true,
+			arguments: map[string]any{"times": 0},
+			wantErr:   []string{"invalid arguments for tool greet", "name is required", "times"},
+		},
+		{
+			name:     "missing arguments are validated as an empty object",
+			validate: true,
+			wantErr:  []string{"name is required"},
+		},
+		{
+			name:      "validation disabled",
+			arguments: map[string]any{"times": 0},
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			p, session := newInMemoryProxy(t, newSchemaTarget(&calls), Config{ValidateToolArguments: tt.validate}, nil)
+
+			_, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "greet", Arguments: tt.arguments})
+			if len(tt.wantErr) > 0 {
+				var wireErr *jsonrpc.Error
+				if !errors.As(err, &wireErr) || wireErr.Code != jsonrpc.CodeInvalidParams {
+					t.Fatalf("CallTool() error = %v, want code %d", err, jsonrpc.CodeInvalidParams)
+				}
+				for _, want := range tt.wantErr {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("CallTool() error = %q, want it to contain %q", err, want)
+					}
+				}
+			} else if err != nil {
+				t.Fatalf("CallTool() unexpected error: %v", err)
+			}
+
+			if calls != tt.wantCalls {
+				t.Errorf("target calls = %d, want %d", calls, tt.wantCalls)
+			}
+			if tt.validate {
+				if _, ok := p.toolSchemas.Load("greet"); !ok {
+					t.Error("compiled schema was not cached")
+				}
+			}
+		})
+	}
+}
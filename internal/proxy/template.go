@@ -0,0 +1,115 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/yosida95/uritemplate/v3"
+)
+
+// templateMatcher checks resource URIs against an RFC 6570 URI template
+type templateMatcher struct {
+	template *uritemplate.Template
+
+	// optional holds variables from query expansions ({?x} and {&x}), which
+	// may be left out of a URI
+	optional map[string]bool
+}
+
+// newTemplateMatcher compiles the URI template of a resource template
+func newTemplateMatcher(uriTemplate string) (*templateMatcher, error) {
+	template, err := uritemplate.New(uriTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &templateMatcher{template: template, optional: queryVarnames(uriTemplate)}, nil
+}
+
+// match extracts the template variables from uri. It returns an Invalid
+// params error when uri does not match the template or a required variable
+// is missing or empty.
+func (m *templateMatcher) match(uri string) (map[string]string, error) {
+	values := m.template.Match(uri)
+	if values == nil {
+		return nil, &jsonrpc.Error{
+			Code:    jsonrpc.CodeInvalidParams,
+			Message: fmt.Sprintf("resource URI %s does not match template %s", uri, m.template.Raw()),
+		}
+	}
+
+	variables := make(map[string]string)
+	var missing []string
+	for _, name := range m.template.Varnames() {
+		value := values.Get(name)
+		switch {
+		case len(value.List()) > 0:
+			variables[name] = strings.Join(value.List(), ",")
+		case len(value.KV()) > 0:
+			variables[name] = strings.Join(value.KV(), ",")
+		default:
+			variables[name] = value.String()
+		}
+		if variables[name] == "" && !m.optional[name] {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, &jsonrpc.Error{
+			Code: jsonrpc.CodeInvalidParams,
+			Message: fmt.Sprintf("resource URI %s does not satisfy template %s: missing or empty variables: %s",
+				uri, m.template.Raw(), strings.Join(missing, ", ")),
+		}
+	}
+	return variables, nil
+}
+
+// queryVarnames returns the variables of the query expansions in uriTemplate
+func queryVarnames(uriTemplate string) map[string]bool {
+	names := make(map[string]bool)
+	for rest := uriTemplate; ; {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			return names
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return names
+		}
+		expression := rest[start+1 : start+end]
+		rest = rest[start+end+1:]
+
+		if !strings.HasPrefix(expression, "?") && !strings.HasPrefix(expression, "&") {
+			continue
+		}
+		for _, spec := range strings.Split(expression[1:], ",") {
+			// Strip the explode and prefix modifiers
+			name, _, _ := strings.Cut(strings.TrimSuffix(spec, "*"), ":")
+			names[name] = true
+		}
+	}
+}
+
+// readTemplateResource returns a handler that validates the URI of each read
+// against the resource template before forwarding it to the target server
+func (p *Proxy) readTemplateResource(template *mcp.ResourceTemplate) mcp.ResourceHandler {
+	matcher, err := newTemplateMatcher(template.URITemplate)
+	if err != nil {
+		// The SDK rejects invalid templates when they are added, so this is not expected
+		p.logger.Warn("resource template could not be compiled, URIs are not validated",
+			"template", template.URITemplate, "error", err)
+		return p.readResource
+	}
+
+	return func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		variables, err := matcher.match(req.Params.URI)
+		if err != nil {
+			return nil, err
+		}
+		p.logger.Debug("resource template variables", "template", template.URITemplate, "uri", req.Params.URI, "variables", variables)
+		return p.readResource(ctx, req)
+	}
+}
@@ -0,0 +1,78 @@
+package proxy
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+)
+
+func TestTemplateMatcher(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		uri      string
+		want     map[string]string
+		wantErr  string
+	}{
+		{
+			name:     "all variables present",
+			template: "file:///repos/{owner}/{repo}",
+			uri:      "file:///repos/nisimpson/proxy",
+			want:     map[string]string{"owner": "nisimpson", "repo": "proxy"},
+		},
+		{
+			name:     "empty variable",
+			template: "file:///repos/{owner}/{repo}",
+			uri:      "file:///repos//proxy",
+			wantErr:  "missing or empty variables: owner",
+		},
+		{
+			name:     "optional query variable omitted",
+			template: "db://tables/{table}{?limit}",
+			uri:      "db://tables/users",
+			want:     map[string]string{"table": "users", "limit": ""},
+		},
+		{
+			name:     "query variable present",
+			template: "db://tables/{table}{?limit}",
+			uri:      "db://tables/users?limit=10",
+			want:     map[string]string{"table": "users", "limit": "10"},
+		},
+		{
+			name:     "no match",
+			template: "file:///repos/{owner}/{repo}",
+			uri:      "http://example.com/other",
+			wantErr:  "does not match template file:///repos/{owner}/{repo}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matcher, err := newTemplateMatcher(tt.template)
+			if err != nil {
+				t.Fatalf("newTemplateMatcher() unexpected error: %v", err)
+			}
+
+			got, err := matcher.match(tt.uri)
+			if tt.wantErr != "" {
+				var wireErr *jsonrpc.Error
+				if !errors.As(err, &wireErr) || wireErr.Code != jsonrpc.CodeInvalidParams {
+					t.Fatalf("match() error = %v, want code %d", err, jsonrpc.CodeInvalidParams)
+				}
+				if !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("match() error = %q, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("match() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package proxy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestProxy_MethodTimeouts(t *testing.T) {
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "wait", InputSchema: map[string]any{"type": "object"}},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(200 * time.Millisecond):
+				return echoTool(ctx, req)
+			}
+		})
+
+	tests := []struct {
+		name     string
+		timeouts map[string]time.Duration
+		wantErr  bool
+	}{
+		{name: "no timeout", timeouts: nil, wantErr: false},
+		{name: "tool call timeout", timeouts: map[string]time.Duration{"tools/call": 20 * time.Millisecond}, wantErr: true},
+		{name: "other method timeout", timeouts: map[string]time.Duration{"prompts/get": 20 * time.Millisecond}, wantErr: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, session := newInMemoryProxy(t, target, Config{MethodTimeouts: tt.timeouts}, nil)
+
+			start := time.Now()
+			_, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "wait"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CallTool() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && time.Since(start) >= 200*time.Millisecond {
+				t.Errorf("expected the call to time out early, took %s", time.Since(start))
+			}
+		})
+	}
+}
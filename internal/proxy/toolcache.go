@@ -0,0 +1,184 @@
+package proxy
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ToolResultCacheConfig configures caching of tool call results, with a TTL
+// for each tool
+type ToolResultCacheConfig struct {
+	// DefaultTTL is how long a result is served for tools without an entry in
+	// PerToolTTL (0 means results are only evicted to make room)
+	DefaultTTL time.Duration
+
+	// PerToolTTL overrides DefaultTTL for the named tools
+	PerToolTTL map[string]time.Duration
+
+	// MaxEntries caps the number of results cached across all tools; the
+	// least recently used result is evicted when it is reached (0 disables the
+	// cache)
+	MaxEntries int
+
+	// NoCacheMethods lists the tools whose results are never cached, such as
+	// tools with side effects
+	NoCacheMethods []string
+}
+
+// toolResultCache caches tool call results in an LRU shared by every tool,
+// keeping a TTL and statistics for each tool. Keys are the tool name and a
+// hash of the call's params, as built by cacheKey.
+type toolResultCache struct {
+	cache      *responseCache
+	defaultTTL time.Duration
+	perToolTTL map[string]time.Duration
+	noCache    map[string]bool
+
+	mu    sync.Mutex
+	stats map[string]*CacheStats
+}
+
+// newToolResultCache creates a tool result cache, or returns nil when cfg
+// leaves caching disabled
+func newToolResultCache(cfg ToolResultCacheConfig) *toolResultCache {
+	if cfg.MaxEntries <= 0 {
+		return nil
+	}
+	noCache := make(map[string]bool, len(cfg.NoCacheMethods))
+	for _, name := range cfg.NoCacheMethods {
+		noCache[name] = true
+	}
+	c := &toolResultCache{
+		defaultTTL: cfg.DefaultTTL,
+		perToolTTL: cfg.PerToolTTL,
+		noCache:    noCache,
+		stats:      make(map[string]*CacheStats),
+	}
+	c.cache = &responseCache{
+		maxEntries: cfg.MaxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		onEvict: func(key string) {
+			c.count(toolFromKey(key), func(s *CacheStats) { s.Evictions++ })
+		},
+		now: time.Now,
+	}
+	return c
+}
+
+// cacheable reports whether results of the tool are cached. It is safe to
+// call on a nil cache.
+func (c *toolResultCache) cacheable(name string) bool {
+	return c != nil && !c.noCache[name]
+}
+
+// ttl returns how long results of the tool are cached
+func (c *toolResultCache) ttl(name string) time.Duration {
+	if ttl, ok := c.perToolTTL[name]; ok {
+		return ttl
+	}
+	return c.defaultTTL
+}
+
+// get returns the cached result for key, counting a hit or a miss for the tool
+func (c *toolResultCache) get(name, key string) (*mcp.CallToolResult, bool) {
+	value, ok := c.cache.get(key)
+	c.count(name, func(s *CacheStats) {
+		if ok {
+			s.Hits++
+		} else {
+			s.Misses++
+		}
+	})
+	if !ok {
+		return nil, false
+	}
+	return value.(*mcp.CallToolResult), true
+}
+
+// put caches a result of the tool for its TTL
+func (c *toolResultCache) put(name, key string, result *mcp.CallToolResult) {
+	c.cache.putTTL(key, result, c.ttl(name))
+}
+
+// count updates the statistics of the tool
+func (c *toolResultCache) count(name string, update func(s *CacheStats)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats, ok := c.stats[name]
+	if !ok {
+		stats = &CacheStats{}
+		c.stats[name] = stats
+	}
+	update(stats)
+}
+
+// snapshot returns the statistics of each tool
+func (c *toolResultCache) snapshot() map[string]CacheStats {
+	entries := make(map[string]int)
+	for _, key := range c.cache.keys() {
+		entries[toolFromKey(key)]++
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := make(map[string]CacheStats, len(c.stats))
+	for name, s := range c.stats {
+		stats[name] = *s
+	}
+	for name, n := range entries {
+		s := stats[name]
+		s.Entries = n
+		stats[name] = s
+	}
+	return stats
+}
+
+// toolFromKey returns the tool name of a cache key. The hash after the last
+// ":" never contains one, so tool names may.
+func toolFromKey(key string) string {
+	if i := strings.LastIndex(key, ":"); i >= 0 {
+		return key[:i]
+	}
+	return key
+}
+
+// cachedToolCall answers a call to the tool from the tool result cache when
+// the tool is cacheable, otherwise calling the target with call and caching
+// its result. Errors and results flagged as errors are never cached.
+func (p *Proxy) cachedToolCall(name string, params any, call func() (*mcp.CallToolResult, error)) (*mcp.CallToolResult, error) {
+	if !p.toolCache.cacheable(name) {
+		return call()
+	}
+
+	key, err := cacheKey(name, params)
+	if err != nil {
+		p.logger.Debug("tool call could not be cached", "tool", name, "error", err)
+		return call()
+	}
+	if result, ok := p.toolCache.get(name, key); ok {
+		p.logger.Debug("tool result cache hit", "tool", name)
+		return result, nil
+	}
+
+	result, err := call()
+	if err != nil || result.IsError {
+		return result, err
+	}
+	p.toolCache.put(name, key, result)
+	return result, nil
+}
+
+// ToolCacheStats returns the hit, miss, and eviction counts and cached
+// entries of the tool result cache for each tool, or nil when caching is
+// disabled
+func (p *Proxy) ToolCacheStats() map[string]CacheStats {
+	if p.toolCache == nil {
+		return nil
+	}
+	return p.toolCache.snapshot()
+}
@@ -0,0 +1,152 @@
+package proxy
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestToolResultCache_PerToolTTL(t *testing.T) {
+	cache := newToolResultCache(ToolResultCacheConfig{
+		DefaultTTL: time.Minute,
+		PerToolTTL: map[string]time.Duration{"weather": time.Second},
+		MaxEntries: 10,
+	})
+	now := time.Now()
+	cache.cache.now = func() time.Time { return now }
+
+	result := &mcp.CallToolResult{}
+	cache.put("weather", "weather:1", result)
+	cache.put("lookup", "lookup:1", result)
+
+	now = now.Add(2 * time.Second)
+	if _, ok := cache.get("weather", "weather:1"); ok {
+		t.Error("get(weather) hit after its TTL, want miss")
+	}
+	if got, ok := cache.get("lookup", "lookup:1"); !ok || got != result {
+		t.Errorf("get(lookup) = %v, %v, want the cached result within the default TTL", got, ok)
+	}
+
+	want := map[string]CacheStats{
+		"weather": {Misses: 1},
+		"lookup":  {Hits: 1, Entries: 1},
+	}
+	got := cache.snapshot()
+	for name, stats := range want {
+		if got[name] != stats {
+			t.Errorf("snapshot()[%s] = %+v, want %+v", name, got[name], stats)
+		}
+	}
+}
+
+func TestToolResultCache_Evictions(t *testing.T) {
+	cache := newToolResultCache(ToolResultCacheConfig{MaxEntries: 1})
+
+	cache.put("ns:lookup", "ns:lookup:1", &mcp.CallToolResult{})
+	cache.put("search", "search:1", &mcp.CallToolResult{})
+
+	stats := cache.snapshot()
+	if want := (CacheStats{Evictions: 1}); stats["ns:lookup"] != want {
+		t.Errorf("snapshot()[ns:lookup] = %+v, want %+v", stats["ns:lookup"], want)
+	}
+	if want := (CacheStats{Entries: 1}); stats["search"] != want {
+		t.Errorf("snapshot()[search] = %+v, want %+v", stats["search"], want)
+	}
+}
+
+func TestNewToolResultCache_Disabled(t *testing.T) {
+	if cache := newToolResultCache(ToolResultCacheConfig{DefaultTTL: time.Minute}); cache != nil {
+		t.Error("newToolResultCache() without MaxEntries should return nil")
+	}
+
+	var cache *toolResultCache
+	if cache.cacheable("lookup") {
+		t.Error("a nil cache should not cache any tool")
+	}
+}
+
+func TestProxy_ToolResultCache(t *testing.T) {
+	var calls atomic.Int64
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls.Add(1)
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "done"}}}, nil
+	}
+	schema := map[string]any{"type": "object"}
+	target.AddTool(&mcp.Tool{Name: "lookup", InputSchema: schema}, handler)
+	target.AddTool(&mcp.Tool{Name: "create", InputSchema: schema}, handler)
+
+	p, session := newInMemoryProxy(t, target, Config{
+		ToolResultCache: ToolResultCacheConfig{
+			DefaultTTL:     time.Minute,
+			MaxEntries:     10,
+			NoCacheMethods: []string{"create"},
+		},
+	}, nil)
+
+	call := func(name string, args map[string]any) {
+		t.Helper()
+		result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: name, Arguments: args})
+		if err != nil {
+			t.Fatalf("CallTool(%s) unexpected error: %v", name, err)
+		}
+		if got := result.Content[0].(*mcp.TextContent).Text; got != "done" {
+			t.Errorf("CallTool(%s) text = %q, want %q", name, got, "done")
+		}
+	}
+
+	// Argument order does not change the cache key
+	call("lookup", map[string]any{"id": "42", "kind": "user"})
+	call("lookup", map[string]any{"kind": "user", "id": "42"})
+	call("lookup", map[string]any{"id": "7"})
+	call("create", map[string]any{"id": "42"})
+	call("create", map[string]any{"id": "42"})
+
+	if got := calls.Load(); got != 4 {
+		t.Errorf("target calls = %d, want 4", got)
+	}
+	stats := p.ToolCacheStats()
+	if want := (CacheStats{Hits: 1, Misses: 2, Entries: 2}); stats["lookup"] != want {
+		t.Errorf("ToolCacheStats()[lookup] = %+v, want %+v", stats["lookup"], want)
+	}
+	if _, ok := stats["create"]; ok {
+		t.Errorf("ToolCacheStats() reports the uncached create tool: %+v", stats["create"])
+	}
+}
+
+func TestProxy_ToolResultCacheReplacesResponseCache(t *testing.T) {
+	var calls atomic.Int64
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "create", InputSchema: map[string]any{"type": "object"}},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			calls.Add(1)
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "done"}}}, nil
+		})
+
+	p, session := newInMemoryProxy(t, target, Config{
+		ResponseCache: ResponseCacheConfig{MaxEntries: 10, TTL: time.Minute, CacheableMethods: []string{"tools/call"}},
+		ToolResultCache: ToolResultCacheConfig{
+			DefaultTTL:     time.Minute,
+			MaxEntries:     10,
+			NoCacheMethods: []string{"create"},
+		},
+	}, nil)
+
+	// A tool excluded from the tool result cache is never served from the response cache either
+	for range 3 {
+		params := &mcp.CallToolParams{Name: "create", Arguments: map[string]any{"id": "42"}}
+		if _, err := session.CallTool(context.Background(), params); err != nil {
+			t.Fatalf("CallTool(create) unexpected error: %v", err)
+		}
+	}
+
+	if got := calls.Load(); got != 3 {
+		t.Errorf("target calls = %d, want 3", got)
+	}
+	if got := p.CacheStats(); got != (CacheStats{}) {
+		t.Errorf("CacheStats() = %+v, want the response cache unused for tool calls", got)
+	}
+}
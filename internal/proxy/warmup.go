@@ -0,0 +1,76 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// WarmupCall is a tool call made at startup to fill the tool result cache
+type WarmupCall struct {
+	// ToolName is the name of the tool to call
+	ToolName string
+
+	// Args are the JSON arguments of the call (optional)
+	Args json.RawMessage
+}
+
+// warmCache calls each of the warmup tools on the target and caches the
+// results, so matching client calls are answered from the cache from the
+// first request onward. Failed calls are logged and skipped.
+func (p *Proxy) warmCache(ctx context.Context) {
+	if len(p.warmupCalls) == 0 {
+		return
+	}
+	if p.toolCache == nil {
+		p.logger.Info("skipping cache warmup because the tool result cache is disabled", "calls", len(p.warmupCalls))
+		return
+	}
+
+	p.logger.Info("warming tool result cache", "calls", len(p.warmupCalls))
+	warmed := 0
+	for i, call := range p.warmupCalls {
+		if err := p.warmTool(ctx, call); err != nil {
+			p.logger.Info("cache warmup call failed", "tool", call.ToolName, "error", err)
+			continue
+		}
+		warmed++
+		p.logger.Info("cache warmup call completed", "tool", call.ToolName, "progress", fmt.Sprintf("%d/%d", i+1, len(p.warmupCalls)))
+	}
+	p.logger.Info("tool result cache warmed", "cached", warmed, "calls", len(p.warmupCalls))
+}
+
+// warmTool calls the tool on the target and caches its result under the key
+// a client call with the same arguments would use
+func (p *Proxy) warmTool(ctx context.Context, call WarmupCall) error {
+	if !p.toolCache.cacheable(call.ToolName) {
+		return fmt.Errorf("tool %s is excluded from the tool result cache", call.ToolName)
+	}
+
+	// Clients send the same params as mcp.CallToolParamsRaw
+	key, err := cacheKey(call.ToolName, &mcp.CallToolParamsRaw{Name: call.ToolName, Arguments: call.Args})
+	if err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	params := &mcp.CallToolParams{Name: call.ToolName}
+	if len(call.Args) > 0 {
+		params.Arguments = call.Args
+	}
+
+	ctx, cancel := p.withMethodTimeout(ctx, "tools/call")
+	defer cancel()
+
+	result, err := p.clientSession.Load().CallTool(ctx, params)
+	if err != nil {
+		return err
+	}
+	if result.IsError {
+		return errors.New("tool returned an error result")
+	}
+	p.toolCache.put(call.ToolName, key, result)
+	return nil
+}
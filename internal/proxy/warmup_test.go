@@ -0,0 +1,69 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestProxy_WarmCache(t *testing.T) {
+	var calls atomic.Int64
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "lookup", InputSchema: map[string]any{"type": "object"}},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			calls.Add(1)
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(req.Params.Arguments)}}}, nil
+		})
+
+	p := connectTarget(t, target, Config{
+		ToolResultCache: ToolResultCacheConfig{DefaultTTL: time.Minute, MaxEntries: 10},
+		WarmCacheOnStartup: []WarmupCall{
+			{ToolName: "lookup", Args: json.RawMessage(`{"kind": "user", "id": "42"}`)},
+			{ToolName: "missing"},
+		},
+	})
+	p.warmCache(context.Background())
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("target calls after warmup = %d, want 1", got)
+	}
+
+	session := connectClient(t, p, mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v1.0.0"}, nil))
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name:      "lookup",
+		Arguments: map[string]any{"id": "42", "kind": "user"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool() unexpected error: %v", err)
+	}
+	if got, want := result.Content[0].(*mcp.TextContent).Text, `{"kind":"user","id":"42"}`; got != want {
+		t.Errorf("CallTool() text = %q, want the warmed result %q", got, want)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("target calls = %d, want 1 (the call should be served from the warmed cache)", got)
+	}
+	if want := (CacheStats{Hits: 1, Entries: 1}); p.ToolCacheStats()["lookup"] != want {
+		t.Errorf("ToolCacheStats()[lookup] = %+v, want %+v", p.ToolCacheStats()["lookup"], want)
+	}
+}
+
+func TestProxy_WarmCache_Disabled(t *testing.T) {
+	var calls atomic.Int64
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "lookup", InputSchema: map[string]any{"type": "object"}},
+		func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			calls.Add(1)
+			return &mcp.CallToolResult{}, nil
+		})
+
+	p := connectTarget(t, target, Config{
+		WarmCacheOnStartup: []WarmupCall{{ToolName: "lookup"}},
+	})
+	p.warmCache(context.Background())
+	if got := calls.Load(); got != 0 {
+		t.Errorf("target calls = %d, want 0 without a tool result cache", got)
+	}
+}
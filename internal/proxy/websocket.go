@@ -0,0 +1,160 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/proxyerr"
+	"golang.org/x/net/websocket"
+)
+
+// webSocketSubprotocol is selected when a client offers it
+const webSocketSubprotocol = "mcp"
+
+// webSocketTransport is an MCP transport over a WebSocket connection that
+// carries one JSON-RPC message per text frame
+type webSocketTransport struct {
+	conn *websocket.Conn
+}
+
+// Connect implements mcp.Transport
+func (t *webSocketTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	return &webSocketConn{conn: t.conn}, nil
+}
+
+// webSocketConn implements mcp.Connection. The WebSocket connection locks its
+// reader and writer, so frames are never interleaved.
+type webSocketConn struct {
+	conn *websocket.Conn
+}
+
+// Read reads the next message, blocking until a frame arrives or the
+// connection is closed
+func (c *webSocketConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	var data []byte
+	if err := websocket.Message.Receive(c.conn, &data); err != nil {
+		return nil, err
+	}
+	return jsonrpc.DecodeMessage(data)
+}
+
+// Write sends msg as a single text frame
+func (c *webSocketConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	data, err := jsonrpc.EncodeMessage(msg)
+	if err != nil {
+		return err
+	}
+	return websocket.Message.Send(c.conn, string(data))
+}
+
+// Close closes the WebSocket connection, unblocking Read
+func (c *webSocketConn) Close() error {
+	return c.conn.Close()
+}
+
+// SessionID is empty, as for stdio; WebSocket connections have no MCP session header
+func (c *webSocketConn) SessionID() string {
+	return ""
+}
+
+// checkWebSocketHandshake selects the MCP subprotocol when it is offered and
+// rejects browser requests from other origins, so web pages cannot call the
+// target with the proxy's credentials. Clients that send no Origin header,
+// which browsers always send, are accepted.
+func checkWebSocketHandshake(cfg *websocket.Config, req *http.Request) error {
+	if slices.Contains(cfg.Protocol, webSocketSubprotocol) {
+		cfg.Protocol = []string{webSocketSubprotocol}
+	} else {
+		cfg.Protocol = nil
+	}
+
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+	parsed, err := url.Parse(origin)
+	if err != nil || parsed.Host != req.Host {
+		return fmt.Errorf("cross-origin WebSocket request from %s", origin)
+	}
+	return nil
+}
+
+// WebSocketHandler returns an HTTP handler that upgrades each request to a
+// WebSocket and serves it as a new MCP client session. Every session shares
+// the target session, or gets its own with IsolatedSessions. Sessions end
+// when the client disconnects or ctx is done.
+func (p *Proxy) WebSocketHandler(ctx context.Context) http.Handler {
+	return websocket.Server{
+		Handshake: checkWebSocketHandshake,
+		Handler: func(conn *websocket.Conn) {
+			p.serveWebSocketConn(ctx, conn)
+		},
+	}
+}
+
+// serveWebSocketConn serves one client until it disconnects or ctx is done
+func (p *Proxy) serveWebSocketConn(ctx context.Context, conn *websocket.Conn) {
+	remoteAddr := conn.Request().RemoteAddr
+	session, err := p.server.Connect(ctx, &webSocketTransport{conn: conn}, nil)
+	if err != nil {
+		p.logger.Warn("failed to start WebSocket client session", "remote_addr", remoteAddr, "error", err)
+		return
+	}
+	p.logger.Info("WebSocket client connected", "remote_addr", remoteAddr)
+
+	stop := context.AfterFunc(ctx, func() { session.Close() })
+	defer stop()
+	if err := session.Wait(); err != nil && ctx.Err() == nil {
+		p.logger.Debug("WebSocket client session ended with error", "remote_addr", remoteAddr, "error", err)
+	}
+	p.logger.Info("WebSocket client disconnected", "remote_addr", remoteAddr)
+}
+
+// isLoopbackAddr reports whether addr only accepts connections from this
+// host. An empty host binds every interface.
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// serveWebSocket accepts MCP clients over WebSocket on ListenAddr until ctx is done
+func (p *Proxy) serveWebSocket(ctx context.Context) error {
+	listener, err := net.Listen("tcp", p.listenAddr)
+	if err != nil {
+		return proxyerr.Wrap(proxyerr.ConnectionFailed, err, "failed to listen on %s", p.listenAddr)
+	}
+
+	server := &http.Server{
+		Handler:           p.WebSocketHandler(ctx),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	stop := context.AfterFunc(ctx, func() { server.Close() })
+	defer stop()
+
+	p.logger.Info("accepting MCP clients over WebSocket", "addr", listener.Addr().String())
+	if !isLoopbackAddr(p.listenAddr) {
+		p.logger.Warn("WebSocket listener is reachable from other hosts and has no authentication; "+
+			"anyone who can connect can call the target with the proxy's AWS credentials "+
+			"(listen on a loopback address such as 127.0.0.1:8080 unless the network is trusted)",
+			"addr", p.listenAddr)
+	}
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return proxyerr.Wrap(proxyerr.ConnectionFailed, err, "proxy server failed")
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/net/websocket"
+)
+
+// dialWebSocket connects an MCP client to the proxy's WebSocket handler
+func dialWebSocket(t *testing.T, server *httptest.Server) *mcp.ClientSession {
+	t.Helper()
+	conn, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http"), webSocketSubprotocol, server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v1.0.0"}, nil)
+	session, err := client.Connect(context.Background(), &webSocketTransport{conn: conn}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { session.Close() })
+	return session
+}
+
+func TestProxy_WebSocketClients(t *testing.T) {
+	target := mcp.NewServer(&mcp.Implementation{Name: "target", Version: "v1.0.0"}, nil)
+	target.AddTool(&mcp.Tool{Name: "echo", InputSchema: map[string]any{"type": "object"}}, echoTool)
+	p := connectTarget(t, target, Config{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server := httptest.NewServer(p.WebSocketHandler(ctx))
+	defer server.Close()
+
+	// Every WebSocket connection is its own client session sharing the target
+	for _, session := range []*mcp.ClientSession{dialWebSocket(t, server), dialWebSocket(t, server)} {
+		result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "echo"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text := result.Content[0].(*mcp.TextContent).Text; text != "echo" {
+			t.Errorf("tool result = %q, want %q", text, "echo")
+		}
+	}
+	waitForSessions(t, p, 2)
+
+	// Sessions end when the context is cancelled
+	cancel()
+	waitForSessions(t, p, 0)
+}
+
+func TestCheckWebSocketHandshake(t *testing.T) {
+	tests := []struct {
+		name     string
+		origin   string
+		offered  []string
+		wantErr  bool
+		selected []string
+	}{
+		{name: "no origin", offered: []string{"mcp"}, selected: []string{"mcp"}},
+		{name: "same origin", origin: "http://proxy.local:8080", offered: []string{"chat", "mcp"}, selected: []string{"mcp"}},
+		{name: "no subprotocol", offered: []string{"chat"}},
+		{name: "cross origin", origin: "https://evil.example.com", wantErr: true},
+		{name: "invalid origin", origin: "://", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "http://proxy.local:8080/", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			cfg := &websocket.Config{Protocol: tt.offered}
+			err := checkWebSocketHandshake(cfg, req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkWebSocketHandshake() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && strings.Join(cfg.Protocol, ",") != strings.Join(tt.selected, ",") {
+				t.Errorf("selected subprotocols = %v, want %v", cfg.Protocol, tt.selected)
+			}
+		})
+	}
+}
+
+func TestIsLoopbackAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{addr: "127.0.0.1:8080", want: true},
+		{addr: "[::1]:8080", want: true},
+		{addr: "localhost:8080", want: true},
+		{addr: ":8080"},
+		{addr: "0.0.0.0:8080"},
+		{addr: "10.0.0.5:8080"},
+		{addr: "8080"},
+	}
+
+	for _, tt := range tests {
+		if got := isLoopbackAddr(tt.addr); got != tt.want {
+			t.Errorf("isLoopbackAddr(%q) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Package proxyerr defines the typed error returned by the proxy's transport,
+// credential, and forwarding layers, so callers can branch on the kind of
+// failure with errors.As instead of matching message text.
+package proxyerr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorCode classifies a ProxyError
+type ErrorCode string
+
+const (
+	// ConnectionFailed means the target MCP server could not be reached or the
+	// MCP session could not be established
+	ConnectionFailed ErrorCode = "connection_failed"
+
+	// SigningFailed means a request could not be prepared or signed with SigV4
+	SigningFailed ErrorCode = "signing_failed"
+
+	// CredentialError means AWS credentials could not be loaded or retrieved
+	CredentialError ErrorCode = "credential_error"
+
+	// TargetError means the target MCP server rejected or failed a request
+	TargetError ErrorCode = "target_error"
+
+	// Timeout means a request to the target exceeded its deadline
+	Timeout ErrorCode = "timeout"
+
+	// RateLimited means the outbound rate limiter did not admit a request
+	RateLimited ErrorCode = "rate_limited"
+
+	// InvalidConfig means the proxy was given an unusable setting
+	InvalidConfig ErrorCode = "invalid_config"
+
+	// InvalidRequest means a client request could not be forwarded as sent
+	InvalidRequest ErrorCode = "invalid_request"
+)
+
+// ProxyError is an error with a machine-readable code
+type ProxyError struct {
+	// Code classifies the failure
+	Code ErrorCode
+
+	// Message describes the failure
+	Message string
+
+	// Cause is the underlying error (optional)
+	Cause error
+
+	// RequestID is the injected request ID of the failed request (optional)
+	RequestID string
+}
+
+// Error formats the message followed by the cause
+func (e *ProxyError) Error() string {
+	if e.Cause == nil {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %v", e.Message, e.Cause)
+}
+
+// Unwrap returns the cause so errors.Is and errors.As see through a ProxyError
+func (e *ProxyError) Unwrap() error {
+	return e.Cause
+}
+
+// New returns a ProxyError with a formatted message and no cause
+func New(code ErrorCode, format string, args ...any) *ProxyError {
+	return &ProxyError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap returns a ProxyError with a formatted message wrapping cause
+func Wrap(code ErrorCode, cause error, format string, args ...any) *ProxyError {
+	return &ProxyError{Code: code, Message: fmt.Sprintf(format, args...), Cause: cause}
+}
+
+// HasCode reports whether any ProxyError in err's chain has the given code
+func HasCode(err error, code ErrorCode) bool {
+	for err != nil {
+		var proxyErr *ProxyError
+		if !errors.As(err, &proxyErr) {
+			return false
+		}
+		if proxyErr.Code == code {
+			return true
+		}
+		err = proxyErr.Cause
+	}
+	return false
+}
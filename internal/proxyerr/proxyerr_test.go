@@ -0,0 +1,38 @@
+package proxyerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyError_Error(t *testing.T) {
+	assert.Equal(t, "transport is required", New(InvalidConfig, "transport is required").Error())
+
+	err := Wrap(Timeout, context.DeadlineExceeded, "request to %s timed out", "example.com")
+	assert.Equal(t, "request to example.com timed out: context deadline exceeded", err.Error())
+}
+
+func TestProxyError_Unwrap(t *testing.T) {
+	err := fmt.Errorf("proxy server failed: %w", Wrap(Timeout, context.DeadlineExceeded, "request timed out"))
+
+	var proxyErr *ProxyError
+	require.True(t, errors.As(err, &proxyErr))
+	assert.Equal(t, Timeout, proxyErr.Code)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestHasCode(t *testing.T) {
+	inner := Wrap(SigningFailed, errors.New("no credentials"), "AWS signature generation failed")
+	outer := Wrap(ConnectionFailed, inner, "failed to connect to target MCP server")
+
+	assert.True(t, HasCode(outer, ConnectionFailed))
+	assert.True(t, HasCode(outer, SigningFailed), "codes deeper in the chain are found")
+	assert.False(t, HasCode(outer, RateLimited))
+	assert.False(t, HasCode(errors.New("plain"), SigningFailed))
+	assert.False(t, HasCode(nil, SigningFailed))
+}
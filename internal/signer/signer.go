@@ -3,6 +3,10 @@ package signer
 import (
 	"context"
 	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
 )
 
 // Signer signs HTTP requests with AWS credentials
@@ -10,3 +14,39 @@ type Signer interface {
 	// SignRequest adds AWS signature headers to the request
 	SignRequest(ctx context.Context, req *http.Request, payloadHash string) error
 }
+
+// PresignableSigner is a Signer that can also move the signature into the
+// query string, producing a URL that can be used without AWS credentials
+// until it expires
+type PresignableSigner interface {
+	Signer
+
+	// PresignRequest returns the presigned URL for the request and the headers
+	// that were signed and must be sent with it
+	PresignRequest(ctx context.Context, req *http.Request, expires time.Duration) (presignedURL string, signedHeaders http.Header, err error)
+}
+
+// RefreshableSigner is a Signer whose credentials can be discarded so the next
+// signature uses newly retrieved ones, such as when the target rejects a
+// request signed with temporary credentials that expired in transit
+type RefreshableSigner interface {
+	Signer
+
+	// InvalidateCredentials discards cached credentials, reporting false when
+	// the signer uses static credentials that cannot be refreshed
+	InvalidateCredentials() bool
+}
+
+// invalidateCredentials expires the credentials cached by provider, if it is
+// a cache such as aws.CredentialsCache, so the next Retrieve fetches new ones.
+// It reports false when there is no provider or it always returns the same
+// static credentials.
+func invalidateCredentials(provider aws.CredentialsProvider) bool {
+	if provider == nil || aws.IsCredentialsProvider(provider, credentials.StaticCredentialsProvider{}) {
+		return false
+	}
+	if cache, ok := provider.(interface{ Invalidate() }); ok {
+		cache.Invalidate()
+	}
+	return true
+}
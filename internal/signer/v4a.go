@@ -26,6 +26,11 @@ type V4aSigner struct {
 	// Credentials are the AWS credentials used for signing
 	Credentials aws.Credentials
 
+	// CredentialsProvider, when set, is asked for credentials on every request
+	// instead of using Credentials. Wrap it in an aws.CredentialsCache so
+	// temporary credentials are reused until they need refreshing.
+	CredentialsProvider aws.CredentialsProvider
+
 	// Region is the AWS region for the signature (e.g., "us-east-1")
 	// For multi-region signing, this is used as the primary region
 	Region string
@@ -62,7 +67,11 @@ func (s *V4aSigner) SignRequest(ctx context.Context, req *http.Request, payloadH
 	if s.Service == "" {
 		return fmt.Errorf("service name is required for SigV4a signing")
 	}
-	if s.Credentials.AccessKeyID == "" || s.Credentials.SecretAccessKey == "" {
+	creds, err := s.credentials(ctx)
+	if err != nil {
+		return err
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
 		return fmt.Errorf("AWS credentials are required for SigV4a signing")
 	}
 
@@ -70,3 +79,23 @@ func (s *V4aSigner) SignRequest(ctx context.Context, req *http.Request, payloadH
 	// Once AWS makes the v4a signer public, this should be replaced with actual signing logic
 	return fmt.Errorf("%w: see https://github.com/aws/aws-sdk-go-v2/issues/1935 for status", ErrV4aNotAvailable)
 }
+
+// InvalidateCredentials discards the credentials cached by CredentialsProvider
+// so the next request is signed with newly retrieved ones. It reports false
+// when the signer has no CredentialsProvider.
+func (s *V4aSigner) InvalidateCredentials() bool {
+	return invalidateCredentials(s.CredentialsProvider)
+}
+
+// credentials returns the credentials to sign with, retrieving them from
+// CredentialsProvider when one is set
+func (s *V4aSigner) credentials(ctx context.Context) (aws.Credentials, error) {
+	if s.CredentialsProvider == nil {
+		return s.Credentials, nil
+	}
+	creds, err := s.CredentialsProvider.Retrieve(ctx)
+	if err != nil {
+		return aws.Credentials{}, fmt.Errorf("failed to retrieve AWS credentials for SigV4a signing: %w", err)
+	}
+	return creds, nil
+}
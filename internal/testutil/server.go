@@ -0,0 +1,66 @@
+package testutil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MockMCPServer is an MCP server served over streamable HTTP by an
+// httptest.Server. Capabilities are registered with the With methods, before
+// or after clients connect, and the headers of every request are recorded.
+type MockMCPServer struct {
+	*httptest.Server
+
+	// MCP is the underlying MCP server
+	MCP *mcp.Server
+
+	mu      sync.Mutex
+	headers []http.Header
+}
+
+// NewMockMCPServer starts a mock MCP server that is closed when the test ends
+func NewMockMCPServer(t testing.TB) *MockMCPServer {
+	t.Helper()
+
+	m := &MockMCPServer{
+		MCP: mcp.NewServer(&mcp.Implementation{Name: "mock-target", Version: "v1.0.0"}, nil),
+	}
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return m.MCP }, nil)
+	m.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.mu.Lock()
+		m.headers = append(m.headers, r.Header.Clone())
+		m.mu.Unlock()
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(m.Close)
+	return m
+}
+
+// WithTool registers a tool that accepts any JSON object as input
+func (m *MockMCPServer) WithTool(name string, handler mcp.ToolHandler) *MockMCPServer {
+	m.MCP.AddTool(&mcp.Tool{Name: name, InputSchema: map[string]any{"type": "object"}}, handler)
+	return m
+}
+
+// WithResource registers a resource at uri
+func (m *MockMCPServer) WithResource(uri string, handler mcp.ResourceHandler) *MockMCPServer {
+	m.MCP.AddResource(&mcp.Resource{Name: uri, URI: uri}, handler)
+	return m
+}
+
+// WithPrompt registers a prompt without arguments
+func (m *MockMCPServer) WithPrompt(name string, handler mcp.PromptHandler) *MockMCPServer {
+	m.MCP.AddPrompt(&mcp.Prompt{Name: name}, handler)
+	return m
+}
+
+// RequestHeaders returns the headers of every HTTP request received so far, in order
+func (m *MockMCPServer) RequestHeaders() []http.Header {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]http.Header(nil), m.headers...)
+}
@@ -0,0 +1,57 @@
+package testutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestMockMCPServer(t *testing.T) {
+	server := NewMockMCPServer(t).
+		WithTool("echo", func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "echo"}}}, nil
+		}).
+		WithResource("file:///readme", func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: req.Params.URI, Text: "readme"}}}, nil
+		}).
+		WithPrompt("greeting", func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			return &mcp.GetPromptResult{Messages: []*mcp.PromptMessage{{Role: "user", Content: &mcp.TextContent{Text: "hello"}}}}, nil
+		})
+
+	ctx := context.Background()
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v1.0.0"}, nil)
+	session, err := client.Connect(ctx, &mcp.StreamableClientTransport{Endpoint: server.URL}, nil)
+	if err != nil {
+		t.Fatalf("Connect() unexpected error: %v", err)
+	}
+	defer session.Close()
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "echo"})
+	if err != nil {
+		t.Fatalf("CallTool() unexpected error: %v", err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; text != "echo" {
+		t.Errorf("CallTool() text = %q, want echo", text)
+	}
+
+	resource, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "file:///readme"})
+	if err != nil {
+		t.Fatalf("ReadResource() unexpected error: %v", err)
+	}
+	if resource.Contents[0].Text != "readme" {
+		t.Errorf("ReadResource() text = %q, want readme", resource.Contents[0].Text)
+	}
+
+	prompt, err := session.GetPrompt(ctx, &mcp.GetPromptParams{Name: "greeting"})
+	if err != nil {
+		t.Fatalf("GetPrompt() unexpected error: %v", err)
+	}
+	if len(prompt.Messages) != 1 {
+		t.Errorf("GetPrompt() messages = %d, want 1", len(prompt.Messages))
+	}
+
+	if len(server.RequestHeaders()) == 0 {
+		t.Error("expected the server to record request headers")
+	}
+}
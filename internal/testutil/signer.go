@@ -0,0 +1,95 @@
+// Package testutil provides shared test doubles for the proxy's packages: a
+// FakeSigner that records signing calls and a MockMCPServer that serves MCP
+// over streamable HTTP.
+package testutil
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/signer"
+)
+
+// FakeAuthorization is the Authorization header FakeSigner sets on signed requests
+const FakeAuthorization = "AWS4-HMAC-SHA256 Credential=test/20240101/us-east-1/execute-api/aws4_request"
+
+// FakeSigner implements signer.Signer without AWS credentials. It records each
+// signed request and its payload hash, and sets fixed Authorization and
+// X-Amz-Date headers so signing can be detected downstream.
+type FakeSigner struct {
+	// Err is returned by SignRequest instead of signing when set
+	Err error
+
+	mu            sync.Mutex
+	requests      []*http.Request
+	payloadHashes []string
+}
+
+var _ signer.Signer = (*FakeSigner)(nil)
+
+// SignRequest records the request and sets the fake signature headers, or returns Err
+func (s *FakeSigner) SignRequest(ctx context.Context, req *http.Request, payloadHash string) error {
+	if s.Err != nil {
+		return s.Err
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	s.payloadHashes = append(s.payloadHashes, payloadHash)
+	s.mu.Unlock()
+
+	req.Header.Set("Authorization", FakeAuthorization)
+	req.Header.Set("X-Amz-Date", "20240101T000000Z")
+	return nil
+}
+
+// Requests returns the requests signed so far, in order
+func (s *FakeSigner) Requests() []*http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*http.Request(nil), s.requests...)
+}
+
+// PayloadHashes returns the payload hash of each signed request, in order
+func (s *FakeSigner) PayloadHashes() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.payloadHashes...)
+}
+
+// Reset forgets the recorded requests and payload hashes
+func (s *FakeSigner) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+	s.payloadHashes = nil
+}
+
+// MustSign hashes the request body and signs req with s, failing the test on
+// error. The body is restored so the request can still be sent.
+func MustSign(t testing.TB, s signer.Signer, req *http.Request) {
+	t.Helper()
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			t.Fatalf("failed to read request body: %v", err)
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.ContentLength = int64(len(body))
+	}
+
+	hash := sha256.Sum256(body)
+	if err := s.SignRequest(req.Context(), req, hex.EncodeToString(hash[:])); err != nil {
+		t.Fatalf("failed to sign request: %v", err)
+	}
+}
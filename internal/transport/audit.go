@@ -0,0 +1,120 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditEntry records one signed request sent to the target server
+type AuditEntry struct {
+	Timestamp          time.Time     `json:"timestamp"`
+	Method             string        `json:"method"`
+	URL                string        `json:"url"`
+	MaskedAccessKeyID  string        `json:"masked_access_key_id"`
+	SignatureAlgorithm string        `json:"signature_algorithm"`
+	StatusCode         int           `json:"status_code"`
+	Latency            time.Duration `json:"latency_ns"`
+	RequestID          string        `json:"request_id,omitempty"`
+	Headers            http.Header   `json:"headers,omitempty"`
+}
+
+// AuditLogger records signed requests. Implementations must be safe for concurrent use.
+type AuditLogger interface {
+	// LogEntry records a signed request; a zero StatusCode means no response was received
+	LogEntry(ctx context.Context, entry AuditEntry)
+}
+
+// FileAuditLogger is an AuditLogger that appends entries to a file as
+// newline-delimited JSON
+type FileAuditLogger struct {
+	mu     sync.Mutex
+	file   *os.File
+	enc    *json.Encoder
+	logger *slog.Logger
+}
+
+// NewFileAuditLogger opens path for appending, creating it with owner-only
+// permissions if needed. Write failures are reported to logger (optional).
+func NewFileAuditLogger(path string, logger *slog.Logger) (*FileAuditLogger, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log %s: %w", path, err)
+	}
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	return &FileAuditLogger{file: file, enc: json.NewEncoder(file), logger: logger}, nil
+}
+
+// LogEntry implements AuditLogger
+func (l *FileAuditLogger) LogEntry(ctx context.Context, entry AuditEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.enc.Encode(entry); err != nil {
+		l.logger.Error("failed to write audit log entry", "file", l.file.Name(), "error", err)
+	}
+}
+
+// Close closes the audit log file
+func (l *FileAuditLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// auditRequest records a signed request with the audit logger, if one is configured
+func (rt *SigningRoundTripper) auditRequest(req *http.Request, resp *http.Response, start time.Time) {
+	if rt.AuditLogger == nil {
+		return
+	}
+
+	algorithm, accessKeyID := parseAuthorization(req.Header.Get("Authorization"))
+	entry := AuditEntry{
+		Timestamp:          start.UTC(),
+		Method:             req.Method,
+		URL:                req.URL.Redacted(),
+		MaskedAccessKeyID:  maskAccessKeyID(accessKeyID),
+		SignatureAlgorithm: algorithm,
+		Latency:            time.Since(start),
+		Headers:            rt.redactHeaders(req.Header),
+	}
+	if resp != nil {
+		entry.StatusCode = resp.StatusCode
+	}
+	if rt.InjectRequestID {
+		header := rt.RequestIDHeader
+		if header == "" {
+			header = DefaultRequestIDHeader
+		}
+		entry.RequestID = req.Header.Get(header)
+	}
+	rt.AuditLogger.LogEntry(req.Context(), entry)
+}
+
+// parseAuthorization extracts the algorithm and access key ID from a SigV4 or
+// SigV4a Authorization header of the form "<algorithm> Credential=<key>/<scope>, ..."
+func parseAuthorization(header string) (algorithm, accessKeyID string) {
+	algorithm, params, _ := strings.Cut(header, " ")
+	for _, param := range strings.Split(params, ",") {
+		if credential, ok := strings.CutPrefix(strings.TrimSpace(param), "Credential="); ok {
+			accessKeyID, _, _ = strings.Cut(credential, "/")
+			break
+		}
+	}
+	return algorithm, accessKeyID
+}
+
+// maskAccessKeyID keeps only the first and last four characters of an access key ID
+func maskAccessKeyID(accessKeyID string) string {
+	if len(accessKeyID) <= 8 {
+		return "****"
+	}
+	return accessKeyID[:4] + "****" + accessKeyID[len(accessKeyID)-4:]
+}
@@ -0,0 +1,249 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// codeInternalError is the JSON-RPC 2.0 Internal error code, returned for
+// batch elements the target answered with an HTTP error status
+const codeInternalError = -32603
+
+// isBatchRequest reports whether the body of a POST request is a JSON-RPC
+// batch: a JSON array. The body is read only up to its first non-whitespace
+// byte, from a copy made with GetBody when the request has one; otherwise
+// req.Body is replaced so it is still sent in full.
+func isBatchRequest(req *http.Request) (bool, error) {
+	if req.Method != http.MethodPost || req.Body == nil || req.Body == http.NoBody {
+		return false, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return false, err
+		}
+		defer body.Close()
+		return startsWithArray(bufio.NewReader(body))
+	}
+	reader := bufio.NewReader(req.Body)
+	req.Body = readCloser{Reader: reader, Closer: req.Body}
+	return startsWithArray(reader)
+}
+
+// startsWithArray reports whether the first non-whitespace byte of reader is
+// '[', without consuming any of it
+func startsWithArray(reader *bufio.Reader) (bool, error) {
+	for n := 1; ; n++ {
+		peeked, err := reader.Peek(n)
+		if len(peeked) < n {
+			if err == io.EOF || errors.Is(err, bufio.ErrBufferFull) {
+				return false, nil
+			}
+			return false, err
+		}
+		switch peeked[n-1] {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return peeked[n-1] == '[', nil
+	}
+}
+
+// readCloser pairs a Reader with the Closer of the body it reads from
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// readBatch reads the body of a request detected by isBatchRequest and
+// returns its elements. The body is restored so the request can be sent as is
+// when it is not a non-empty JSON array, such as when it is malformed; the
+// target then reports the error.
+func readBatch(req *http.Request) ([]json.RawMessage, error) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	var messages []json.RawMessage
+	if json.Unmarshal(body, &messages) != nil {
+		return nil, nil
+	}
+	return messages, nil
+}
+
+// roundTripBatch sends each request of a JSON-RPC batch to the target as its
+// own signed request, concurrently, and answers with a JSON array of their
+// responses in the order of the requests. Notifications have no response; a
+// batch of only notifications is answered with 202 Accepted. A request
+// answered with an HTTP error status or 202 Accepted, or that failed to reach
+// the target, gets a JSON-RPC error response; the other requests are still
+// answered.
+func (rt *SigningRoundTripper) roundTripBatch(req *http.Request, messages []json.RawMessage) (*http.Response, error) {
+	rt.Logger.Debug("forwarding JSON-RPC batch", "requests", len(messages))
+
+	results := make([]json.RawMessage, len(messages))
+	responses := make([]*http.Response, len(messages))
+	var group errgroup.Group
+	for i, message := range messages {
+		group.Go(func() error {
+			single := req.Clone(req.Context())
+			single.Body = io.NopCloser(bytes.NewReader(message))
+			single.ContentLength = int64(len(message))
+			single.GetBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(message)), nil
+			}
+			single.Header.Set("Content-Length", strconv.Itoa(len(message)))
+
+			resp, err := rt.RoundTrip(single)
+			if err != nil {
+				rt.Logger.Warn("JSON-RPC batch element failed", "error", err)
+				results[i], err = batchFailure(message, err)
+				return err
+			}
+			defer resp.Body.Close()
+			responses[i] = resp
+			results[i], err = batchResult(message, resp)
+			return err
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+
+	var assembled []json.RawMessage
+	header := http.Header{}
+	for i, result := range results {
+		if header.Get("Mcp-Session-Id") == "" && responses[i] != nil {
+			if sessionID := responses[i].Header.Get("Mcp-Session-Id"); sessionID != "" {
+				header.Set("Mcp-Session-Id", sessionID)
+			}
+		}
+		if result != nil {
+			assembled = append(assembled, result)
+		}
+	}
+
+	resp := &http.Response{
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     header,
+		Request:    req,
+	}
+	if len(assembled) == 0 {
+		resp.StatusCode, resp.Status = http.StatusAccepted, "202 Accepted"
+		resp.Body = http.NoBody
+		return resp, nil
+	}
+	data, err := json.Marshal(assembled)
+	if err != nil {
+		return nil, err
+	}
+	resp.StatusCode, resp.Status = http.StatusOK, "200 OK"
+	resp.Header.Set("Content-Type", "application/json")
+	resp.Header.Set("Content-Length", strconv.Itoa(len(data)))
+	resp.ContentLength = int64(len(data))
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	return resp, nil
+}
+
+// batchResult returns the JSON-RPC response to message in resp, which is JSON
+// or an event stream, or nil when message is a notification. A request the
+// target answered with an HTTP error status, or accepted without a response,
+// gets a JSON-RPC error response so the batch still answers it.
+func batchResult(message json.RawMessage, resp *http.Response) (json.RawMessage, error) {
+	id, err := batchRequestID(message)
+	switch {
+	case err != nil:
+		return nil, err
+	case id == nil:
+		return nil, nil
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return batchError(id, fmt.Sprintf("target returned HTTP %d", resp.StatusCode))
+	case resp.StatusCode == http.StatusAccepted:
+		return batchError(id, "target accepted the request without a response")
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if !isEventStream(resp) {
+		return bytes.TrimSpace(body), nil
+	}
+
+	// The response is the event whose data carries a result or an error;
+	// other events are requests and notifications from the target. The data
+	// lines of an event are joined with newlines, as the SSE specification
+	// requires.
+	stream := strings.ReplaceAll(string(body), "\r\n", "\n")
+	for event := range strings.SplitSeq(stream, "\n\n") {
+		var lines []string
+		for line := range strings.SplitSeq(event, "\n") {
+			if value, ok := strings.CutPrefix(line, "data:"); ok {
+				lines = append(lines, strings.TrimPrefix(value, " "))
+			}
+		}
+		data := strings.Join(lines, "\n")
+		var reply struct {
+			Result json.RawMessage `json:"result"`
+			Error  json.RawMessage `json:"error"`
+		}
+		if json.Unmarshal([]byte(data), &reply) == nil && (reply.Result != nil || reply.Error != nil) {
+			return json.RawMessage(data), nil
+		}
+	}
+	return nil, fmt.Errorf("no response for JSON-RPC request %s in event stream", id)
+}
+
+// batchFailure returns the JSON-RPC error response to message when sending it
+// to the target failed with err, or nil when message is a notification
+func batchFailure(message json.RawMessage, err error) (json.RawMessage, error) {
+	id, idErr := batchRequestID(message)
+	if idErr != nil || id == nil {
+		return nil, idErr
+	}
+	return batchError(id, err.Error())
+}
+
+// batchRequestID returns the id of the JSON-RPC request in message, or nil
+// when message is a notification
+func batchRequestID(message json.RawMessage) (json.RawMessage, error) {
+	var request struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if err := json.Unmarshal(message, &request); err != nil {
+		return nil, fmt.Errorf("invalid JSON-RPC batch element: %w", err)
+	}
+	return request.ID, nil
+}
+
+// batchError returns a JSON-RPC Internal error response to the request with id
+func batchError(id json.RawMessage, message string) (json.RawMessage, error) {
+	return json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"error": map[string]any{
+			"code":    codeInternalError,
+			"message": message,
+		},
+	})
+}
@@ -0,0 +1,217 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningRoundTripper_Batch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			http.Error(w, "batch element expected", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Mcp-Session-Id", "session-1")
+		switch message.Method {
+		case "notifications/initialized":
+			w.WriteHeader(http.StatusAccepted)
+		case "tools/list":
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprintf(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\"}\n\n")
+			fmt.Fprintf(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":%s,\"result\":{\"tools\":[]}}\n\n", message.ID)
+		case "fail":
+			w.WriteHeader(http.StatusForbidden)
+		case "accept":
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{}}`, message.ID)
+		}
+	}))
+	defer server.Close()
+
+	signer := &testutil.FakeSigner{}
+	rt := NewSigningRoundTripper(http.DefaultTransport, signer, nil)
+
+	batch := ` [
+		{"jsonrpc":"2.0","id":1,"method":"ping"},
+		{"jsonrpc":"2.0","method":"notifications/initialized"},
+		{"jsonrpc":"2.0","id":2,"method":"tools/list"},
+		{"jsonrpc":"2.0","id":3,"method":"fail"},
+		{"jsonrpc":"2.0","id":4,"method":"accept"}
+	]`
+	req, err := http.NewRequest("POST", server.URL, strings.NewReader(batch))
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "session-1", resp.Header.Get("Mcp-Session-Id"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"jsonrpc":"2.0","id":1,"result":{}},
+		{"jsonrpc":"2.0","id":2,"result":{"tools":[]}},
+		{"jsonrpc":"2.0","id":3,"error":{"code":-32603,"message":"target returned HTTP 403"}},
+		{"jsonrpc":"2.0","id":4,"error":{"code":-32603,"message":"target accepted the request without a response"}}
+	]`, string(body))
+
+	assert.Len(t, signer.PayloadHashes(), 5, "each batch element is signed as its own request")
+}
+
+func TestSigningRoundTripper_BatchElementUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			http.Error(w, "batch element expected", http.StatusBadRequest)
+			return
+		}
+		if message.Method == "dead" {
+			// Drop the connection without a response
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			conn.Close()
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":{}}`, message.ID)
+	}))
+	defer server.Close()
+
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil)
+
+	batch := `[
+		{"jsonrpc":"2.0","id":1,"method":"ping"},
+		{"jsonrpc":"2.0","id":2,"method":"dead"},
+		{"jsonrpc":"2.0","id":3,"method":"ping"}
+	]`
+	req, err := http.NewRequest("POST", server.URL, strings.NewReader(batch))
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err, "one unreachable element must not fail the batch")
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var replies []struct {
+		ID     int             `json:"id"`
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&replies))
+	require.Len(t, replies, 3)
+	assert.JSONEq(t, `{}`, string(replies[0].Result))
+	require.NotNil(t, replies[1].Error)
+	assert.Equal(t, 2, replies[1].ID)
+	assert.Equal(t, -32603, replies[1].Error.Code)
+	assert.Contains(t, replies[1].Error.Message, "failed to connect to target")
+	assert.JSONEq(t, `{}`, string(replies[2].Result))
+}
+
+func TestBatchResult_MultilineEventData(t *testing.T) {
+	stream := "event: message\r\n" +
+		"data: {\"jsonrpc\": \"2.0\", \"id\": 7,\r\n" +
+		"data:  \"result\": {\"n\": 1}}\r\n\r\n"
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"text/event-stream"}},
+		Body:       io.NopCloser(strings.NewReader(stream)),
+	}
+
+	result, err := batchResult(json.RawMessage(`{"jsonrpc":"2.0","id":7,"method":"ping"}`), resp)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"jsonrpc\": \"2.0\", \"id\": 7,\n \"result\": {\"n\": 1}}", string(result),
+		"data lines are joined with a newline")
+}
+
+func TestSigningRoundTripper_BatchOfNotifications(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil)
+	req, err := http.NewRequest("POST", server.URL, strings.NewReader(`[{"jsonrpc":"2.0","method":"notifications/initialized"}]`))
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+}
+
+func TestIsBatchRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		body        string
+		withGetBody bool
+		want        bool
+	}{
+		{"array", "POST", `[{"id":1}]`, true, true},
+		{"leading whitespace", "POST", " \n\t[{}]", true, true},
+		{"object", "POST", `{"id":1}`, true, false},
+		{"whitespace only", "POST", "  ", true, false},
+		{"without GetBody", "POST", " [1]", false, true},
+		{"GET", "GET", `[1]`, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader = strings.NewReader(tt.body)
+			if !tt.withGetBody {
+				body = io.NopCloser(body)
+			}
+			req, err := http.NewRequest(tt.method, "https://example.com", body)
+			require.NoError(t, err)
+			got, err := isBatchRequest(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			// The body is still sent in full
+			sent, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, string(sent))
+		})
+	}
+}
+
+func TestSigningRoundTripper_MalformedBatch(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	// A body that is not a JSON array is sent as is for the target to reject
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil)
+	req, err := http.NewRequest("POST", server.URL, strings.NewReader(`[{"jsonrpc":`))
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `[{"jsonrpc":`, received)
+}
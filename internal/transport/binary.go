@@ -0,0 +1,55 @@
+package transport
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// DefaultBinaryContentTypes are the response media types passed through as
+// raw bytes when BinaryContentTypes is not set
+var DefaultBinaryContentTypes = []string{"application/octet-stream", "image/*", "audio/*"}
+
+// WithBinaryContentTypes replaces the response media types passed through as
+// raw bytes. An entry ending in /* matches every subtype of its type.
+func WithBinaryContentTypes(types ...string) Option {
+	return func(rt *SigningRoundTripper) {
+		rt.BinaryContentTypes = types
+	}
+}
+
+// isBinaryResponse reports whether the Content-Type of resp is one of the
+// binary content types
+func (rt *SigningRoundTripper) isBinaryResponse(resp *http.Response) bool {
+	types := rt.BinaryContentTypes
+	if types == nil {
+		types = DefaultBinaryContentTypes
+	}
+	return matchMediaType(resp.Header.Get("Content-Type"), types)
+}
+
+// matchMediaType reports whether the media type of contentType matches one of
+// patterns, ignoring parameters and case. A pattern of type/* matches every
+// subtype of type.
+func matchMediaType(contentType string, patterns []string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, pattern := range patterns {
+		patternType, _, err := mime.ParseMediaType(pattern)
+		if err != nil {
+			continue
+		}
+		if mainType, ok := strings.CutSuffix(patternType, "/*"); ok {
+			if strings.HasPrefix(mediaType, mainType+"/") {
+				return true
+			}
+			continue
+		}
+		if mediaType == patternType {
+			return true
+		}
+	}
+	return false
+}
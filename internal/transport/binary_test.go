@@ -0,0 +1,82 @@
+package transport
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchMediaType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        bool
+	}{
+		{contentType: "application/octet-stream", want: true},
+		{contentType: "image/png", want: true},
+		{contentType: "Image/PNG; charset=binary", want: true},
+		{contentType: "audio/mpeg", want: true},
+		{contentType: "application/json", want: false},
+		{contentType: "text/event-stream", want: false},
+		{contentType: "imagery/png", want: false},
+		{contentType: "", want: false},
+		{contentType: "not a media type", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchMediaType(tt.contentType, DefaultBinaryContentTypes))
+		})
+	}
+}
+
+func TestSigningRoundTripper_BinaryContentTypes(t *testing.T) {
+	png := []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	zw.Write(png)
+	zw.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", r.URL.Query().Get("type"))
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(compressed.Bytes())
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		contentType string
+		opts        []Option
+		wantBody    []byte
+	}{
+		{name: "binary response is passed through", contentType: "image/png", wantBody: compressed.Bytes()},
+		{name: "other responses are decompressed", contentType: "application/json", wantBody: png},
+		{
+			name:        "configured types replace the defaults",
+			contentType: "image/png",
+			opts:        []Option{WithBinaryContentTypes("application/pdf")},
+			wantBody:    png,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{WithResponseDecompression(), WithDebugMode()}, tt.opts...)
+			rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil, opts...)
+			req, err := http.NewRequest("GET", server.URL+"?type="+tt.contentType, nil)
+			require.NoError(t, err)
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantBody, body)
+		})
+	}
+}
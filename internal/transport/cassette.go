@@ -0,0 +1,208 @@
+package transport
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// CassetteRecord is one request and its response, stored as a line of NDJSON in
+// a cassette file by RecordingTransport and served back by ReplayTransport
+type CassetteRecord struct {
+	Method          string      `json:"method"`
+	URL             string      `json:"url"`
+	RequestHeaders  http.Header `json:"request_headers,omitempty"`
+	RequestBody     string      `json:"request_body,omitempty"`
+	RequestBodyHash string      `json:"request_body_sha256"`
+	StatusCode      int         `json:"status_code"`
+	ResponseHeaders http.Header `json:"response_headers,omitempty"`
+	ResponseBody    string      `json:"response_body,omitempty"`
+}
+
+// unrecordedHeaders are removed from recorded requests because they hold
+// credentials or change on every signature
+var unrecordedHeaders = append([]string{"X-Amz-Date"}, DefaultRedactedHeaders...)
+
+// ErrNoRecording is returned by ReplayTransport for a request that has no
+// unreplayed record in the cassette
+var ErrNoRecording = errors.New("no recorded response matches the request")
+
+// RecordingTransport sends requests with Transport, typically a
+// SigningRoundTripper, and appends every completed exchange to a cassette file.
+// Response bodies are read in full before they are returned, so it is not
+// suited to long-lived SSE streams.
+type RecordingTransport struct {
+	// Transport sends the requests being recorded
+	Transport http.RoundTripper
+
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// NewRecordingTransport opens the cassette at path for appending, creating it
+// with owner-only permissions if needed
+func NewRecordingTransport(transport http.RoundTripper, path string) (*RecordingTransport, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cassette %s: %w", path, err)
+	}
+	return &RecordingTransport{Transport: transport, file: file, enc: json.NewEncoder(file)}, nil
+}
+
+// RoundTrip implements http.RoundTripper, recording the exchange when a response is received
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body for recording: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	headers := req.Header.Clone()
+	for _, name := range unrecordedHeaders {
+		headers.Del(name)
+	}
+	record := CassetteRecord{
+		Method:          req.Method,
+		URL:             req.URL.String(),
+		RequestHeaders:  headers,
+		RequestBody:     string(body),
+		RequestBodyHash: bodyHash(body),
+		StatusCode:      resp.StatusCode,
+		ResponseHeaders: resp.Header,
+		ResponseBody:    string(respBody),
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if err := t.enc.Encode(record); err != nil {
+		return nil, fmt.Errorf("failed to write cassette record: %w", err)
+	}
+	return resp, nil
+}
+
+// Close closes the cassette file
+func (t *RecordingTransport) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.file.Close()
+}
+
+// ReplayTransport answers requests from a cassette written by RecordingTransport
+// without contacting a server. Each record is replayed at most once, matched by
+// method, URL, and the SHA256 hash of the request body.
+type ReplayTransport struct {
+	mu       sync.Mutex
+	records  []CassetteRecord
+	replayed []bool
+}
+
+// NewReplayTransport reads the cassette at path
+func NewReplayTransport(path string) (*ReplayTransport, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cassette %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var records []CassetteRecord
+	dec := json.NewDecoder(file)
+	for dec.More() {
+		var record CassetteRecord
+		if err := dec.Decode(&record); err != nil {
+			return nil, fmt.Errorf("invalid cassette record %d in %s: %w", len(records)+1, path, err)
+		}
+		records = append(records, record)
+	}
+
+	return &ReplayTransport{records: records, replayed: make([]bool, len(records))}, nil
+}
+
+// RoundTrip implements http.RoundTripper, returning the first unreplayed
+// matching record or an error wrapping ErrNoRecording
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	url, hash := req.URL.String(), bodyHash(body)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, record := range t.records {
+		if t.replayed[i] || record.Method != req.Method || record.URL != url || record.RequestBodyHash != hash {
+			continue
+		}
+		t.replayed[i] = true
+
+		header := record.ResponseHeaders.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		return &http.Response{
+			Status:        strconv.Itoa(record.StatusCode) + " " + http.StatusText(record.StatusCode),
+			StatusCode:    record.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader([]byte(record.ResponseBody))),
+			ContentLength: int64(len(record.ResponseBody)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s (body sha256 %s)", ErrNoRecording, req.Method, url, hash)
+}
+
+// Remaining returns the number of records that have not been replayed yet
+func (t *ReplayTransport) Remaining() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	remaining := 0
+	for _, replayed := range t.replayed {
+		if !replayed {
+			remaining++
+		}
+	}
+	return remaining
+}
+
+// readRequestBody reads and restores the request body so it can still be sent
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// bodyHash returns the hex SHA256 hash of body
+func bodyHash(body []byte) string {
+	hash := sha256.Sum256(body)
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,84 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordingTransport_Replay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"echo":` + string(body) + `}`))
+	}))
+	defer server.Close()
+
+	cassette := filepath.Join(t.TempDir(), "cassette.ndjson")
+	recorder, err := NewRecordingTransport(NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil), cassette)
+	require.NoError(t, err)
+
+	for _, body := range []string{`{"id":1}`, `{"id":2}`} {
+		req, err := http.NewRequest("POST", server.URL+"/mcp", strings.NewReader(body))
+		require.NoError(t, err)
+		resp, err := recorder.RoundTrip(req)
+		require.NoError(t, err)
+		got, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, `{"echo":`+body+`}`, string(got))
+	}
+	require.NoError(t, recorder.Close())
+
+	// Credentials and signature headers are not recorded
+	data, err := os.ReadFile(cassette)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), "\n"))
+	assert.NotContains(t, string(data), "Authorization")
+	assert.NotContains(t, string(data), "X-Amz-Date")
+
+	// Replay answers without the server, in any order
+	server.Close()
+	replay, err := NewReplayTransport(cassette)
+	require.NoError(t, err)
+	assert.Equal(t, 2, replay.Remaining())
+
+	for _, body := range []string{`{"id":2}`, `{"id":1}`} {
+		req, err := http.NewRequest("POST", server.URL+"/mcp", strings.NewReader(body))
+		require.NoError(t, err)
+		resp, err := replay.RoundTrip(req)
+		require.NoError(t, err)
+		got, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		assert.Equal(t, `{"echo":`+body+`}`, string(got))
+	}
+	assert.Zero(t, replay.Remaining())
+
+	// Each record is replayed once, and unknown requests do not match
+	for _, body := range []string{`{"id":1}`, `{"id":3}`} {
+		req, err := http.NewRequest("POST", server.URL+"/mcp", strings.NewReader(body))
+		require.NoError(t, err)
+		_, err = replay.RoundTrip(req)
+		assert.ErrorIs(t, err, ErrNoRecording)
+	}
+}
+
+func TestNewReplayTransport_Errors(t *testing.T) {
+	_, err := NewReplayTransport(filepath.Join(t.TempDir(), "missing.ndjson"))
+	assert.ErrorContains(t, err, "failed to open cassette")
+
+	invalid := filepath.Join(t.TempDir(), "invalid.ndjson")
+	require.NoError(t, os.WriteFile(invalid, []byte("{\"method\":\"POST\"}\nnot json\n"), 0o600))
+	_, err = NewReplayTransport(invalid)
+	assert.ErrorContains(t, err, "invalid cassette record 2")
+}
@@ -0,0 +1,87 @@
+package transport
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningRoundTripper_RequestCompression(t *testing.T) {
+	var received []byte
+	var encoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding = r.Header.Get("Content-Encoding")
+		received, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	signer := &testutil.FakeSigner{}
+	rt := NewSigningRoundTripper(http.DefaultTransport, signer, nil, WithRequestCompression())
+
+	payload := `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`
+	req, err := http.NewRequest("POST", server.URL, strings.NewReader(payload))
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, "gzip", encoding)
+	zr, err := gzip.NewReader(bytes.NewReader(received))
+	require.NoError(t, err)
+	decompressed, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, payload, string(decompressed))
+
+	// The signature covers the compressed bytes
+	hash := sha256.Sum256(received)
+	assert.Equal(t, []string{hex.EncodeToString(hash[:])}, signer.PayloadHashes())
+}
+
+func TestSigningRoundTripper_ResponseDecompression(t *testing.T) {
+	payload := `{"jsonrpc":"2.0","id":1,"result":{}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != "gzip" {
+			io.WriteString(w, payload)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		io.WriteString(zw, payload)
+		zw.Close()
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "decompression enabled", opts: []Option{WithResponseDecompression()}},
+		{name: "decompression disabled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil, tt.opts...)
+			req, err := http.NewRequest("GET", server.URL, nil)
+			require.NoError(t, err)
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, payload, string(body))
+			assert.Empty(t, resp.Header.Get("Content-Encoding"))
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package transport
+
+import "context"
+
+// WithMaxConcurrentRequests caps the requests in flight at n; further
+// requests wait for a slot or for their context to be cancelled. A
+// non-positive n disables the limit.
+func WithMaxConcurrentRequests(n int) Option {
+	return func(rt *SigningRoundTripper) {
+		rt.MaxConcurrentRequests = n
+	}
+}
+
+// WaitingRequests returns the number of requests waiting for a slot under
+// MaxConcurrentRequests
+func (rt *SigningRoundTripper) WaitingRequests() int {
+	return int(rt.waitingRequests.Load())
+}
+
+// acquireSlot blocks until a request slot is free under MaxConcurrentRequests
+// or ctx is done, returning a function that frees the slot. It returns ctx's
+// error if ctx is done first.
+func (rt *SigningRoundTripper) acquireSlot(ctx context.Context) (release func(), err error) {
+	if rt.MaxConcurrentRequests <= 0 {
+		return func() {}, nil
+	}
+	rt.slotsOnce.Do(func() {
+		rt.slots = make(chan struct{}, rt.MaxConcurrentRequests)
+	})
+	release = func() { <-rt.slots }
+
+	select {
+	case rt.slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+
+	rt.waitingRequests.Add(1)
+	defer rt.waitingRequests.Add(-1)
+	select {
+	case rt.slots <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
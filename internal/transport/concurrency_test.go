@@ -0,0 +1,91 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningRoundTripper_WithMaxConcurrentRequests(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int64
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			highest := maxInFlight.Load()
+			if n <= highest || maxInFlight.CompareAndSwap(highest, n) {
+				break
+			}
+		}
+		<-unblock
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil, WithMaxConcurrentRequests(2))
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequest("POST", server.URL, strings.NewReader("{}"))
+			if !assert.NoError(t, err) {
+				return
+			}
+			resp, err := rt.RoundTrip(req)
+			if assert.NoError(t, err) {
+				resp.Body.Close()
+			}
+		}()
+	}
+
+	require.Eventually(t, func() bool { return inFlight.Load() == 2 && rt.WaitingRequests() == 3 }, time.Second, time.Millisecond,
+		"two requests should reach the server while three wait for a slot")
+	close(unblock)
+	wg.Wait()
+
+	assert.Equal(t, int64(2), maxInFlight.Load())
+	assert.Equal(t, 0, rt.WaitingRequests())
+}
+
+func TestSigningRoundTripper_MaxConcurrentRequestsCancelled(t *testing.T) {
+	unblock := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	defer close(unblock)
+
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil, WithMaxConcurrentRequests(1))
+
+	// Hold the only slot
+	go func() {
+		req, _ := http.NewRequest("POST", server.URL, strings.NewReader("first"))
+		if resp, err := rt.RoundTrip(req); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	require.Eventually(t, func() bool { return rt.ActiveRequests() == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "POST", server.URL, strings.NewReader("second"))
+	require.NoError(t, err)
+
+	_, err = rt.RoundTrip(req)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "RoundTrip() error = %v, want the context's error", err)
+	assert.Equal(t, 0, rt.WaitingRequests())
+}
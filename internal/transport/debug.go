@@ -0,0 +1,39 @@
+package transport
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+)
+
+// dumpRequest logs the signed request, including its body, with sensitive
+// headers redacted. The request itself is not modified.
+func (rt *SigningRoundTripper) dumpRequest(logger *slog.Logger, req *http.Request, body []byte) {
+	clone := req.Clone(req.Context())
+	clone.Header = rt.redactHeaders(req.Header)
+	clone.Body = nil
+	if body != nil {
+		clone.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	dump, err := httputil.DumpRequestOut(clone, true)
+	if err != nil {
+		logger.Debug("failed to dump signed request", "error", err)
+		return
+	}
+	logger.Debug("signed request dump", "request", string(dump))
+}
+
+// dumpResponse logs the target's response. The body is buffered and restored
+// so the caller can still read it; event streams and binary responses are
+// dumped without their body.
+func (rt *SigningRoundTripper) dumpResponse(logger *slog.Logger, resp *http.Response) {
+	dump, err := httputil.DumpResponse(resp, !isEventStream(resp) && !rt.isBinaryResponse(resp))
+	if err != nil {
+		logger.Debug("failed to dump response", "error", err)
+		return
+	}
+	logger.Debug("response dump", "response", string(dump))
+}
@@ -0,0 +1,72 @@
+package transport
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningRoundTripper_DebugMode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"echo":` + string(body) + `}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		debugMode bool
+		level     slog.Level
+		wantDump  bool
+	}{
+		{name: "debug mode", debugMode: true, level: slog.LevelDebug, wantDump: true},
+		{name: "debug mode with info logging", debugMode: true, level: slog.LevelInfo},
+		{name: "disabled", level: slog.LevelDebug},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: tt.level}))
+			opts := []Option{WithLogger(logger)}
+			if tt.debugMode {
+				opts = append(opts, WithDebugMode())
+			}
+			rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil, opts...)
+
+			req, err := http.NewRequest("POST", server.URL, strings.NewReader(`"ping"`))
+			require.NoError(t, err)
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			// The dump must not consume the response body
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, `{"echo":"ping"}`, string(body))
+
+			output := logs.String()
+			assert.NotContains(t, output, "Credential=test")
+			// Production builds compile the dumps out
+			if !tt.wantDump || !DebugAvailable {
+				assert.NotContains(t, output, "signed request dump")
+				assert.NotContains(t, output, "response dump")
+				return
+			}
+			assert.Contains(t, output, "signed request dump")
+			assert.Contains(t, output, `Authorization: [REDACTED]`)
+			assert.Contains(t, output, `\"ping\"`)
+			assert.Contains(t, output, "response dump")
+			assert.Contains(t, output, `{\"echo\":\"ping\"}`)
+		})
+	}
+}
@@ -0,0 +1,7 @@
+//go:build production
+
+package transport
+
+// DebugAvailable reports whether request and response dumping is compiled in.
+// Builds with the production tag leave it out entirely.
+const DebugAvailable = false
@@ -0,0 +1,114 @@
+package transport
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+)
+
+// dialFunc dials a network address, matching net.Dialer.DialContext
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// dnsCacheEntry holds the resolved addresses of a host
+type dnsCacheEntry struct {
+	addrs     []string
+	expiresAt time.Time
+}
+
+// dnsCache caches host lookups for a fixed TTL so new connections to the
+// target skip DNS resolution. net.Resolver cannot be extended with a cache, so
+// the cache wraps the dialer instead and dials the resolved addresses directly.
+type dnsCache struct {
+	ttl      time.Duration
+	resolver *net.Resolver
+	logger   *slog.Logger
+	entries  sync.Map // hostname -> dnsCacheEntry
+
+	// now returns the current time (replaced in tests)
+	now func() time.Time
+}
+
+// newDNSCache creates a cache that resolves hosts with the default resolver
+func newDNSCache(ttl time.Duration, logger *slog.Logger) *dnsCache {
+	return &dnsCache{ttl: ttl, resolver: net.DefaultResolver, logger: logger, now: time.Now}
+}
+
+// lookup returns the addresses of host, resolving it when there is no cached
+// entry or the entry has expired
+func (c *dnsCache) lookup(ctx context.Context, host string) ([]string, error) {
+	if value, ok := c.entries.Load(host); ok {
+		if entry := value.(dnsCacheEntry); c.now().Before(entry.expiresAt) {
+			return entry.addrs, nil
+		}
+	}
+
+	if c.logger != nil {
+		c.logger.Debug("DNS cache miss", "host", host, "ttl", c.ttl)
+	}
+	addrs, err := c.resolver.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	c.entries.Store(host, dnsCacheEntry{addrs: addrs, expiresAt: c.now().Add(c.ttl)})
+	return addrs, nil
+}
+
+// len returns the number of unexpired entries
+func (c *dnsCache) len() int {
+	now := c.now()
+	count := 0
+	c.entries.Range(func(_, value any) bool {
+		if now.Before(value.(dnsCacheEntry).expiresAt) {
+			count++
+		}
+		return true
+	})
+	return count
+}
+
+// dialContext returns a dial function that resolves host names through the
+// cache and dials each resolved address with dial until one succeeds
+func (c *dnsCache) dialContext(dial dialFunc) dialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || net.ParseIP(host) != nil {
+			return dial(ctx, network, addr)
+		}
+
+		addrs, err := c.lookup(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		var lastErr error
+		for _, ip := range addrs {
+			conn, err := dial(ctx, network, net.JoinHostPort(ip, port))
+			if err == nil {
+				return conn, nil
+			}
+			lastErr = err
+		}
+		return nil, lastErr
+	}
+}
+
+// resolverCache returns the transport's DNS cache, creating it on first use so
+// cached entries survive reconnects
+func (t *SigningTransport) resolverCache() *dnsCache {
+	if cache := t.dnsCache.Load(); cache != nil {
+		return cache
+	}
+	t.dnsCache.CompareAndSwap(nil, newDNSCache(t.DNSCacheTTL, t.Logger))
+	return t.dnsCache.Load()
+}
+
+// DNSCacheEntries returns the number of unexpired hosts in the DNS cache, or 0
+// when DNSCacheTTL is not set
+func (t *SigningTransport) DNSCacheEntries() int {
+	if cache := t.dnsCache.Load(); cache != nil {
+		return cache.len()
+	}
+	return 0
+}
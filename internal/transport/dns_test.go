@@ -0,0 +1,68 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDNSCache_Lookup(t *testing.T) {
+	cache := newDNSCache(time.Minute, nil)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	addrs, err := cache.lookup(context.Background(), "localhost")
+	require.NoError(t, err)
+	require.NotEmpty(t, addrs)
+	assert.Equal(t, 1, cache.len())
+
+	// A hit within the TTL returns the cached addresses without resolving
+	cache.entries.Store("localhost", dnsCacheEntry{addrs: []string{"192.0.2.1"}, expiresAt: now.Add(time.Minute)})
+	addrs, err = cache.lookup(context.Background(), "localhost")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.0.2.1"}, addrs)
+
+	// Expired entries are resolved again
+	now = now.Add(2 * time.Minute)
+	assert.Zero(t, cache.len())
+	addrs, err = cache.lookup(context.Background(), "localhost")
+	require.NoError(t, err)
+	assert.NotContains(t, addrs, "192.0.2.1")
+	assert.Equal(t, 1, cache.len())
+}
+
+func TestSigningTransport_DNSCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	targetURL := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+
+	transport := &SigningTransport{
+		HTTPClient:  &http.Client{},
+		DNSCacheTTL: time.Minute,
+	}
+	assert.Zero(t, transport.DNSCacheEntries())
+
+	client, err := transport.UnsignedClient()
+	require.NoError(t, err)
+	resp, err := client.Get(targetURL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 1, transport.DNSCacheEntries())
+
+	// The cache is shared by later connections
+	client, err = transport.UnsignedClient()
+	require.NoError(t, err)
+	client.Transport.(*http.Transport).DisableKeepAlives = true
+	resp, err = client.Get(targetURL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 1, transport.DNSCacheEntries())
+}
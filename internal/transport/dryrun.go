@@ -0,0 +1,55 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+// DryRunResult is the JSON body of the synthetic response returned in dry-run
+// mode. Headers are redacted with RedactedHeaders.
+type DryRunResult struct {
+	DryRun  bool        `json:"dry_run"`
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers"`
+}
+
+// dryRunResponse logs a signed request and answers it with a synthetic 200 OK
+// response instead of sending it to the target
+func (rt *SigningRoundTripper) dryRunResponse(req *http.Request, logger *slog.Logger) (*http.Response, error) {
+	headers := rt.redactHeaders(req.Header)
+	algorithm, accessKeyID := parseAuthorization(req.Header.Get("Authorization"))
+	logger.Info("dry run: request signed but not sent",
+		"method", req.Method,
+		"url", req.URL.Redacted(),
+		"signature_algorithm", algorithm,
+		"access_key", maskAccessKeyID(accessKeyID),
+		"headers", headers,
+	)
+
+	body, err := json.Marshal(DryRunResult{
+		DryRun:  true,
+		Method:  req.Method,
+		URL:     req.URL.Redacted(),
+		Headers: headers,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode dry run response: %w", err)
+	}
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
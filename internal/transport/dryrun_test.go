@@ -0,0 +1,55 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingRoundTripper fails the test if a request reaches it
+type failingRoundTripper struct {
+	t *testing.T
+}
+
+func (f failingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.t.Errorf("unexpected request to %s in dry-run mode", req.URL)
+	return nil, http.ErrNotSupported
+}
+
+func TestSigningRoundTripper_DryRun(t *testing.T) {
+	var logs bytes.Buffer
+	signer := &testutil.FakeSigner{}
+	rt := NewSigningRoundTripper(failingRoundTripper{t}, signer, nil,
+		WithDryRun(), WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+
+	req, err := http.NewRequest("POST", "https://example.com/mcp", strings.NewReader(`{"jsonrpc":"2.0"}`))
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.Len(t, signer.Requests(), 1)
+
+	var result DryRunResult
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.True(t, result.DryRun)
+	assert.Equal(t, "POST", result.Method)
+	assert.Equal(t, "https://example.com/mcp", result.URL)
+	assert.Equal(t, "[REDACTED]", result.Headers.Get("Authorization"))
+	assert.NotEmpty(t, result.Headers.Get("X-Amz-Date"))
+
+	output := logs.String()
+	assert.Contains(t, output, "level=INFO")
+	assert.Contains(t, output, "dry run: request signed but not sent")
+	assert.Contains(t, output, "signature_algorithm=AWS4-HMAC-SHA256")
+	assert.NotContains(t, output, "Credential=test")
+}
@@ -0,0 +1,104 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/proxyerr"
+)
+
+// failover retries a failed request against each fallback URL in order.
+// The primary attempt's result is passed in; if it succeeded it is returned
+// unchanged. Each fallback request is re-signed because the host and path are
+// part of the SigV4 canonical request.
+func (rt *SigningRoundTripper) failover(
+	send func(*http.Request) (*http.Response, error),
+	req *http.Request,
+	body []byte,
+	resp *http.Response,
+	err error,
+	logger *slog.Logger,
+) (*http.Response, error) {
+	if !rt.shouldFailover(resp, err) || req.Context().Err() != nil {
+		return resp, err
+	}
+	if err == nil {
+		err = newStatusError(resp)
+		resp.Body.Close()
+	}
+
+	attempts := []string{fmt.Sprintf("%s (%v)", req.URL.Redacted(), err)}
+	for _, fallback := range rt.FallbackURLs {
+		target, parseErr := url.Parse(fallback)
+		if parseErr != nil {
+			attempts = append(attempts, fmt.Sprintf("%s (invalid URL: %v)", fallback, parseErr))
+			continue
+		}
+
+		logger.Warn("target request failed, trying fallback", "failed_url", req.URL.Redacted(), "fallback_url", target.Redacted())
+
+		attemptReq, cancel := rt.fallbackRequest(req, target, body)
+		resp, err := send(attemptReq)
+		if err == nil && !rt.shouldFailover(resp, nil) {
+			logger.Info("fallback target succeeded", "url", target.Redacted())
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+		if err != nil && !rt.retriable(err) {
+			cancel()
+			return nil, err
+		}
+
+		if err == nil {
+			err = newStatusError(resp)
+			resp.Body.Close()
+		}
+		cancel()
+		attempts = append(attempts, fmt.Sprintf("%s (%v)", target.Redacted(), err))
+	}
+
+	return nil, proxyerr.New(proxyerr.ConnectionFailed, "all target URLs failed: %s", strings.Join(attempts, "; "))
+}
+
+// fallbackRequest clones req for the fallback target, restoring the buffered body
+// and applying FailoverTimeout. The returned cancel function must be called once
+// the response is no longer needed.
+func (rt *SigningRoundTripper) fallbackRequest(req *http.Request, target *url.URL, body []byte) (*http.Request, context.CancelFunc) {
+	ctx, cancel := req.Context(), context.CancelFunc(func() {})
+	if rt.FailoverTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, rt.FailoverTimeout)
+	}
+
+	clone := req.Clone(ctx)
+	clone.URL = &url.URL{
+		Scheme:   target.Scheme,
+		User:     target.User,
+		Host:     target.Host,
+		Path:     target.Path,
+		RawPath:  target.RawPath,
+		RawQuery: req.URL.RawQuery,
+	}
+	clone.Host = ""
+	if body != nil {
+		clone.Body = io.NopCloser(bytes.NewReader(body))
+		clone.ContentLength = int64(len(body))
+	}
+
+	return clone, cancel
+}
+
+// shouldFailover reports whether an attempt failed with a retriable error or
+// response status. Signing failures are not retried since every target would
+// fail the same way.
+func (rt *SigningRoundTripper) shouldFailover(resp *http.Response, err error) bool {
+	if err != nil {
+		return rt.retriable(err)
+	}
+	return resp.StatusCode >= http.StatusBadRequest && rt.retriable(newStatusError(resp))
+}
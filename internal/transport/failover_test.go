@@ -0,0 +1,127 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningRoundTripper_Failover(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	var fallbackBody, fallbackHost string
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		fallbackBody = string(body)
+		fallbackHost = r.Host
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+
+	tests := []struct {
+		name       string
+		primary    string
+		fallbacks  []string
+		wantStatus int
+		wantErr    []string
+	}{
+		{
+			name:       "primary 5xx fails over",
+			primary:    failing.URL + "/mcp",
+			fallbacks:  []string{healthy.URL + "/mcp"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "primary unreachable fails over",
+			primary:    "http://localhost:59999/mcp",
+			fallbacks:  []string{failing.URL + "/mcp", healthy.URL + "/mcp"},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:      "all targets fail",
+			primary:   "http://localhost:59999/mcp",
+			fallbacks: []string{failing.URL + "/mcp"},
+			wantErr:   []string{"all target URLs failed", "localhost:59999", failing.URL, "503 Service Unavailable"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fallbackBody, fallbackHost = "", ""
+			signer := &testutil.FakeSigner{}
+			rt := NewSigningRoundTripper(http.DefaultTransport, signer, nil, WithFailover(tt.fallbacks, time.Second))
+
+			req, err := http.NewRequest("POST", tt.primary, strings.NewReader(`{"jsonrpc":"2.0"}`))
+			require.NoError(t, err)
+
+			resp, err := rt.RoundTrip(req)
+			if len(tt.wantErr) > 0 {
+				require.Error(t, err)
+				assert.Nil(t, resp)
+				for _, want := range tt.wantErr {
+					assert.Contains(t, err.Error(), want)
+				}
+				return
+			}
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, `{"jsonrpc":"2.0"}`, fallbackBody)
+			assert.Equal(t, strings.TrimPrefix(healthy.URL, "http://"), fallbackHost)
+
+			// Every attempt is signed for its own host
+			lastSigned := signer.Requests()[len(signer.Requests())-1]
+			assert.Equal(t, fallbackHost, lastSigned.URL.Host)
+		})
+	}
+}
+
+func TestSigningRoundTripper_FailoverSkipsSigningErrors(t *testing.T) {
+	calls := 0
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{Err: assert.AnError}, nil,
+		WithFailover([]string{healthy.URL}, 0))
+
+	req, err := http.NewRequest("POST", "http://localhost:59999", strings.NewReader("test"))
+	require.NoError(t, err)
+
+	resp, err := rt.RoundTrip(req)
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Contains(t, err.Error(), "AWS signature generation failed")
+	assert.Equal(t, 0, calls)
+}
+
+func TestSigningRoundTripper_NoFailoverOnClientError(t *testing.T) {
+	forbidden := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer forbidden.Close()
+
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil,
+		WithFailover([]string{"http://localhost:59999"}, 0))
+
+	req, err := http.NewRequest("POST", forbidden.URL, strings.NewReader("test"))
+	require.NoError(t, err)
+
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
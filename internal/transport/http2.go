@@ -0,0 +1,110 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/proxyerr"
+	"golang.org/x/net/http2"
+)
+
+// DefaultHTTP2KeepAlivePingInterval is how long an HTTP/2 connection may go
+// without receiving a frame before a PING is sent, when
+// HTTP2KeepAlivePingInterval is not set
+const DefaultHTTP2KeepAlivePingInterval = 30 * time.Second
+
+// DefaultHTTP2KeepAlivePingTimeout is how long to wait for a PING to be
+// answered before the connection is closed, when HTTP2KeepAlivePingTimeout is
+// not set
+const DefaultHTTP2KeepAlivePingTimeout = 15 * time.Second
+
+// http2KeepAlive returns the PING interval and timeout of HTTP/2 connections.
+// The interval is 0 when keep-alive pings are disabled.
+func (t *SigningTransport) http2KeepAlive() (interval, timeout time.Duration) {
+	interval, timeout = t.HTTP2KeepAlivePingInterval, t.HTTP2KeepAlivePingTimeout
+	if interval == 0 {
+		interval = DefaultHTTP2KeepAlivePingInterval
+	}
+	if interval < 0 {
+		return 0, 0
+	}
+	if timeout <= 0 {
+		timeout = DefaultHTTP2KeepAlivePingTimeout
+	}
+	return interval, timeout
+}
+
+// applyHTTP2KeepAlive makes HTTP/2 connections of httpTransport send a PING
+// frame after the keep-alive interval without receiving a frame, and close
+// when the PING is not answered within the keep-alive timeout, so the next
+// request dials a new connection instead of waiting on a dead one. HTTP/1.1
+// connections are not affected.
+func (t *SigningTransport) applyHTTP2KeepAlive(httpTransport *http.Transport) {
+	interval, timeout := t.http2KeepAlive()
+	if interval == 0 {
+		return
+	}
+	config := &http.HTTP2Config{}
+	if httpTransport.HTTP2 != nil {
+		*config = *httpTransport.HTTP2
+	}
+	config.SendPingTimeout = interval
+	config.PingTimeout = timeout
+	httpTransport.HTTP2 = config
+}
+
+// configureHTTP2 enables HTTP/2 negotiated with ALPN on httpTransport for
+// HTTPS targets. The TLS configuration is cloned first because ALPN protocols
+// are added to it.
+func configureHTTP2(httpTransport *http.Transport) error {
+	if httpTransport.TLSClientConfig != nil {
+		httpTransport.TLSClientConfig = httpTransport.TLSClientConfig.Clone()
+	}
+	if err := http2.ConfigureTransport(httpTransport); err != nil {
+		return proxyerr.Wrap(proxyerr.InvalidConfig, err, "failed to enable HTTP/2")
+	}
+	return nil
+}
+
+// h2cRoundTripper sends plain http:// requests with HTTP/2 over cleartext
+// (h2c) and all other requests with Transport
+type h2cRoundTripper struct {
+	// Transport sends https:// requests
+	Transport http.RoundTripper
+
+	// h2c sends http:// requests using prior knowledge of HTTP/2 support
+	h2c *http2.Transport
+}
+
+// newH2CRoundTripper creates a round tripper that speaks h2c to http://
+// targets, dialing connections with dial (or a default dialer when nil).
+// Outbound proxies are not used for h2c connections. h2c connections are
+// checked with a PING after pingInterval without a frame and closed when it
+// is not answered within pingTimeout (a zero pingInterval disables the check).
+func newH2CRoundTripper(transport http.RoundTripper, dial dialFunc, pingInterval, pingTimeout time.Duration) *h2cRoundTripper {
+	if dial == nil {
+		dial = (&net.Dialer{KeepAlive: defaultKeepAlive}).DialContext
+	}
+	return &h2cRoundTripper{
+		Transport: transport,
+		h2c: &http2.Transport{
+			AllowHTTP:       true,
+			ReadIdleTimeout: pingInterval,
+			PingTimeout:     pingTimeout,
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				return dial(ctx, network, addr)
+			},
+		},
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (rt *h2cRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme == "http" {
+		return rt.h2c.RoundTrip(req)
+	}
+	return rt.Transport.RoundTrip(req)
+}
@@ -0,0 +1,126 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// protoRecorder records the protocol and Authorization header of each request
+type protoRecorder struct {
+	proto         string
+	authorization string
+}
+
+func (p *protoRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.proto = r.Proto
+	p.authorization = r.Header.Get("Authorization")
+	w.WriteHeader(http.StatusOK)
+}
+
+// sendSigned sends a signed POST to url through transport's base round tripper
+func sendSigned(t *testing.T, transport *SigningTransport, url string) {
+	t.Helper()
+	base, err := transport.baseTransport()
+	require.NoError(t, err)
+
+	req, err := http.NewRequest("POST", url, strings.NewReader(`{"jsonrpc":"2.0"}`))
+	require.NoError(t, err)
+	resp, err := NewSigningRoundTripper(base, transport.Signer, nil).RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+}
+
+func TestSigningTransport_ForceHTTP2(t *testing.T) {
+	recorder := &protoRecorder{}
+	server := httptest.NewUnstartedServer(recorder)
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	tlsConfig := &tls.Config{RootCAs: pool}
+
+	transport := &SigningTransport{
+		Signer:     &testutil.FakeSigner{},
+		HTTPClient: &http.Client{},
+		TLSConfig:  tlsConfig,
+		ForceHTTP2: true,
+	}
+	sendSigned(t, transport, server.URL)
+
+	assert.Equal(t, "HTTP/2.0", recorder.proto)
+	assert.NotEmpty(t, recorder.authorization, "the signature is sent as a header over HTTP/2")
+	assert.Empty(t, tlsConfig.NextProtos, "the caller's TLS configuration must not be modified")
+}
+
+func TestSigningTransport_AllowH2C(t *testing.T) {
+	recorder := &protoRecorder{}
+	server := httptest.NewServer(h2c.NewHandler(recorder, &http2.Server{}))
+	defer server.Close()
+
+	transport := &SigningTransport{
+		Signer:     &testutil.FakeSigner{},
+		HTTPClient: &http.Client{},
+	}
+	sendSigned(t, transport, server.URL)
+	assert.Equal(t, "HTTP/1.1", recorder.proto)
+
+	transport.AllowH2C = true
+	sendSigned(t, transport, server.URL)
+	assert.Equal(t, "HTTP/2.0", recorder.proto)
+	assert.NotEmpty(t, recorder.authorization)
+}
+
+func TestSigningTransport_HTTP2KeepAlive(t *testing.T) {
+	tests := []struct {
+		name         string
+		interval     time.Duration
+		timeout      time.Duration
+		wantInterval time.Duration
+		wantTimeout  time.Duration
+	}{
+		{"defaults", 0, 0, DefaultHTTP2KeepAlivePingInterval, DefaultHTTP2KeepAlivePingTimeout},
+		{"custom", 10 * time.Second, 2 * time.Second, 10 * time.Second, 2 * time.Second},
+		{"disabled", -1, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &SigningTransport{
+				Signer:                     &testutil.FakeSigner{},
+				HTTPClient:                 &http.Client{},
+				ForceHTTP2:                 true,
+				AllowH2C:                   true,
+				HTTP2KeepAlivePingInterval: tt.interval,
+				HTTP2KeepAlivePingTimeout:  tt.timeout,
+			}
+			base, err := transport.baseTransport()
+			require.NoError(t, err)
+			rt, ok := base.(*h2cRoundTripper)
+			require.True(t, ok, "AllowH2C should wrap the transport, got %T", base)
+
+			assert.Equal(t, tt.wantInterval, rt.h2c.ReadIdleTimeout)
+			assert.Equal(t, tt.wantTimeout, rt.h2c.PingTimeout)
+
+			httpTransport := rt.Transport.(*http.Transport)
+			if tt.wantInterval == 0 {
+				assert.True(t, httpTransport.HTTP2 == nil || httpTransport.HTTP2.SendPingTimeout == 0)
+				return
+			}
+			require.NotNil(t, httpTransport.HTTP2)
+			assert.Equal(t, tt.wantInterval, httpTransport.HTTP2.SendPingTimeout)
+			assert.Equal(t, tt.wantTimeout, httpTransport.HTTP2.PingTimeout)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package transport
+
+import (
+	"net"
+	"time"
+)
+
+const (
+	// DefaultSSETCPKeepAlive is the TCP keep-alive idle time used when the
+	// standalone SSE stream is enabled and TCPKeepAlive is not set
+	DefaultSSETCPKeepAlive = 30 * time.Second
+
+	// dialTimeout matches the connect timeout of http.DefaultTransport
+	dialTimeout = 30 * time.Second
+)
+
+// keepAliveDialer returns a dialer applying the transport's TCP keep-alive
+// settings, or nil if none apply. Unset probe settings keep the OS defaults and
+// a negative TCPKeepAlive disables keep-alive probes.
+func (t *SigningTransport) keepAliveDialer(enableSSE bool) *net.Dialer {
+	idle := t.TCPKeepAlive
+	if idle == 0 && enableSSE {
+		idle = DefaultSSETCPKeepAlive
+	}
+	if idle == 0 && t.TCPKeepAliveInterval == 0 && t.TCPKeepAliveCount == 0 {
+		return nil
+	}
+	if idle < 0 {
+		return &net.Dialer{Timeout: dialTimeout, KeepAlive: -1}
+	}
+
+	config := net.KeepAliveConfig{Enable: true, Idle: -1, Interval: -1, Count: -1}
+	if idle > 0 {
+		config.Idle = idle
+	}
+	if t.TCPKeepAliveInterval > 0 {
+		config.Interval = t.TCPKeepAliveInterval
+	}
+	if t.TCPKeepAliveCount > 0 {
+		config.Count = t.TCPKeepAliveCount
+	}
+	return &net.Dialer{Timeout: dialTimeout, KeepAliveConfig: config}
+}
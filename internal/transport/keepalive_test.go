@@ -0,0 +1,73 @@
+package transport
+
+import (
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningTransport_KeepAliveDialer(t *testing.T) {
+	tests := []struct {
+		name      string
+		transport *SigningTransport
+		enableSSE bool
+		want      *net.Dialer
+	}{
+		{
+			name:      "unset without SSE",
+			transport: &SigningTransport{},
+			want:      nil,
+		},
+		{
+			name:      "SSE default",
+			transport: &SigningTransport{},
+			enableSSE: true,
+			want: &net.Dialer{Timeout: dialTimeout, KeepAliveConfig: net.KeepAliveConfig{
+				Enable: true, Idle: DefaultSSETCPKeepAlive, Interval: -1, Count: -1,
+			}},
+		},
+		{
+			name: "all probe settings",
+			transport: &SigningTransport{
+				TCPKeepAlive:         time.Minute,
+				TCPKeepAliveInterval: 10 * time.Second,
+				TCPKeepAliveCount:    3,
+			},
+			want: &net.Dialer{Timeout: dialTimeout, KeepAliveConfig: net.KeepAliveConfig{
+				Enable: true, Idle: time.Minute, Interval: 10 * time.Second, Count: 3,
+			}},
+		},
+		{
+			name:      "disabled",
+			transport: &SigningTransport{TCPKeepAlive: -1},
+			enableSSE: true,
+			want:      &net.Dialer{Timeout: dialTimeout, KeepAlive: -1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.transport.keepAliveDialer(tt.enableSSE))
+		})
+	}
+}
+
+func TestSigningTransport_BaseTransportKeepAlive(t *testing.T) {
+	original := &http.Transport{}
+	transport := &SigningTransport{
+		HTTPClient:   &http.Client{Transport: original},
+		TCPKeepAlive: time.Minute,
+	}
+
+	base, err := transport.baseTransport()
+	require.NoError(t, err)
+	httpTransport, ok := base.(*http.Transport)
+	require.True(t, ok)
+	assert.NotSame(t, original, httpTransport, "the client's transport must not be modified")
+	assert.NotNil(t, httpTransport.DialContext)
+	assert.Nil(t, original.DialContext)
+}
@@ -0,0 +1,146 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	// DefaultMaxBodyBytes is the default limit on request and response body sizes
+	DefaultMaxBodyBytes int64 = 64 << 20
+
+	// DefaultMaxSSEEventBytes is the default limit on the size of a single SSE event
+	DefaultMaxSSEEventBytes int64 = 10 << 20
+
+	// DefaultMaxSSETotalBytes is the default number of bytes read from an SSE
+	// stream before it is ended for reconnection
+	DefaultMaxSSETotalBytes int64 = 10 << 20
+)
+
+var (
+	// ErrRequestBodyTooLarge is returned when a request body exceeds MaxRequestBodyBytes
+	ErrRequestBodyTooLarge = errors.New("request body too large")
+
+	// ErrResponseBodyTooLarge is returned when reading a response body past MaxResponseBodyBytes
+	ErrResponseBodyTooLarge = errors.New("response body too large")
+
+	// ErrSSEEventTooLarge is returned when an SSE event exceeds MaxSSEEventBytes
+	ErrSSEEventTooLarge = errors.New("SSE event too large")
+)
+
+// readLimited reads all of r, failing with ErrRequestBodyTooLarge if it holds
+// more than limit bytes. A non-positive limit reads without a limit.
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return io.ReadAll(r)
+	}
+	body, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("%w: exceeds %d bytes", ErrRequestBodyTooLarge, limit)
+	}
+	return body, nil
+}
+
+// limitResponse caps the response body at limit bytes. Event streams are not
+// limited, since a long-lived stream legitimately carries unbounded data.
+func limitResponse(resp *http.Response, limit int64) {
+	if limit <= 0 || isEventStream(resp) {
+		return
+	}
+	resp.Body = &limitedBody{ReadCloser: resp.Body, remaining: limit, limit: limit, tooLarge: ErrResponseBodyTooLarge}
+}
+
+// limitedBody fails reads with tooLarge once more than limit bytes are read
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	limit     int64
+	tooLarge  error
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, fmt.Errorf("%w: exceeds %d bytes", b.tooLarge, b.limit)
+	}
+	// Allow one byte past the limit so an exact-size body still reaches EOF
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n + int(b.remaining), fmt.Errorf("%w: exceeds %d bytes", b.tooLarge, b.limit)
+	}
+	return n, err
+}
+
+// limitEventStream bounds the memory an SSE response can make the reader
+// buffer. An event larger than maxEventBytes closes the stream and fails with
+// ErrSSEEventTooLarge. Once maxTotalBytes have been read, the stream ends at
+// the next event boundary, so the client reconnects with a fresh budget and
+// resumes after the last complete event. A non-positive limit is not applied.
+func limitEventStream(resp *http.Response, maxEventBytes, maxTotalBytes int64, logger *slog.Logger) {
+	if (maxEventBytes <= 0 && maxTotalBytes <= 0) || !isEventStream(resp) {
+		return
+	}
+	resp.Body = &limitedEventStream{ReadCloser: resp.Body, maxEvent: maxEventBytes, maxTotal: maxTotalBytes, logger: logger}
+}
+
+// limitedEventStream counts the bytes of each event, delimited by a blank
+// line, and of the whole stream
+type limitedEventStream struct {
+	io.ReadCloser
+	maxEvent int64
+	maxTotal int64
+	logger   *slog.Logger
+
+	event   int64
+	total   int64
+	newline bool
+	err     error
+}
+
+func (s *limitedEventStream) Read(p []byte) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	n, err := s.ReadCloser.Read(p)
+	for i, b := range p[:n] {
+		s.event++
+		s.total++
+		if s.maxEvent > 0 && s.event > s.maxEvent {
+			s.ReadCloser.Close()
+			s.err = fmt.Errorf("%w: exceeds %d bytes", ErrSSEEventTooLarge, s.maxEvent)
+			if i == 0 {
+				return 0, s.err
+			}
+			return i, nil
+		}
+
+		switch b {
+		case '\n':
+			if !s.newline {
+				s.newline = true
+				continue
+			}
+			// A blank line ends the event
+			s.event, s.newline = 0, false
+			if s.maxTotal > 0 && s.total >= s.maxTotal {
+				s.logger.Info("SSE stream reached its byte limit, ending it for reconnection", "bytes", s.total)
+				s.ReadCloser.Close()
+				s.err = io.EOF
+				return i + 1, nil
+			}
+		case '\r':
+		default:
+			s.newline = false
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,146 @@
+package transport
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningRoundTripper_RequestBodyLimit(t *testing.T) {
+	var received int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	signer := &testutil.FakeSigner{}
+	rt := NewSigningRoundTripper(http.DefaultTransport, signer, nil, WithBodyLimits(8, 0))
+
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{name: "within limit", body: "12345678", wantErr: false},
+		{name: "over limit", body: "123456789", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("POST", server.URL, strings.NewReader(tt.body))
+			require.NoError(t, err)
+			resp, err := rt.RoundTrip(req)
+			if !tt.wantErr {
+				require.NoError(t, err)
+				resp.Body.Close()
+				return
+			}
+			assert.Nil(t, resp)
+			assert.True(t, errors.Is(err, ErrRequestBodyTooLarge), "expected ErrRequestBodyTooLarge, got %v", err)
+		})
+	}
+
+	// The oversized request is rejected before signing
+	assert.Len(t, signer.Requests(), 1)
+	assert.Equal(t, 1, received)
+}
+
+func TestSigningRoundTripper_ResponseBodyLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", r.Header.Get("X-Content-Type"))
+		io.WriteString(w, strings.Repeat("x", 16))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		limit       int64
+		contentType string
+		wantErr     bool
+	}{
+		{name: "over limit", limit: 8, contentType: "application/json", wantErr: true},
+		{name: "exact limit", limit: 16, contentType: "application/json", wantErr: false},
+		{name: "disabled", limit: -1, contentType: "application/json", wantErr: false},
+		{name: "event streams are not limited", limit: 8, contentType: "text/event-stream", wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil, WithBodyLimits(0, tt.limit))
+			req, err := http.NewRequest("POST", server.URL, nil)
+			require.NoError(t, err)
+			req.Header.Set("X-Content-Type", tt.contentType)
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrResponseBodyTooLarge), "expected ErrResponseBodyTooLarge, got %v", err)
+				assert.Len(t, body, int(tt.limit))
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, body, 16)
+		})
+	}
+}
+
+func TestSigningRoundTripper_SSELimits(t *testing.T) {
+	events := "id: 1\ndata: small\n\nid: 2\ndata: " + strings.Repeat("x", 32) + "\n\nid: 3\ndata: after\n\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", r.Header.Get("X-Content-Type"))
+		io.WriteString(w, events)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		maxEvent    int64
+		maxTotal    int64
+		contentType string
+		wantBody    string
+		wantErr     error
+	}{
+		{name: "within limits", contentType: "text/event-stream", wantBody: events},
+		{name: "event too large", maxEvent: 24, contentType: "text/event-stream", wantBody: events[:len("id: 1\ndata: small\n\n")+24], wantErr: ErrSSEEventTooLarge},
+		{name: "total ends stream at event boundary", maxTotal: 24, contentType: "text/event-stream", wantBody: events[:strings.Index(events, "id: 3")]},
+		{name: "disabled", maxEvent: -1, maxTotal: -1, contentType: "text/event-stream", wantBody: events},
+		{name: "other responses are not limited", maxEvent: 8, maxTotal: 8, contentType: "application/json", wantBody: events},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil, WithSSELimits(tt.maxEvent, tt.maxTotal))
+			req, err := http.NewRequest("POST", server.URL, nil)
+			require.NoError(t, err)
+			req.Header.Set("X-Content-Type", tt.contentType)
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), "expected %v, got %v", tt.wantErr, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantBody, string(body))
+		})
+	}
+}
+
+func TestWithSSELimits_Defaults(t *testing.T) {
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil)
+	assert.Equal(t, DefaultMaxSSEEventBytes, rt.MaxSSEEventBytes)
+	assert.Equal(t, DefaultMaxSSETotalBytes, rt.MaxSSETotalBytes)
+
+	rt = NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil, WithSSELimits(0, -1))
+	assert.Equal(t, DefaultMaxSSEEventBytes, rt.MaxSSEEventBytes)
+	assert.Zero(t, rt.MaxSSETotalBytes)
+}
@@ -0,0 +1,72 @@
+package transport
+
+import (
+	"time"
+)
+
+// Error kinds reported to MetricsCollector.RecordError
+const (
+	// ErrorKindReadBody indicates the request body could not be read for signing
+	ErrorKindReadBody = "read_body"
+
+	// ErrorKindSigning indicates the signer failed to sign the request
+	ErrorKindSigning = "signing"
+
+	// ErrorKindRateLimit indicates the request was cancelled while waiting for the rate limiter
+	ErrorKindRateLimit = "rate_limit"
+
+	// ErrorKindNetwork indicates the signed request could not reach the target server
+	ErrorKindNetwork = "network"
+)
+
+// MetricsCollector records observations about requests made by SigningRoundTripper.
+// Implementations must be safe for concurrent use.
+type MetricsCollector interface {
+	// RecordRequest records a completed round trip to the target server.
+	// The status is the HTTP status code, or "error" if no response was received.
+	RecordRequest(method, status string, latency time.Duration)
+
+	// RecordSigningLatency records the time spent hashing and signing a request
+	RecordSigningLatency(latency time.Duration)
+
+	// RecordError records a failed round trip by kind (see the ErrorKind constants)
+	RecordError(kind string)
+}
+
+// NoopCollector is a MetricsCollector that discards all observations.
+// It is used by SigningRoundTripper when no collector is configured.
+type NoopCollector struct{}
+
+// RecordRequest implements MetricsCollector
+func (NoopCollector) RecordRequest(string, string, time.Duration) {}
+
+// RecordSigningLatency implements MetricsCollector
+func (NoopCollector) RecordSigningLatency(time.Duration) {}
+
+// RecordError implements MetricsCollector
+func (NoopCollector) RecordError(string) {}
+
+// MultiCollector is a MetricsCollector that records every observation with
+// each of its collectors, such as StatsD and Prometheus together
+type MultiCollector []MetricsCollector
+
+// RecordRequest implements MetricsCollector
+func (m MultiCollector) RecordRequest(method, status string, latency time.Duration) {
+	for _, c := range m {
+		c.RecordRequest(method, status, latency)
+	}
+}
+
+// RecordSigningLatency implements MetricsCollector
+func (m MultiCollector) RecordSigningLatency(latency time.Duration) {
+	for _, c := range m {
+		c.RecordSigningLatency(latency)
+	}
+}
+
+// RecordError implements MetricsCollector
+func (m MultiCollector) RecordError(kind string) {
+	for _, c := range m {
+		c.RecordError(kind)
+	}
+}
@@ -0,0 +1,145 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+	"github.com/prometheus/client_golang/prometheus"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingCollector is a test implementation of MetricsCollector
+type recordingCollector struct {
+	mu             sync.Mutex
+	requests       []string
+	signingLatency []time.Duration
+	errors         []string
+	requestLatency []time.Duration
+}
+
+func (c *recordingCollector) RecordRequest(method, status string, latency time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, method+" "+status)
+	c.requestLatency = append(c.requestLatency, latency)
+}
+
+func (c *recordingCollector) RecordSigningLatency(latency time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.signingLatency = append(c.signingLatency, latency)
+}
+
+func (c *recordingCollector) RecordError(kind string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.errors = append(c.errors, kind)
+}
+
+func TestSigningRoundTripper_WithMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	collector := &recordingCollector{}
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil, WithMetrics(collector))
+
+	req, err := http.NewRequest("POST", server.URL, strings.NewReader(`{"test":"data"}`))
+	require.NoError(t, err)
+
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, []string{"POST 202"}, collector.requests)
+	assert.Len(t, collector.signingLatency, 1)
+	assert.Empty(t, collector.errors)
+}
+
+func TestSigningRoundTripper_MetricsOnError(t *testing.T) {
+	tests := []struct {
+		name       string
+		signer     *testutil.FakeSigner
+		targetURL  string
+		wantErrors []string
+		wantReqs   []string
+	}{
+		{
+			name:       "signing failure",
+			signer:     &testutil.FakeSigner{Err: assert.AnError},
+			targetURL:  "http://localhost:59999",
+			wantErrors: []string{ErrorKindSigning},
+		},
+		{
+			name:       "network failure",
+			signer:     &testutil.FakeSigner{},
+			targetURL:  "http://localhost:59999",
+			wantErrors: []string{ErrorKindNetwork},
+			wantReqs:   []string{"POST error"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collector := &recordingCollector{}
+			rt := NewSigningRoundTripper(http.DefaultTransport, tt.signer, nil, WithMetrics(collector))
+
+			req, err := http.NewRequest("POST", tt.targetURL, strings.NewReader("test"))
+			require.NoError(t, err)
+
+			resp, err := rt.RoundTrip(req)
+			assert.Error(t, err)
+			assert.Nil(t, resp)
+
+			assert.Equal(t, tt.wantErrors, collector.errors)
+			assert.Equal(t, tt.wantReqs, collector.requests)
+		})
+	}
+}
+
+func TestSigningRoundTripper_DefaultNoopCollector(t *testing.T) {
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil)
+	assert.Equal(t, NoopCollector{}, rt.Metrics)
+}
+
+func TestPrometheusCollector(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	collector, err := NewPrometheusCollector(reg)
+	require.NoError(t, err)
+
+	collector.RecordRequest("POST", "200", 10*time.Millisecond)
+	collector.RecordRequest("POST", "200", 20*time.Millisecond)
+	collector.RecordSigningLatency(time.Millisecond)
+	collector.RecordError(ErrorKindNetwork)
+
+	assert.Equal(t, float64(2), promtestutil.ToFloat64(collector.requests.WithLabelValues("POST", "200")))
+	assert.Equal(t, float64(1), promtestutil.ToFloat64(collector.errors.WithLabelValues(ErrorKindNetwork)))
+	assert.Equal(t, 1, promtestutil.CollectAndCount(collector.signingLatency))
+
+	// Registering a second collector on the same registry must fail
+	_, err = NewPrometheusCollector(reg)
+	assert.Error(t, err)
+}
+
+func TestMultiCollector(t *testing.T) {
+	first, second := &recordingCollector{}, &recordingCollector{}
+	collector := MultiCollector{first, second}
+
+	collector.RecordRequest("POST", "200", 10*time.Millisecond)
+	collector.RecordSigningLatency(time.Millisecond)
+	collector.RecordError(ErrorKindSigning)
+
+	for _, c := range []*recordingCollector{first, second} {
+		assert.Equal(t, []string{"POST 200"}, c.requests)
+		assert.Equal(t, []time.Duration{time.Millisecond}, c.signingLatency)
+		assert.Equal(t, []string{ErrorKindSigning}, c.errors)
+	}
+}
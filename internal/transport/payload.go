@@ -0,0 +1,131 @@
+package transport
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/proxyerr"
+)
+
+// UnsignedPayload is the payload hash that leaves the request body out of the signature
+const UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+// PayloadHashProvider computes the SigV4 payload hash of a request body. It
+// returns the hash and a body to send in its place, positioned at the start.
+type PayloadHashProvider func(body io.Reader) (hash string, rewoundBody io.ReadCloser, err error)
+
+// BufferedPayloadHash reads the whole body into memory and hashes it. It is
+// the default provider.
+func BufferedPayloadHash(body io.Reader) (string, io.ReadCloser, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", nil, err
+	}
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:]), io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// StreamingPayloadHash hashes the body incrementally. Bodies that implement
+// io.Seeker, such as files, are hashed without being held in memory and then
+// rewound; other bodies are buffered as they are hashed, since SigV4 needs the
+// hash before any of the body is sent.
+func StreamingPayloadHash(body io.Reader) (string, io.ReadCloser, error) {
+	hasher := sha256.New()
+
+	if seeker, ok := body.(io.ReadSeeker); ok {
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return "", nil, err
+		}
+		if _, err := io.Copy(hasher, seeker); err != nil {
+			return "", nil, err
+		}
+		if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+			return "", nil, err
+		}
+		return hex.EncodeToString(hasher.Sum(nil)), io.NopCloser(seeker), nil
+	}
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, io.TeeReader(body, hasher)); err != nil {
+		return "", nil, err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), io.NopCloser(&buf), nil
+}
+
+// UnsignedPayloadHash skips hashing and sends the body unread, signing with
+// UnsignedPayload. Only use it with internally trusted targets: the signature
+// no longer protects the body from being altered in transit, and the target
+// service must accept unsigned payloads.
+func UnsignedPayloadHash(body io.Reader) (string, io.ReadCloser, error) {
+	return UnsignedPayload, io.NopCloser(body), nil
+}
+
+// hashedBody is the body returned by a PayloadHashProvider. Closing it also
+// closes the original request body, which the provider may have wrapped in
+// io.NopCloser.
+type hashedBody struct {
+	io.ReadCloser
+	original io.Closer
+}
+
+// Close closes both the provided and the original body
+func (b *hashedBody) Close() error {
+	err := b.ReadCloser.Close()
+	if closeErr := b.original.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// buffersBody reports whether the request body must be held in memory,
+// because it is compressed, replayed to fallback or shadow targets or after a
+// credential refresh, or dumped
+func (rt *SigningRoundTripper) buffersBody() bool {
+	return rt.PayloadHashProvider == nil || rt.CompressRequests || len(rt.FallbackURLs) > 0 || rt.Shadow != nil || rt.RefreshOn401 || rt.DebugMode
+}
+
+// hashBody computes the payload hash of req's body with the payload hash
+// provider without buffering it first. MaxRequestBodyBytes is enforced up
+// front when the content length is known and while the body is read otherwise.
+func (rt *SigningRoundTripper) hashBody(req *http.Request) (string, error) {
+	limit := rt.MaxRequestBodyBytes
+	if limit > 0 && req.ContentLength > limit {
+		req.Body.Close()
+		err := fmt.Errorf("%w: exceeds %d bytes", ErrRequestBodyTooLarge, limit)
+		return "", proxyerr.Wrap(proxyerr.SigningFailed, err, "failed to read request body for signing")
+	}
+
+	// Pass the body itself when possible so providers can detect io.Seeker.
+	// A zero content length with a body means the length is unknown.
+	var body io.Reader = req.Body
+	if limit > 0 && req.ContentLength <= 0 {
+		body = &limitedBody{ReadCloser: req.Body, remaining: limit, limit: limit, tooLarge: ErrRequestBodyTooLarge}
+	}
+
+	hash, rewound, err := rt.PayloadHashProvider(body)
+	if err != nil {
+		req.Body.Close()
+		return "", proxyerr.Wrap(proxyerr.SigningFailed, err, "failed to hash request body for signing")
+	}
+	req.Body = &hashedBody{ReadCloser: rewound, original: req.Body}
+	return hash, nil
+}
+
+// hashBufferedBody computes the payload hash of a body already read into
+// memory, using the payload hash provider when one is set
+func (rt *SigningRoundTripper) hashBufferedBody(body []byte) (string, error) {
+	if rt.PayloadHashProvider == nil {
+		hash := sha256.Sum256(body)
+		return hex.EncodeToString(hash[:]), nil
+	}
+	hash, _, err := rt.PayloadHashProvider(bytes.NewReader(body))
+	if err != nil {
+		return "", proxyerr.Wrap(proxyerr.SigningFailed, err, "failed to hash request body for signing")
+	}
+	return hash, nil
+}
@@ -0,0 +1,140 @@
+package transport
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPayload = `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`
+
+func testPayloadHash() string {
+	hash := sha256.Sum256([]byte(testPayload))
+	return hex.EncodeToString(hash[:])
+}
+
+func TestPayloadHashProviders(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider PayloadHashProvider
+		body     func(t *testing.T) io.Reader
+		wantHash string
+	}{
+		{
+			name:     "buffered",
+			provider: BufferedPayloadHash,
+			body:     func(t *testing.T) io.Reader { return strings.NewReader(testPayload) },
+			wantHash: testPayloadHash(),
+		},
+		{
+			name:     "streaming seekable body",
+			provider: StreamingPayloadHash,
+			body: func(t *testing.T) io.Reader {
+				path := filepath.Join(t.TempDir(), "body.json")
+				require.NoError(t, os.WriteFile(path, []byte(testPayload), 0o600))
+				file, err := os.Open(path)
+				require.NoError(t, err)
+				t.Cleanup(func() { file.Close() })
+				return file
+			},
+			wantHash: testPayloadHash(),
+		},
+		{
+			name:     "streaming unseekable body",
+			provider: StreamingPayloadHash,
+			body:     func(t *testing.T) io.Reader { return io.NopCloser(strings.NewReader(testPayload)) },
+			wantHash: testPayloadHash(),
+		},
+		{
+			name:     "unsigned",
+			provider: UnsignedPayloadHash,
+			body:     func(t *testing.T) io.Reader { return strings.NewReader(testPayload) },
+			wantHash: UnsignedPayload,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, rewound, err := tt.provider(tt.body(t))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantHash, hash)
+
+			body, err := io.ReadAll(rewound)
+			require.NoError(t, err)
+			assert.Equal(t, testPayload, string(body), "the returned body must start at the beginning")
+		})
+	}
+}
+
+func TestSigningRoundTripper_PayloadHashProvider(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		wantHash string
+	}{
+		{name: "default", wantHash: testPayloadHash()},
+		{name: "unsigned", opts: []Option{WithPayloadHashProvider(UnsignedPayloadHash)}, wantHash: UnsignedPayload},
+		{name: "streaming", opts: []Option{WithPayloadHashProvider(StreamingPayloadHash)}, wantHash: testPayloadHash()},
+		{
+			name:     "unsigned with a buffered body",
+			opts:     []Option{WithPayloadHashProvider(UnsignedPayloadHash), WithDebugMode()},
+			wantHash: UnsignedPayload,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received = ""
+			signer := &testutil.FakeSigner{}
+			rt := NewSigningRoundTripper(http.DefaultTransport, signer, nil, tt.opts...)
+
+			req, err := http.NewRequest("POST", server.URL, strings.NewReader(testPayload))
+			require.NoError(t, err)
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			assert.Equal(t, []string{tt.wantHash}, signer.PayloadHashes())
+			assert.Equal(t, testPayload, received)
+		})
+	}
+}
+
+func TestSigningRoundTripper_PayloadHashProviderBodyLimit(t *testing.T) {
+	signer := &testutil.FakeSigner{}
+	rt := NewSigningRoundTripper(http.DefaultTransport, signer, nil,
+		WithPayloadHashProvider(StreamingPayloadHash), WithBodyLimits(10, 0))
+
+	req, err := http.NewRequest("POST", "http://example.com", strings.NewReader(testPayload))
+	require.NoError(t, err)
+	_, err = rt.RoundTrip(req)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrRequestBodyTooLarge)
+	assert.Empty(t, signer.PayloadHashes())
+
+	// Bodies of unknown length are limited while they are hashed
+	req, err = http.NewRequest("POST", "http://example.com", io.NopCloser(strings.NewReader(testPayload)))
+	require.NoError(t, err)
+	_, err = rt.RoundTrip(req)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrRequestBodyTooLarge)
+	assert.Empty(t, signer.PayloadHashes())
+}
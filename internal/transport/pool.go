@@ -0,0 +1,31 @@
+package transport
+
+import "net/http"
+
+const (
+	// DefaultMaxIdleConns caps idle connections across all hosts when
+	// SigningTransport.MaxIdleConns is zero
+	DefaultMaxIdleConns = 100
+
+	// DefaultMaxIdleConnsPerHost caps idle connections to each host when
+	// SigningTransport.MaxIdleConnsPerHost is zero
+	DefaultMaxIdleConnsPerHost = 10
+)
+
+// poolLimits returns the idle, idle per host, and per host connection limits,
+// with zero values replaced by their defaults
+func (t *SigningTransport) poolLimits() (maxIdle, maxIdlePerHost, maxPerHost int) {
+	maxIdle, maxIdlePerHost = t.MaxIdleConns, t.MaxIdleConnsPerHost
+	if maxIdle == 0 {
+		maxIdle = DefaultMaxIdleConns
+	}
+	if maxIdlePerHost == 0 {
+		maxIdlePerHost = DefaultMaxIdleConnsPerHost
+	}
+	return maxIdle, maxIdlePerHost, t.MaxConnsPerHost
+}
+
+// applyPoolLimits sets the connection pool limits on httpTransport
+func (t *SigningTransport) applyPoolLimits(httpTransport *http.Transport) {
+	httpTransport.MaxIdleConns, httpTransport.MaxIdleConnsPerHost, httpTransport.MaxConnsPerHost = t.poolLimits()
+}
@@ -0,0 +1,51 @@
+package transport
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningTransport_PoolLimits(t *testing.T) {
+	tests := []struct {
+		name               string
+		transport          *SigningTransport
+		wantMaxIdle        int
+		wantMaxIdlePerHost int
+		wantMaxPerHost     int
+	}{
+		{
+			name:               "defaults",
+			transport:          &SigningTransport{},
+			wantMaxIdle:        DefaultMaxIdleConns,
+			wantMaxIdlePerHost: DefaultMaxIdleConnsPerHost,
+		},
+		{
+			name:               "configured",
+			transport:          &SigningTransport{MaxIdleConns: 50, MaxIdleConnsPerHost: 20, MaxConnsPerHost: 32},
+			wantMaxIdle:        50,
+			wantMaxIdlePerHost: 20,
+			wantMaxPerHost:     32,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := &http.Transport{}
+			tt.transport.HTTPClient = &http.Client{Transport: original}
+
+			base, err := tt.transport.baseTransport()
+			require.NoError(t, err)
+			httpTransport, ok := base.(*http.Transport)
+			require.True(t, ok)
+			assert.NotSame(t, original, httpTransport, "the client's transport must not be modified")
+
+			assert.Equal(t, tt.wantMaxIdle, httpTransport.MaxIdleConns)
+			assert.Equal(t, tt.wantMaxIdlePerHost, httpTransport.MaxIdleConnsPerHost)
+			assert.Equal(t, tt.wantMaxPerHost, httpTransport.MaxConnsPerHost)
+			assert.Zero(t, original.MaxIdleConnsPerHost)
+		})
+	}
+}
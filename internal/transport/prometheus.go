@@ -0,0 +1,72 @@
+package transport
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PrometheusCollector is a MetricsCollector backed by Prometheus counters and histograms.
+type PrometheusCollector struct {
+	requests       *prometheus.CounterVec
+	requestLatency *prometheus.HistogramVec
+	signingLatency prometheus.Histogram
+	errors         *prometheus.CounterVec
+}
+
+// NewPrometheusCollector creates a PrometheusCollector and registers its metrics
+// with the given registerer. If reg is nil, prometheus.DefaultRegisterer is used.
+func NewPrometheusCollector(reg prometheus.Registerer) (*PrometheusCollector, error) {
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+
+	c := &PrometheusCollector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "sigv4_proxy",
+			Name:      "requests_total",
+			Help:      "Total number of signed requests sent to the target MCP server.",
+		}, []string{"method", "status"}),
+		requestLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "sigv4_proxy",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of signed requests to the target MCP server.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "status"}),
+		signingLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: "sigv4_proxy",
+			Name:      "signing_duration_seconds",
+			Help:      "Time spent hashing and signing outgoing requests.",
+			Buckets:   []float64{.0001, .0005, .001, .005, .01, .05, .1},
+		}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "sigv4_proxy",
+			Name:      "errors_total",
+			Help:      "Total number of failed requests by error kind.",
+		}, []string{"kind"}),
+	}
+
+	for _, collector := range []prometheus.Collector{c.requests, c.requestLatency, c.signingLatency, c.errors} {
+		if err := reg.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// RecordRequest implements MetricsCollector
+func (c *PrometheusCollector) RecordRequest(method, status string, latency time.Duration) {
+	c.requests.WithLabelValues(method, status).Inc()
+	c.requestLatency.WithLabelValues(method, status).Observe(latency.Seconds())
+}
+
+// RecordSigningLatency implements MetricsCollector
+func (c *PrometheusCollector) RecordSigningLatency(latency time.Duration) {
+	c.signingLatency.Observe(latency.Seconds())
+}
+
+// RecordError implements MetricsCollector
+func (c *PrometheusCollector) RecordError(kind string) {
+	c.errors.WithLabelValues(kind).Inc()
+}
@@ -0,0 +1,45 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+)
+
+// DefaultPropagateHeaders are the trace context headers copied onto outgoing
+// requests when SigningRoundTripper.PropagateHeaders is not customized
+var DefaultPropagateHeaders = []string{"traceparent", "tracestate", "b3", "x-b3-traceid"}
+
+// propagatedHeadersKey is the context key for headers supplied by the MCP client
+type propagatedHeadersKey struct{}
+
+// ContextWithPropagatedHeaders returns a context carrying headers from the MCP client.
+// SigningRoundTripper copies any header listed in PropagateHeaders from this set onto
+// the outgoing request before signing, making them tamper-evident.
+func ContextWithPropagatedHeaders(ctx context.Context, headers http.Header) context.Context {
+	return context.WithValue(ctx, propagatedHeadersKey{}, headers)
+}
+
+// propagatedHeadersFromContext returns the headers stored by ContextWithPropagatedHeaders, if any
+func propagatedHeadersFromContext(ctx context.Context) http.Header {
+	headers, _ := ctx.Value(propagatedHeadersKey{}).(http.Header)
+	return headers
+}
+
+// propagateHeaders copies the listed headers from the request context onto the request.
+// Headers already present on the request are left unchanged.
+func propagateHeaders(req *http.Request, names []string) {
+	incoming := propagatedHeadersFromContext(req.Context())
+	if len(incoming) == 0 {
+		return
+	}
+
+	for _, name := range names {
+		values := incoming.Values(name)
+		if len(values) == 0 || req.Header.Get(name) != "" {
+			continue
+		}
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+}
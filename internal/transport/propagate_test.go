@@ -0,0 +1,114 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningRoundTripper_PropagateHeaders(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []Option
+		incoming   http.Header
+		existing   http.Header
+		wantHeader http.Header
+		wantAbsent []string
+	}{
+		{
+			name: "default trace headers are propagated",
+			incoming: http.Header{
+				"Traceparent":  {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+				"Tracestate":   {"vendor=value"},
+				"B3":           {"80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1"},
+				"X-B3-Traceid": {"80f198ee56343ba864fe8b2a57d3eff7"},
+				"X-Unlisted":   {"secret"},
+			},
+			wantHeader: http.Header{
+				"Traceparent":  {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+				"Tracestate":   {"vendor=value"},
+				"B3":           {"80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1"},
+				"X-B3-Traceid": {"80f198ee56343ba864fe8b2a57d3eff7"},
+			},
+			wantAbsent: []string{"X-Unlisted"},
+		},
+		{
+			name: "custom header list",
+			opts: []Option{WithPropagateHeaders("X-Unlisted")},
+			incoming: http.Header{
+				"Traceparent": {"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+				"X-Unlisted":  {"value"},
+			},
+			wantHeader: http.Header{"X-Unlisted": {"value"}},
+			wantAbsent: []string{"Traceparent"},
+		},
+		{
+			name:     "existing request headers are not overwritten",
+			incoming: http.Header{"Traceparent": {"00-from-context-01"}},
+			existing: http.Header{"Traceparent": {"00-from-request-01"}},
+			wantHeader: http.Header{
+				"Traceparent": {"00-from-request-01"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received http.Header
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r.Header.Clone()
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			signer := &headerCapturingSigner{}
+			client := &http.Client{
+				Transport: NewSigningRoundTripper(http.DefaultTransport, signer, nil, tt.opts...),
+			}
+
+			ctx := ContextWithPropagatedHeaders(context.Background(), tt.incoming)
+			req, err := http.NewRequestWithContext(ctx, "POST", server.URL, strings.NewReader(`{"jsonrpc":"2.0"}`))
+			require.NoError(t, err)
+			for key, values := range tt.existing {
+				req.Header[key] = values
+			}
+
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			for key, values := range tt.wantHeader {
+				// Headers must be present at signing time and survive the round trip
+				assert.Equal(t, values, signer.headers.Values(key), "signed header %s", key)
+				assert.Equal(t, values, received.Values(key), "received header %s", key)
+			}
+			for _, key := range tt.wantAbsent {
+				assert.Empty(t, received.Get(key), "header %s should not be propagated", key)
+			}
+		})
+	}
+}
+
+func TestSigningRoundTripper_PropagateHeadersWithoutContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Traceparent"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil)
+	assert.Equal(t, DefaultPropagateHeaders, rt.PropagateHeaders)
+
+	req, err := http.NewRequest("POST", server.URL, strings.NewReader("test"))
+	require.NoError(t, err)
+
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+}
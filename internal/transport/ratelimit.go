@@ -0,0 +1,26 @@
+package transport
+
+import (
+	"context"
+	"math"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimiter limits the rate of requests sent to the target server.
+// Implementations must be safe for concurrent use.
+type RateLimiter interface {
+	// Wait blocks until a request may proceed or the context is done.
+	// It returns the context's error if the context is cancelled while waiting.
+	Wait(ctx context.Context) error
+}
+
+// NewTokenBucketLimiter creates a RateLimiter that allows rps requests per second
+// with bursts of up to burst requests. A non-positive burst defaults to rps
+// rounded up (minimum 1).
+func NewTokenBucketLimiter(rps float64, burst int) RateLimiter {
+	if burst <= 0 {
+		burst = int(math.Max(1, math.Ceil(rps)))
+	}
+	return rate.NewLimiter(rate.Limit(rps), burst)
+}
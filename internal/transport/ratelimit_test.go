@@ -0,0 +1,66 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTokenBucketLimiter(t *testing.T) {
+	limiter := NewTokenBucketLimiter(1, 2)
+
+	// The initial burst is available immediately
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, limiter.Wait(ctx))
+	require.NoError(t, limiter.Wait(ctx))
+
+	// The next token is a second away, so a cancelled wait must fail
+	cancelled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	assert.Error(t, limiter.Wait(cancelled))
+}
+
+func TestSigningRoundTripper_WithRateLimiter(t *testing.T) {
+	var received int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	collector := &recordingCollector{}
+	signer := &testutil.FakeSigner{}
+	rt := NewSigningRoundTripper(http.DefaultTransport, signer, nil,
+		WithRateLimiter(NewTokenBucketLimiter(0.001, 1)), WithMetrics(collector))
+
+	req, err := http.NewRequest("POST", server.URL, strings.NewReader("first"))
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// The bucket is now empty; cancelling the wait must propagate the cancellation
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err = http.NewRequestWithContext(ctx, "POST", server.URL, strings.NewReader("second"))
+	require.NoError(t, err)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	resp, err = rt.RoundTrip(req)
+	assert.Nil(t, resp)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled), "expected context.Canceled, got %v", err)
+	assert.Equal(t, 1, received)
+	assert.Equal(t, []string{ErrorKindRateLimit}, collector.errors)
+}
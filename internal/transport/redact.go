@@ -0,0 +1,25 @@
+package transport
+
+import "net/http"
+
+// DefaultRedactedHeaders are the headers whose values are never logged
+var DefaultRedactedHeaders = []string{"Authorization", "X-Amz-Security-Token", "X-Amz-Credential"}
+
+// redactedValue replaces the values of redacted headers
+const redactedValue = "[REDACTED]"
+
+// redactHeaders returns a copy of h with the values of the listed headers
+// replaced by [REDACTED]. The original header map is never modified.
+func redactHeaders(h http.Header, list []string) http.Header {
+	redacted := h.Clone()
+	for _, name := range list {
+		if values := redacted.Values(name); len(values) > 0 {
+			replaced := make([]string, len(values))
+			for i := range replaced {
+				replaced[i] = redactedValue
+			}
+			redacted[http.CanonicalHeaderKey(name)] = replaced
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,55 @@
+package transport
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactHeaders(t *testing.T) {
+	h := http.Header{}
+	h.Set("Authorization", "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/...")
+	h.Add("X-Amz-Security-Token", "token-1")
+	h.Add("X-Amz-Security-Token", "token-2")
+	h.Set("Content-Type", "application/json")
+
+	redacted := redactHeaders(h, []string{"authorization", "X-Amz-Security-Token", "X-Amz-Credential"})
+
+	assert.Equal(t, []string{"[REDACTED]"}, redacted.Values("Authorization"))
+	assert.Equal(t, []string{"[REDACTED]", "[REDACTED]"}, redacted.Values("X-Amz-Security-Token"))
+	assert.Equal(t, "application/json", redacted.Get("Content-Type"))
+	assert.NotContains(t, redacted, "X-Amz-Credential")
+
+	// The original headers are untouched
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/...", h.Get("Authorization"))
+	assert.Equal(t, []string{"token-1", "token-2"}, h.Values("X-Amz-Security-Token"))
+}
+
+func TestSigningRoundTripper_DebugLogRedactsHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil, WithLogger(logger))
+
+	req, err := http.NewRequest("POST", server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Amz-Security-Token", "session-token")
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Contains(t, logs.String(), "[REDACTED]")
+	assert.NotContains(t, logs.String(), "Credential=test")
+	assert.NotContains(t, logs.String(), "session-token")
+	assert.Equal(t, "session-token", req.Header.Get("X-Amz-Security-Token"))
+}
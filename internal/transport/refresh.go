@@ -0,0 +1,52 @@
+package transport
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/signer"
+)
+
+// WithCredentialRefreshOn401 retries a request the target rejects with 401
+// Unauthorized once, re-signed with refreshed credentials. Request bodies are
+// buffered so they can be sent again.
+func WithCredentialRefreshOn401() Option {
+	return func(rt *SigningRoundTripper) {
+		rt.RefreshOn401 = true
+	}
+}
+
+// refreshOn401 handles a 401 Unauthorized response to req by discarding the
+// signer's cached credentials and sending a copy of req with the buffered body
+// through resend, which signs it with newly retrieved credentials. The retry is
+// made once, so a persistent authorization failure returns the second 401.
+// resp is returned unchanged when the signer's credentials cannot be refreshed.
+func (rt *SigningRoundTripper) refreshOn401(
+	resend func(*http.Request) (*http.Response, error),
+	req *http.Request,
+	body []byte,
+	resp *http.Response,
+	logger *slog.Logger,
+) (*http.Response, error) {
+	refreshable, ok := rt.Signer.(signer.RefreshableSigner)
+	if !ok || req.Context().Err() != nil || !refreshable.InvalidateCredentials() {
+		return resp, nil
+	}
+	logger.Warn("target rejected the request signature, retrying with refreshed credentials",
+		"method", req.Method, "host", req.URL.Host)
+
+	// Drain the rejected response so its connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	retry := req.Clone(req.Context())
+	// The old session token would be signed along with the new credentials
+	retry.Header.Del("X-Amz-Security-Token")
+	if body != nil {
+		retry.Body = io.NopCloser(bytes.NewReader(body))
+		retry.ContentLength = int64(len(body))
+	}
+	return resend(retry)
+}
@@ -0,0 +1,108 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/signer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rotatingProvider returns expired credentials on its first retrieval and
+// fresh ones afterwards
+func rotatingProvider(retrievals *atomic.Int64) aws.CredentialsProvider {
+	return aws.NewCredentialsCache(aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+		key := "ASIAFRESHKEY"
+		if retrievals.Add(1) == 1 {
+			key = "ASIAEXPIREDKEY"
+		}
+		return aws.Credentials{
+			AccessKeyID:     key,
+			SecretAccessKey: "secret",
+			SessionToken:    key + "-token",
+			CanExpire:       true,
+			Expires:         time.Now().Add(time.Hour),
+		}, nil
+	}))
+}
+
+func TestSigningRoundTripper_RefreshOn401(t *testing.T) {
+	var requests atomic.Int64
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if !strings.Contains(r.Header.Get("Authorization"), "Credential=ASIAFRESHKEY/") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "ASIAFRESHKEY-token", r.Header.Get("X-Amz-Security-Token"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var retrievals atomic.Int64
+	sig := &signer.V4Signer{CredentialsProvider: rotatingProvider(&retrievals), Region: "us-east-1", Service: "execute-api"}
+	rt := NewSigningRoundTripper(http.DefaultTransport, sig, nil, WithCredentialRefreshOn401())
+
+	req, err := http.NewRequest("POST", server.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int64(2), requests.Load())
+	assert.Equal(t, int64(2), retrievals.Load())
+	assert.Equal(t, []string{`{"jsonrpc":"2.0","id":1,"method":"ping"}`, `{"jsonrpc":"2.0","id":1,"method":"ping"}`}, bodies,
+		"the body should be sent again with the retry")
+}
+
+func TestSigningRoundTripper_RefreshOn401_RetriesOnce(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	var retrievals atomic.Int64
+	sig := &signer.V4Signer{CredentialsProvider: rotatingProvider(&retrievals), Region: "us-east-1", Service: "execute-api"}
+	rt := NewSigningRoundTripper(http.DefaultTransport, sig, nil, WithCredentialRefreshOn401())
+
+	req, err := http.NewRequest("POST", server.URL, strings.NewReader("{}"))
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, int64(2), requests.Load(), "a persistent 401 should be retried only once")
+
+	// Static credentials cannot be refreshed, so the 401 is returned as is
+	requests.Store(0)
+	static := &signer.V4Signer{
+		CredentialsProvider: credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+		Region:              "us-east-1",
+		Service:             "execute-api",
+	}
+	rt = NewSigningRoundTripper(http.DefaultTransport, static, nil, WithCredentialRefreshOn401())
+	req, err = http.NewRequest("POST", server.URL, strings.NewReader("{}"))
+	require.NoError(t, err)
+	resp, err = rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, int64(1), requests.Load())
+}
@@ -0,0 +1,24 @@
+package transport
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// DefaultRequestIDHeader is the header used for injected request IDs when
+// SigningRoundTripper.RequestIDHeader is empty
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// newRequestID generates a random RFC 4122 version 4 UUID
+func newRequestID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate request ID: %w", err)
+	}
+
+	// Set the version (4) and variant (RFC 4122) bits
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
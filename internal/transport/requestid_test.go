@@ -0,0 +1,115 @@
+package transport
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/proxyerr"
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var uuidV4Pattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestNewRequestID(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		id, err := newRequestID()
+		require.NoError(t, err)
+		assert.Regexp(t, uuidV4Pattern, id)
+		assert.False(t, seen[id], "request IDs must be unique")
+		seen[id] = true
+	}
+}
+
+func TestSigningRoundTripper_WithRequestID(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		wantHeader string
+	}{
+		{
+			name:       "default header",
+			header:     "",
+			wantHeader: DefaultRequestIDHeader,
+		},
+		{
+			name:       "custom header",
+			header:     "X-Correlation-ID",
+			wantHeader: "X-Correlation-ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var receivedID string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				receivedID = r.Header.Get(tt.wantHeader)
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+			signer := &headerCapturingSigner{}
+			rt := NewSigningRoundTripper(http.DefaultTransport, signer, nil, WithRequestID(tt.header), WithLogger(logger))
+
+			req, err := http.NewRequest("POST", server.URL, strings.NewReader("test"))
+			require.NoError(t, err)
+
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			// The ID is set before signing, sent to the target, and copied onto the response
+			assert.Regexp(t, uuidV4Pattern, receivedID)
+			assert.Equal(t, receivedID, signer.headers.Get(tt.wantHeader))
+			assert.Equal(t, receivedID, resp.Header.Get(tt.wantHeader))
+			assert.Contains(t, logs.String(), "request_id="+receivedID)
+		})
+	}
+}
+
+func TestSigningRoundTripper_WithoutRequestID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get(DefaultRequestIDHeader))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil)
+
+	req, err := http.NewRequest("POST", server.URL, strings.NewReader("test"))
+	require.NoError(t, err)
+
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Empty(t, resp.Header.Get(DefaultRequestIDHeader))
+}
+
+func TestSigningRoundTripper_RequestIDOnError(t *testing.T) {
+	signErr := errors.New("no credentials")
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{Err: signErr}, nil, WithRequestID(""))
+
+	req, err := http.NewRequest("POST", "http://localhost:59999", strings.NewReader("test"))
+	require.NoError(t, err)
+
+	_, err = rt.RoundTrip(req)
+	require.Error(t, err)
+
+	var proxyErr *proxyerr.ProxyError
+	require.True(t, errors.As(err, &proxyErr))
+	assert.Equal(t, proxyerr.SigningFailed, proxyErr.Code)
+	assert.Regexp(t, uuidV4Pattern, proxyErr.RequestID)
+	assert.Equal(t, req.Header.Get(DefaultRequestIDHeader), proxyErr.RequestID)
+}
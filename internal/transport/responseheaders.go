@@ -0,0 +1,46 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+)
+
+// ResponseHeaders collects the headers of the target's responses to the
+// requests made with a context. When several responses arrive, such as for a
+// resumed stream, the latest value of each header wins.
+type ResponseHeaders struct {
+	mu     sync.Mutex
+	header http.Header
+}
+
+// responseHeadersKey is the context key for a ResponseHeaders collector
+type responseHeadersKey struct{}
+
+// ContextWithResponseHeaders returns a context whose requests record their
+// response headers in the returned collector
+func ContextWithResponseHeaders(ctx context.Context) (context.Context, *ResponseHeaders) {
+	headers := &ResponseHeaders{header: make(http.Header)}
+	return context.WithValue(ctx, responseHeadersKey{}, headers), headers
+}
+
+// Header returns a copy of the collected headers
+func (h *ResponseHeaders) Header() http.Header {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.header.Clone()
+}
+
+// recordResponseHeaders stores header in the request context's collector, if any
+func recordResponseHeaders(ctx context.Context, header http.Header) {
+	h, ok := ctx.Value(responseHeadersKey{}).(*ResponseHeaders)
+	if !ok {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for name, values := range header {
+		h.header[name] = slices.Clone(values)
+	}
+}
@@ -0,0 +1,50 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningRoundTripper_ResponseHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Amzn-Requestid", r.Header.Get("X-Test-Request"))
+		w.Header().Add("X-Cache", "Hit from cloudfront")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	for _, timeout := range []time.Duration{0, time.Minute} {
+		rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil)
+
+		// Requests bounded by a timeout take a separate path through send
+		ctx, headers := ContextWithResponseHeaders(context.Background())
+		if timeout > 0 {
+			ctx = ContextWithRequestTimeout(ctx, timeout)
+		}
+		req, err := http.NewRequestWithContext(ctx, "POST", server.URL, strings.NewReader(`{"jsonrpc":"2.0"}`))
+		require.NoError(t, err)
+		req.Header.Set("X-Test-Request", "req-1")
+
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, "req-1", headers.Header().Get("X-Amzn-Requestid"), "timeout %s", timeout)
+		assert.Equal(t, "Hit from cloudfront", headers.Header().Get("X-Cache"), "timeout %s", timeout)
+
+		// Requests without a collector are unaffected
+		req, err = http.NewRequest("POST", server.URL, strings.NewReader(`{"jsonrpc":"2.0"}`))
+		require.NoError(t, err)
+		resp, err = rt.RoundTrip(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+}
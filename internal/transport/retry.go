@@ -0,0 +1,86 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/proxyerr"
+)
+
+// StatusError reports an unsuccessful HTTP response from the target, so retry
+// decisions can be made on the status code
+type StatusError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+
+	// Status is the HTTP status line, such as "503 Service Unavailable"
+	Status string
+}
+
+// Error formats the response status
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("target returned %s", e.Status)
+}
+
+// newStatusError returns a StatusError for resp
+func newStatusError(resp *http.Response) *StatusError {
+	return &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+}
+
+// retriableStatus maps the HTTP status codes that may succeed on a later
+// attempt to true and those that will not to false
+var retriableStatus = map[int]bool{
+	http.StatusTooManyRequests:     true,
+	http.StatusInternalServerError: true,
+	http.StatusBadGateway:          true,
+	http.StatusServiceUnavailable:  true,
+	http.StatusGatewayTimeout:      true,
+	http.StatusBadRequest:          false,
+	http.StatusUnauthorized:        false,
+	http.StatusForbidden:           false,
+	http.StatusNotFound:            false,
+	http.StatusMethodNotAllowed:    false,
+	http.StatusNotImplemented:      false,
+}
+
+// IsRetriable reports whether a request that failed with err may succeed if
+// sent again. Signing, credential, and rate limiting failures are fatal since
+// every attempt would fail the same way; connection failures, timeouts, and
+// 429, 500, 502, 503, and 504 responses are retriable.
+func IsRetriable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// A fatal cause anywhere in the chain outweighs a retriable wrapper
+	for _, code := range []proxyerr.ErrorCode{proxyerr.SigningFailed, proxyerr.CredentialError, proxyerr.RateLimited} {
+		if proxyerr.HasCode(err, code) {
+			return false
+		}
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return retriableStatus[statusErr.StatusCode]
+	}
+
+	if proxyerr.HasCode(err, proxyerr.ConnectionFailed) || proxyerr.HasCode(err, proxyerr.Timeout) {
+		return true
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Timeout() || urlErr.Temporary()
+	}
+	return false
+}
+
+// retriable applies the round tripper's retry policy to err
+func (rt *SigningRoundTripper) retriable(err error) bool {
+	if rt.RetryPolicy != nil {
+		return rt.RetryPolicy(err)
+	}
+	return IsRetriable(err)
+}
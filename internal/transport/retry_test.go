@@ -0,0 +1,89 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/proxyerr"
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// timeoutError is a net.Error that reports a timeout
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return false }
+
+func TestIsRetriable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "url timeout", err: &url.Error{Op: "Post", URL: "https://example.com", Err: timeoutError{}}, want: true},
+		{name: "url error without timeout", err: &url.Error{Op: "Post", URL: "https://example.com", Err: errors.New("unsupported protocol scheme")}, want: false},
+		{name: "connection failed", err: proxyerr.New(proxyerr.ConnectionFailed, "failed to connect"), want: true},
+		{name: "timeout", err: proxyerr.Wrap(proxyerr.Timeout, context.DeadlineExceeded, "timed out"), want: true},
+		{name: "signing failed", err: proxyerr.New(proxyerr.SigningFailed, "AWS signature generation failed"), want: false},
+		{name: "credential error", err: proxyerr.New(proxyerr.CredentialError, "failed to retrieve AWS credentials"), want: false},
+		{name: "rate limited", err: proxyerr.Wrap(proxyerr.RateLimited, context.Canceled, "rate limiter wait failed"), want: false},
+		{
+			name: "signing failure inside connection failure",
+			err:  proxyerr.Wrap(proxyerr.ConnectionFailed, proxyerr.New(proxyerr.SigningFailed, "signing"), "failed to connect"),
+			want: false,
+		},
+		{name: "plain error", err: errors.New("boom"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRetriable(tt.err))
+		})
+	}
+}
+
+func TestIsRetriable_StatusCodes(t *testing.T) {
+	for status, want := range map[int]bool{
+		429: true, 500: true, 502: true, 503: true, 504: true,
+		400: false, 401: false, 403: false, 404: false, 405: false, 501: false,
+	} {
+		err := &StatusError{StatusCode: status, Status: http.StatusText(status)}
+		assert.Equal(t, want, IsRetriable(err), "status %d", status)
+	}
+}
+
+func TestSigningRoundTripper_RetryPolicy(t *testing.T) {
+	var fallbackCalls int
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fallbackCalls++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer fallback.Close()
+
+	var policyErrs []error
+	policy := func(err error) bool {
+		policyErrs = append(policyErrs, err)
+		return false
+	}
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil,
+		WithFailover([]string{fallback.URL}, time.Second), WithRetryPolicy(policy))
+
+	req, err := http.NewRequest("POST", "http://localhost:59999", strings.NewReader("test"))
+	require.NoError(t, err)
+
+	_, err = rt.RoundTrip(req)
+	require.Error(t, err)
+	assert.Equal(t, 0, fallbackCalls, "the policy rejected the retry")
+	require.Len(t, policyErrs, 1)
+	assert.True(t, proxyerr.HasCode(policyErrs[0], proxyerr.ConnectionFailed))
+}
@@ -0,0 +1,109 @@
+package transport
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// PathRewrite replaces matches of the regular expression Pattern in request
+// paths with Replacement, which may reference capture groups as $1 or ${name}
+type PathRewrite struct {
+	Pattern     string
+	Replacement string
+}
+
+// PathRewriteRoundTripper rewrites request paths before passing requests to
+// Transport. Wrapping a SigningRoundTripper ensures the signature covers the
+// rewritten path the target receives.
+type PathRewriteRoundTripper struct {
+	// Transport signs and sends the rewritten request
+	Transport http.RoundTripper
+
+	// StripPrefix is removed from the start of matching paths
+	StripPrefix string
+
+	// Logger records each rewritten path at debug level (optional)
+	Logger *slog.Logger
+
+	// pattern and replacement are applied after StripPrefix
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// NewPathRewriteRoundTripper creates a round tripper that strips stripPrefix
+// from request paths and then applies rewrite. The rewrite pattern is compiled
+// once here; an invalid pattern returns an error.
+func NewPathRewriteRoundTripper(transport http.RoundTripper, stripPrefix string, rewrite PathRewrite, logger *slog.Logger) (*PathRewriteRoundTripper, error) {
+	rt := &PathRewriteRoundTripper{
+		Transport:   transport,
+		StripPrefix: stripPrefix,
+		Logger:      logger,
+		replacement: rewrite.Replacement,
+	}
+	if rewrite.Pattern != "" {
+		pattern, err := regexp.Compile(rewrite.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path rewrite pattern %q: %w", rewrite.Pattern, err)
+		}
+		rt.pattern = pattern
+	}
+	return rt, nil
+}
+
+// RoundTrip implements http.RoundTripper. The caller's request is not
+// modified; a rewritten path is sent on a clone.
+func (rt *PathRewriteRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := rt.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	path, rule := rt.rewritePath(req.URL.Path)
+	if path == req.URL.Path {
+		return transport.RoundTrip(req)
+	}
+
+	if rt.Logger != nil {
+		rt.Logger.Debug("rewrote request path", "rule", rule, "from", req.URL.Path, "to", path)
+	}
+	rewritten := req.Clone(req.Context())
+	rewritten.URL.Path = path
+	rewritten.URL.RawPath = ""
+	return transport.RoundTrip(rewritten)
+}
+
+// rewritePath applies the prefix strip and pattern rewrite to path, returning
+// the new path and a description of the rules that changed it
+func (rt *PathRewriteRoundTripper) rewritePath(path string) (string, string) {
+	var rules []string
+	if stripped, ok := stripPathPrefix(path, rt.StripPrefix); ok {
+		path = stripped
+		rules = append(rules, fmt.Sprintf("strip prefix %s", rt.StripPrefix))
+	}
+	if rt.pattern != nil && rt.pattern.MatchString(path) {
+		path = rt.pattern.ReplaceAllString(path, rt.replacement)
+		rules = append(rules, fmt.Sprintf("rewrite %s => %s", rt.pattern, rt.replacement))
+	}
+	return path, strings.Join(rules, ", ")
+}
+
+// stripPathPrefix removes prefix from path when it matches whole path
+// segments, so "/mcp" strips "/mcp/tools" but not "/mcpserver". The result
+// always starts with "/".
+func stripPathPrefix(path, prefix string) (string, bool) {
+	trimmed := strings.TrimSuffix(prefix, "/")
+	if trimmed == "" || !strings.HasPrefix(path, trimmed) {
+		return path, false
+	}
+	rest := path[len(trimmed):]
+	if rest != "" && !strings.HasPrefix(rest, "/") {
+		return path, false
+	}
+	if rest == "" {
+		rest = "/"
+	}
+	return rest, true
+}
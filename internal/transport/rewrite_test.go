@@ -0,0 +1,101 @@
+package transport
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStripPathPrefix(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		prefix string
+		want   string
+		ok     bool
+	}{
+		{name: "nested path", path: "/mcp/tools", prefix: "/mcp", want: "/tools", ok: true},
+		{name: "prefix with trailing slash", path: "/mcp/tools", prefix: "/mcp/", want: "/tools", ok: true},
+		{name: "exact match", path: "/mcp", prefix: "/mcp", want: "/", ok: true},
+		{name: "exact match with trailing slash", path: "/mcp/", prefix: "/mcp/", want: "/", ok: true},
+		{name: "partial segment", path: "/mcpserver", prefix: "/mcp", want: "/mcpserver"},
+		{name: "no match", path: "/api/mcp", prefix: "/mcp", want: "/api/mcp"},
+		{name: "empty prefix", path: "/mcp", prefix: "", want: "/mcp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := stripPathPrefix(tt.path, tt.prefix)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.ok, ok)
+		})
+	}
+}
+
+func TestNewPathRewriteRoundTripper_InvalidPattern(t *testing.T) {
+	_, err := NewPathRewriteRoundTripper(nil, "", PathRewrite{Pattern: "^/v(\\d+"}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid path rewrite pattern")
+}
+
+func TestPathRewriteRoundTripper_SignsRewrittenPath(t *testing.T) {
+	var receivedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	signer := &testutil.FakeSigner{}
+	signing := NewSigningRoundTripper(http.DefaultTransport, signer, nil)
+	rt, err := NewPathRewriteRoundTripper(signing, "/mcp/", PathRewrite{Pattern: `^/v(\d+)/`, Replacement: "/api/v$1/"}, logger)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/mcp/v2/messages", nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, "/api/v2/messages", receivedPath)
+	require.Len(t, signer.Requests(), 1)
+	assert.Equal(t, "/api/v2/messages", signer.Requests()[0].URL.Path, "the signature must cover the rewritten path")
+	assert.Equal(t, "/mcp/v2/messages", req.URL.Path, "the caller's request must not be modified")
+
+	assert.Contains(t, logs.String(), "rewrote request path")
+	assert.Contains(t, logs.String(), "strip prefix /mcp/")
+	assert.Contains(t, logs.String(), "from=/mcp/v2/messages")
+}
+
+func TestPathRewriteRoundTripper_UnmatchedPathUnchanged(t *testing.T) {
+	var receivedPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedPath = r.URL.Path
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	rt, err := NewPathRewriteRoundTripper(http.DefaultTransport, "/mcp", PathRewrite{}, logger)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/health", nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, "/health", receivedPath)
+	assert.Empty(t, logs.String())
+}
@@ -0,0 +1,52 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Settings holds the SigningTransport options that can be changed while the
+// proxy is running (see SigningTransport.UpdateSettings).
+type Settings struct {
+	// Headers contains additional headers to add to all signed requests
+	Headers map[string]string
+
+	// Timeout is the per-request timeout, including reading the response body (0 means no timeout)
+	Timeout time.Duration
+
+	// EnableSSE enables the standalone SSE stream; changes apply to the next connection
+	EnableSSE bool
+
+	// RateLimiter delays requests to stay within a request rate (nil disables rate limiting)
+	RateLimiter RateLimiter
+}
+
+// cancelOnClose releases a request's timeout context once its response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the response body and cancels the request context
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// requestTimeoutKey is the context key for a per-request timeout override
+type requestTimeoutKey struct{}
+
+// ContextWithRequestTimeout returns a context whose requests are bounded by
+// timeout instead of the transport's live Timeout setting. A non-positive
+// timeout disables the transport timeout for those requests.
+func ContextWithRequestTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, requestTimeoutKey{}, timeout)
+}
+
+// requestTimeoutFromContext returns the timeout stored by ContextWithRequestTimeout, if any
+func requestTimeoutFromContext(ctx context.Context) (time.Duration, bool) {
+	timeout, ok := ctx.Value(requestTimeoutKey{}).(time.Duration)
+	return timeout, ok
+}
@@ -0,0 +1,106 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningTransport_CurrentSettings(t *testing.T) {
+	transport := &SigningTransport{
+		Headers:    map[string]string{"X-Team": "platform"},
+		EnableSSE:  true,
+		HTTPClient: &http.Client{Timeout: 5 * time.Second},
+	}
+
+	// Initial settings come from the struct fields
+	settings := transport.CurrentSettings()
+	assert.Equal(t, map[string]string{"X-Team": "platform"}, settings.Headers)
+	assert.Equal(t, 5*time.Second, settings.Timeout)
+	assert.True(t, settings.EnableSSE)
+
+	transport.UpdateSettings(Settings{Timeout: time.Second})
+	settings = transport.CurrentSettings()
+	assert.Nil(t, settings.Headers)
+	assert.Equal(t, time.Second, settings.Timeout)
+	assert.False(t, settings.EnableSSE)
+}
+
+func TestSigningRoundTripper_LiveSettings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if delay := r.Header.Get("X-Delay"); delay != "" {
+			d, _ := time.ParseDuration(delay)
+			time.Sleep(d)
+		}
+		w.Header().Set("X-Seen-Team", r.Header.Get("X-Team"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var settings atomic.Pointer[Settings]
+	settings.Store(&Settings{Headers: map[string]string{"X-Team": "platform"}})
+
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, map[string]string{"X-Team": "ignored"}, withSettings(&settings))
+
+	send := func(delay string) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(context.Background(), "POST", server.URL, strings.NewReader("test"))
+		require.NoError(t, err)
+		if delay != "" {
+			req.Header.Set("X-Delay", delay)
+		}
+		return rt.RoundTrip(req)
+	}
+
+	resp, err := send("")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "platform", resp.Header.Get("X-Seen-Team"))
+
+	// Updated headers apply to the next request
+	settings.Store(&Settings{Headers: map[string]string{"X-Team": "security"}})
+	resp, err = send("")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "security", resp.Header.Get("X-Seen-Team"))
+
+	// Updated timeouts apply to the next request
+	settings.Store(&Settings{Timeout: 50 * time.Millisecond})
+	resp, err = send("500ms")
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+}
+
+func TestSigningRoundTripper_ContextRequestTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var settings atomic.Pointer[Settings]
+	settings.Store(&Settings{Timeout: 20 * time.Millisecond})
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil, withSettings(&settings))
+
+	send := func(ctx context.Context) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", server.URL, strings.NewReader("test"))
+		require.NoError(t, err)
+		return rt.RoundTrip(req)
+	}
+
+	// The live timeout applies by default
+	_, err := send(context.Background())
+	assert.Error(t, err)
+
+	// A context override replaces it
+	resp, err := send(ContextWithRequestTimeout(context.Background(), time.Second))
+	require.NoError(t, err)
+	resp.Body.Close()
+}
@@ -0,0 +1,94 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/signer"
+)
+
+// DefaultShadowTimeout bounds shadow requests when the shadow HTTP client has no timeout
+const DefaultShadowTimeout = 5 * time.Second
+
+// ShadowTarget is a secondary target that receives a copy of every MCP request
+// sent to the primary target, such as a new server version under test. Shadow
+// requests are sent asynchronously and their responses are discarded, so the
+// shadow target never affects what clients see. It receives the primary
+// session's Mcp-Session-Id, so it should be a stateless MCP server.
+type ShadowTarget struct {
+	// URL is the shadow target's endpoint; it replaces the scheme, host, and
+	// path of each request, keeping the query
+	URL string
+
+	// Signer signs shadow requests (optional, defaults to the round tripper's signer)
+	Signer signer.Signer
+
+	// HTTPClient sends shadow requests (optional, defaults to a client with
+	// DefaultShadowTimeout). Give it a short timeout so slow shadow requests
+	// do not pile up.
+	HTTPClient *http.Client
+}
+
+// WithShadow sends an asynchronous copy of every MCP request to a shadow target.
+func WithShadow(shadow *ShadowTarget) Option {
+	return func(rt *SigningRoundTripper) {
+		rt.Shadow = shadow
+	}
+}
+
+// shadow sends a copy of req, whose body has been buffered and hashed, to the
+// shadow target in the background. The copy is re-signed because the host and
+// path are part of the SigV4 canonical request. It outlives the primary
+// request, and failures are only logged at DEBUG level.
+func (rt *SigningRoundTripper) shadow(req *http.Request, body []byte, payloadHash string, logger *slog.Logger) {
+	target, err := url.Parse(rt.Shadow.URL)
+	if err != nil {
+		logger.Debug("invalid shadow target URL", "error", err)
+		return
+	}
+
+	clone := req.Clone(context.WithoutCancel(req.Context()))
+	clone.URL = &url.URL{
+		Scheme:   target.Scheme,
+		User:     target.User,
+		Host:     target.Host,
+		Path:     target.Path,
+		RawPath:  target.RawPath,
+		RawQuery: req.URL.RawQuery,
+	}
+	clone.Host = ""
+	clone.Body = nil
+	if body != nil {
+		clone.Body = io.NopCloser(bytes.NewReader(body))
+		clone.ContentLength = int64(len(body))
+	}
+
+	sig := rt.Shadow.Signer
+	if sig == nil {
+		sig = rt.Signer
+	}
+	client := rt.Shadow.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: DefaultShadowTimeout}
+	}
+
+	go func() {
+		if err := sig.SignRequest(clone.Context(), clone, payloadHash); err != nil {
+			logger.Debug("shadow request signing failed", "url", target.Redacted(), "error", err)
+			return
+		}
+		resp, err := client.Do(clone)
+		if err != nil {
+			logger.Debug("shadow request failed", "url", target.Redacted(), "error", err)
+			return
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		logger.Debug("shadow request completed", "url", target.Redacted(), "status", resp.StatusCode)
+	}()
+}
@@ -0,0 +1,90 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shadowRequest is a request received by a shadow target
+type shadowRequest struct {
+	path          string
+	body          string
+	authorization string
+}
+
+func TestSigningRoundTripper_Shadow(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("primary"))
+	}))
+	defer primary.Close()
+
+	// The shadow target holds its response until released, which must not delay the primary
+	received := make(chan shadowRequest, 1)
+	release := make(chan struct{})
+	shadowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- shadowRequest{path: r.URL.Path, body: string(body), authorization: r.Header.Get("Authorization")}
+		<-release
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer shadowServer.Close()
+	defer close(release)
+
+	primarySigner, shadowSigner := &testutil.FakeSigner{}, &testutil.FakeSigner{}
+	rt := NewSigningRoundTripper(http.DefaultTransport, primarySigner, nil,
+		WithShadow(&ShadowTarget{URL: shadowServer.URL + "/v2/mcp", Signer: shadowSigner}))
+
+	req, err := http.NewRequest(http.MethodPost, primary.URL+"/mcp?stage=prod", strings.NewReader(testPayload))
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "primary", string(body))
+
+	select {
+	case got := <-received:
+		assert.Equal(t, "/v2/mcp", got.path)
+		assert.Equal(t, testPayload, got.body)
+		assert.Equal(t, testutil.FakeAuthorization, got.authorization)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the shadow request")
+	}
+
+	// Each target's request was signed separately over the same payload
+	require.Len(t, shadowSigner.Requests(), 1)
+	assert.Equal(t, "stage=prod", shadowSigner.Requests()[0].URL.RawQuery)
+	assert.Equal(t, primarySigner.PayloadHashes(), shadowSigner.PayloadHashes())
+}
+
+func TestSigningRoundTripper_ShadowSkipsStreams(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer primary.Close()
+
+	shadowSigner := &testutil.FakeSigner{}
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil,
+		WithShadow(&ShadowTarget{URL: "http://127.0.0.1:1/mcp", Signer: shadowSigner}))
+
+	// The standalone SSE stream is a GET and is not shadowed
+	req, err := http.NewRequest(http.MethodGet, primary.URL, nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Empty(t, shadowSigner.Requests())
+}
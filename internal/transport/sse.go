@@ -0,0 +1,277 @@
+package transport
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSSEReconnectDelay is the delay before the first SSE reconnect attempt
+	DefaultSSEReconnectDelay = time.Second
+
+	// DefaultSSEMaxReconnectDelay caps the exponential backoff between SSE reconnect attempts
+	DefaultSSEMaxReconnectDelay = 30 * time.Second
+
+	// DefaultSSEHeartbeatTimeout is how long an SSE stream may stay silent before it is
+	// considered dead and reconnected
+	DefaultSSEHeartbeatTimeout = 60 * time.Second
+
+	// lastEventIDHeader asks the server to resume an SSE stream after the given event
+	lastEventIDHeader = "Last-Event-ID"
+)
+
+// isResumableStream reports whether req opens the standalone SSE stream. Requests
+// that already carry a Last-Event-ID are resumptions of a call's response stream,
+// which the MCP client drains and closes itself once the response arrives.
+func isResumableStream(req *http.Request) bool {
+	return req.Method == http.MethodGet && req.Header.Get(lastEventIDHeader) == ""
+}
+
+// isEventStream reports whether resp is a successful text/event-stream response
+func isEventStream(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return err == nil && mediaType == "text/event-stream"
+}
+
+// resumableStream is an SSE response body that reconnects when the stream drops.
+// Bytes are released to the reader one complete event at a time, so a reconnect
+// never splices a partial event onto the resumed stream. Each reconnect re-signs
+// the original request with Last-Event-ID set to the last complete event's ID.
+//
+// When a heartbeat timeout is set, a stream that receives no data, including
+// comment-only keep-alives, for that long is closed and reconnected.
+type resumableStream struct {
+	rt     *SigningRoundTripper
+	req    *http.Request
+	logger *slog.Logger
+
+	mu        sync.Mutex
+	body      io.ReadCloser
+	closed    bool
+	stalled   bool
+	heartbeat *time.Timer
+	done      chan struct{}
+
+	buf         []byte
+	pending     []byte
+	ready       []byte
+	err         error
+	lastEventID string
+	attempts    int
+}
+
+func newResumableStream(rt *SigningRoundTripper, req *http.Request, body io.ReadCloser) *resumableStream {
+	logger := rt.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	s := &resumableStream{
+		rt:     rt,
+		req:    req,
+		logger: logger,
+		body:   body,
+		done:   make(chan struct{}),
+		buf:    make([]byte, 4096),
+	}
+	s.watch(body)
+	return s
+}
+
+// Read returns complete events from the stream, reconnecting if it ends
+func (s *resumableStream) Read(p []byte) (int, error) {
+	for len(s.ready) == 0 {
+		if s.err != nil {
+			cause := s.err
+			s.err = nil
+			if err := s.reconnect(cause); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		s.mu.Lock()
+		body, closed := s.body, s.closed
+		s.mu.Unlock()
+		if closed {
+			return 0, io.EOF
+		}
+
+		// A read error is handled once the events already received are delivered
+		n, err := body.Read(s.buf)
+		if n > 0 && s.heartbeat != nil {
+			s.heartbeat.Reset(s.rt.SSEHeartbeatTimeout)
+		}
+		s.consume(s.buf[:n])
+		s.err = err
+
+		s.mu.Lock()
+		if err != nil && s.stalled {
+			s.err = fmt.Errorf("no data received for %s", s.rt.SSEHeartbeatTimeout)
+		}
+		s.mu.Unlock()
+	}
+
+	n := copy(p, s.ready)
+	s.ready = s.ready[n:]
+	return n, nil
+}
+
+// Close closes the current stream and stops any reconnect in progress
+func (s *resumableStream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	close(s.done)
+	if s.heartbeat != nil {
+		s.heartbeat.Stop()
+	}
+	return s.body.Close()
+}
+
+// replace swaps in a new stream body, closing the old one. It returns false if
+// the stream was closed, in which case body is closed instead.
+func (s *resumableStream) replace(body io.ReadCloser) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		body.Close()
+		return false
+	}
+	s.body.Close()
+	s.body = body
+	s.watch(body)
+	return true
+}
+
+// watch starts the heartbeat timer for body, replacing the timer of the previous
+// body. It must be called with s.mu held or before the stream is shared.
+func (s *resumableStream) watch(body io.ReadCloser) {
+	if s.rt.SSEHeartbeatTimeout <= 0 {
+		return
+	}
+	if s.heartbeat != nil {
+		s.heartbeat.Stop()
+	}
+	s.stalled = false
+	s.heartbeat = time.AfterFunc(s.rt.SSEHeartbeatTimeout, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.closed || s.body != body {
+			return
+		}
+		s.logger.Warn("SSE stream heartbeat timed out", "timeout", s.rt.SSEHeartbeatTimeout)
+		s.stalled = true
+		s.body.Close()
+	})
+}
+
+// consume appends data to the pending event and moves every complete event,
+// terminated by a blank line, to the ready buffer
+func (s *resumableStream) consume(data []byte) {
+	s.pending = append(s.pending, data...)
+
+	start := 0
+	id, hasID := "", false
+	for start < len(s.pending) {
+		end := bytes.IndexByte(s.pending[start:], '\n')
+		if end < 0 {
+			return
+		}
+		line := bytes.TrimSuffix(s.pending[start:start+end], []byte("\r"))
+		start += end + 1
+
+		if len(line) == 0 {
+			s.ready = append(s.ready, s.pending[:start]...)
+			s.pending = s.pending[start:]
+			if hasID {
+				s.lastEventID = id
+			}
+			start, id, hasID = 0, "", false
+			continue
+		}
+		if value, ok := bytes.CutPrefix(line, []byte("id:")); ok {
+			id, hasID = string(bytes.TrimPrefix(value, []byte(" "))), true
+		}
+	}
+}
+
+// reconnect re-requests the stream with exponential backoff until it succeeds,
+// the attempts are exhausted, or the stream is closed
+func (s *resumableStream) reconnect(cause error) error {
+	if s.rt.SSEMaxReconnects <= 0 {
+		// Only the heartbeat is enabled; the MCP client reconnects on its own
+		return cause
+	}
+	if errors.Is(cause, ErrSSEEventTooLarge) {
+		// The server would replay the same event after reconnecting
+		return cause
+	}
+	if cause == io.EOF {
+		cause = fmt.Errorf("stream closed by server")
+	}
+	s.pending = s.pending[:0]
+
+	ctx := s.req.Context()
+	delay := s.rt.SSEReconnectDelay
+	maxDelay := s.rt.SSEMaxReconnectDelay
+	for s.attempts < s.rt.SSEMaxReconnects {
+		s.attempts++
+		s.logger.Warn("SSE stream interrupted, reconnecting",
+			"attempt", s.attempts, "max_attempts", s.rt.SSEMaxReconnects, "delay", delay,
+			"last_event_id", s.lastEventID, "error", cause)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-s.done:
+			timer.Stop()
+			return io.EOF
+		}
+		delay = min(delay*2, maxDelay)
+
+		req := s.req.Clone(ctx)
+		if s.lastEventID != "" {
+			req.Header.Set(lastEventIDHeader, s.lastEventID)
+		}
+		resp, err := s.rt.send(req)
+		if err != nil {
+			cause = err
+			if !s.rt.retriable(err) {
+				break
+			}
+			continue
+		}
+		if !isEventStream(resp) {
+			resp.Body.Close()
+			cause = newStatusError(resp)
+			if resp.StatusCode >= http.StatusBadRequest && !s.rt.retriable(cause) {
+				break
+			}
+			continue
+		}
+
+		if !s.replace(resp.Body) {
+			return io.EOF
+		}
+		s.logger.Info("SSE stream reconnected", "attempt", s.attempts, "last_event_id", s.lastEventID)
+		return nil
+	}
+
+	return fmt.Errorf("SSE stream lost after %d reconnect attempts: %w", s.attempts, cause)
+}
@@ -0,0 +1,242 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sseServer serves a scripted event stream per connection and records the
+// Last-Event-ID header of each request
+type sseServer struct {
+	mu           sync.Mutex
+	streams      []string
+	lastEventIDs []string
+}
+
+func (s *sseServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	n := len(s.lastEventIDs)
+	s.lastEventIDs = append(s.lastEventIDs, r.Header.Get("Last-Event-ID"))
+	s.mu.Unlock()
+
+	if n >= len(s.streams) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, s.streams[n])
+}
+
+func (s *sseServer) requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.lastEventIDs...)
+}
+
+func TestSigningRoundTripper_SSEReconnect(t *testing.T) {
+	server := &sseServer{streams: []string{
+		// The second event is cut off mid-stream and must not be delivered
+		"id: 1\ndata: first\n\nid: 2\ndata: trunc",
+		"id: 2\ndata: second\n\n",
+		"id: 3\ndata: third\n\n",
+	}}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	var logs bytes.Buffer
+	signer := &testutil.FakeSigner{}
+	rt := NewSigningRoundTripper(http.DefaultTransport, signer, nil,
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+		WithSSEReconnect(4, time.Millisecond, 2*time.Millisecond))
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.ErrorContains(t, err, "SSE stream lost after 4 reconnect attempts")
+	assert.Equal(t, "id: 1\ndata: first\n\nid: 2\ndata: second\n\nid: 3\ndata: third\n\n", string(body))
+
+	// Each reconnect resumes after the last complete event and is signed again
+	assert.Equal(t, []string{"", "1", "2", "3", "3"}, server.requests())
+	assert.Len(t, signer.Requests(), 5)
+	assert.Equal(t, 4, strings.Count(logs.String(), "SSE stream interrupted, reconnecting"))
+	assert.Contains(t, logs.String(), "level=WARN")
+	assert.Contains(t, logs.String(), "attempt=4")
+	assert.Contains(t, logs.String(), "delay=2ms")
+}
+
+func TestSigningRoundTripper_SSEReconnectStopsOnClientError(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 1 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "id: 1\ndata: only\n\n")
+	}))
+	defer ts.Close()
+
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil,
+		WithSSEReconnect(5, time.Millisecond, time.Millisecond))
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	_, err = io.ReadAll(resp.Body)
+	require.ErrorContains(t, err, "404 Not Found")
+	assert.Equal(t, 2, requests)
+}
+
+func TestSigningRoundTripper_SSEReconnectClose(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+	}))
+	defer ts.Close()
+
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil,
+		WithSSEReconnect(5, time.Hour, time.Hour))
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+
+	// Closing the body interrupts the wait before the next attempt
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		resp.Body.Close()
+	}()
+	_, err = resp.Body.Read(make([]byte, 16))
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestSigningRoundTripper_SSEReconnectSkipsOtherRequests(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "id: 1\ndata: only\n\n")
+	}))
+	defer ts.Close()
+
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil,
+		WithSSEReconnect(5, time.Millisecond, time.Millisecond))
+
+	tests := []struct {
+		name   string
+		method string
+		header string
+	}{
+		{name: "POST response stream", method: http.MethodPost},
+		{name: "resumed call stream", method: http.MethodGet, header: "7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, ts.URL, nil)
+			require.NoError(t, err)
+			if tt.header != "" {
+				req.Header.Set("Last-Event-ID", tt.header)
+			}
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			assert.Equal(t, "id: 1\ndata: only\n\n", string(body))
+		})
+	}
+}
+
+func TestSigningRoundTripper_SSEHeartbeatTimeout(t *testing.T) {
+	var mu sync.Mutex
+	var lastEventIDs []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		n := len(lastEventIDs)
+		lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		if n > 0 {
+			fmt.Fprint(w, "id: 2\ndata: second\n\n")
+			return
+		}
+
+		// Keep-alive comments hold the stream open until they stop
+		fmt.Fprint(w, "id: 1\ndata: first\n\n")
+		for range 5 {
+			w.(http.Flusher).Flush()
+			time.Sleep(20 * time.Millisecond)
+			fmt.Fprint(w, ": ping\n\n")
+		}
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+
+	var logs bytes.Buffer
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil,
+		WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+		WithSSEReconnect(1, time.Millisecond, time.Millisecond),
+		WithSSEHeartbeat(60*time.Millisecond))
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.ErrorContains(t, err, "SSE stream lost after 1 reconnect attempts")
+	assert.Contains(t, string(body), "data: first")
+	assert.Contains(t, string(body), "data: second")
+
+	mu.Lock()
+	assert.Equal(t, []string{"", "1"}, lastEventIDs)
+	mu.Unlock()
+	assert.Equal(t, 1, strings.Count(logs.String(), "SSE stream heartbeat timed out"))
+	assert.Contains(t, logs.String(), "no data received for 60ms")
+}
+
+func TestSigningRoundTripper_SSEHeartbeatWithoutReconnect(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{}, nil,
+		WithSSEHeartbeat(20*time.Millisecond))
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	// The silent stream fails so the MCP client can reconnect it
+	_, err = io.ReadAll(resp.Body)
+	assert.ErrorContains(t, err, "no data received for 20ms")
+}
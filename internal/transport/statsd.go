@@ -0,0 +1,69 @@
+package transport
+
+import (
+	"time"
+
+	"gopkg.in/alexcesaro/statsd.v2"
+)
+
+// DefaultStatsDPrefix prefixes StatsD metric names when no prefix is set
+const DefaultStatsDPrefix = "sigv4proxy"
+
+// StatsDCollector is a MetricsCollector that pushes metrics to a StatsD
+// server over UDP. It records the counter requests.total, the timers
+// requests.latency and signing.latency in milliseconds, and one
+// errors.total.<kind> counter per error kind. Metrics are buffered and sent
+// periodically; Close flushes them.
+type StatsDCollector struct {
+	client *statsd.Client
+}
+
+// NewStatsDCollector creates a StatsDCollector that sends to the StatsD server
+// at addr (host:port). An empty prefix uses DefaultStatsDPrefix, and a sample
+// rate of 0 sends every metric.
+func NewStatsDCollector(addr, prefix string, sampleRate float64) (*StatsDCollector, error) {
+	if prefix == "" {
+		prefix = DefaultStatsDPrefix
+	}
+	if sampleRate <= 0 {
+		sampleRate = 1
+	}
+
+	client, err := statsd.New(
+		statsd.Address(addr),
+		statsd.Prefix(prefix),
+		statsd.SampleRate(float32(sampleRate)),
+		// Metrics are best effort; failed sends are not worth reporting per packet
+		statsd.ErrorHandler(func(error) {}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &StatsDCollector{client: client}, nil
+}
+
+// RecordRequest implements MetricsCollector
+func (c *StatsDCollector) RecordRequest(method, status string, latency time.Duration) {
+	c.client.Increment("requests.total")
+	c.client.Timing("requests.latency", milliseconds(latency))
+}
+
+// RecordSigningLatency implements MetricsCollector
+func (c *StatsDCollector) RecordSigningLatency(latency time.Duration) {
+	c.client.Timing("signing.latency", milliseconds(latency))
+}
+
+// RecordError implements MetricsCollector
+func (c *StatsDCollector) RecordError(kind string) {
+	c.client.Increment("errors.total." + kind)
+}
+
+// Close flushes buffered metrics and closes the connection
+func (c *StatsDCollector) Close() {
+	c.client.Close()
+}
+
+// milliseconds converts a duration to fractional milliseconds, the unit of StatsD timers
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,63 @@
+package transport
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readStatsDPacket returns the next non-empty packet received by conn. The
+// client writes an empty packet when it connects.
+func readStatsDPacket(t *testing.T, conn net.PacketConn) string {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	buf := make([]byte, 1500)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		if n > 0 {
+			return string(buf[:n])
+		}
+	}
+}
+
+func TestStatsDCollector(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	collector, err := NewStatsDCollector(conn.LocalAddr().String(), "", 0)
+	require.NoError(t, err)
+	collector.RecordRequest("POST", "200", 1500*time.Microsecond)
+	collector.RecordSigningLatency(250 * time.Microsecond)
+	collector.RecordError(ErrorKindNetwork)
+	collector.Close()
+
+	lines := strings.Split(strings.TrimSpace(readStatsDPacket(t, conn)), "\n")
+	assert.Equal(t, []string{
+		"sigv4proxy.requests.total:1|c",
+		"sigv4proxy.requests.latency:1.5|ms",
+		"sigv4proxy.signing.latency:0.25|ms",
+		"sigv4proxy.errors.total.network:1|c",
+	}, lines)
+}
+
+func TestStatsDCollector_PrefixAndSampleRate(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	collector, err := NewStatsDCollector(conn.LocalAddr().String(), "mcp", 0.999999)
+	require.NoError(t, err)
+	// Sampling may skip the metric, so record until one is sent
+	for range 10 {
+		collector.RecordError(ErrorKindSigning)
+	}
+	collector.Close()
+
+	assert.Contains(t, readStatsDPacket(t, conn), "mcp.errors.total.signing:1|c|@0.999999")
+}
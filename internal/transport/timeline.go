@@ -0,0 +1,181 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// RequestTimeline records when each phase of a request to the target
+// happened, for diagnosing where its latency comes from. Phases that did not
+// happen, such as dialing on a reused connection, are left zero. When a
+// request is retried, the signing and connection phases are those of the
+// last attempt. The timeline is complete once the response body is closed.
+type RequestTimeline struct {
+	// Queued is when the request entered RoundTrip, before waiting for a
+	// concurrency slot
+	Queued time.Time
+
+	// SigningStart and SigningEnd bracket signing the request
+	SigningStart time.Time
+	SigningEnd   time.Time
+
+	// DialDone is when a new TCP connection to the target was established
+	DialDone time.Time
+
+	// TLSHandshakeDone is when the TLS handshake on a new connection completed
+	TLSHandshakeDone time.Time
+
+	// FirstByteSent is when the request began to be written to the connection
+	FirstByteSent time.Time
+
+	// ResponseHeaders is when the target's response headers were received
+	ResponseHeaders time.Time
+
+	// BodyRead is when the response body was read to the end or closed
+	BodyRead time.Time
+
+	mu sync.Mutex
+}
+
+// mark sets *at to the current time
+func (t *RequestTimeline) mark(at *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	*at = time.Now()
+}
+
+// markOnce sets *at to the current time unless it is already set
+func (t *RequestTimeline) markOnce(at *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if at.IsZero() {
+		*at = time.Now()
+	}
+}
+
+// clientTrace returns the hooks that record the connection phases
+func (t *RequestTimeline) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		ConnectDone: func(network, addr string, err error) {
+			if err == nil {
+				t.mark(&t.DialDone)
+			}
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			if err == nil {
+				t.mark(&t.TLSHandshakeDone)
+			}
+		},
+		WroteHeaderField: func(key string, value []string) {
+			t.markOnce(&t.FirstByteSent)
+		},
+		WroteHeaders: func() {
+			t.markOnce(&t.FirstByteSent)
+		},
+	}
+}
+
+// LogValue logs each recorded phase as its offset from Queued
+func (t *RequestTimeline) LogValue() slog.Value {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	phases := []struct {
+		key string
+		at  time.Time
+	}{
+		{"signing_start", t.SigningStart},
+		{"signing_end", t.SigningEnd},
+		{"dial_done", t.DialDone},
+		{"tls_handshake_done", t.TLSHandshakeDone},
+		{"first_byte_sent", t.FirstByteSent},
+		{"response_headers", t.ResponseHeaders},
+		{"body_read", t.BodyRead},
+	}
+	attrs := make([]slog.Attr, 0, len(phases))
+	for _, phase := range phases {
+		if !phase.at.IsZero() {
+			attrs = append(attrs, slog.Duration(phase.key, phase.at.Sub(t.Queued)))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// timelineKey is the context key for a request's RequestTimeline
+type timelineKey struct{}
+
+// ParseTimeline returns the timeline of the request whose context is ctx, if
+// the request is being sent by a SigningRoundTripper with timelines enabled.
+func ParseTimeline(ctx context.Context) (*RequestTimeline, bool) {
+	timeline, ok := ctx.Value(timelineKey{}).(*RequestTimeline)
+	return timeline, ok
+}
+
+// WithTimeline records a RequestTimeline for every request and logs it at
+// DEBUG level once the response body is closed.
+func WithTimeline() Option {
+	return func(rt *SigningRoundTripper) {
+		rt.Timeline = true
+	}
+}
+
+// startTimeline returns req with a new timeline and the client trace that
+// fills it attached to its context
+func startTimeline(req *http.Request) (*http.Request, *RequestTimeline) {
+	timeline := &RequestTimeline{Queued: time.Now()}
+	ctx := context.WithValue(req.Context(), timelineKey{}, timeline)
+	ctx = httptrace.WithClientTrace(ctx, timeline.clientTrace())
+	return req.WithContext(ctx), timeline
+}
+
+// finishTimeline logs the timeline of a failed request right away, and that
+// of a response once its body is closed
+func (rt *SigningRoundTripper) finishTimeline(timeline *RequestTimeline, req *http.Request, resp *http.Response, err error) {
+	logTimeline := func(status int) {
+		if rt.Logger == nil {
+			return
+		}
+		rt.Logger.Debug("request timeline",
+			"method", req.Method,
+			"url", req.URL.Redacted(),
+			"status", status,
+			"timeline", timeline)
+	}
+	if err != nil {
+		logTimeline(0)
+		return
+	}
+	resp.Body = &timelineBody{ReadCloser: resp.Body, timeline: timeline, done: func() { logTimeline(resp.StatusCode) }}
+}
+
+// timelineBody marks the end of reading a response body, when it is read to
+// the end or closed, and then calls done once
+type timelineBody struct {
+	io.ReadCloser
+	timeline *RequestTimeline
+	done     func()
+	once     sync.Once
+}
+
+// Read reads from the body, marking the timeline at the end of the body
+func (b *timelineBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.timeline.markOnce(&b.timeline.BodyRead)
+	}
+	return n, err
+}
+
+// Close closes the body and logs the completed timeline
+func (b *timelineBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.timeline.markOnce(&b.timeline.BodyRead)
+	b.once.Do(b.done)
+	return err
+}
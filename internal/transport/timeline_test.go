@@ -0,0 +1,103 @@
+package transport
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/signer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// timelineCapture records the timeline in the context of the requests it sends
+type timelineCapture struct {
+	next     http.RoundTripper
+	timeline *RequestTimeline
+}
+
+func (c *timelineCapture) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.timeline, _ = ParseTimeline(req.Context())
+	return c.next.RoundTrip(req)
+}
+
+func TestSigningRoundTripper_Timeline(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{}}`))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	capture := &timelineCapture{next: server.Client().Transport}
+	sig := &signer.V4Signer{
+		CredentialsProvider: credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+		Region:              "us-east-1",
+		Service:             "execute-api",
+	}
+	rt := NewSigningRoundTripper(capture, sig, nil, WithTimeline(),
+		WithLogger(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+
+	req, err := http.NewRequest("POST", server.URL, strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"ping"}`))
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+
+	timeline := capture.timeline
+	require.NotNil(t, timeline, "the timeline is attached to the request context")
+	assert.True(t, timeline.BodyRead.IsZero(), "the body has not been read yet")
+	assert.NotContains(t, logs.String(), "request timeline")
+
+	_, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	phases := []struct {
+		name string
+		at   func() bool
+	}{
+		{"SigningStart", func() bool { return !timeline.SigningStart.Before(timeline.Queued) }},
+		{"SigningEnd", func() bool { return !timeline.SigningEnd.Before(timeline.SigningStart) }},
+		{"DialDone", func() bool { return !timeline.DialDone.Before(timeline.SigningEnd) }},
+		{"TLSHandshakeDone", func() bool { return !timeline.TLSHandshakeDone.Before(timeline.DialDone) }},
+		{"FirstByteSent", func() bool { return !timeline.FirstByteSent.Before(timeline.TLSHandshakeDone) }},
+		{"ResponseHeaders", func() bool { return !timeline.ResponseHeaders.Before(timeline.FirstByteSent) }},
+		{"BodyRead", func() bool { return !timeline.BodyRead.Before(timeline.ResponseHeaders) }},
+	}
+	assert.False(t, timeline.Queued.IsZero())
+	for _, phase := range phases {
+		assert.True(t, phase.at(), "%s is recorded in order", phase.name)
+	}
+	assert.False(t, timeline.BodyRead.IsZero())
+
+	assert.Contains(t, logs.String(), "request timeline")
+	assert.Contains(t, logs.String(), "timeline.tls_handshake_done=")
+	assert.Contains(t, logs.String(), "timeline.body_read=")
+}
+
+func TestSigningRoundTripper_TimelineDisabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok := ParseTimeline(r.Context())
+		assert.False(t, ok)
+	}))
+	defer server.Close()
+
+	capture := &timelineCapture{next: http.DefaultTransport}
+	sig := &signer.V4Signer{
+		CredentialsProvider: credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+		Region:              "us-east-1",
+		Service:             "execute-api",
+	}
+	rt := NewSigningRoundTripper(capture, sig, nil)
+
+	req, err := http.NewRequest("POST", server.URL, strings.NewReader(`{}`))
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Nil(t, capture.timeline)
+}
@@ -0,0 +1,48 @@
+package transport
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// defaultKeepAlive matches the TCP keep-alive period of http.DefaultTransport
+const defaultKeepAlive = 30 * time.Second
+
+// cappedTimeout returns timeout, or the overall request timeout when both are
+// set and the request timeout is shorter
+func (t *SigningTransport) cappedTimeout(timeout time.Duration) time.Duration {
+	if overall := t.CurrentSettings().Timeout; timeout > 0 && overall > 0 {
+		return min(timeout, overall)
+	}
+	return timeout
+}
+
+// dialer returns the dialer for connections to the target, applying the TCP
+// keep-alive settings and DialTimeout, or nil if neither applies
+func (t *SigningTransport) dialer(enableSSE bool) *net.Dialer {
+	dialer := t.keepAliveDialer(enableSSE)
+	timeout := t.cappedTimeout(t.DialTimeout)
+	if timeout <= 0 {
+		return dialer
+	}
+	if dialer == nil {
+		dialer = &net.Dialer{KeepAlive: defaultKeepAlive}
+	}
+	dialer.Timeout = timeout
+	return dialer
+}
+
+// applyConnectionTimeouts sets the TLS handshake, response header, and idle
+// connection timeouts on httpTransport, leaving unset ones at their defaults
+func (t *SigningTransport) applyConnectionTimeouts(httpTransport *http.Transport) {
+	if timeout := t.cappedTimeout(t.TLSHandshakeTimeout); timeout > 0 {
+		httpTransport.TLSHandshakeTimeout = timeout
+	}
+	if timeout := t.cappedTimeout(t.ResponseHeaderTimeout); timeout > 0 {
+		httpTransport.ResponseHeaderTimeout = timeout
+	}
+	if timeout := t.cappedTimeout(t.IdleConnectionTimeout); timeout > 0 {
+		httpTransport.IdleConnTimeout = timeout
+	}
+}
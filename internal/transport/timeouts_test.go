@@ -0,0 +1,69 @@
+package transport
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningTransport_ConnectionTimeouts(t *testing.T) {
+	tests := []struct {
+		name               string
+		timeout            time.Duration
+		transport          *SigningTransport
+		wantTLSHandshake   time.Duration
+		wantResponseHeader time.Duration
+		wantIdleConnection time.Duration
+		wantDialTimeout    time.Duration
+	}{
+		{
+			name: "fine-grained timeouts only",
+			transport: &SigningTransport{
+				DialTimeout:           5 * time.Second,
+				TLSHandshakeTimeout:   3 * time.Second,
+				ResponseHeaderTimeout: 20 * time.Second,
+				IdleConnectionTimeout: time.Minute,
+			},
+			wantDialTimeout:    5 * time.Second,
+			wantTLSHandshake:   3 * time.Second,
+			wantResponseHeader: 20 * time.Second,
+			wantIdleConnection: time.Minute,
+		},
+		{
+			name:    "capped at the request timeout",
+			timeout: 10 * time.Second,
+			transport: &SigningTransport{
+				DialTimeout:           5 * time.Second,
+				ResponseHeaderTimeout: 20 * time.Second,
+				IdleConnectionTimeout: time.Minute,
+			},
+			wantDialTimeout:    5 * time.Second,
+			wantTLSHandshake:   10 * time.Second,
+			wantResponseHeader: 10 * time.Second,
+			wantIdleConnection: 10 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := &http.Transport{TLSHandshakeTimeout: 10 * time.Second}
+			transport := tt.transport
+			transport.HTTPClient = &http.Client{Transport: original, Timeout: tt.timeout}
+
+			base, err := transport.baseTransport()
+			require.NoError(t, err)
+			httpTransport, ok := base.(*http.Transport)
+			require.True(t, ok)
+			assert.NotSame(t, original, httpTransport, "the client's transport must not be modified")
+
+			assert.Equal(t, tt.wantTLSHandshake, httpTransport.TLSHandshakeTimeout)
+			assert.Equal(t, tt.wantResponseHeader, httpTransport.ResponseHeaderTimeout)
+			assert.Equal(t, tt.wantIdleConnection, httpTransport.IdleConnTimeout)
+			assert.NotNil(t, httpTransport.DialContext)
+			assert.Equal(t, tt.wantDialTimeout, transport.dialer(false).Timeout)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// headerCapturingSigner records the headers present at signing time
+type headerCapturingSigner struct {
+	headers http.Header
+}
+
+func (s *headerCapturingSigner) SignRequest(ctx context.Context, req *http.Request, payloadHash string) error {
+	s.headers = req.Header.Clone()
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential=test/20240101/us-east-1/execute-api/aws4_request")
+	return nil
+}
+
+func TestSigningRoundTripper_WithTracer(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		wantStatus codes.Code
+	}{
+		{
+			name:       "successful response",
+			status:     http.StatusOK,
+			wantStatus: codes.Unset,
+		},
+		{
+			name:       "error response",
+			status:     http.StatusForbidden,
+			wantStatus: codes.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var receivedTraceparent string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				receivedTraceparent = r.Header.Get("Traceparent")
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			recorder := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+			signer := &headerCapturingSigner{}
+			rt := NewSigningRoundTripper(http.DefaultTransport, signer, nil,
+				WithTracer(tp), WithServiceName("execute-api"))
+
+			req, err := http.NewRequest("POST", server.URL, strings.NewReader(`{"test":"data"}`))
+			require.NoError(t, err)
+
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			// The trace context must be present before signing so it is covered by the signature
+			assert.NotEmpty(t, signer.headers.Get("Traceparent"))
+			assert.Equal(t, signer.headers.Get("Traceparent"), receivedTraceparent)
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			assert.Equal(t, "HTTP POST", spans[0].Name())
+			assert.Equal(t, tt.wantStatus, spans[0].Status().Code)
+			assert.Contains(t, spans[0].Attributes(), attribute.String("aws.service", "execute-api"))
+			assert.Contains(t, spans[0].Attributes(), attribute.Int("http.response.status_code", tt.status))
+		})
+	}
+}
+
+func TestSigningRoundTripper_TracerRecordsError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	rt := NewSigningRoundTripper(http.DefaultTransport, &testutil.FakeSigner{Err: assert.AnError}, nil, WithTracer(tp))
+
+	req, err := http.NewRequest("POST", "http://localhost:59999", strings.NewReader("test"))
+	require.NoError(t, err)
+
+	resp, err := rt.RoundTrip(req)
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
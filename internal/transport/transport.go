@@ -3,14 +3,26 @@ package transport
 import (
 	"bytes"
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
-	"fmt"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/proxyerr"
 	"github.com/nisimpson/mcp-sigv4-proxy/internal/signer"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // SigningTransport implements mcp.Transport with AWS signature support.
@@ -30,95 +42,1191 @@ type SigningTransport struct {
 
 	// EnableSSE enables Server-Sent Events support for streaming responses
 	EnableSSE bool
+
+	// Metrics records request observations (optional, defaults to a no-op collector)
+	Metrics MetricsCollector
+
+	// TracerProvider creates spans for outgoing requests (optional, tracing is disabled when nil)
+	TracerProvider trace.TracerProvider
+
+	// ServiceName is the AWS service name recorded on trace spans (e.g., "execute-api")
+	ServiceName string
+
+	// Logger records signed requests at debug level (optional)
+	Logger *slog.Logger
+
+	// TLSConfig customizes outbound HTTPS connections, such as custom CAs or
+	// client certificates (optional, uses Go defaults when nil)
+	TLSConfig *tls.Config
+
+	// InjectRequestID adds a unique X-Request-ID header to every signed request
+	InjectRequestID bool
+
+	// XRayEnabled records an AWS X-Ray subsegment for every request (see WithXRay)
+	XRayEnabled bool
+
+	// FallbackURLs are alternate target endpoints tried in order when the
+	// primary TargetURL fails with a network error or 5xx response (optional)
+	FallbackURLs []string
+
+	// FailoverTimeout caps the time spent on each fallback attempt (0 means no limit)
+	FailoverTimeout time.Duration
+
+	// Shadow is a secondary target that receives an asynchronous copy of every
+	// MCP request, signed with its Signer and sent with its HTTPClient, whose
+	// responses are discarded (optional, see WithShadow)
+	Shadow *SigningTransport
+
+	// RateLimiter delays requests to stay within a request rate (optional)
+	RateLimiter RateLimiter
+
+	// MaxConcurrentRequests caps the requests in flight to the target; further
+	// requests wait for a slot (0 means no limit)
+	MaxConcurrentRequests int
+
+	// SSEMaxReconnects caps the attempts to reconnect a dropped standalone SSE
+	// stream (0 leaves reconnection to the MCP client)
+	SSEMaxReconnects int
+
+	// SSEReconnectDelay is the delay before the first reconnect attempt, doubling
+	// after each failure (defaults to DefaultSSEReconnectDelay)
+	SSEReconnectDelay time.Duration
+
+	// SSEMaxReconnectDelay caps the delay between reconnect attempts
+	// (defaults to DefaultSSEMaxReconnectDelay)
+	SSEMaxReconnectDelay time.Duration
+
+	// SSEHeartbeatTimeout is how long the standalone SSE stream may go without
+	// data before it is closed and reconnected (0 defaults to
+	// DefaultSSEHeartbeatTimeout, negative disables the check)
+	SSEHeartbeatTimeout time.Duration
+
+	// AuditLogger records every signed request sent to the target (optional)
+	AuditLogger AuditLogger
+
+	// MaxRequestBodyBytes caps the request body buffered for signing
+	// (0 defaults to DefaultMaxBodyBytes, negative disables the limit)
+	MaxRequestBodyBytes int64
+
+	// MaxResponseBodyBytes caps non-streaming response bodies
+	// (0 defaults to DefaultMaxBodyBytes, negative disables the limit)
+	MaxResponseBodyBytes int64
+
+	// MaxSSEEventBytes caps the size of a single SSE event; a larger event
+	// closes the stream with ErrSSEEventTooLarge (0 defaults to
+	// DefaultMaxSSEEventBytes, negative disables the limit)
+	MaxSSEEventBytes int64
+
+	// MaxSSETotalBytes caps the bytes read from an SSE stream before it is
+	// ended at an event boundary for reconnection (0 defaults to
+	// DefaultMaxSSETotalBytes, negative disables the limit)
+	MaxSSETotalBytes int64
+
+	// CompressRequests gzips request bodies before signing
+	CompressRequests bool
+
+	// DecompressResponse requests gzip-encoded responses and decompresses them
+	DecompressResponse bool
+
+	// BinaryContentTypes are the response media types passed through as raw
+	// bytes (optional, defaults to DefaultBinaryContentTypes)
+	BinaryContentTypes []string
+
+	// DebugMode dumps every signed request and its response to Logger at DEBUG level
+	DebugMode bool
+
+	// PayloadHashProvider computes the payload hash signed for each request
+	// body (optional, defaults to hashing the buffered body with SHA-256)
+	PayloadHashProvider PayloadHashProvider
+
+	// DialTimeout bounds establishing a TCP connection to the target
+	// (0 keeps the 30s default). Like the other connection timeouts, it is
+	// capped at the request timeout when both are set.
+	DialTimeout time.Duration
+
+	// TLSHandshakeTimeout bounds the TLS handshake with the target (0 keeps the 10s default)
+	TLSHandshakeTimeout time.Duration
+
+	// ResponseHeaderTimeout bounds the wait for response headers after the
+	// request is written (0 means no limit)
+	ResponseHeaderTimeout time.Duration
+
+	// IdleConnectionTimeout is how long idle keep-alive connections to the
+	// target stay open (0 keeps the 90s default)
+	IdleConnectionTimeout time.Duration
+
+	// DNSCacheTTL caches the resolved addresses of the target host for this
+	// long, so new connections skip DNS resolution (0 disables the cache)
+	DNSCacheTTL time.Duration
+
+	// ForceHTTP2 negotiates HTTP/2 with ALPN for HTTPS targets, even when a
+	// custom TLS configuration or dialer would otherwise disable it
+	ForceHTTP2 bool
+
+	// AllowH2C sends requests to http:// targets with HTTP/2 over cleartext
+	// (h2c), for local targets that support it. Outbound proxies are not used
+	// for h2c connections.
+	AllowH2C bool
+
+	// HTTP2KeepAlivePingInterval is how long an HTTP/2 connection to the
+	// target may go without receiving a frame before a PING frame is sent
+	// (0 uses DefaultHTTP2KeepAlivePingInterval, negative disables PINGs)
+	HTTP2KeepAlivePingInterval time.Duration
+
+	// HTTP2KeepAlivePingTimeout is how long to wait for a PING to be answered
+	// before the connection is closed and the next request re-dials
+	// (0 uses DefaultHTTP2KeepAlivePingTimeout)
+	HTTP2KeepAlivePingTimeout time.Duration
+
+	// MaxIdleConns caps idle connections kept open across all hosts
+	// (0 uses DefaultMaxIdleConns)
+	MaxIdleConns int
+
+	// MaxIdleConnsPerHost caps idle connections kept open to each host
+	// (0 uses DefaultMaxIdleConnsPerHost)
+	MaxIdleConnsPerHost int
+
+	// MaxConnsPerHost caps connections to each host, including those in use (0 means no limit)
+	MaxConnsPerHost int
+
+	// TCPKeepAlive is the idle time before TCP keep-alive probes are sent on
+	// connections to the target (0 uses DefaultSSETCPKeepAlive when EnableSSE is
+	// set and the OS default otherwise, negative disables probes)
+	TCPKeepAlive time.Duration
+
+	// TCPKeepAliveInterval is the time between unanswered keep-alive probes (0 uses the OS default)
+	TCPKeepAliveInterval time.Duration
+
+	// TCPKeepAliveCount is the number of unanswered probes before the connection
+	// is dropped (0 uses the OS default)
+	TCPKeepAliveCount int
+
+	// ProxyURL routes outbound connections through an HTTP, HTTPS, or SOCKS5 proxy
+	// (optional, defaults to the HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment variables)
+	ProxyURL string
+
+	// StripPathPrefix is removed from request paths before signing, for targets
+	// that expect requests at the root of the URL the proxy is mounted under (optional)
+	StripPathPrefix string
+
+	// PathRewrite rewrites request paths with a regular expression before
+	// signing, after StripPathPrefix is removed (optional)
+	PathRewrite PathRewrite
+
+	// RefreshOn401 re-signs a request the target rejects with 401 Unauthorized
+	// with refreshed credentials and sends it once more, for temporary
+	// credentials that expire between signing and the target's check
+	RefreshOn401 bool
+
+	// EnableTimeline records and logs at DEBUG level when each phase of every
+	// request happened, from queueing to reading the response body
+	EnableTimeline bool
+
+	// settings holds the live Headers, Timeout, and EnableSSE values; it is
+	// initialized from the struct fields on first use
+	settings atomic.Pointer[Settings]
+
+	// roundTripper is the signing round tripper of the most recent connection
+	roundTripper atomic.Pointer[SigningRoundTripper]
+
+	// dnsCache caches host lookups when DNSCacheTTL is set; it is shared by
+	// every connection
+	dnsCache atomic.Pointer[dnsCache]
+}
+
+// UpdateSettings replaces the headers, timeout, and SSE settings used by the
+// transport. Header and timeout changes apply to the next request; SSE changes
+// apply to the next connection.
+func (t *SigningTransport) UpdateSettings(s Settings) {
+	t.settings.Store(&s)
+}
+
+// CurrentSettings returns the settings currently in use by the transport.
+func (t *SigningTransport) CurrentSettings() Settings {
+	if s := t.settings.Load(); s != nil {
+		return *s
+	}
+
+	initial := &Settings{
+		Headers:     t.Headers,
+		EnableSSE:   t.EnableSSE,
+		RateLimiter: t.RateLimiter,
+	}
+	if t.HTTPClient != nil {
+		initial.Timeout = t.HTTPClient.Timeout
+	}
+	t.settings.CompareAndSwap(nil, initial)
+	return *t.settings.Load()
 }
 
 // Connect implements mcp.Transport by creating a connection to the target MCP server
 // using the streamable HTTP transport with request signing.
 func (t *SigningTransport) Connect(ctx context.Context) (mcp.Connection, error) {
-	if t.HTTPClient == nil {
-		t.HTTPClient = http.DefaultClient
+	roundTripper, err := t.newRoundTripper()
+	if err != nil {
+		return nil, err
+	}
+	t.roundTripper.Store(roundTripper)
+	rewriter, err := t.pathRewriter(roundTripper)
+	if err != nil {
+		return nil, err
+	}
+	if t.Logger != nil {
+		maxIdle, maxIdlePerHost, maxPerHost := t.poolLimits()
+		t.Logger.Debug("connection pool limits",
+			"max_idle_conns", maxIdle, "max_idle_conns_per_host", maxIdlePerHost, "max_conns_per_host", maxPerHost)
 	}
-
-	// Create a signing HTTP client that wraps the original client's transport
 	signingClient := &http.Client{
-		Transport: NewSigningRoundTripper(t.HTTPClient.Transport, t.Signer, t.Headers),
-		Timeout:   t.HTTPClient.Timeout,
+		Transport: rewriter,
 	}
 
 	// Use the MCP SDK's StreamableClientTransport with our signing client
 	streamTransport := &mcp.StreamableClientTransport{
 		Endpoint:             t.TargetURL,
 		HTTPClient:           signingClient,
-		DisableStandaloneSSE: !t.EnableSSE,
+		DisableStandaloneSSE: !t.CurrentSettings().EnableSSE,
 	}
 
 	return streamTransport.Connect(ctx)
 }
 
+// DryRunClient returns an HTTP client that signs requests exactly as Connect
+// does but answers them with a synthetic response instead of sending them.
+func (t *SigningTransport) DryRunClient() (*http.Client, error) {
+	roundTripper, err := t.newRoundTripper(WithDryRun())
+	if err != nil {
+		return nil, err
+	}
+	rewriter, err := t.pathRewriter(roundTripper)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: rewriter}, nil
+}
+
+// pathRewriter wraps rt so request paths are rewritten before they are signed.
+// It returns rt unchanged when no path rewriting is configured.
+func (t *SigningTransport) pathRewriter(rt http.RoundTripper) (http.RoundTripper, error) {
+	if t.StripPathPrefix == "" && t.PathRewrite.Pattern == "" {
+		return rt, nil
+	}
+	return NewPathRewriteRoundTripper(rt, t.StripPathPrefix, t.PathRewrite, t.Logger)
+}
+
+// newRoundTripper creates a signing round tripper from the transport's
+// configuration. The request timeout is read from the live settings so it can
+// be changed while connected.
+func (t *SigningTransport) newRoundTripper(extra ...Option) (*SigningRoundTripper, error) {
+	if t.HTTPClient == nil {
+		t.HTTPClient = http.DefaultClient
+	}
+
+	settings := t.CurrentSettings()
+
+	opts := []Option{
+		withSettings(&t.settings),
+		WithBodyLimits(t.MaxRequestBodyBytes, t.MaxResponseBodyBytes),
+		WithSSELimits(t.MaxSSEEventBytes, t.MaxSSETotalBytes),
+	}
+	if t.Metrics != nil {
+		opts = append(opts, WithMetrics(t.Metrics))
+	}
+	if t.TracerProvider != nil {
+		opts = append(opts, WithTracer(t.TracerProvider), WithServiceName(t.ServiceName))
+	}
+	if t.Logger != nil {
+		opts = append(opts, WithLogger(t.Logger))
+	}
+	if t.InjectRequestID {
+		opts = append(opts, WithRequestID(DefaultRequestIDHeader))
+	}
+	if t.XRayEnabled {
+		opts = append(opts, WithXRay())
+	}
+	if len(t.FallbackURLs) > 0 {
+		opts = append(opts, WithFailover(t.FallbackURLs, t.FailoverTimeout))
+	}
+	if t.Shadow != nil {
+		opts = append(opts, WithShadow(&ShadowTarget{URL: t.Shadow.TargetURL, Signer: t.Shadow.Signer, HTTPClient: t.Shadow.HTTPClient}))
+	}
+	if t.AuditLogger != nil {
+		opts = append(opts, WithAuditLogger(t.AuditLogger))
+	}
+	if t.MaxConcurrentRequests > 0 {
+		opts = append(opts, WithMaxConcurrentRequests(t.MaxConcurrentRequests))
+	}
+	if t.RefreshOn401 {
+		opts = append(opts, WithCredentialRefreshOn401())
+	}
+	if t.EnableTimeline {
+		opts = append(opts, WithTimeline())
+	}
+	if t.CompressRequests {
+		opts = append(opts, WithRequestCompression())
+	}
+	if t.DecompressResponse {
+		opts = append(opts, WithResponseDecompression())
+	}
+	if t.BinaryContentTypes != nil {
+		opts = append(opts, WithBinaryContentTypes(t.BinaryContentTypes...))
+	}
+	if t.DebugMode {
+		opts = append(opts, WithDebugMode())
+	}
+	if t.PayloadHashProvider != nil {
+		opts = append(opts, WithPayloadHashProvider(t.PayloadHashProvider))
+	}
+	if t.SSEMaxReconnects > 0 {
+		opts = append(opts, WithSSEReconnect(t.SSEMaxReconnects, t.SSEReconnectDelay, t.SSEMaxReconnectDelay))
+	}
+	switch {
+	case t.SSEHeartbeatTimeout == 0:
+		opts = append(opts, WithSSEHeartbeat(DefaultSSEHeartbeatTimeout))
+	case t.SSEHeartbeatTimeout > 0:
+		opts = append(opts, WithSSEHeartbeat(t.SSEHeartbeatTimeout))
+	}
+
+	opts = append(opts, extra...)
+
+	base, err := t.baseTransport()
+	if err != nil {
+		return nil, err
+	}
+
+	// Wrap the original client's transport
+	return NewSigningRoundTripper(base, t.Signer, settings.Headers, opts...), nil
+}
+
+// ActiveRequests returns the number of requests to the target currently in
+// flight on the most recent connection, or 0 before Connect is called.
+func (t *SigningTransport) ActiveRequests() int64 {
+	if rt := t.roundTripper.Load(); rt != nil {
+		return rt.ActiveRequests()
+	}
+	return 0
+}
+
+// WaitingRequests returns the number of requests waiting for a slot under
+// MaxConcurrentRequests on the most recent connection, or 0 before Connect is
+// called.
+func (t *SigningTransport) WaitingRequests() int {
+	if rt := t.roundTripper.Load(); rt != nil {
+		return rt.WaitingRequests()
+	}
+	return 0
+}
+
+// UnsignedClient returns an HTTP client that shares the transport's TLS and
+// proxy configuration but does not sign requests. It is used for connectivity
+// checks that must not depend on AWS credentials.
+func (t *SigningTransport) UnsignedClient() (*http.Client, error) {
+	if t.HTTPClient == nil {
+		t.HTTPClient = http.DefaultClient
+	}
+
+	base, err := t.baseTransport()
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{Transport: base, Timeout: t.CurrentSettings().Timeout}, nil
+}
+
+// baseTransport returns the round tripper used to send signed requests.
+// The connection pool limits, TLSConfig, ProxyURL, TCP keep-alive, DNS cache, and connection timeout
+// settings are set on a clone of the HTTP client's transport (or http.DefaultTransport) so the original is
+// never modified. ForceHTTP2 and AllowH2C then enable HTTP/2 on the clone, with
+// keep-alive PINGs on HTTP/2 connections.
+func (t *SigningTransport) baseTransport() (http.RoundTripper, error) {
+	base := t.HTTPClient.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	httpTransport, ok := base.(*http.Transport)
+	if !ok {
+		// Custom round trippers are responsible for their own pool, TLS, and proxy configuration
+		return base, nil
+	}
+
+	httpTransport = httpTransport.Clone()
+	t.applyPoolLimits(httpTransport)
+	t.applyConnectionTimeouts(httpTransport)
+	if t.TLSConfig != nil {
+		httpTransport.TLSClientConfig = t.TLSConfig
+	}
+	var dial dialFunc
+	if dialer := t.dialer(t.CurrentSettings().EnableSSE); dialer != nil {
+		dial = dialer.DialContext
+	}
+	if t.DNSCacheTTL > 0 {
+		if dial == nil {
+			dial = (&net.Dialer{Timeout: dialTimeout, KeepAlive: defaultKeepAlive}).DialContext
+		}
+		dial = t.resolverCache().dialContext(dial)
+	}
+	if dial != nil {
+		httpTransport.DialContext = dial
+	}
+
+	if t.ProxyURL != "" {
+		// net/http dials http, https, and socks5 proxy URLs natively
+		proxyURL, err := url.Parse(t.ProxyURL)
+		if err != nil {
+			return nil, proxyerr.Wrap(proxyerr.InvalidConfig, err, "invalid proxy URL")
+		}
+		httpTransport.Proxy = http.ProxyURL(proxyURL)
+	} else if httpTransport.Proxy == nil {
+		httpTransport.Proxy = http.ProxyFromEnvironment
+	}
+
+	t.applyHTTP2KeepAlive(httpTransport)
+	if t.ForceHTTP2 {
+		if err := configureHTTP2(httpTransport); err != nil {
+			return nil, err
+		}
+	}
+	if t.AllowH2C {
+		interval, timeout := t.http2KeepAlive()
+		return newH2CRoundTripper(httpTransport, dial, interval, timeout), nil
+	}
+	return httpTransport, nil
+}
+
 // SigningRoundTripper wraps an http.RoundTripper and signs all requests.
 // This is exported for use in testing and custom HTTP client configurations.
 type SigningRoundTripper struct {
 	Transport http.RoundTripper
 	Signer    signer.Signer
 	Headers   map[string]string
+	Metrics   MetricsCollector
+
+	// Tracer starts a client span for each request (optional)
+	Tracer trace.Tracer
+
+	// Propagator injects the trace context into outgoing request headers
+	Propagator propagation.TextMapPropagator
+
+	// ServiceName is the AWS service name recorded on trace spans
+	ServiceName string
+
+	// XRay records an AWS X-Ray subsegment around each request and sends the
+	// X-Amzn-Trace-Id header to the target
+	XRay bool
+
+	// Logger records signed requests at debug level, with the values of
+	// RedactedHeaders redacted from the logged headers
+	Logger *slog.Logger
+
+	// InjectRequestID adds a unique request ID header to every request before signing
+	// and copies it onto the response so proxy and target logs can be correlated
+	InjectRequestID bool
+
+	// RequestIDHeader is the header carrying the request ID (defaults to DefaultRequestIDHeader)
+	RequestIDHeader string
+
+	// PropagateHeaders lists headers copied from the request context (see
+	// ContextWithPropagatedHeaders) onto the outgoing request before signing
+	PropagateHeaders []string
+
+	// FallbackURLs are tried in order when the request to the primary target
+	// fails with a retriable error (see RetryPolicy)
+	FallbackURLs []string
+
+	// FailoverTimeout caps the time spent on each fallback attempt (0 means no limit)
+	FailoverTimeout time.Duration
+
+	// Shadow receives an asynchronous copy of every MCP request (optional)
+	Shadow *ShadowTarget
+
+	// RateLimiter delays requests to stay within a request rate (optional)
+	RateLimiter RateLimiter
+
+	// MaxConcurrentRequests caps the requests in flight; further requests
+	// block until a slot is free or their context is cancelled (0 means no
+	// limit). Slots are held until RoundTrip returns.
+	MaxConcurrentRequests int
+
+	// AuditLogger records every signed request sent to the target, including
+	// failover attempts (optional)
+	AuditLogger AuditLogger
+
+	// RedactedHeaders are the request headers whose values are replaced with
+	// [REDACTED] in debug logs and audit entries
+	RedactedHeaders []string
+
+	// MaxRequestBodyBytes caps the request body buffered for signing; larger
+	// bodies fail with ErrRequestBodyTooLarge (0 disables the limit)
+	MaxRequestBodyBytes int64
+
+	// MaxResponseBodyBytes caps non-streaming response bodies; reading past it
+	// fails with ErrResponseBodyTooLarge (0 disables the limit)
+	MaxResponseBodyBytes int64
+
+	// MaxSSEEventBytes caps each event of an SSE response; a larger event
+	// closes the stream with ErrSSEEventTooLarge (0 disables the limit)
+	MaxSSEEventBytes int64
+
+	// MaxSSETotalBytes caps the bytes read from an SSE response before the
+	// stream ends at an event boundary for reconnection (0 disables the limit)
+	MaxSSETotalBytes int64
+
+	// CompressRequests gzips request bodies and sets Content-Encoding: gzip
+	// before signing, so the payload hash covers the compressed bytes
+	CompressRequests bool
+
+	// DecompressResponse sends Accept-Encoding: gzip and decompresses gzip-encoded responses
+	DecompressResponse bool
+
+	// BinaryContentTypes are the response media types, such as image/*, whose
+	// bodies are returned exactly as received: they are never decompressed or
+	// dumped in debug mode (nil uses DefaultBinaryContentTypes)
+	BinaryContentTypes []string
+
+	// DebugMode dumps the signed request and the response at DEBUG level, with
+	// RedactedHeaders redacted. It has no effect in builds with the production tag.
+	DebugMode bool
+
+	// DryRun signs requests without sending them, answering each with a
+	// synthetic 200 OK response describing the signed request
+	DryRun bool
+
+	// PayloadHashProvider computes the payload hash signed for each request
+	// body (optional, defaults to hashing the buffered body with SHA-256). The
+	// body is still buffered when it is compressed, replayed to fallback or shadow
+	// targets, or dumped in debug mode.
+	PayloadHashProvider PayloadHashProvider
+
+	// RetryPolicy decides whether a failed attempt is retried, by failover and
+	// by SSE reconnection (nil uses IsRetriable)
+	RetryPolicy func(err error) bool
+
+	// RefreshOn401 retries a request the target rejects with 401 Unauthorized
+	// once, re-signed after the signer's cached credentials are discarded.
+	// Signers that do not implement signer.RefreshableSigner are not retried.
+	RefreshOn401 bool
+
+	// Timeline records a RequestTimeline for each request, available from its
+	// context through ParseTimeline and logged at DEBUG level
+	Timeline bool
+
+	// SSEMaxReconnects caps the attempts to reconnect a dropped standalone SSE
+	// stream with Last-Event-ID (0 disables reconnection)
+	SSEMaxReconnects int
+
+	// SSEReconnectDelay is the delay before the first reconnect attempt, doubling after each failure
+	SSEReconnectDelay time.Duration
+
+	// SSEMaxReconnectDelay caps the delay between reconnect attempts
+	SSEMaxReconnectDelay time.Duration
+
+	// SSEHeartbeatTimeout closes and reconnects the standalone SSE stream when no
+	// data arrives for this long (0 disables the check)
+	SSEHeartbeatTimeout time.Duration
+
+	// settings, when set, supplies live Headers, Timeout, and RateLimiter
+	// values that replace the Headers and RateLimiter fields
+	settings *atomic.Pointer[Settings]
+
+	// activeRequests, totalRequests, and totalErrors count the requests in
+	// flight, the requests completed, and the requests that failed
+	activeRequests atomic.Int64
+	totalRequests  atomic.Int64
+	totalErrors    atomic.Int64
+
+	// slots is the MaxConcurrentRequests semaphore, created on first use, and
+	// waitingRequests counts the requests blocked on it
+	slotsOnce       sync.Once
+	slots           chan struct{}
+	waitingRequests atomic.Int64
+}
+
+// Option configures optional behavior of a SigningRoundTripper.
+type Option func(*SigningRoundTripper)
+
+// WithMetrics configures the collector that records request latency, status, and errors.
+func WithMetrics(collector MetricsCollector) Option {
+	return func(rt *SigningRoundTripper) {
+		rt.Metrics = collector
+	}
+}
+
+// WithTracer enables OpenTelemetry tracing using a tracer from the given provider.
+// W3C traceparent/tracestate headers are injected before signing so they are
+// covered by the request signature.
+func WithTracer(tp trace.TracerProvider) Option {
+	return func(rt *SigningRoundTripper) {
+		rt.Tracer = tp.Tracer(tracerName)
+		rt.Propagator = propagation.TraceContext{}
+	}
+}
+
+// WithServiceName sets the AWS service name recorded on trace spans.
+func WithServiceName(name string) Option {
+	return func(rt *SigningRoundTripper) {
+		rt.ServiceName = name
+	}
+}
+
+// WithLogger configures the structured logger used for request logging.
+func WithLogger(logger *slog.Logger) Option {
+	return func(rt *SigningRoundTripper) {
+		rt.Logger = logger
+	}
+}
+
+// WithRequestID enables request ID injection using the given header name.
+// An empty header uses DefaultRequestIDHeader.
+func WithRequestID(header string) Option {
+	return func(rt *SigningRoundTripper) {
+		rt.InjectRequestID = true
+		rt.RequestIDHeader = header
+	}
+}
+
+// WithPropagateHeaders replaces the list of context headers copied onto outgoing requests.
+func WithPropagateHeaders(headers ...string) Option {
+	return func(rt *SigningRoundTripper) {
+		rt.PropagateHeaders = headers
+	}
+}
+
+// WithFailover configures fallback target URLs that are tried in order when the
+// primary target fails, with each fallback attempt bounded by timeout (0 means no limit).
+func WithFailover(fallbackURLs []string, timeout time.Duration) Option {
+	return func(rt *SigningRoundTripper) {
+		rt.FallbackURLs = fallbackURLs
+		rt.FailoverTimeout = timeout
+	}
+}
+
+// WithRetryPolicy replaces IsRetriable as the test deciding whether failed
+// attempts are retried by failover and SSE reconnection.
+func WithRetryPolicy(policy func(err error) bool) Option {
+	return func(rt *SigningRoundTripper) {
+		rt.RetryPolicy = policy
+	}
+}
+
+// WithPayloadHashProvider replaces the default SHA-256 hashing of buffered
+// request bodies with provider, such as StreamingPayloadHash or UnsignedPayloadHash.
+func WithPayloadHashProvider(provider PayloadHashProvider) Option {
+	return func(rt *SigningRoundTripper) {
+		rt.PayloadHashProvider = provider
+	}
+}
+
+// WithRateLimiter delays each request until the limiter allows it.
+func WithRateLimiter(limiter RateLimiter) Option {
+	return func(rt *SigningRoundTripper) {
+		rt.RateLimiter = limiter
+	}
+}
+
+// WithAuditLogger records every signed request with the given audit logger.
+func WithAuditLogger(logger AuditLogger) Option {
+	return func(rt *SigningRoundTripper) {
+		rt.AuditLogger = logger
+	}
+}
+
+// WithRedactedHeaders replaces the list of headers whose values are redacted in logs.
+func WithRedactedHeaders(headers ...string) Option {
+	return func(rt *SigningRoundTripper) {
+		rt.RedactedHeaders = headers
+	}
+}
+
+// WithBodyLimits caps request and response body sizes. A zero limit uses
+// DefaultMaxBodyBytes and a negative limit disables it.
+func WithBodyLimits(maxRequestBytes, maxResponseBytes int64) Option {
+	return func(rt *SigningRoundTripper) {
+		rt.MaxRequestBodyBytes = bodyLimit(maxRequestBytes)
+		rt.MaxResponseBodyBytes = bodyLimit(maxResponseBytes)
+	}
+}
+
+// WithSSELimits caps the size of each SSE event and the bytes read from an SSE
+// stream before it is ended for reconnection. A zero limit uses
+// DefaultMaxSSEEventBytes or DefaultMaxSSETotalBytes and a negative limit
+// disables it.
+func WithSSELimits(maxEventBytes, maxTotalBytes int64) Option {
+	return func(rt *SigningRoundTripper) {
+		rt.MaxSSEEventBytes = sseLimit(maxEventBytes, DefaultMaxSSEEventBytes)
+		rt.MaxSSETotalBytes = sseLimit(maxTotalBytes, DefaultMaxSSETotalBytes)
+	}
+}
+
+// sseLimit resolves a configured SSE limit like bodyLimit, with its own default
+func sseLimit(limit, defaultLimit int64) int64 {
+	if limit == 0 {
+		return defaultLimit
+	}
+	return bodyLimit(limit)
+}
+
+// bodyLimit resolves a configured body limit, where 0 means the default and
+// a negative value means no limit
+func bodyLimit(limit int64) int64 {
+	switch {
+	case limit == 0:
+		return DefaultMaxBodyBytes
+	case limit < 0:
+		return 0
+	}
+	return limit
+}
+
+// WithRequestCompression gzips request bodies before signing.
+func WithRequestCompression() Option {
+	return func(rt *SigningRoundTripper) {
+		rt.CompressRequests = true
+	}
+}
+
+// WithResponseDecompression requests gzip-encoded responses and decompresses them.
+func WithResponseDecompression() Option {
+	return func(rt *SigningRoundTripper) {
+		rt.DecompressResponse = true
+	}
+}
+
+// WithDebugMode dumps every signed request and its response at DEBUG level.
+func WithDebugMode() Option {
+	return func(rt *SigningRoundTripper) {
+		rt.DebugMode = true
+	}
+}
+
+// WithDryRun signs requests without sending them to the target.
+func WithDryRun() Option {
+	return func(rt *SigningRoundTripper) {
+		rt.DryRun = true
+	}
+}
+
+// WithSSEReconnect reconnects a dropped standalone SSE stream up to maxReconnects
+// times, re-signing the request with the Last-Event-ID header. The delay between
+// attempts starts at delay and doubles up to maxDelay; non-positive values use
+// DefaultSSEReconnectDelay and DefaultSSEMaxReconnectDelay.
+func WithSSEReconnect(maxReconnects int, delay, maxDelay time.Duration) Option {
+	return func(rt *SigningRoundTripper) {
+		if delay <= 0 {
+			delay = DefaultSSEReconnectDelay
+		}
+		if maxDelay <= 0 {
+			maxDelay = DefaultSSEMaxReconnectDelay
+		}
+		rt.SSEMaxReconnects = maxReconnects
+		rt.SSEReconnectDelay = delay
+		rt.SSEMaxReconnectDelay = max(delay, maxDelay)
+	}
+}
+
+// WithSSEHeartbeat closes the standalone SSE stream when no data, including
+// comment-only keep-alives, arrives within timeout. The stream is then
+// reconnected as configured by WithSSEReconnect, or by the MCP client otherwise.
+func WithSSEHeartbeat(timeout time.Duration) Option {
+	return func(rt *SigningRoundTripper) {
+		rt.SSEHeartbeatTimeout = timeout
+	}
+}
+
+// withSettings makes the round tripper read headers and the request timeout
+// from the live settings of a SigningTransport.
+func withSettings(settings *atomic.Pointer[Settings]) Option {
+	return func(rt *SigningRoundTripper) {
+		rt.settings = settings
+	}
 }
 
 // NewSigningRoundTripper creates a new SigningRoundTripper with the given transport and signer.
-func NewSigningRoundTripper(transport http.RoundTripper, signer signer.Signer, headers map[string]string) *SigningRoundTripper {
-	return &SigningRoundTripper{
-		Transport: transport,
-		Signer:    signer,
-		Headers:   headers,
+func NewSigningRoundTripper(transport http.RoundTripper, signer signer.Signer, headers map[string]string, opts ...Option) *SigningRoundTripper {
+	rt := &SigningRoundTripper{
+		Transport:            transport,
+		Signer:               signer,
+		Headers:              headers,
+		Metrics:              NoopCollector{},
+		Logger:               slog.New(slog.DiscardHandler),
+		PropagateHeaders:     DefaultPropagateHeaders,
+		RedactedHeaders:      DefaultRedactedHeaders,
+		MaxRequestBodyBytes:  DefaultMaxBodyBytes,
+		MaxResponseBodyBytes: DefaultMaxBodyBytes,
+		MaxSSEEventBytes:     DefaultMaxSSEEventBytes,
+		MaxSSETotalBytes:     DefaultMaxSSETotalBytes,
+	}
+	for _, opt := range opts {
+		opt(rt)
 	}
+	return rt
+}
+
+// tracerName is the instrumentation scope name used for spans created by SigningRoundTripper
+const tracerName = "github.com/nisimpson/mcp-sigv4-proxy/internal/transport"
+
+// ActiveRequests returns the number of requests currently in flight
+func (rt *SigningRoundTripper) ActiveRequests() int64 {
+	return rt.activeRequests.Load()
+}
+
+// TotalRequests returns the number of requests that have completed, successfully or not
+func (rt *SigningRoundTripper) TotalRequests() int64 {
+	return rt.totalRequests.Load()
+}
+
+// TotalErrors returns the number of requests that failed without a response
+func (rt *SigningRoundTripper) TotalErrors() int64 {
+	return rt.totalErrors.Load()
 }
 
 // RoundTrip implements the http.RoundTripper interface with request signing
-func (rt *SigningRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+func (rt *SigningRoundTripper) RoundTrip(req *http.Request) (resp *http.Response, err error) {
+	// Batches are sent as one request per element, each taking its own slot
+	batch, err := isBatchRequest(req)
+	if batch && err == nil {
+		var messages []json.RawMessage
+		if messages, err = readBatch(req); len(messages) > 0 {
+			return rt.roundTripBatch(req, messages)
+		}
+	}
+	if err != nil {
+		req.Body.Close()
+		return nil, proxyerr.Wrap(proxyerr.SigningFailed, err, "failed to read request body for signing")
+	}
+
+	if rt.Timeline {
+		var timeline *RequestTimeline
+		req, timeline = startTimeline(req)
+		defer func() { rt.finishTimeline(timeline, req, resp, err) }()
+	}
+
+	release, err := rt.acquireSlot(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	rt.activeRequests.Add(1)
+	defer func() {
+		rt.activeRequests.Add(-1)
+		rt.totalRequests.Add(1)
+		if err != nil {
+			rt.totalErrors.Add(1)
+		}
+	}()
+
+	if (rt.SSEMaxReconnects <= 0 && rt.SSEHeartbeatTimeout <= 0) || !isResumableStream(req) {
+		return rt.send(req)
+	}
+
+	// Keep an unsigned copy so a dropped stream can be requested again
+	retry := req.Clone(req.Context())
+	resp, err = rt.send(req)
+	if err != nil || !isEventStream(resp) {
+		return resp, err
+	}
+	resp.Body = newResumableStream(rt, retry, resp.Body)
+	return resp, nil
+}
+
+// send signs and sends a request bounded by the live timeout, recording the
+// response headers for ContextWithResponseHeaders
+func (rt *SigningRoundTripper) send(req *http.Request) (*http.Response, error) {
+	var timeout time.Duration
+	if rt.settings != nil {
+		if s := rt.settings.Load(); s != nil {
+			timeout = s.Timeout
+		}
+	}
+	if override, ok := requestTimeoutFromContext(req.Context()); ok {
+		timeout = override
+	}
+	if timeout <= 0 {
+		resp, err := rt.roundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		recordResponseHeaders(req.Context(), resp.Header)
+		return resp, nil
+	}
+
+	// Bound the request, including reading the response body, by the live timeout
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	resp, err := rt.roundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	recordResponseHeaders(ctx, resp.Header)
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// roundTrip wraps signAndSend in an X-Ray subsegment and a client span when
+// tracing is enabled
+func (rt *SigningRoundTripper) roundTrip(req *http.Request) (*http.Response, error) {
+	if rt.XRay {
+		return rt.xrayRoundTrip(req, rt.spanRoundTrip)
+	}
+	return rt.spanRoundTrip(req)
+}
+
+// spanRoundTrip wraps signAndSend in an OpenTelemetry client span when a
+// tracer is set
+func (rt *SigningRoundTripper) spanRoundTrip(req *http.Request) (*http.Response, error) {
+	if rt.Tracer == nil {
+		return rt.signAndSend(req)
+	}
+
+	// Start a client span and inject its context before signing so the
+	// trace headers become part of the signed header set
+	ctx, span := rt.Tracer.Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.method", req.Method),
+			attribute.String("url.full", req.URL.String()),
+			attribute.String("aws.service", rt.ServiceName),
+		),
+	)
+	defer span.End()
+
+	req = req.WithContext(ctx)
+	if rt.Propagator != nil {
+		rt.Propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	}
+
+	resp, err := rt.signAndSend(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+
+	return resp, nil
+}
+
+// signAndSend adds custom headers, signs the request, and sends it to the target server
+func (rt *SigningRoundTripper) signAndSend(req *http.Request) (*http.Response, error) {
 	// Use the default transport if none is specified
 	transport := rt.Transport
 	if transport == nil {
 		transport = http.DefaultTransport
 	}
 
-	if len(rt.Headers) > 0 {
-		for key, value := range rt.Headers {
-			req.Header.Set(key, value)
+	metrics := rt.Metrics
+	if metrics == nil {
+		metrics = NoopCollector{}
+	}
+	logger := rt.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	start := time.Now()
+
+	headers, limiter := rt.Headers, rt.RateLimiter
+	if rt.settings != nil {
+		if s := rt.settings.Load(); s != nil {
+			headers, limiter = s.Headers, s.RateLimiter
 		}
 	}
 
+	// Wait for the rate limiter before signing so the signature timestamp is fresh
+	if limiter != nil {
+		if err := limiter.Wait(req.Context()); err != nil {
+			metrics.RecordError(ErrorKindRateLimit)
+			return nil, proxyerr.Wrap(proxyerr.RateLimited, err, "rate limiter wait failed")
+		}
+	}
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	// Copy trace context headers from the MCP client so they are covered by the signature
+	propagateHeaders(req, rt.PropagateHeaders)
+
+	// Setting Accept-Encoding disables the transport's transparent decompression,
+	// so gzip responses are decompressed below
+	if rt.DecompressResponse && req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+
+	// Inject the request ID before signing so it is part of the signed header set
+	var requestIDHeader, requestID string
+	if rt.InjectRequestID {
+		requestIDHeader = rt.RequestIDHeader
+		if requestIDHeader == "" {
+			requestIDHeader = DefaultRequestIDHeader
+		}
+		id, err := newRequestID()
+		if err != nil {
+			return nil, err
+		}
+		requestID = id
+		req.Header.Set(requestIDHeader, requestID)
+		logger = logger.With("request_id", requestID)
+	}
+
 	// Read the request body to calculate the payload hash
 	var payloadHash string
-	if req.Body != nil {
-		body, err := io.ReadAll(req.Body)
+	var body []byte
+	switch {
+	case req.Body != nil && !rt.buffersBody():
+		// The payload hash provider decides whether the body is held in memory
+		hash, err := rt.hashBody(req)
+		if err != nil {
+			metrics.RecordError(ErrorKindReadBody)
+			return nil, err
+		}
+		payloadHash = hash
+	case req.Body != nil:
+		var err error
+		body, err = readLimited(req.Body, rt.MaxRequestBodyBytes)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read request body for signing: %w", err)
+			req.Body.Close()
+			metrics.RecordError(ErrorKindReadBody)
+			return nil, proxyerr.Wrap(proxyerr.SigningFailed, err, "failed to read request body for signing")
 		}
 		req.Body.Close() // Close the original body
 
+		// Compress before hashing so the signature covers the bytes sent
+		if rt.CompressRequests && len(body) > 0 {
+			body, err = gzipBody(body)
+			if err != nil {
+				metrics.RecordError(ErrorKindReadBody)
+				return nil, proxyerr.Wrap(proxyerr.SigningFailed, err, "failed to compress request body")
+			}
+			req.Header.Set("Content-Encoding", "gzip")
+		}
+
 		// Calculate SHA256 hash of the payload
-		hash := sha256.Sum256(body)
-		payloadHash = hex.EncodeToString(hash[:])
+		payloadHash, err = rt.hashBufferedBody(body)
+		if err != nil {
+			metrics.RecordError(ErrorKindReadBody)
+			return nil, err
+		}
 
 		// Create a new reader with the body content for the actual request
 		req.Body = io.NopCloser(bytes.NewReader(body))
 		req.ContentLength = int64(len(body))
-	} else {
+	default:
 		// Empty payload hash for requests without a body
-		hash := sha256.Sum256([]byte{})
-		payloadHash = hex.EncodeToString(hash[:])
+		var err error
+		payloadHash, err = rt.hashBufferedBody(nil)
+		if err != nil {
+			return nil, err
+		}
 	}
 
+	send := func(r *http.Request) (*http.Response, error) {
+		resp, err := rt.signAndExecute(transport, r, body, payloadHash, metrics, logger, start)
+		if err != nil || !rt.RefreshOn401 || resp.StatusCode != http.StatusUnauthorized {
+			return resp, err
+		}
+		return rt.refreshOn401(func(retry *http.Request) (*http.Response, error) {
+			return rt.signAndExecute(transport, retry, body, payloadHash, metrics, logger, time.Now())
+		}, r, body, resp, logger)
+	}
+
+	if rt.Shadow != nil && req.Method == http.MethodPost {
+		rt.shadow(req, body, payloadHash, logger)
+	}
+
+	resp, err := send(req)
+	if len(rt.FallbackURLs) > 0 {
+		resp, err = rt.failover(send, req, body, resp, err, logger)
+	}
+	if err != nil {
+		var proxyErr *proxyerr.ProxyError
+		if requestID != "" && errors.As(err, &proxyErr) {
+			proxyErr.RequestID = requestID
+		}
+		return nil, err
+	}
+
+	if requestID != "" {
+		resp.Header.Set(requestIDHeader, requestID)
+	}
+	if rt.isBinaryResponse(resp) {
+		logger.Debug("passing binary response through", "content_type", resp.Header.Get("Content-Type"))
+	} else if rt.DecompressResponse {
+		decompressResponse(resp)
+	}
+	limitResponse(resp, rt.MaxResponseBodyBytes)
+	limitEventStream(resp, rt.MaxSSEEventBytes, rt.MaxSSETotalBytes, logger)
+
+	return resp, nil
+}
+
+// redactHeaders returns a copy of h safe to log. A round tripper created
+// without NewSigningRoundTripper redacts DefaultRedactedHeaders.
+func (rt *SigningRoundTripper) redactHeaders(h http.Header) http.Header {
+	list := rt.RedactedHeaders
+	if list == nil {
+		list = DefaultRedactedHeaders
+	}
+	return redactHeaders(h, list)
+}
+
+// signAndExecute signs a request whose body has already been hashed and sends it
+// to the target server, recording metrics for the attempt
+func (rt *SigningRoundTripper) signAndExecute(transport http.RoundTripper, req *http.Request, body []byte, payloadHash string, metrics MetricsCollector, logger *slog.Logger, start time.Time) (*http.Response, error) {
 	// Sign the request using the context from the request
+	timeline, _ := ParseTimeline(req.Context())
+	if timeline != nil {
+		timeline.mark(&timeline.SigningStart)
+	}
 	if err := rt.Signer.SignRequest(req.Context(), req, payloadHash); err != nil {
-		return nil, fmt.Errorf("AWS signature generation failed: %w", err)
+		metrics.RecordError(ErrorKindSigning)
+		logger.Error("AWS signature generation failed", "method", req.Method, "host", req.URL.Host, "error", err)
+		return nil, proxyerr.Wrap(proxyerr.SigningFailed, err, "AWS signature generation failed")
+	}
+	if timeline != nil {
+		timeline.mark(&timeline.SigningEnd)
+	}
+	metrics.RecordSigningLatency(time.Since(start))
+
+	// DebugAvailable is constant, so production builds compile the dumps out
+	dump := DebugAvailable && rt.DebugMode && logger.Enabled(req.Context(), slog.LevelDebug)
+	if dump {
+		rt.dumpRequest(logger, req, body)
+	}
+
+	if rt.DryRun {
+		return rt.dryRunResponse(req, logger)
 	}
 
 	// Execute the signed request
 	resp, err := transport.RoundTrip(req)
+	if timeline != nil && err == nil {
+		timeline.mark(&timeline.ResponseHeaders)
+	}
+	rt.auditRequest(req, resp, start)
 	if err != nil {
+		metrics.RecordError(ErrorKindNetwork)
+		metrics.RecordRequest(req.Method, "error", time.Since(start))
+		logger.Warn("request to target MCP server failed", "method", req.Method, "host", req.URL.Host, "error", err)
 		// Enhance network error messages
-		return nil, fmt.Errorf("failed to connect to target MCP server at %s: %w", req.URL.Host, err)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, proxyerr.Wrap(proxyerr.Timeout, err, "request to target MCP server at %s timed out", req.URL.Host)
+		}
+		return nil, proxyerr.Wrap(proxyerr.ConnectionFailed, err, "failed to connect to target MCP server at %s", req.URL.Host)
 	}
 
+	metrics.RecordRequest(req.Method, strconv.Itoa(resp.StatusCode), time.Since(start))
+	if dump {
+		rt.dumpResponse(logger, resp)
+	}
+	logger.Debug("signed request completed",
+		"method", req.Method,
+		"url", req.URL.String(),
+		"status", resp.StatusCode,
+		"duration", time.Since(start),
+		"headers", rt.redactHeaders(req.Header),
+	)
 	return resp, nil
 }
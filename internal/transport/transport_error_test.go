@@ -7,6 +7,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/proxyerr"
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -43,7 +45,7 @@ func TestTransportError_NetworkFailure(t *testing.T) {
 			targetURL := tt.setupFunc()
 
 			// Create a mock signer
-			signer := &mockSigner{}
+			signer := &testutil.FakeSigner{}
 
 			// Create the signing round tripper
 			rt := NewSigningRoundTripper(http.DefaultTransport, signer, map[string]string{})
@@ -59,6 +61,10 @@ func TestTransportError_NetworkFailure(t *testing.T) {
 			assert.Error(t, err)
 			assert.Nil(t, resp)
 			assert.Contains(t, err.Error(), tt.wantErr)
+
+			var proxyErr *proxyerr.ProxyError
+			require.True(t, errors.As(err, &proxyErr))
+			assert.Equal(t, proxyerr.ConnectionFailed, proxyErr.Code)
 		})
 	}
 }
@@ -99,9 +105,7 @@ func TestTransportError_SigningFailure(t *testing.T) {
 			defer server.Close()
 
 			// Create a signer that returns an error
-			signer := &mockSigner{
-				signError: tt.signError,
-			}
+			signer := &testutil.FakeSigner{Err: tt.signError}
 
 			// Create the signing round tripper
 			rt := NewSigningRoundTripper(http.DefaultTransport, signer, map[string]string{})
@@ -118,6 +122,11 @@ func TestTransportError_SigningFailure(t *testing.T) {
 			assert.Nil(t, resp)
 			assert.Contains(t, err.Error(), tt.wantErr)
 			assert.Contains(t, err.Error(), tt.signError.Error())
+
+			var proxyErr *proxyerr.ProxyError
+			require.True(t, errors.As(err, &proxyErr))
+			assert.Equal(t, proxyerr.SigningFailed, proxyErr.Code)
+			assert.ErrorIs(t, err, tt.signError)
 		})
 	}
 }
@@ -132,7 +141,7 @@ func TestTransportError_BodyReadFailure(t *testing.T) {
 	defer server.Close()
 
 	// Create a mock signer
-	signer := &mockSigner{}
+	signer := &testutil.FakeSigner{}
 
 	// Create the signing round tripper
 	rt := NewSigningRoundTripper(http.DefaultTransport, signer, map[string]string{})
@@ -148,6 +157,10 @@ func TestTransportError_BodyReadFailure(t *testing.T) {
 	assert.Error(t, err)
 	assert.Nil(t, resp)
 	assert.Contains(t, err.Error(), "failed to read request body for signing")
+
+	var proxyErr *proxyerr.ProxyError
+	require.True(t, errors.As(err, &proxyErr))
+	assert.Equal(t, proxyerr.SigningFailed, proxyErr.Code)
 }
 
 // TestTransportError_TargetServerHTTPError tests that HTTP errors from
@@ -195,7 +208,7 @@ func TestTransportError_TargetServerHTTPError(t *testing.T) {
 			defer server.Close()
 
 			// Create a mock signer
-			signer := &mockSigner{}
+			signer := &testutil.FakeSigner{}
 
 			// Create the signing round tripper
 			rt := NewSigningRoundTripper(http.DefaultTransport, signer, map[string]string{})
@@ -226,7 +239,7 @@ func TestTransportError_NetworkErrorIncludesHost(t *testing.T) {
 	server.Close()
 
 	// Create a mock signer
-	signer := &mockSigner{}
+	signer := &testutil.FakeSigner{}
 
 	// Create the signing round tripper
 	rt := NewSigningRoundTripper(http.DefaultTransport, signer, map[string]string{})
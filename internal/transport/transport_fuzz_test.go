@@ -0,0 +1,76 @@
+package transport
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
+)
+
+// FuzzSigningRoundTripper_RoundTrip verifies that request bodies are buffered
+// and hashed without corruption: the target server must receive exactly the
+// bytes that were sent, and the signer must see their SHA256 hash.
+func FuzzSigningRoundTripper_RoundTrip(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1.0.0"}}}`))
+	f.Add([]byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`))
+	f.Add([]byte(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"echo","arguments":{"text":"héllo, 世界 🌍"}}}`))
+	f.Add([]byte(`[{"jsonrpc":"2.0","id":3,"method":"tools/list"},{"jsonrpc":"2.0","id":4,"method":"prompts/list"}]`))
+	f.Add([]byte{0x00, 0xff, 0xfe, 0x1f, 0x8b, 0x08, 0x00, '\r', '\n'})
+	f.Add([]byte("\xed\xa0\x80\xc3\x28 invalid UTF-8"))
+	f.Add(bytes.Repeat([]byte(`{"key":"value"},`), 1<<16))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Echo the body so the fuzz target can compare what the server received
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	}))
+	f.Cleanup(server.Close)
+
+	signer := &testutil.FakeSigner{}
+	rt := NewSigningRoundTripper(server.Client().Transport, signer, nil)
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		signer.Reset()
+		req, err := http.NewRequest("POST", server.URL, bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := rt.RoundTrip(req)
+		if err == nil && resp == nil {
+			t.Fatal("RoundTrip returned neither a response nor an error")
+		}
+		if err != nil {
+			return
+		}
+		defer resp.Body.Close()
+
+		received, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("failed to read response body: %v", err)
+		}
+		var batch []json.RawMessage
+		if json.Unmarshal(body, &batch) == nil && len(batch) > 0 {
+			// Batches are sent as one request per element; see TestSigningRoundTripper_Batch
+			return
+		}
+		if !bytes.Equal(received, body) {
+			t.Fatalf("server received %d bytes, want the %d bytes sent", len(received), len(body))
+		}
+
+		hash := sha256.Sum256(body)
+		if want := []string{hex.EncodeToString(hash[:])}; !slices.Equal(signer.PayloadHashes(), want) {
+			t.Fatalf("payload hashes = %v, want %v", signer.PayloadHashes(), want)
+		}
+	})
+}
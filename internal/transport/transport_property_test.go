@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/testutil"
 	"pgregory.net/rapid"
 )
 
@@ -47,7 +48,7 @@ func TestProperty7_MessageBodyPreservation(t *testing.T) {
 		defer server.Close()
 
 		// Create the signing round tripper
-		signer := &mockSigner{}
+		signer := &testutil.FakeSigner{}
 		rt := NewSigningRoundTripper(http.DefaultTransport, signer, map[string]string{})
 
 		// Create and execute a request with the generated message body
@@ -69,8 +70,8 @@ func TestProperty7_MessageBodyPreservation(t *testing.T) {
 		}
 
 		// Additional verification: Ensure signing occurred
-		if len(signer.signedRequests) != 1 {
-			t.Fatalf("expected 1 signed request, got %d", len(signer.signedRequests))
+		if len(signer.Requests()) != 1 {
+			t.Fatalf("expected 1 signed request, got %d", len(signer.Requests()))
 		}
 	})
 }
@@ -148,7 +149,7 @@ func TestProperty_SigningPreservesHeaders(t *testing.T) {
 		defer server.Close()
 
 		// Create the signing round tripper
-		signer := &mockSigner{}
+		signer := &testutil.FakeSigner{}
 		rt := NewSigningRoundTripper(http.DefaultTransport, signer, map[string]string{})
 
 		// Create a request with a custom header
@@ -0,0 +1,64 @@
+package transport
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/aws/aws-xray-sdk-go/xray"
+)
+
+// xraySegmentName names the segment started for requests that arrive without
+// one, such as those from the stdio transport
+const xraySegmentName = "mcp-sigv4-proxy"
+
+// WithXRay records an AWS X-Ray subsegment around each request. The segment
+// is taken from the request context, where the X-Ray SDK middleware puts it;
+// requests without one start a new segment. The X-Amzn-Trace-Id header is set
+// before signing, although SigV4 always leaves that header unsigned so load
+// balancers can append to it.
+func WithXRay() Option {
+	return func(rt *SigningRoundTripper) {
+		rt.XRay = true
+	}
+}
+
+// xrayRoundTrip sends req with next inside an X-Ray subsegment named after the
+// target host, recording the target URL, method, status code, and error state
+func (rt *SigningRoundTripper) xrayRoundTrip(req *http.Request, next func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	ctx := req.Context()
+	var seg *xray.Segment
+	if xray.GetSegment(ctx) == nil {
+		ctx, seg = xray.BeginSegment(ctx, xraySegmentName)
+	} else {
+		ctx, seg = xray.BeginSubsegment(ctx, req.URL.Host)
+	}
+
+	seg.Lock()
+	seg.Namespace = "remote"
+	seg.GetHTTP().GetRequest().Method = req.Method
+	seg.GetHTTP().GetRequest().URL = traceURL(req.URL)
+	traceHeader := seg.DownstreamHeader().String()
+	seg.Unlock()
+
+	req = req.WithContext(ctx)
+	req.Header.Set(xray.TraceIDHeaderKey, traceHeader)
+
+	resp, err := next(req)
+	if resp != nil {
+		seg.Lock()
+		seg.GetHTTP().GetResponse().Status = resp.StatusCode
+		seg.Error = resp.StatusCode >= http.StatusBadRequest && resp.StatusCode < http.StatusInternalServerError
+		seg.Throttle = resp.StatusCode == http.StatusTooManyRequests
+		seg.Fault = resp.StatusCode >= http.StatusInternalServerError
+		seg.Unlock()
+	}
+	seg.Close(err)
+	return resp, err
+}
+
+// traceURL returns u without its query, which may hold credentials
+func traceURL(u *url.URL) string {
+	stripped := *u
+	stripped.RawQuery = ""
+	return stripped.String()
+}
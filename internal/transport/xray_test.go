@@ -0,0 +1,132 @@
+package transport
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-xray-sdk-go/strategy/sampling"
+	"github.com/aws/aws-xray-sdk-go/xray"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingEmitter collects the segments X-Ray would send to the daemon
+type recordingEmitter struct {
+	mu       sync.Mutex
+	segments []*xray.Segment
+}
+
+func (e *recordingEmitter) Emit(seg *xray.Segment) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.segments = append(e.segments, seg)
+}
+
+func (e *recordingEmitter) RefreshEmitterWithAddress(*net.UDPAddr) {}
+
+// xrayContext returns a context whose X-Ray recorder samples every request
+// and records emitted segments
+func xrayContext(t *testing.T) (context.Context, *recordingEmitter) {
+	t.Helper()
+	strategy, err := sampling.NewLocalizedStrategyFromJSONBytes([]byte(`{"version": 2, "default": {"fixed_target": 1, "rate": 1}}`))
+	require.NoError(t, err)
+	emitter := &recordingEmitter{}
+	ctx, err := xray.ContextWithConfig(context.Background(), xray.Config{
+		Emitter:          emitter,
+		SamplingStrategy: strategy,
+	})
+	require.NoError(t, err)
+	return ctx, emitter
+}
+
+func TestSigningRoundTripper_WithXRay(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		wantError bool
+		wantFault bool
+	}{
+		{name: "successful response", status: http.StatusOK},
+		{name: "client error", status: http.StatusForbidden, wantError: true},
+		{name: "server error", status: http.StatusBadGateway, wantFault: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var receivedTraceID string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				receivedTraceID = r.Header.Get("X-Amzn-Trace-Id")
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			ctx, emitter := xrayContext(t)
+			signer := &headerCapturingSigner{}
+			rt := NewSigningRoundTripper(http.DefaultTransport, signer, nil, WithXRay())
+
+			req, err := http.NewRequestWithContext(ctx, "POST", server.URL+"/mcp?token=secret", strings.NewReader(`{"test":"data"}`))
+			require.NoError(t, err)
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			// The trace header is set before signing and reaches the target
+			traceID := signer.headers.Get("X-Amzn-Trace-Id")
+			assert.Contains(t, traceID, "Root=")
+			assert.Equal(t, traceID, receivedTraceID)
+
+			// Without a segment in the context the request starts its own
+			require.Len(t, emitter.segments, 1)
+			seg := emitter.segments[0]
+			assert.Equal(t, xraySegmentName, seg.Name)
+			assert.Contains(t, traceID, seg.TraceID)
+			assert.Equal(t, "POST", seg.GetHTTP().GetRequest().Method)
+			assert.Equal(t, server.URL+"/mcp", seg.GetHTTP().GetRequest().URL)
+			assert.Equal(t, tt.status, seg.GetHTTP().GetResponse().Status)
+			assert.Equal(t, tt.wantError, seg.Error)
+			assert.Equal(t, tt.wantFault, seg.Fault)
+		})
+	}
+}
+
+func TestSigningRoundTripper_WithXRaySubsegment(t *testing.T) {
+	var receivedTraceID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedTraceID = r.Header.Get("X-Amzn-Trace-Id")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// A segment in the context, as set by the X-Ray middleware, is the parent
+	ctx, _ := xrayContext(t)
+	ctx, parent := xray.BeginSegment(ctx, "incoming")
+	defer parent.Close(nil)
+
+	rt := NewSigningRoundTripper(http.DefaultTransport, &headerCapturingSigner{}, nil, WithXRay())
+	req, err := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	require.NoError(t, err)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Contains(t, receivedTraceID, "Root="+parent.TraceID)
+	assert.NotContains(t, receivedTraceID, "Parent="+parent.ID, "the target's parent should be the subsegment")
+}
+
+func TestSigningRoundTripper_WithXRayError(t *testing.T) {
+	ctx, emitter := xrayContext(t)
+	rt := NewSigningRoundTripper(http.DefaultTransport, &headerCapturingSigner{}, nil, WithXRay())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", "http://127.0.0.1:1", nil)
+	require.NoError(t, err)
+	_, err = rt.RoundTrip(req)
+	require.Error(t, err)
+
+	require.Len(t, emitter.segments, 1)
+	assert.True(t, emitter.segments[0].Fault)
+}
@@ -2,19 +2,32 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"runtime"
 	"strings"
+	"sync"
 	"syscall"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-xray-sdk-go/xray"
+	"github.com/aws/aws-xray-sdk-go/xraylog"
 	"github.com/nisimpson/mcp-sigv4-proxy/internal/config"
 	"github.com/nisimpson/mcp-sigv4-proxy/internal/credentials"
 	"github.com/nisimpson/mcp-sigv4-proxy/internal/proxy"
 	"github.com/nisimpson/mcp-sigv4-proxy/internal/signer"
 	"github.com/nisimpson/mcp-sigv4-proxy/internal/transport"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 const (
@@ -22,35 +35,129 @@ const (
 	serverVersion = "v1.0.0"
 )
 
+// Logging flags are parsed together with the configuration flags in config.Load
+var (
+	logFormat = flag.String("log-format", "text", "log output format (json or text)")
+	logLevel  = flag.String("log-level", "info", "minimum log level (debug, info, warn, or error)")
+)
+
+// showVersion is parsed with the configuration flags and works without a valid configuration
+var showVersion = flag.Bool("version", false, "print version information and exit")
+
+// failFast is parsed with the configuration flags and stops startup at the
+// first failed dependency check instead of reporting every failed check
+var failFast = flag.Bool("fail-fast", false, "stop startup at the first failed configuration, credential, identity, or DNS check")
+
+// explainConfig is parsed with the configuration flags and prints the source of
+// each configuration field, even when the configuration is invalid
+var explainConfig = flag.Bool("explain-config", false, "print the value and source of each configuration field and exit")
+
 func main() {
-	// Set up structured logging
-	logger := log.New(os.Stderr, "", log.LstdFlags)
+	// Set up structured logging; this is replaced once the logging flags are parsed
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 
-	// Run the proxy and handle errors
+	// Subcommands run in place of the proxy; their flags follow the subcommand name
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "validate":
+			os.Args = append(os.Args[:1], os.Args[2:]...)
+			if !runValidate(os.Stdout, logger) {
+				os.Exit(1)
+			}
+			return
+		case "dump-config":
+			os.Args = append(os.Args[:1], os.Args[2:]...)
+			if err := runDumpConfig(os.Stdout, logger); err != nil {
+				logger.Error("failed to load configuration", "error", err)
+				os.Exit(1)
+			}
+			return
+		case "check-credentials":
+			if err := runCheckCredentials(context.Background(), os.Args[2:], os.Stdout); err != nil {
+				logger.Error("credential check failed", "error", err)
+				os.Exit(1)
+			}
+			return
+		case "presign":
+			if err := runPresign(context.Background(), os.Args[2:], os.Stdout); err != nil {
+				logger.Error("presigning failed", "error", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	// Run the proxy and handle errors; startup failures are logged with their phase as they happen
 	if err := run(logger); err != nil {
-		logger.Printf("ERROR: %v\n", err)
+		var startupErr *startupError
+		if !errors.As(err, &startupErr) {
+			logger.Error("proxy exited with error", "error", err)
+		}
 		os.Exit(1)
 	}
 }
 
-// run contains the main application logic
-func run(logger *log.Logger) error {
-	logger.Printf("AWS SigV4 Signing Proxy MCP Server v%s\n", serverVersion)
+// run contains the main application logic. Startup runs as a numbered
+// sequence of phases, each logged as it begins; see startup.
+func run(logger *slog.Logger) error {
+	// The command line is parsed here, so the logging flags are known before phase 1
+	builder := config.CommandLineBuilder()
+	if *showVersion {
+		fmt.Println(versionString())
+		return nil
+	}
+	s := &startup{logger: logger, failFast: *failFast}
 
-	// Load configuration from environment variables and command-line flags
-	logger.Println("Loading configuration...")
-	cfg, err := config.Load(logger)
+	// Phase 1: load configuration from environment variables, files, and command-line flags
+	configured, err := newLogger(os.Stderr, *logFormat, *logLevel)
 	if err != nil {
-		return fmt.Errorf("configuration error: %w", err)
+		return s.abort(phaseLoadConfig, fmt.Errorf("configuration error: %w", err))
+	}
+	*logger = *configured
+	s.begin(phaseLoadConfig)
+	cfg, err := builder.Build()
+	if *explainConfig && cfg != nil {
+		fmt.Print(builder.ExplainAll())
+		return nil
+	}
+	if cfg == nil {
+		return s.abort(phaseLoadConfig, fmt.Errorf("configuration error: %w", err))
 	}
 
-	logger.Printf("Configuration loaded successfully:")
-	logger.Printf("  Target URL: %s", cfg.TargetURL)
-	logger.Printf("  Region: %s", cfg.Region)
-	logger.Printf("  Service: %s", cfg.ServiceName)
-	logger.Printf("  Signature Version: %s", cfg.SignatureVersion)
-	logger.Printf("  Profile: %s", cfg.Profile)
-	logger.Printf("  EnableSSE: %v", cfg.EnableSSE)
+	// Phase 2: validate the configuration; Build returns it alongside any validation error
+	s.begin(phaseValidateConfig)
+	if err := s.check(phaseValidateConfig, err); err != nil {
+		return err
+	}
+	if cfg.DebugMode {
+		if !transport.DebugAvailable {
+			return s.abort(phaseValidateConfig, errors.New("configuration error: debug mode is not available in production builds"))
+		}
+		configured, err := newLogger(os.Stderr, *logFormat, "debug")
+		if err != nil {
+			return s.abort(phaseValidateConfig, fmt.Errorf("configuration error: %w", err))
+		}
+		*logger = *configured
+	}
+
+	logger.Info("AWS SigV4 Signing Proxy MCP Server",
+		"version", Version,
+		"commit", Commit,
+		"build_date", BuildDate,
+		"go_version", runtime.Version(),
+	)
+	logger.Info("configuration loaded",
+		"target_url", cfg.TargetURL,
+		"region", cfg.Region,
+		"service", cfg.ServiceName,
+		"signature_version", cfg.SignatureVersion,
+		"profile", cfg.Profile,
+		"role_arn", cfg.RoleARN,
+		"enable_sse", cfg.EnableSSE,
+	)
+	if active := cfg.ActiveFeatureFlags(); len(active) > 0 {
+		logger.Info("feature flags enabled", "flags", active)
+	}
 
 	// Create context that can be cancelled on shutdown signals
 	ctx, cancel := context.WithCancel(context.Background())
@@ -61,94 +168,587 @@ func run(logger *log.Logger) error {
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		sig := <-sigChan
-		logger.Printf("Received signal %v, shutting down gracefully...", sig)
+		logger.Info("received signal, shutting down gracefully", "signal", sig.String())
 		cancel()
 	}()
 
-	// Initialize AWS credentials
-	logger.Println("Loading AWS credentials...")
+	// Phase 3: initialize AWS credentials
+	s.begin(phaseLoadCredentials)
 	credProvider := &credentials.Provider{
-		Profile: cfg.Profile,
-		Region:  cfg.Region,
+		Profile:               cfg.Profile,
+		Region:                cfg.Region,
+		AssumeRoleARN:         cfg.RoleARN,
+		AssumeRoleExternalID:  cfg.ExternalID,
+		AssumeRoleSessionName: cfg.RoleSessionName,
+		AssumeRoleDuration:    cfg.RoleSessionDuration,
+		AssumeRoleSessionTags: cfg.RoleSessionTags,
+		CredentialProcess:     cfg.CredentialProcess,
+		CredentialSources:     cfg.CredentialSources,
+		Vault:                 vaultProvider(cfg.VaultAddr, cfg.VaultPath),
+		SSOStartURL:           cfg.SSOStartURL,
+		SSOAccountID:          cfg.SSOAccountID,
+		SSORoleName:           cfg.SSORoleName,
+		SSORegion:             cfg.SSORegion,
+		WebIdentityTokenFile:  cfg.WebIdentityTokenFile,
+		WebIdentityRoleARN:    cfg.WebIdentityRoleARN,
+		Logger:                logger,
 	}
 
-	creds, err := credProvider.LoadCredentials(ctx)
+	var creds aws.Credentials
+	awsCfg, err := credProvider.LoadConfig(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to load AWS credentials: %w (ensure AWS credentials are configured via environment variables, ~/.aws/credentials, or IAM role)", err)
+		err = fmt.Errorf("failed to load AWS credentials: %w (ensure AWS credentials are configured via environment variables, ~/.aws/credentials, or IAM role)", err)
+	} else if creds, err = awsCfg.Credentials.Retrieve(ctx); err != nil {
+		err = fmt.Errorf("failed to load AWS credentials: %w", err)
+	}
+	credentialsLoaded := err == nil
+	if err := s.check(phaseLoadCredentials, err); err != nil {
+		return err
+	}
+	if credentialsLoaded {
+		// Mask the access key in logs for security; the secret key and session token are never logged.
+		// The source shows which provider in the chain supplied the credentials.
+		logger.Info("AWS credentials loaded",
+			"source", creds.Source,
+			"access_key", maskAccessKey(creds.AccessKeyID),
+			"session_token_present", creds.SessionToken != "",
+		)
+	}
+
+	// Phase 4: confirm the credentials are accepted by AWS before serving requests
+	s.begin(phaseValidateIdentity)
+	switch {
+	case cfg.SkipIdentityValidation:
+		logger.Info("skipping AWS identity validation")
+	case !credentialsLoaded:
+		logger.Info("skipping AWS identity validation; credentials were not loaded")
+	default:
+		identity, err := credentials.IdentityFromConfig(ctx, awsCfg)
+		if err != nil {
+			err = fmt.Errorf("AWS identity validation failed: %w (check that the credentials are valid and IAM policies allow sts:GetCallerIdentity, or use --skip-identity-check)", err)
+			if err := s.check(phaseValidateIdentity, err); err != nil {
+				return err
+			}
+			break
+		}
+		identity = maskIdentity(identity)
+		logger.Info("AWS identity validated",
+			"account", identity.Account,
+			"arn", identity.Arn,
+			"user_id", identity.UserID,
+		)
 	}
 
-	// Mask the secret key in logs for security
-	logger.Printf("AWS credentials loaded successfully (Access Key: %s...)", maskAccessKey(creds.AccessKeyID))
-	if creds.SessionToken != "" {
-		logger.Println("  Session token present")
+	// Phase 5: resolve the target hostname, unless requests go through an
+	// outbound proxy that resolves it or are never sent
+	s.begin(phaseResolveTarget)
+	switch {
+	case cfg.DryRun:
+		logger.Info("skipping target hostname resolution in dry run mode")
+	case cfg.ProxyURL != "":
+		logger.Info("skipping target hostname resolution; requests go through an outbound proxy")
+	default:
+		if err := s.check(phaseResolveTarget, resolveTargetHost(ctx, cfg.TargetURL, logger)); err != nil {
+			return err
+		}
 	}
 
+	// Without fail-fast the dependency checks all run; stop now if any failed
+	if err := s.checked(); err != nil {
+		return err
+	}
+
+	// Phase 6: create the signer, transport, and proxy server
+	s.begin(phaseCreateProxy)
+
+	// Warn before temporary credentials expire mid-session
+	watchCredentialExpiry(ctx, awsCfg.Credentials, cfg.CredentialWarnCheckInterval, cfg.CredentialWarnThreshold, logger)
+
 	// Create the appropriate signer based on signature version
 	var sig signer.Signer
 	switch cfg.SignatureVersion {
 	case "v4":
-		logger.Println("Using AWS Signature Version 4 (SigV4)")
+		logger.Info("using AWS Signature Version 4 (SigV4)")
 		sig = &signer.V4Signer{
-			Credentials: creds,
-			Region:      cfg.Region,
-			Service:     cfg.ServiceName,
+			CredentialsProvider: awsCfg.Credentials,
+			Region:              cfg.Region,
+			Service:             cfg.ServiceName,
+			ExtraSignedHeaders:  cfg.ExtraSignedHeaders,
+			SigningHost:         cfg.SigningHost,
 		}
 	case "v4a":
-		logger.Println("Using AWS Signature Version 4A (SigV4a)")
+		logger.Info("using AWS Signature Version 4A (SigV4a)")
 		sig = &signer.V4aSigner{
-			Credentials: creds,
-			Region:      cfg.Region,
-			Service:     cfg.ServiceName,
+			CredentialsProvider: awsCfg.Credentials,
+			Region:              cfg.Region,
+			Service:             cfg.ServiceName,
 		}
 	default:
-		return fmt.Errorf("unsupported signature version: %s (must be 'v4' or 'v4a')", cfg.SignatureVersion)
+		return s.abort(phaseCreateProxy, fmt.Errorf("unsupported signature version: %s (must be 'v4' or 'v4a')", cfg.SignatureVersion))
+	}
+
+	// Load TLS settings for connections to the target server
+	tlsConfig, err := buildTLSConfig(cfg, logger)
+	if err != nil {
+		return s.abort(phaseCreateProxy, fmt.Errorf("TLS configuration error: %w", err))
+	}
+
+	// The X-Ray SDK logs to stdout by default, which carries the MCP protocol
+	if cfg.XRayEnabled {
+		xray.SetLogger(xraylog.NewDefaultLogger(os.Stderr, xraylog.LogLevelWarn))
+		logger.Info("AWS X-Ray tracing enabled")
 	}
 
 	// Create the signing transport
 	signingTransport := &transport.SigningTransport{
-		TargetURL:  cfg.TargetURL,
-		Signer:     sig,
-		EnableSSE:  cfg.EnableSSE,
-		HTTPClient: &http.Client{Timeout: cfg.Timeout},
-		Headers:    make(map[string]string),
+		TargetURL:       cfg.TargetURL,
+		Signer:          sig,
+		EnableSSE:       cfg.EnableSSE,
+		HTTPClient:      &http.Client{Timeout: cfg.Timeout},
+		Headers:         cfg.HeaderMap(),
+		ServiceName:     cfg.ServiceName,
+		Logger:          logger,
+		TLSConfig:       tlsConfig,
+		ProxyURL:        cfg.ProxyURL,
+		StripPathPrefix: cfg.StripPathPrefix,
+		PathRewrite:     transport.PathRewrite(cfg.PathRewrite),
+		InjectRequestID: cfg.InjectRequestID,
+		XRayEnabled:     cfg.XRayEnabled,
+		FallbackURLs:    cfg.FallbackURLs,
+		FailoverTimeout: cfg.FailoverTimeout,
 	}
-
-	if cfg.Headers != "" {
-		tokens := strings.Split(cfg.Headers, ",")
-		for _, token := range tokens {
-			pair := strings.Split(token, "=")
-			signingTransport.Headers[pair[0]] = pair[1]
+	signingTransport.SSEHeartbeatTimeout = cfg.SSEHeartbeat
+	signingTransport.MaxRequestBodyBytes = cfg.MaxRequestBody
+	signingTransport.MaxResponseBodyBytes = cfg.MaxResponseBody
+	signingTransport.MaxSSEEventBytes = cfg.MaxSSEEventBytes
+	signingTransport.MaxSSETotalBytes = cfg.MaxSSETotalBytes
+	signingTransport.RefreshOn401 = cfg.RefreshOn401
+	signingTransport.CompressRequests = cfg.GzipRequests
+	signingTransport.DecompressResponse = cfg.GzipResponses
+	signingTransport.BinaryContentTypes = cfg.BinaryContentTypes
+	signingTransport.DebugMode = cfg.DebugMode
+	signingTransport.EnableTimeline = cfg.EnableTimeline
+	signingTransport.TCPKeepAlive = cfg.TCPKeepAlive
+	signingTransport.TCPKeepAliveInterval = cfg.TCPProbeInterval
+	signingTransport.TCPKeepAliveCount = cfg.TCPProbeCount
+	signingTransport.DialTimeout = cfg.DialTimeout
+	signingTransport.TLSHandshakeTimeout = cfg.TLSHandshakeTimeout
+	signingTransport.ResponseHeaderTimeout = cfg.ResponseHeaderTimeout
+	signingTransport.IdleConnectionTimeout = cfg.IdleConnectionTimeout
+	signingTransport.DNSCacheTTL = cfg.DNSCacheTTL
+	signingTransport.MaxIdleConns = cfg.MaxIdleConns
+	signingTransport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	signingTransport.MaxConnsPerHost = cfg.MaxConnsPerHost
+	signingTransport.MaxConcurrentRequests = cfg.MaxConcurrentRequests
+	signingTransport.ForceHTTP2 = cfg.ForceHTTP2
+	signingTransport.AllowH2C = cfg.AllowH2C
+	signingTransport.HTTP2KeepAlivePingInterval = cfg.HTTP2PingInterval
+	signingTransport.HTTP2KeepAlivePingTimeout = cfg.HTTP2PingTimeout
+	if cfg.SSEMaxReconnects > 0 {
+		signingTransport.SSEMaxReconnects = cfg.SSEMaxReconnects
+		signingTransport.SSEReconnectDelay = cfg.SSERetryDelay
+		signingTransport.SSEMaxReconnectDelay = cfg.SSEMaxRetryDelay
+	}
+	if cfg.ShadowTargetURL != "" {
+		// A short timeout keeps slow shadow requests from piling up
+		signingTransport.Shadow = &transport.SigningTransport{
+			TargetURL:  cfg.ShadowTargetURL,
+			Signer:     sig,
+			HTTPClient: &http.Client{Timeout: transport.DefaultShadowTimeout},
 		}
+		logger.Info("shadowing requests to secondary target", "url", cfg.ShadowTargetURL)
+	}
+	if cfg.AuditLogPath != "" {
+		auditLogger, err := transport.NewFileAuditLogger(cfg.AuditLogPath, logger)
+		if err != nil {
+			return s.abort(phaseCreateProxy, fmt.Errorf("audit log error: %w", err))
+		}
+		defer auditLogger.Close()
+		signingTransport.AuditLogger = auditLogger
+		logger.Info("audit logging enabled", "file", cfg.AuditLogPath)
+	}
+	var collectors transport.MultiCollector
+	if cfg.StatsDAddr != "" {
+		collector, err := transport.NewStatsDCollector(cfg.StatsDAddr, cfg.StatsDPrefix, cfg.StatsDSampleRate)
+		if err != nil {
+			return s.abort(phaseCreateProxy, fmt.Errorf("StatsD error: %w", err))
+		}
+		// Flush buffered metrics when the proxy shuts down
+		defer collector.Close()
+		collectors = append(collectors, collector)
+		logger.Info("StatsD metrics enabled", "addr", cfg.StatsDAddr)
+	}
+	if cfg.MetricsAddr != "" {
+		collector, err := transport.NewPrometheusCollector(nil)
+		if err != nil {
+			return s.abort(phaseCreateProxy, fmt.Errorf("Prometheus error: %w", err))
+		}
+		collectors = append(collectors, collector)
+	}
+	switch len(collectors) {
+	case 0:
+	case 1:
+		signingTransport.Metrics = collectors[0]
+	default:
+		signingTransport.Metrics = collectors
+	}
+	if cfg.DebugMode {
+		logger.Warn("debug mode enabled, signed requests and responses will be logged")
+	}
+	if cfg.RateLimitRPS > 0 {
+		signingTransport.RateLimiter = transport.NewTokenBucketLimiter(cfg.RateLimitRPS, cfg.RateLimitBurst)
+		logger.Info("rate limiting enabled", "rps", cfg.RateLimitRPS, "burst", cfg.RateLimitBurst)
+	}
+
+	// Experimental features stay off until their feature flag is enabled
+	enableDeduplication := experimentalFeature(cfg, config.FeatureDeduplication, cfg.EnableDeduplication, logger)
+	responseCacheMaxEntries := cfg.ResponseCacheMaxEntries
+	if !experimentalFeature(cfg, config.FeatureResponseCache, responseCacheMaxEntries > 0, logger) {
+		responseCacheMaxEntries = 0
 	}
 
 	// Create the proxy server
-	logger.Println("Creating proxy server...")
+	logger.Debug("creating proxy server")
 	proxyServer, err := proxy.New(proxy.Config{
-		Transport:     signingTransport,
-		ServerName:    serverName,
-		ServerVersion: serverVersion,
+		Transport:                signingTransport,
+		ServerName:               serverName,
+		ServerVersion:            Version,
+		Logger:                   logger,
+		ProxyConfig:              cfg,
+		SkipHealthCheck:          cfg.SkipHealthCheck,
+		HealthCheckPath:          cfg.HealthCheckPath,
+		RefreshInterval:          cfg.RefreshInterval,
+		PingInterval:             cfg.PingInterval,
+		PingTimeout:              cfg.PingTimeout,
+		EnableSamplingForwarding: cfg.EnableSampling,
+		EnableRootsForwarding:    cfg.EnableRoots,
+		EnableLogForwarding:      cfg.EnableLogForwarding,
+		IsolatedSessions:         cfg.IsolatedSessions,
+		ListenAddr:               cfg.ListenAddr,
+		ToolNamePrefix:           cfg.ToolNamePrefix,
+		PromptNamePrefix:         cfg.PromptNamePrefix,
+		ForwardResponseHeaders:   cfg.ForwardResponseHeaders,
+		ValidateToolArguments:    cfg.ValidateToolArguments,
+		EnableDeduplication:      enableDeduplication,
+		DrainTimeout:             cfg.DrainTimeout,
+		MethodTimeouts:           cfg.MethodTimeouts,
+		DryRun:                   cfg.DryRun,
+		ResponseCache: proxy.ResponseCacheConfig{
+			MaxEntries:       responseCacheMaxEntries,
+			TTL:              cfg.ResponseCacheTTL,
+			CacheableMethods: cfg.ResponseCacheMethods,
+		},
+		ToolResultCache: proxy.ToolResultCacheConfig{
+			DefaultTTL:     cfg.ToolCacheTTL,
+			PerToolTTL:     cfg.ToolCacheToolTTLs,
+			MaxEntries:     cfg.ToolCacheMaxEntries,
+			NoCacheMethods: cfg.ToolCacheExclude,
+		},
+		WarmCacheOnStartup: warmupCalls(cfg.ToolCacheWarmup),
+		PerClientRateLimit: proxy.PerClientRateLimit{
+			RPS:   cfg.ClientRateLimitRPS,
+			Burst: cfg.ClientRateLimitBurst,
+		},
+		ClientLimiterEvictionIdle: cfg.ClientLimiterIdle,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to create proxy server: %w", err)
+		return s.abort(phaseCreateProxy, fmt.Errorf("failed to create proxy server: %w", err))
 	}
 
-	// Start the proxy server
-	logger.Println("Starting proxy server on stdio...")
-	logger.Println("Proxy is ready to accept MCP protocol messages")
+	// Reload the configuration file and config source on SIGHUP, and when a
+	// Secrets Manager config source is rotated
+	if cfg.ConfigFile != "" || cfg.ConfigSource != "" {
+		watchConfigReload(ctx, builder, proxyServer, logger)
+	}
+	if cfg.ConfigSource != "" {
+		err := config.WatchConfigSource(ctx, cfg.ConfigSource, cfg, logger, func() {
+			reloadConfig(builder, proxyServer, logger)
+		})
+		if err != nil {
+			return s.abort(phaseCreateProxy, fmt.Errorf("failed to watch config source: %w", err))
+		}
+	}
+
+	// Serve Prometheus metrics, separately from the MCP traffic, until the proxy stops
+	if cfg.MetricsAddr != "" {
+		stopMetrics, err := serveMetrics(cfg.MetricsAddr, cfg.DrainTimeout, logger)
+		if err != nil {
+			return s.abort(phaseCreateProxy, err)
+		}
+		defer stopMetrics()
+	}
+
+	// Serve health probes while the proxy connects and runs
+	if cfg.ProbeAddr != "" {
+		stopProbes, err := serveProbes(cfg.ProbeAddr, proxyServer, logger)
+		if err != nil {
+			return s.abort(phaseCreateProxy, err)
+		}
+		defer stopProbes()
+	}
+
+	// Phase 7: start the proxy server
+	s.begin(phaseRunServer)
+	switch {
+	case cfg.DryRun:
+		logger.Info("dry run mode, requests will be signed but not sent")
+	case cfg.ListenAddr != "":
+		logger.Info("starting proxy server on WebSocket", "addr", cfg.ListenAddr)
+	default:
+		logger.Info("starting proxy server on stdio")
+	}
 
 	if err := proxyServer.Run(ctx); err != nil {
 		// Check if this is a graceful shutdown
 		if ctx.Err() == context.Canceled {
-			logger.Println("Proxy server stopped gracefully")
+			logger.Info("proxy server stopped gracefully")
 			return nil
 		}
-		return fmt.Errorf("proxy server error: %w", err)
+		return s.abort(phaseRunServer, fmt.Errorf("proxy server error: %w", err))
 	}
 
-	logger.Println("Proxy server stopped")
+	logger.Info("proxy server stopped")
 	return nil
 }
 
+// serveProbes starts an HTTP server on addr for the proxy's /live and /ready
+// health probes, returning a function that stops it
+func serveProbes(addr string, proxyServer *proxy.Proxy, logger *slog.Logger) (func(), error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for health probes on %s: %w", addr, err)
+	}
+	server := &http.Server{
+		Handler:           proxyServer.ProbeHandler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("health probe server failed", "error", err)
+		}
+	}()
+	logger.Info("serving health probes", "addr", listener.Addr().String())
+	return func() { _ = server.Close() }, nil
+}
+
+// serveMetrics starts an HTTP server on addr serving the Prometheus default
+// gatherer at /metrics, returning a function that stops it. Like the proxy,
+// the server stops gracefully, giving in-flight scrapes up to drainTimeout.
+func serveMetrics(addr string, drainTimeout time.Duration, logger *slog.Logger) (func(), error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for metrics on %s: %w", addr, err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("metrics server failed", "error", err)
+		}
+	}()
+	logger.Info("serving Prometheus metrics", "addr", listener.Addr().String(), "path", "/metrics")
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			_ = server.Close()
+		}
+	}, nil
+}
+
+// watchConfigReload re-reads the configuration file and config source whenever
+// the process receives SIGHUP and applies the reloadable settings to the
+// running proxy. Invalid configurations are logged and ignored.
+func watchConfigReload(ctx context.Context, builder *config.Builder, proxyServer *proxy.Proxy, logger *slog.Logger) {
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hupChan)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hupChan:
+				logger.Info("received SIGHUP, reloading configuration")
+				reloadConfig(builder, proxyServer, logger)
+			}
+		}
+	}()
+}
+
+// reloadMu serializes configuration reloads, which SIGHUP and config source
+// rotation can trigger at the same time
+var reloadMu sync.Mutex
+
+// reloadConfig rebuilds the configuration from scratch with builder, re-reading
+// the environment, configuration file, and config source and then re-applying
+// the command-line flags parsed at startup, so flags still take precedence and
+// keys removed from the file fall back to their environment or default values.
+// The result is applied to the running proxy.
+func reloadConfig(builder *config.Builder, proxyServer *proxy.Proxy, logger *slog.Logger) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	current := proxyServer.Config()
+	logger.Info("reloading configuration", "file", current.ConfigFile, "source", current.ConfigSource)
+
+	next, err := builder.Build()
+	if err != nil {
+		logger.Error("configuration reload failed, keeping current configuration", "error", err)
+		return
+	}
+
+	if changes := proxyServer.Reload(next); len(changes) == 0 {
+		logger.Info("configuration reloaded with no changes")
+	}
+}
+
+// experimentalFeature reports whether an experimental feature that is
+// configured may be activated, warning when its feature flag is off
+func experimentalFeature(cfg *config.Config, flag string, configured bool, logger *slog.Logger) bool {
+	if !configured {
+		return false
+	}
+	if !cfg.FeatureEnabled(flag) {
+		logger.Warn("experimental feature is configured but its feature flag is off, leaving it disabled", "flag", flag)
+		return false
+	}
+	return true
+}
+
+// warmupCalls converts the configured tool cache warmup calls for the proxy
+func warmupCalls(calls []config.WarmupCall) []proxy.WarmupCall {
+	var converted []proxy.WarmupCall
+	for _, call := range calls {
+		converted = append(converted, proxy.WarmupCall{ToolName: call.Tool, Args: call.Arguments})
+	}
+	return converted
+}
+
+// buildTLSConfig creates the TLS configuration for connections to the target server.
+// It returns nil when no TLS settings are configured so Go defaults are used.
+func buildTLSConfig(cfg *config.Config, logger *slog.Logger) (*tls.Config, error) {
+	if cfg.TLSCAFile == "" && cfg.ClientCertFile == "" && !cfg.TLSSkipVerify {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if cfg.TLSCAFile != "" {
+		pem, err := os.ReadFile(cfg.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file %s: %w", cfg.TLSCAFile, err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid certificates found in CA file %s", cfg.TLSCAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCertFile != "" {
+		cert, err := loadClientCertificate(cfg.ClientCertFile, cfg.ClientKeyFile, logger)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if cfg.TLSSkipVerify {
+		logger.Warn("!!! TLS CERTIFICATE VERIFICATION IS DISABLED !!! " +
+			"Connections to the target server are vulnerable to man-in-the-middle attacks; " +
+			"do not use --tls-skip-verify in production")
+		tlsConfig.InsecureSkipVerify = true //nolint:gosec // explicitly requested via --tls-skip-verify
+	}
+
+	return tlsConfig, nil
+}
+
+// clientCertExpiryWarning is how far ahead of expiry a client certificate triggers a warning
+const clientCertExpiryWarning = 30 * 24 * time.Hour
+
+// loadClientCertificate loads the mutual TLS certificate/key pair and logs its
+// subject and expiry. It warns when the certificate is close to expiring.
+func loadClientCertificate(certFile, keyFile string, logger *slog.Logger) (tls.Certificate, error) {
+	if keyFile == "" {
+		return tls.Certificate{}, errors.New("client key file is required when a client certificate is set")
+	}
+
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to read client certificate file %s: %w", certFile, err)
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to read client key file %s: %w", keyFile, err)
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("failed to load client certificate (check that %s and %s are a matching PEM pair): %w", certFile, keyFile, err)
+	}
+
+	leaf := cert.Leaf
+	if leaf == nil {
+		leaf, err = x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("failed to parse client certificate: %w", err)
+		}
+	}
+
+	// The target would reject every handshake with a certificate outside its validity period
+	now := time.Now()
+	if now.After(leaf.NotAfter) {
+		return tls.Certificate{}, fmt.Errorf("TLS client certificate %s (CN %s) expired at %s",
+			certFile, leaf.Subject.CommonName, leaf.NotAfter.Format(time.RFC3339))
+	}
+	if now.Before(leaf.NotBefore) {
+		return tls.Certificate{}, fmt.Errorf("TLS client certificate %s (CN %s) is not valid until %s",
+			certFile, leaf.Subject.CommonName, leaf.NotBefore.Format(time.RFC3339))
+	}
+
+	logger.Info("loaded TLS client certificate",
+		"subject_cn", leaf.Subject.CommonName,
+		"expires", leaf.NotAfter.Format(time.RFC3339),
+	)
+	if remaining := leaf.NotAfter.Sub(now); remaining < clientCertExpiryWarning {
+		logger.Warn("TLS client certificate expires soon",
+			"subject_cn", leaf.Subject.CommonName,
+			"expires", leaf.NotAfter.Format(time.RFC3339),
+			"remaining", remaining.Round(time.Hour).String(),
+		)
+	}
+
+	return cert, nil
+}
+
+// newLogger creates a structured logger with the given output format and minimum level
+func newLogger(w io.Writer, format, level string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q (must be debug, info, warn, or error)", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch format {
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	case "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q (must be json or text)", format)
+	}
+}
+
 // maskAccessKey masks most of the access key for security logging
 func maskAccessKey(accessKey string) string {
 	if len(accessKey) <= 8 {
@@ -156,3 +756,26 @@ func maskAccessKey(accessKey string) string {
 	}
 	return accessKey[:4] + "****" + accessKey[len(accessKey)-4:]
 }
+
+// maskIdentity masks the account ID wherever it appears in identity
+func maskIdentity(identity credentials.Identity) credentials.Identity {
+	if identity.Account == "" {
+		return identity
+	}
+	masked := maskAccountID(identity.Account)
+	return credentials.Identity{
+		Account: masked,
+		Arn:     strings.ReplaceAll(identity.Arn, identity.Account, masked),
+		UserID:  strings.ReplaceAll(identity.UserID, identity.Account, masked),
+	}
+}
+
+// maskAccountID replaces the middle six digits of an AWS account ID with "***"
+// so logs identify the account without disclosing it
+func maskAccountID(accountID string) string {
+	if len(accountID) <= 6 {
+		return "***"
+	}
+	head := (len(accountID) - 6) / 2
+	return accountID[:head] + "***" + accountID[head+6:]
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/credentials"
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/signer"
+)
+
+// defaultPresignExpiry is how long presigned URLs are valid when --expires is not set
+const defaultPresignExpiry = 15 * time.Minute
+
+// runPresign loads AWS credentials and writes a SigV4 presigned URL for the
+// target to w. The URL, region, and service default to MCP_TARGET_URL,
+// AWS_REGION, and AWS_SERVICE_NAME.
+func runPresign(ctx context.Context, args []string, w io.Writer) error {
+	flags := flag.NewFlagSet("presign", flag.ContinueOnError)
+	targetURL := flags.String("url", os.Getenv("MCP_TARGET_URL"), "URL to presign")
+	method := flags.String("method", http.MethodGet, "HTTP method the presigned URL is used with")
+	expires := flags.Duration("expires", defaultPresignExpiry, "how long the presigned URL is valid (at most 168h)")
+	region := flags.String("region", os.Getenv("AWS_REGION"), "AWS region for the signature")
+	service := flags.String("service-name", os.Getenv("AWS_SERVICE_NAME"), "AWS service name for the signature")
+	profile := flags.String("profile", "", "AWS credential profile name")
+	roleARN := flags.String("role-arn", "", "IAM role to assume with STS AssumeRole")
+	externalID := flags.String("external-id", "", "external ID passed to AssumeRole")
+	roleSessionName := flags.String("role-session-name", "", "session name for the assumed role")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *targetURL == "" {
+		return errors.New("--url is required")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, *method, *targetURL, nil)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+
+	provider := &credentials.Provider{
+		Profile:               *profile,
+		Region:                *region,
+		AssumeRoleARN:         *roleARN,
+		AssumeRoleExternalID:  *externalID,
+		AssumeRoleSessionName: *roleSessionName,
+	}
+	awsCfg, err := provider.LoadConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS credentials: %w", err)
+	}
+
+	var presigner signer.PresignableSigner = &signer.V4Signer{
+		CredentialsProvider: awsCfg.Credentials,
+		Region:              *region,
+		Service:             *service,
+	}
+	presignedURL, _, err := presigner.PresignRequest(ctx, req, *expires)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, presignedURL)
+	return err
+}
@@ -0,0 +1,68 @@
+#!/bin/bash
+set -e
+
+# Run the signing benchmarks and compare allocations per operation with the baseline
+# Usage: ./bench.sh [--update]
+#
+# Fails when a benchmark allocates more than 20% above its baseline or has no
+# baseline. With --update, the baseline is rewritten from the current results instead.
+
+BASELINE="$(dirname "$0")/bench-baseline.txt"
+THRESHOLD=1.20
+PACKAGES="./internal/signer ./internal/transport"
+
+UPDATE=false
+if [ "$1" = "--update" ]; then
+    UPDATE=true
+fi
+
+OUTPUT=$(go test -run '^$' -bench . -benchmem -count=5 $PACKAGES)
+echo "$OUTPUT"
+
+# Average allocs/op per benchmark, with the GOMAXPROCS suffix removed from the name
+RESULTS=$(echo "$OUTPUT" | awk '
+    /^Benchmark/ {
+        name = $1
+        sub(/-[0-9]+$/, "", name)
+        for (i = 2; i < NF; i++) {
+            if ($(i + 1) == "allocs/op") {
+                sum[name] += $i
+                count[name]++
+            }
+        }
+    }
+    END {
+        for (name in sum) {
+            printf "%s %d\n", name, sum[name] / count[name] + 0.5
+        }
+    }' | sort)
+
+if [ "$UPDATE" = true ]; then
+    echo "$RESULTS" > "$BASELINE"
+    echo ""
+    echo "Updated $BASELINE"
+    exit 0
+fi
+
+echo ""
+echo "Allocations per operation (baseline -> current):"
+FAILED=false
+while read -r NAME ALLOCS; do
+    [ -z "$NAME" ] && continue
+    BASE=$(awk -v name="$NAME" '$1 == name { print $2 }' "$BASELINE")
+    if [ -z "$BASE" ]; then
+        echo "  $NAME: no baseline -> $ALLOCS (MISSING, run ./scripts/bench.sh --update to record it)"
+        FAILED=true
+        continue
+    fi
+    if awk -v cur="$ALLOCS" -v base="$BASE" -v limit="$THRESHOLD" 'BEGIN { exit !(cur > base * limit) }'; then
+        echo "  $NAME: $BASE -> $ALLOCS (REGRESSION, more than 20% above baseline)"
+        FAILED=true
+    else
+        echo "  $NAME: $BASE -> $ALLOCS"
+    fi
+done <<< "$RESULTS"
+
+if [ "$FAILED" = true ]; then
+    exit 1
+fi
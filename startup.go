@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+)
+
+// startupPhase is one step of the startup sequence
+type startupPhase struct {
+	number int
+	name   string
+}
+
+// The startup sequence, in order. Phases 1 through 5 check the proxy's
+// dependencies before anything is served.
+var (
+	phaseLoadConfig       = startupPhase{1, "load config"}
+	phaseValidateConfig   = startupPhase{2, "validate config"}
+	phaseLoadCredentials  = startupPhase{3, "load credentials"}
+	phaseValidateIdentity = startupPhase{4, "validate identity"}
+	phaseResolveTarget    = startupPhase{5, "resolve target hostname"}
+	phaseCreateProxy      = startupPhase{6, "create proxy"}
+	phaseRunServer        = startupPhase{7, "run server"}
+)
+
+// startupError reports the phase in which startup failed
+type startupError struct {
+	phase startupPhase
+	err   error
+}
+
+func (e *startupError) Error() string {
+	return fmt.Sprintf("startup failed at phase %d (%s): %v", e.phase.number, e.phase.name, e.err)
+}
+
+func (e *startupError) Unwrap() error {
+	return e.err
+}
+
+// startup tracks the startup sequence. Without fail-fast, a failed dependency
+// check does not stop the checks that follow it, so every problem is reported
+// before the proxy exits; with fail-fast, startup stops at the first failure.
+type startup struct {
+	logger   *slog.Logger
+	failFast bool
+	failures []error
+}
+
+// begin logs the start of phase
+func (s *startup) begin(phase startupPhase) {
+	s.logger.Info(fmt.Sprintf("phase %d: %s", phase.number, phase.name))
+}
+
+// abort logs the failure of a phase that later phases cannot run without and
+// returns the error to stop startup with
+func (s *startup) abort(phase startupPhase, err error) error {
+	s.fail(phase, err)
+	return errors.Join(s.failures...)
+}
+
+// check logs the failure of a dependency check and returns the error to stop
+// startup with under fail-fast, or nil so the remaining checks still run.
+// It returns nil when err is nil.
+func (s *startup) check(phase startupPhase, err error) error {
+	if err == nil {
+		return nil
+	}
+	s.fail(phase, err)
+	if s.failFast {
+		return errors.Join(s.failures...)
+	}
+	return nil
+}
+
+// checked returns the failures of the dependency checks, or nil if they all passed
+func (s *startup) checked() error {
+	return errors.Join(s.failures...)
+}
+
+// fail logs and records the failure of phase
+func (s *startup) fail(phase startupPhase, err error) {
+	failure := &startupError{phase: phase, err: err}
+	s.logger.Error(failure.Error())
+	s.failures = append(s.failures, failure)
+}
+
+// resolveTargetHost looks up the hostname of targetURL, reporting DNS problems
+// before the first request to the target server
+func resolveTargetHost(ctx context.Context, targetURL string, logger *slog.Logger) error {
+	parsed, err := url.Parse(targetURL)
+	if err != nil {
+		return fmt.Errorf("invalid target URL: %w", err)
+	}
+	host := parsed.Hostname()
+	if host == "" {
+		return fmt.Errorf("target URL %q has no hostname", targetURL)
+	}
+	addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve target hostname: %w", err)
+	}
+	logger.Info("target hostname resolved", "host", host, "addresses", len(addrs))
+	return nil
+}
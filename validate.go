@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/nisimpson/mcp-sigv4-proxy/internal/config"
+)
+
+// runValidate loads the configuration from the environment, configuration file,
+// and command-line flags and writes a validation report to w. It does not load
+// credentials or contact the target server. It reports whether the configuration
+// is valid.
+func runValidate(w io.Writer, logger *slog.Logger) bool {
+	cfg, err := config.Load(logger)
+	return writeValidationReport(w, cfg, err)
+}
+
+// writeValidationReport writes the result of loading cfg to w, listing each
+// validation error with its field, value, and remediation. It reports whether
+// the configuration is valid.
+func writeValidationReport(w io.Writer, cfg *config.Config, err error) bool {
+	if err == nil {
+		fmt.Fprintf(w, "configuration is valid (target %s, region %s, service %s, signature %s)\n",
+			cfg.TargetURL, cfg.Region, cfg.ServiceName, cfg.SignatureVersion)
+		return true
+	}
+
+	fieldErrs := config.ValidationErrors(err)
+	if len(fieldErrs) == 0 {
+		// Errors such as an unreadable configuration file are not tied to a field
+		fmt.Fprintf(w, "configuration is invalid:\n  %v\n", err)
+		return false
+	}
+
+	fmt.Fprintln(w, "configuration is invalid:")
+	for _, fieldErr := range fieldErrs {
+		fmt.Fprintf(w, "  %s: %s\n", fieldErr.Field, fieldErr.Problem)
+		if fieldErr.Value != "" {
+			fmt.Fprintf(w, "    value: %q\n", fieldErr.Value)
+		}
+		if fieldErr.Remediation != "" {
+			fmt.Fprintf(w, "    fix:   %s\n", fieldErr.Remediation)
+		}
+	}
+	return false
+}
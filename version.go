@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// Build information, set at link time with
+// go build -ldflags "-X main.Version=... -X main.Commit=... -X main.BuildDate=..."
+// (see the Makefile build target)
+var (
+	// Version is the release version, defaulting to serverVersion for untagged builds
+	Version = serverVersion
+
+	// Commit is the git commit the binary was built from
+	Commit = "unknown"
+
+	// BuildDate is when the binary was built, in RFC 3339 format
+	BuildDate = "unknown"
+)
+
+// versionString describes the build for the --version flag
+func versionString() string {
+	return fmt.Sprintf("sigv4-proxy version %s commit %s built %s go %s",
+		Version, Commit, BuildDate, runtime.Version())
+}